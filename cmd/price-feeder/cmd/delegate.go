@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	oracletypes "github.com/kiichain/kiichain/v3/x/oracle/types"
+	"github.com/rs/zerolog"
+	"github.com/spf13/cobra"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle"
+	"github.com/kiichain/price-feeder/oracle/client"
+)
+
+const (
+	// Define the flags of the delegate command
+	FlagDryRun = "dry-run"
+
+	// delegationVerifyTimeout is the time waited for the delegation to be on-chain
+	delegationVerifyTimeout = 30 * time.Second
+	// delegationVerifyInterval is the interval between the delegation queries
+	delegationVerifyInterval = 2 * time.Second
+)
+
+var delegateCmd = &cobra.Command{
+	Use:   "delegate [config-file]",
+	Args:  cobra.ExactArgs(1),
+	Short: "delegates the validator's feeder consent to the configured feeder account",
+	Long: `delegates the validator's feeder consent to the configured feeder account, by signing
+and broadcasting a MsgDelegateFeedConsent with the validator owner key. The key must be on the
+configured keyring. Once broadcasted, the feeder delegation is queried until it is on-chain.
+If the flag --dry-run is set, the unsigned transaction is printed instead of being broadcasted,
+without opening the keyring or querying the chain.
+The environment variable PRICE_FEEDER_PASS can be used to set the keyring password.`,
+	RunE: delegateCmdHandler,
+}
+
+func init() {
+	// set the delegate command's flags
+	delegateCmd.Flags().Bool(FlagDryRun, false, "print the unsigned transaction without broadcasting it")
+	delegateCmd.Flags().Bool(FlagSkipPassword, false, "skip keyring password prompt. Useful if using keyring test.")
+}
+
+// delegateCmdHandler delegates the feeder consent of the validator
+func delegateCmdHandler(cmd *cobra.Command, args []string) error {
+	// create logger from the cmd flags
+	logger, err := getLogger(cmd)
+	if err != nil {
+		return err
+	}
+
+	// parse configurations from the config file to Config struct
+	cfg, err := config.ParseConfig(args[0])
+	if err != nil {
+		return err
+	}
+
+	dryRun, err := cmd.Flags().GetBool(FlagDryRun)
+	if err != nil {
+		return err
+	}
+
+	// get the validator and the feeder accounts
+	validatorAddr, err := sdk.ValAddressFromBech32(cfg.Account.Validator)
+	if err != nil {
+		return fmt.Errorf("invalid validator address: %w", err)
+	}
+	feederAddr, err := sdk.AccAddressFromBech32(cfg.Account.Address)
+	if err != nil {
+		return fmt.Errorf("invalid feeder address: %w", err)
+	}
+
+	msg := oracletypes.NewMsgDelegateFeedConsent(sdk.AccAddress(validatorAddr), feederAddr)
+
+	// get rpc timeout from config
+	rpcTimeout, err := time.ParseDuration(cfg.RPC.RPCTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to parse RPC timeout: %w", err)
+	}
+
+	// only print the unsigned transaction on dry run, before using the
+	// keyring or the chain
+	if dryRun {
+		delegationClient, err := newDelegationClient(logger, cfg, rpcTimeout, "")
+		if err != nil {
+			return err
+		}
+
+		bz, err := delegationClient.GenerateUnsignedTx(msg)
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(cmd.OutOrStdout(), string(bz))
+		return err
+	}
+
+	// check the current delegation
+	currentFeeder, err := oracle.QueryFeederDelegation(cmd.Context(), cfg.RPC.GRPCEndpoint, cfg.Account.Validator)
+	if err != nil {
+		return err
+	}
+	if currentFeeder == feederAddr.String() {
+		_, err = fmt.Fprintf(cmd.OutOrStdout(), "validator %s already delegates to %s\n", cfg.Account.Validator, currentFeeder)
+		return err
+	}
+
+	// Gather password via env variable or std input
+	skipPassword, err := cmd.Flags().GetBool(FlagSkipPassword)
+	if err != nil {
+		return err
+	}
+
+	keyringPass, err := getKeyringPassword(skipPassword)
+	if err != nil {
+		return err
+	}
+
+	// create the client signing with the validator owner key
+	delegationClient, err := newDelegationClient(logger, cfg, rpcTimeout, keyringPass)
+	if err != nil {
+		return err
+	}
+
+	clientCtx, err := delegationClient.CreateClientContext()
+	if err != nil {
+		return fmt.Errorf("failed to find the validator owner key on the keyring: %w", err)
+	}
+	clientCtx = clientCtx.WithCmdContext(cmd.Context())
+
+	// broadcast the delegation
+	resp, err := delegationClient.BroadcastTx(clientCtx, msg)
+	if err != nil {
+		return fmt.Errorf("failed to broadcast the feeder delegation: %w", err)
+	}
+
+	logger.Info().
+		Str("tx_hash", resp.TxHash).
+		Str("validator", cfg.Account.Validator).
+		Str("feeder", feederAddr.String()).
+		Msg("broadcasted the feeder delegation, waiting for it to be on-chain")
+
+	// verify the delegation is on-chain
+	if err := verifyFeederDelegation(cmd.Context(), cfg, feederAddr.String()); err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(cmd.OutOrStdout(), "validator %s now delegates to %s (tx %s)\n", cfg.Account.Validator, feederAddr, resp.TxHash)
+	return err
+}
+
+// newDelegationClient creates the client signing with the validator owner
+// key, the keyring is only opened once the client context is created
+func newDelegationClient(
+	logger zerolog.Logger,
+	cfg config.Config,
+	rpcTimeout time.Duration,
+	keyringPass string,
+) (client.OracleClient, error) {
+	return client.NewDelegationClient(
+		logger,
+		cfg.Account.ChainID,
+		cfg.Keyring.Backend,
+		cfg.Keyring.Dir,
+		keyringPass,
+		cfg.RPC.TMRPCEndpoint,
+		rpcTimeout,
+		cfg.Account.Validator,
+		cfg.RPC.GRPCEndpoint,
+		cfg.Gas.GasAdjustment,
+		cfg.Gas.GasPrices,
+		cfg.Gas.GasLimit,
+	)
+}
+
+// verifyFeederDelegation queries the feeder delegation of the validator until
+// it matches the expected feeder or the verification times out
+func verifyFeederDelegation(ctx context.Context, cfg config.Config, expectedFeeder string) error {
+	ctx, cancel := context.WithTimeout(ctx, delegationVerifyTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(delegationVerifyInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("feeder delegation of %s not found on-chain after %s", cfg.Account.Validator, delegationVerifyTimeout)
+
+		case <-ticker.C:
+			feeder, err := oracle.QueryFeederDelegation(ctx, cfg.RPC.GRPCEndpoint, cfg.Account.Validator)
+			if err != nil {
+				continue
+			}
+			if feeder == expectedFeeder {
+				return nil
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// writeDelegateConfig writes a config with the validator and feeder accounts
+// and an empty keyring
+func writeDelegateConfig(t *testing.T, validator, feeder string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "price-feeder.toml")
+	content := fmt.Sprintf(`
+[main]
+enable_voting = true
+enable_server = true
+
+[server]
+listen_addr = "0.0.0.0:7171"
+read_timeout = "20s"
+write_timeout = "20s"
+enable_cors = true
+allowed_origins = ["*"]
+
+[account]
+address = %q
+validator = %q
+chain_id = "kii-local-testnet"
+prefix = "kii"
+
+[keyring]
+backend = "test"
+dir = %q
+
+[rpc]
+tmrpc_endpoint = "http://localhost:26657"
+grpc_endpoint = "localhost:9090"
+rpc_timeout = "100ms"
+
+[gas]
+gas_adjustment = 1.5
+gas_prices = "0.00125akii"
+gas_limit = 200000
+
+[telemetry]
+enabled = false
+
+[[currency_pairs]]
+base = "BTC"
+chain_denom = "ubtc"
+quote = "USD"
+providers = ["kraken", "coinbase", "bitstamp"]
+`, feeder, validator, filepath.Join(dir, "keyring"))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+// executeDelegate runs the delegate command, returning its output
+func executeDelegate(args ...string) (string, error) {
+	out := new(bytes.Buffer)
+	rootCmd.SetOut(out)
+	rootCmd.SetErr(out)
+	rootCmd.SetArgs(append([]string{"delegate"}, args...))
+	defer rootCmd.SetArgs(nil)
+
+	_, err := rootCmd.ExecuteC()
+	return out.String(), err
+}
+
+func TestDelegateCmdDryRun(t *testing.T) {
+	validator := sdk.ValAddress(bytes.Repeat([]byte{1}, 20))
+	feeder := sdk.AccAddress(bytes.Repeat([]byte{2}, 20))
+	path := writeDelegateConfig(t, validator.String(), feeder.String())
+
+	// the keyring is empty, the unsigned transaction doesn't need the key
+	out, err := executeDelegate(path, "--dry-run")
+	require.NoError(t, err)
+	require.Contains(t, out, "/kiichain.oracle.v1beta1.MsgDelegateFeedConsent")
+	require.Contains(t, out, sdk.AccAddress(validator).String())
+	require.Contains(t, out, feeder.String())
+	require.Contains(t, out, `"gas_limit":"200000"`)
+	require.Contains(t, out, `"amount":"250"`)
+}
+
+func TestDelegateCmdArgs(t *testing.T) {
+	feeder := sdk.AccAddress(bytes.Repeat([]byte{2}, 20))
+
+	// the config file is required
+	_, err := executeDelegate()
+	require.ErrorContains(t, err, "accepts 1 arg(s), received 0")
+
+	// the validator must be a validator address
+	path := writeDelegateConfig(t, feeder.String(), feeder.String())
+	_, err = executeDelegate(path, "--dry-run")
+	require.ErrorContains(t, err, "invalid validator address")
+}
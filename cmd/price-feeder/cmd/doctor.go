@@ -0,0 +1,292 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	tmrpchttp "github.com/cometbft/cometbft/rpc/client/http"
+	"github.com/rs/zerolog"
+	"github.com/spf13/cobra"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle"
+	"github.com/kiichain/price-feeder/oracle/client"
+)
+
+const (
+	// doctorTimeout is the time given to each network check
+	doctorTimeout = 15 * time.Second
+
+	// Define the status of the doctor checks
+	doctorStatusPass = "PASS"
+	doctorStatusFail = "FAIL"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor [config-file]",
+	Args:  cobra.ExactArgs(1),
+	Short: "validates the configuration, keyring, endpoints and providers of a deployment",
+	Long: `validates the configuration, keyring, endpoints and providers of a deployment before
+starting the price-feeder, printing a pass/fail table. The command fails when any check fails.
+The environment variable PRICE_FEEDER_PASS can be used to set the keyring password.`,
+	RunE: doctorCmdHandler,
+}
+
+func init() {
+	// set the doctor command's flags
+	doctorCmd.Flags().Bool(FlagSkipPassword, false, "skip keyring password prompt. Useful if using keyring test.")
+}
+
+// doctorCheck is the result of a single doctor check
+type doctorCheck struct {
+	Name   string
+	Err    error
+	Detail string
+}
+
+// doctorReport accumulates the results of the doctor checks
+type doctorReport struct {
+	checks []doctorCheck
+}
+
+// pass records a successful check
+func (r *doctorReport) pass(name, detail string) {
+	r.checks = append(r.checks, doctorCheck{Name: name, Detail: detail})
+}
+
+// fail records a failed check
+func (r *doctorReport) fail(name string, err error) {
+	r.checks = append(r.checks, doctorCheck{Name: name, Err: err})
+}
+
+// failed returns the number of failed checks
+func (r *doctorReport) failed() int {
+	failed := 0
+	for _, check := range r.checks {
+		if check.Err != nil {
+			failed++
+		}
+	}
+	return failed
+}
+
+// print writes the checks as a table
+func (r *doctorReport) print(cmd *cobra.Command) error {
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(w, "CHECK\tSTATUS\tDETAIL"); err != nil {
+		return err
+	}
+
+	for _, check := range r.checks {
+		status, detail := doctorStatusPass, check.Detail
+		if check.Err != nil {
+			status, detail = doctorStatusFail, check.Err.Error()
+		}
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\n", check.Name, status, detail); err != nil {
+			return err
+		}
+	}
+
+	return w.Flush()
+}
+
+// doctorCmdHandler runs the preflight checks of the deployment
+func doctorCmdHandler(cmd *cobra.Command, args []string) error {
+	// create logger from the cmd flags
+	logger, err := getLogger(cmd)
+	if err != nil {
+		return err
+	}
+
+	report := &doctorReport{}
+
+	// every other check depends on the configuration
+	cfg, err := config.ParseConfig(args[0])
+	if err != nil {
+		report.fail("config", err)
+		return finishDoctor(cmd, report)
+	}
+	report.pass("config", args[0])
+
+	// Gather password via env variable or std input
+	skipPassword, err := cmd.Flags().GetBool(FlagSkipPassword)
+	if err != nil {
+		return err
+	}
+
+	keyringPass, err := getKeyringPassword(skipPassword)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	checkKeyring(report, cfg, keyringPass)
+	checkTendermintRPC(ctx, report, cfg)
+
+	// the oracle is only used to query the chain and connect to the providers
+	o, err := newDoctorOracle(logger, cfg)
+	if err != nil {
+		report.fail("oracle", err)
+		return finishDoctor(cmd, report)
+	}
+
+	checkGRPC(ctx, report, cfg, o)
+	checkProviders(ctx, report, o)
+
+	return finishDoctor(cmd, report)
+}
+
+// finishDoctor prints the report and fails when any check failed
+func finishDoctor(cmd *cobra.Command, report *doctorReport) error {
+	if err := report.print(cmd); err != nil {
+		return err
+	}
+
+	if failed := report.failed(); failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(report.checks))
+	}
+
+	return nil
+}
+
+// checkKeyring validates the keyring unlocks and contains the feeder account
+func checkKeyring(report *doctorReport, cfg config.Config, keyringPass string) {
+	kr, err := client.NewKeyring(cfg.Keyring.Backend, cfg.Keyring.Dir, keyringPass)
+	if err != nil {
+		report.fail("keyring", err)
+		return
+	}
+
+	feederAddr, err := sdk.AccAddressFromBech32(cfg.Account.Address)
+	if err != nil {
+		report.fail("keyring", fmt.Errorf("invalid feeder address: %w", err))
+		return
+	}
+
+	record, err := kr.KeyByAddress(feederAddr)
+	if err != nil {
+		report.fail("keyring", fmt.Errorf("feeder account %s not found: %w", cfg.Account.Address, err))
+		return
+	}
+
+	report.pass("keyring", fmt.Sprintf("found key %s for %s", record.Name, cfg.Account.Address))
+}
+
+// checkTendermintRPC validates the Tendermint RPC responds and the chain ID matches
+func checkTendermintRPC(ctx context.Context, report *doctorReport, cfg config.Config) {
+	rpcClient, err := tmrpchttp.New(cfg.RPC.TMRPCEndpoint, "/websocket")
+	if err != nil {
+		report.fail("tendermint rpc", err)
+		report.fail("chain id", fmt.Errorf("tendermint rpc unavailable"))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, doctorTimeout)
+	defer cancel()
+
+	status, err := rpcClient.Status(ctx)
+	if err != nil {
+		report.fail("tendermint rpc", err)
+		report.fail("chain id", fmt.Errorf("tendermint rpc unavailable"))
+		return
+	}
+	report.pass("tendermint rpc", fmt.Sprintf("%s at height %d", cfg.RPC.TMRPCEndpoint, status.SyncInfo.LatestBlockHeight))
+
+	if status.NodeInfo.Network != cfg.Account.ChainID {
+		report.fail("chain id", fmt.Errorf("configured %s, node is on %s", cfg.Account.ChainID, status.NodeInfo.Network))
+		return
+	}
+	report.pass("chain id", cfg.Account.ChainID)
+}
+
+// checkGRPC validates the gRPC endpoint responds, the feeder delegation and
+// the whitelist of the configured currency pairs
+func checkGRPC(ctx context.Context, report *doctorReport, cfg config.Config, o *oracle.Oracle) {
+	params, err := o.GetParams(ctx)
+	if err != nil {
+		report.fail("grpc", err)
+		report.fail("feeder delegation", fmt.Errorf("grpc unavailable"))
+		report.fail("whitelist", fmt.Errorf("grpc unavailable"))
+		return
+	}
+	report.pass("grpc", cfg.RPC.GRPCEndpoint)
+
+	// the validator must delegate to the configured feeder
+	feeder, err := oracle.QueryFeederDelegation(ctx, cfg.RPC.GRPCEndpoint, cfg.Account.Validator)
+	switch {
+	case err != nil:
+		report.fail("feeder delegation", err)
+	case feeder != cfg.Account.Address:
+		report.fail("feeder delegation", fmt.Errorf("validator %s delegates to %s, not %s", cfg.Account.Validator, feeder, cfg.Account.Address))
+	default:
+		report.pass("feeder delegation", fmt.Sprintf("%s delegates to %s", cfg.Account.Validator, feeder))
+	}
+
+	// every configured denom must be whitelisted, and every whitelisted denom priced
+	missingPrices, notWhitelisted := o.CompareWhitelist(params)
+	var problems []string
+	if len(notWhitelisted) > 0 {
+		problems = append(problems, fmt.Sprintf("not whitelisted: %s", strings.Join(notWhitelisted, ", ")))
+	}
+	if len(missingPrices) > 0 {
+		problems = append(problems, fmt.Sprintf("missing prices: %s", strings.Join(missingPrices, ", ")))
+	}
+	if len(problems) > 0 {
+		report.fail("whitelist", fmt.Errorf("%s", strings.Join(problems, "; ")))
+		return
+	}
+	report.pass("whitelist", fmt.Sprintf("%d denoms whitelisted", len(params.Whitelist)))
+}
+
+// checkProviders validates every provider connects and lists the configured pairs
+func checkProviders(ctx context.Context, report *doctorReport, o *oracle.Oracle) {
+	for _, providerName := range o.GetProviderNames() {
+		name := fmt.Sprintf("provider %s", providerName)
+
+		unavailablePairs, err := o.CheckProviderPairs(ctx, providerName)
+		if err != nil {
+			report.fail(name, err)
+			continue
+		}
+
+		if len(unavailablePairs) > 0 {
+			report.fail(name, fmt.Errorf("pairs not available: %s", strings.Join(unavailablePairs, ", ")))
+			continue
+		}
+
+		report.pass(name, "all pairs available")
+	}
+
+	// the minimum amount of providers is checked on the available pairs
+	validation := o.GetPairValidation()
+	if len(validation.UnderProvided) > 0 {
+		report.fail("provider minimum", fmt.Errorf("less than %d providers for %s", config.MinimumProviders, strings.Join(validation.UnderProvided, ", ")))
+		return
+	}
+	report.pass("provider minimum", fmt.Sprintf("at least %d providers by pair", config.MinimumProviders))
+}
+
+// newDoctorOracle creates an oracle able to query the chain and the providers,
+// without the transaction signing setup of the oracle client
+func newDoctorOracle(logger zerolog.Logger, cfg config.Config) (*oracle.Oracle, error) {
+	opts, err := getOracleOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	oracleClient := client.OracleClient{
+		ChainID:             cfg.Account.ChainID,
+		GRPCEndpoint:        cfg.RPC.GRPCEndpoint,
+		ValidatorAddrString: cfg.Account.Validator,
+		OracleAddrString:    cfg.Account.Address,
+	}
+
+	return oracle.New(logger, oracleClient, opts), nil
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle"
+	v1 "github.com/kiichain/price-feeder/router/v1"
+)
+
+const (
+	// Define the flags of the journal command
+	FlagHeight = "height"
+	FlagOffset = "offset"
+	FlagLimit  = "limit"
+)
+
+var journalCmd = &cobra.Command{
+	Use:   "journal [config-file]",
+	Args:  cobra.ExactArgs(1),
+	Short: "prints the votes recorded on the vote journal",
+	Long: `prints the votes recorded on the vote journal configured by journal_path,
+from the newest to the oldest. Each vote includes the price reported by each provider,
+the computation method, the final rates and the broadcast outcome.
+Use --height to print the votes attempted at a given block height.`,
+	RunE: journalCmdHandler,
+}
+
+func init() {
+	// set the journal command's flags
+	journalCmd.Flags().Int64(FlagHeight, 0, "only print the votes attempted at this block height")
+	journalCmd.Flags().Int(FlagOffset, 0, "amount of votes to skip, from the newest")
+	journalCmd.Flags().Int(FlagLimit, 50, "maximum amount of votes to print")
+}
+
+// journalCmdHandler prints a page of the vote journal
+func journalCmdHandler(cmd *cobra.Command, args []string) error {
+	// parse configurations from the config file to Config struct
+	cfg, err := config.ParseConfig(args[0])
+	if err != nil {
+		return err
+	}
+
+	if cfg.Main.JournalPath == "" {
+		return oracle.ErrJournalDisabled
+	}
+
+	// get the query from the flags
+	height, err := cmd.Flags().GetInt64(FlagHeight)
+	if err != nil {
+		return err
+	}
+	offset, err := cmd.Flags().GetInt(FlagOffset)
+	if err != nil {
+		return err
+	}
+	limit, err := cmd.Flags().GetInt(FlagLimit)
+	if err != nil {
+		return err
+	}
+
+	// read the journal file
+	entries, total, err := oracle.ReadJournal(cfg.Main.JournalPath, oracle.JournalQuery{
+		Height: height,
+		Offset: offset,
+		Limit:  limit,
+	})
+	if err != nil {
+		return err
+	}
+
+	bz, err := json.MarshalIndent(v1.JournalResponse{
+		Entries: entries,
+		Total:   total,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// print on the console
+	_, err = fmt.Fprintln(cmd.OutOrStdout(), string(bz))
+	return err
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle"
+)
+
+// configWatchInterval is the interval between the checks of the config file
+const configWatchInterval = 10 * time.Second
+
+// watchConfig reloads the oracle configuration when the config file is
+// modified or a SIGHUP is received
+func watchConfig(ctx context.Context, logger zerolog.Logger, configPath string, o *oracle.Oracle) error {
+	// listen for SIGHUP, which otherwise terminates the process
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+	defer signal.Stop(sigCh)
+
+	ticker := time.NewTicker(configWatchInterval)
+	defer ticker.Stop()
+
+	lastModTime := getModTime(configPath)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case <-sigCh:
+			logger.Info().Str("config", configPath).Msg("caught SIGHUP; reloading config...")
+			reloadConfig(logger, configPath, o)
+
+		case <-ticker.C:
+			modTime := getModTime(configPath)
+			if !modTime.After(lastModTime) {
+				continue
+			}
+			lastModTime = modTime
+
+			logger.Info().Str("config", configPath).Msg("config file changed; reloading config...")
+			reloadConfig(logger, configPath, o)
+		}
+	}
+}
+
+// reloadConfig parses the config file and queues the reloadable settings on
+// the oracle. The current settings are kept when the config is invalid.
+func reloadConfig(logger zerolog.Logger, configPath string, o *oracle.Oracle) {
+	cfg, err := config.ParseConfig(configPath)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to parse config, keeping the current config")
+		return
+	}
+
+	reloadable, err := getReloadConfig(cfg)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to parse config, keeping the current config")
+		return
+	}
+
+	o.Reload(reloadable)
+}
+
+// getModTime returns the modification time of the file, or the zero time
+// when it can't be read
+func getModTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return info.ModTime()
+}
@@ -34,6 +34,9 @@ func init() {
 	// add subcommands to the root command
 	rootCmd.AddCommand(CmdgetVersion())
 	rootCmd.AddCommand(startCMD)
+	rootCmd.AddCommand(journalCmd)
+	rootCmd.AddCommand(delegateCmd)
+	rootCmd.AddCommand(doctorCmd)
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
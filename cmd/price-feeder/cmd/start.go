@@ -54,40 +54,12 @@ func init() {
 
 // priceFeederCmdHandler init the price feeder
 func priceFeederCmdHandler(cmd *cobra.Command, args []string) error {
-	// get value from the log level cmd flag
-	logLvlStr, err := cmd.Flags().GetString(FlagLogLevel)
-	if err != nil {
-		return err
-	}
-
-	// get value from the log format cmd flag
-	logFormatStr, err := cmd.Flags().GetString(FlagLogFormat)
-	if err != nil {
-		return err
-	}
-
-	logLvl, err := zerolog.ParseLevel(logLvlStr)
+	// create logger from the cmd flags
+	logger, err := getLogger(cmd)
 	if err != nil {
 		return err
 	}
 
-	// set the log format based on the flags
-	var logWriter io.Writer
-	switch strings.ToLower(logFormatStr) {
-
-	case LogLevelJSON:
-		logWriter = os.Stderr
-
-	case LogLevelTest:
-		logWriter = zerolog.ConsoleWriter{Out: os.Stderr}
-
-	default:
-		return fmt.Errorf("invalid logging format: %s", logFormatStr)
-	}
-
-	// create logger
-	logger := zerolog.New(logWriter).Level(logLvl).With().Timestamp().Logger()
-
 	// pase configurations from the config file to Config struct
 	cfg, err := config.ParseConfig(args[0])
 	if err != nil {
@@ -150,38 +122,21 @@ func priceFeederCmdHandler(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("error creating oracle client: %w", err)
 	}
 
-	// get provider timeout from config
-	providerTimeout, err := time.ParseDuration(cfg.ProviderTimeout)
+	opts, err := getOracleOptions(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to parse provider timeout: %w", err)
+		return err
 	}
 
-	// create a map with the deviation by denom from config file
-	deviations := make(map[string]math.LegacyDec, len(cfg.Deviations))
-	for _, deviation := range cfg.Deviations {
-		threshold, err := math.LegacyNewDecFromStr(deviation.Threshold)
+	// create the vote journal when configured
+	if cfg.Main.JournalPath != "" {
+		opts.Journal, err = oracle.NewJournal(cfg.Main.JournalPath)
 		if err != nil {
 			return err
 		}
-		deviations[deviation.Base] = threshold
-	}
-
-	// create a map with the endpoitns listed on the config file
-	endpoints := make(map[string]config.ProviderEndpoint, len(cfg.ProviderEndpoints))
-	for _, endpoint := range cfg.ProviderEndpoints {
-		endpoints[endpoint.Name] = endpoint
 	}
 
 	// create new oracle instance
-	oracle := oracle.New(
-		logger,
-		oracleClient,
-		cfg.CurrencyPairs,
-		providerTimeout,
-		deviations,
-		endpoints,
-		cfg.Healthchecks,
-	)
+	oracle := oracle.New(logger, oracleClient, opts)
 
 	// Create the telemetry config
 	telemetryConfig := telemetry.Config{
@@ -211,6 +166,11 @@ func priceFeederCmdHandler(cmd *cobra.Command, args []string) error {
 		})
 	}
 
+	// Reload the pairs, deviations and endpoints when the config file changes
+	group.Go(func() error {
+		return watchConfig(ctx, logger, args[0], oracle)
+	})
+
 	// Check if voter is enabled
 	if cfg.Main.EnableVoting {
 		// Start the voter process
@@ -218,6 +178,11 @@ func priceFeederCmdHandler(cmd *cobra.Command, args []string) error {
 			// Start the voter process
 			return startPriceOracle(ctx, logger, oracle)
 		})
+	} else {
+		// Apply the reloaded config without the voter process
+		group.Go(func() error {
+			return oracle.ApplyReloads(ctx)
+		})
 	}
 
 	// Block main process until all spawned goroutines have gracefully exited and
@@ -225,6 +190,237 @@ func priceFeederCmdHandler(cmd *cobra.Command, args []string) error {
 	return group.Wait()
 }
 
+// getOracleOptions parses the settings of the oracle from the config, the
+// reloadable ones being parsed again on each reload
+func getOracleOptions(cfg config.Config) (oracle.Options, error) {
+	// get provider timeout from config
+	providerTimeout, err := time.ParseDuration(cfg.ProviderTimeout)
+	if err != nil {
+		return oracle.Options{}, fmt.Errorf("failed to parse provider timeout: %w", err)
+	}
+
+	reloadable, err := getReloadConfig(cfg)
+	if err != nil {
+		return oracle.Options{}, err
+	}
+
+	return oracle.Options{
+		ReloadConfig:    reloadable,
+		ProviderTimeout: providerTimeout,
+		Healthchecks:    cfg.Healthchecks,
+		ShadowMode:      cfg.Main.ShadowMode,
+	}, nil
+}
+
+// getReloadConfig parses the settings of the oracle which can change without
+// a restart
+func getReloadConfig(cfg config.Config) (oracle.ReloadConfig, error) {
+	// create a map with the deviation by denom from config file
+	deviations, err := getDeviations(cfg)
+	if err != nil {
+		return oracle.ReloadConfig{}, fmt.Errorf("failed to parse deviations: %w", err)
+	}
+
+	orderBooks, err := getOrderBooks(cfg)
+	if err != nil {
+		return oracle.ReloadConfig{}, fmt.Errorf("failed to parse order books: %w", err)
+	}
+
+	candleFreshness, err := getCandleFreshness(cfg)
+	if err != nil {
+		return oracle.ReloadConfig{}, fmt.Errorf("failed to parse candle ages: %w", err)
+	}
+
+	circuitBreakers, err := getCircuitBreakers(cfg)
+	if err != nil {
+		return oracle.ReloadConfig{}, fmt.Errorf("failed to parse circuit breakers: %w", err)
+	}
+
+	return oracle.ReloadConfig{
+		CurrencyPairs:    cfg.CurrencyPairs,
+		Deviations:       deviations,
+		Endpoints:        getEndpoints(cfg),
+		GenericProviders: getGenericProviders(cfg),
+		Plugins:          getPlugins(cfg),
+		DexPools:         getDexPools(cfg),
+		FXProviders:      getFXProviders(cfg),
+		OrderBooks:       orderBooks,
+		CandleFreshness:  candleFreshness,
+		CircuitBreakers:  circuitBreakers,
+	}, nil
+}
+
+// getDeviations creates a map with the deviation threshold by base
+func getDeviations(cfg config.Config) (map[string]math.LegacyDec, error) {
+	deviations := make(map[string]math.LegacyDec, len(cfg.Deviations))
+	for _, deviation := range cfg.Deviations {
+		threshold, err := math.LegacyNewDecFromStr(deviation.Threshold)
+		if err != nil {
+			return nil, err
+		}
+		deviations[deviation.Base] = threshold
+	}
+
+	return deviations, nil
+}
+
+// getOrderBooks creates a map with the order book settings by base
+func getOrderBooks(cfg config.Config) (map[string]oracle.OrderBookSettings, error) {
+	orderBooks := make(map[string]oracle.OrderBookSettings, len(cfg.OrderBooks))
+	for _, orderBook := range cfg.OrderBooks {
+		depthBand, err := orderBook.GetDepthBand()
+		if err != nil {
+			return nil, err
+		}
+		maxSpread, err := orderBook.GetMaxSpread()
+		if err != nil {
+			return nil, err
+		}
+		orderBooks[orderBook.Base] = oracle.OrderBookSettings{
+			DepthBand:   depthBand,
+			MaxSpread:   maxSpread,
+			UseMidPrice: orderBook.UseMidPrice,
+		}
+	}
+
+	return orderBooks, nil
+}
+
+// getCandleFreshness creates the maximum candle ages and clock skew, with the
+// maximum age by base
+func getCandleFreshness(cfg config.Config) (oracle.CandleFreshness, error) {
+	maxAge, err := cfg.GetMaxCandleAge()
+	if err != nil {
+		return oracle.CandleFreshness{}, err
+	}
+	maxClockSkew, err := cfg.GetMaxClockSkew()
+	if err != nil {
+		return oracle.CandleFreshness{}, err
+	}
+
+	maxAges := make(map[string]time.Duration)
+	for _, pair := range cfg.CurrencyPairs {
+		pairMaxAge, err := pair.GetMaxCandleAge()
+		if err != nil {
+			return oracle.CandleFreshness{}, err
+		}
+		if pairMaxAge > 0 {
+			maxAges[pair.Base] = pairMaxAge
+		}
+	}
+
+	return oracle.CandleFreshness{
+		MaxAge:       maxAge,
+		MaxAges:      maxAges,
+		MaxClockSkew: maxClockSkew,
+	}, nil
+}
+
+// getCircuitBreakers creates a map with the circuit breakers by base
+func getCircuitBreakers(cfg config.Config) (map[string]oracle.CircuitBreaker, error) {
+	circuitBreakers := make(map[string]oracle.CircuitBreaker, len(cfg.CircuitBreakers))
+	for _, circuitBreaker := range cfg.CircuitBreakers {
+		maxChange, err := circuitBreaker.GetMaxChange()
+		if err != nil {
+			return nil, err
+		}
+		circuitBreakers[circuitBreaker.Base] = oracle.CircuitBreaker{
+			MaxChange:     maxChange,
+			Confirmations: circuitBreaker.Confirmations,
+		}
+	}
+
+	return circuitBreakers, nil
+}
+
+// getEndpoints creates a map with the endpoints by provider
+func getEndpoints(cfg config.Config) map[string]config.ProviderEndpoint {
+	endpoints := make(map[string]config.ProviderEndpoint, len(cfg.ProviderEndpoints))
+	for _, endpoint := range cfg.ProviderEndpoints {
+		endpoints[endpoint.Name] = endpoint
+	}
+
+	return endpoints
+}
+
+// getGenericProviders creates a map with the providers declared in the config
+// by name
+func getGenericProviders(cfg config.Config) map[string]config.GenericProvider {
+	genericProviders := make(map[string]config.GenericProvider, len(cfg.GenericProviders))
+	for _, genericProvider := range cfg.GenericProviders {
+		genericProviders[genericProvider.Name] = genericProvider
+	}
+
+	return genericProviders
+}
+
+// getPlugins creates a map with the providers served by plugins by name
+func getPlugins(cfg config.Config) map[string]config.Plugin {
+	plugins := make(map[string]config.Plugin, len(cfg.Plugins))
+	for _, plugin := range cfg.Plugins {
+		plugins[plugin.Name] = plugin
+	}
+
+	return plugins
+}
+
+// getDexPools creates a map with the pools of the DEX providers by provider
+func getDexPools(cfg config.Config) map[string][]config.DexPool {
+	dexPools := make(map[string][]config.DexPool)
+	for _, pool := range cfg.DexPools {
+		dexPools[pool.Provider] = append(dexPools[pool.Provider], pool)
+	}
+
+	return dexPools
+}
+
+// getFXProviders creates a map with the sources of the fiat rates by name
+func getFXProviders(cfg config.Config) map[string]config.FXProvider {
+	fxProviders := make(map[string]config.FXProvider, len(cfg.FXProviders))
+	for _, fxProvider := range cfg.FXProviders {
+		fxProviders[fxProvider.Name] = fxProvider
+	}
+
+	return fxProviders
+}
+
+// getLogger creates the logger with the level and format set by the cmd flags
+func getLogger(cmd *cobra.Command) (zerolog.Logger, error) {
+	// get value from the log level cmd flag
+	logLvlStr, err := cmd.Flags().GetString(FlagLogLevel)
+	if err != nil {
+		return zerolog.Logger{}, err
+	}
+
+	// get value from the log format cmd flag
+	logFormatStr, err := cmd.Flags().GetString(FlagLogFormat)
+	if err != nil {
+		return zerolog.Logger{}, err
+	}
+
+	logLvl, err := zerolog.ParseLevel(logLvlStr)
+	if err != nil {
+		return zerolog.Logger{}, err
+	}
+
+	// set the log format based on the flags
+	var logWriter io.Writer
+	switch strings.ToLower(logFormatStr) {
+
+	case LogLevelJSON:
+		logWriter = os.Stderr
+
+	case LogLevelTest:
+		logWriter = zerolog.ConsoleWriter{Out: os.Stderr}
+
+	default:
+		return zerolog.Logger{}, fmt.Errorf("invalid logging format: %s", logFormatStr)
+	}
+
+	// create logger
+	return zerolog.New(logWriter).Level(logLvl).With().Timestamp().Logger(), nil
+}
+
 // getKeyringPassword obtains the keyring password from the env var or stdin
 func getKeyringPassword(skipPassword bool) (string, error) {
 	pass := os.Getenv(envVariablePass)
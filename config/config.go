@@ -3,7 +3,9 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
@@ -18,6 +20,13 @@ const (
 
 	defaultProviderTimeout = 100 * time.Millisecond
 
+	// MinimumProviders is the minimum amount of providers required by base
+	MinimumProviders = 3
+
+	// MaxConversionHops is the longest path of pairs converting a quote to
+	// USD, ex. X→ETH→USDT→USD
+	MaxConversionHops = 3
+
 	// API sources for oracle price feed - examples include price of BTC, ETH
 	ProviderKraken   = "kraken"
 	ProviderBinance  = "binance"
@@ -27,7 +36,36 @@ const (
 	ProviderOkx      = "okx"
 	ProviderGate     = "gate"
 	ProviderCoinbase = "coinbase"
+	ProviderBybit    = "bybit"
+	ProviderKucoin   = "kucoin"
+	ProviderBitstamp = "bitstamp"
+	ProviderGemini   = "gemini"
+	ProviderOsmosis  = "osmosis"
+	ProviderUniswap  = "uniswapv3"
 	ProviderMock     = "mock"
+
+	// the types of the providers declared in the config
+	GenericProviderTypeREST = "generic_rest"
+	GenericProviderTypeWS   = "generic_ws"
+
+	// the methods combining the prices of the providers of an asset
+	AggregationVWAP           = "vwap"
+	AggregationTVWAP          = "tvwap"
+	AggregationWeightedMedian = "weighted_median"
+	AggregationMedian         = "median"
+
+	// the filters of the providers deviating from the others
+	OutlierFilterStdDev = "stddev"
+	OutlierFilterMAD    = "mad"
+
+	defaultGenericPollInterval = 5 * time.Second
+	defaultGenericPingDuration = 20 * time.Second
+	defaultPluginTimeout       = 5 * time.Second
+	defaultDexTwapWindow       = 5 * time.Minute
+	defaultFXPollInterval      = 10 * time.Minute
+	defaultFXRates             = "rates"
+	defaultMaxCandleAge        = 5 * time.Minute
+	defaultMaxClockSkew        = 10 * time.Second
 )
 
 var (
@@ -44,13 +82,44 @@ var (
 		ProviderHuobi:    {},
 		ProviderGate:     {},
 		ProviderCoinbase: {},
+		ProviderBybit:    {},
+		ProviderKucoin:   {},
+		ProviderBitstamp: {},
+		ProviderGemini:   {},
+		ProviderOsmosis:  {},
+		ProviderUniswap:  {},
 		ProviderMock:     {},
 	}
 
+	// SupportedAggregations is a lookup table of the aggregation methods
+	SupportedAggregations = map[string]struct{}{
+		AggregationVWAP:           {},
+		AggregationTVWAP:          {},
+		AggregationWeightedMedian: {},
+		AggregationMedian:         {},
+	}
+
+	// SupportedOutlierFilters is a lookup table of the outlier filters
+	SupportedOutlierFilters = map[string]struct{}{
+		OutlierFilterStdDev: {},
+		OutlierFilterMAD:    {},
+	}
+
+	// DexProviders are the providers quoting the pairs from the pools of the
+	// config
+	DexProviders = map[string]struct{}{
+		ProviderOsmosis: {},
+		ProviderUniswap: {},
+	}
+
 	// maxDeviationThreshold is the maxmimum allowed amount of standard
 	// deviations which validators are able to set for a given asset.
 	maxDeviationThreshold = math.LegacyMustNewDecFromStr("3.0")
 
+	// defaultOrderBookDepthBand is the band around the mid price where the
+	// depth of the order books is summed, ±2%.
+	defaultOrderBookDepthBand = math.LegacyMustNewDecFromStr("0.02")
+
 	// SupportedQuotes defines a lookup table for which assets we support
 	// using as quotes.
 	SupportedQuotes = map[string]struct{}{
@@ -63,6 +132,27 @@ var (
 		"ETH":     {},
 		"ATOM":    {},
 	}
+
+	// SupportedFiatQuotes defines a lookup table for the fiat currencies we
+	// support using as quotes, converted to USD by the FX providers.
+	SupportedFiatQuotes = map[string]struct{}{
+		"EUR": {},
+		"GBP": {},
+		"JPY": {},
+		"CHF": {},
+		"CAD": {},
+		"AUD": {},
+		"NZD": {},
+		"BRL": {},
+		"MXN": {},
+		"KRW": {},
+		"CNY": {},
+		"HKD": {},
+		"SGD": {},
+		"INR": {},
+		"TRY": {},
+		"ZAR": {},
+	}
 )
 
 type (
@@ -72,13 +162,21 @@ type (
 		Server            Server             `toml:"server" validate:"required,gt=0,dive,required"`
 		CurrencyPairs     []CurrencyPair     `toml:"currency_pairs" validate:"required,gt=0,dive,required"`
 		Deviations        []Deviation        `toml:"deviation_thresholds"`
+		OrderBooks        []OrderBook        `toml:"order_books" validate:"dive"`
+		CircuitBreakers   []CircuitBreaker   `toml:"circuit_breakers" validate:"dive"`
 		Account           Account            `toml:"account" validate:"required,gt=0,dive,required"`
 		Keyring           Keyring            `toml:"keyring" validate:"required,gt=0,dive,required"`
 		RPC               RPC                `toml:"rpc" validate:"required,gt=0,dive,required"`
 		Telemetry         Telemetry          `toml:"telemetry"`
 		Gas               Gas                `toml:"gas" validate:"required,gt=0,dive,required"`
 		ProviderTimeout   string             `toml:"provider_timeout"`
+		MaxCandleAge      string             `toml:"max_candle_age"`
+		MaxClockSkew      string             `toml:"max_clock_skew"`
 		ProviderEndpoints []ProviderEndpoint `toml:"provider_endpoints" validate:"dive"`
+		GenericProviders  []GenericProvider  `toml:"generic_providers" validate:"dive"`
+		Plugins           []Plugin           `toml:"plugins" validate:"dive"`
+		DexPools          []DexPool          `toml:"dex_pools" validate:"dive"`
+		FXProviders       []FXProvider       `toml:"fx_providers" validate:"dive"`
 		Healthchecks      []Healthchecks     `toml:"healthchecks" validate:"dive"`
 	}
 
@@ -88,6 +186,12 @@ type (
 		EnableServer bool `toml:"enable_server" validate:"required"`
 		// EnableVoting indicates whether the price-feeder should vote on prices
 		EnableVoting bool `toml:"enable_voting" validate:"required"`
+		// ShadowMode indicates whether the votes should only be computed and
+		// recorded every vote period, without being broadcasted to the chain
+		ShadowMode bool `toml:"shadow_mode"`
+		// JournalPath is the file where every attempted vote is appended,
+		// the journal is disabled when empty
+		JournalPath string `toml:"journal_path"`
 	}
 
 	// Server defines the server configuration parameters for the price-feeder
@@ -124,6 +228,18 @@ type (
 		ChainDenom string   `toml:"chain_denom" validate:"required"`
 		Quote      string   `toml:"quote" validate:"required"`
 		Providers  []string `toml:"providers" validate:"required,gt=0,dive,required"`
+
+		// Aggregation is the method combining the prices of the providers of
+		// the base, tvwap of the candles by default
+		Aggregation string `toml:"aggregation"`
+
+		// OutlierFilter is the filter of the providers deviating from the
+		// others, stddev around the mean by default or mad around the median
+		OutlierFilter string `toml:"outlier_filter"`
+
+		// MaxCandleAge is the maximum age of the newest candle of a provider
+		// for the base, the max_candle_age of the config by default
+		MaxCandleAge string `toml:"max_candle_age"`
 	}
 
 	// Deviation defines a maximum amount of standard deviations that a given asset can
@@ -133,6 +249,38 @@ type (
 		Threshold string `toml:"threshold" validate:"required"`
 	}
 
+	// OrderBook defines how the order books of the providers are used for a
+	// given asset.
+	OrderBook struct {
+		Base string `toml:"base" validate:"required"`
+
+		// DepthBand is the band around the mid price where the depth is summed,
+		// ex. "0.02" for ±2%
+		DepthBand string `toml:"depth_band"`
+
+		// MaxSpread excludes the providers with a wider spread relative to the
+		// mid price, ex. "0.005", no limit when empty
+		MaxSpread string `toml:"max_spread"`
+
+		// UseMidPrice computes the price from the depth weighted mid prices of
+		// the order books instead of the trades
+		UseMidPrice bool `toml:"use_mid_price"`
+	}
+
+	// CircuitBreaker defines the largest move of the price of an asset between
+	// vote periods before it is held back from the vote.
+	CircuitBreaker struct {
+		Base string `toml:"base" validate:"required"`
+
+		// MaxChange is the largest change relative to the last voted rate, or
+		// the on-chain rate, ex. "0.1" for ±10%
+		MaxChange string `toml:"max_change" validate:"required"`
+
+		// Confirmations is the amount of providers, above the minimum, that
+		// must agree on a larger move to vote it, held back when zero
+		Confirmations int `toml:"confirmations"`
+	}
+
 	// Account defines account related configuration that is related to the
 	// network and transaction signing functionality.
 	Account struct {
@@ -198,6 +346,181 @@ type (
 
 		// Websocket endpoint for the provider, ex. "stream.binance.com:9443"
 		Websocket string `toml:"websocket"`
+
+		// BusinessWebsocket endpoint for the providers streaming the candles on
+		// a separate websocket, ex. "ws.okx.com:8443"
+		BusinessWebsocket string `toml:"business_websocket"`
+
+		// GRPC endpoint for the DEX providers, ex. "grpc.osmosis.zone:9090"
+		GRPC string `toml:"grpc"`
+
+		// JSONRPC endpoint for the EVM DEX providers, ex. "http://localhost:8545"
+		JSONRPC string `toml:"json_rpc"`
+	}
+
+	// DexPool defines the pool quoting a pair on a DEX provider.
+	DexPool struct {
+		// Provider of the pool, ex. "osmosis"
+		Provider string `toml:"provider" validate:"required,oneof=osmosis uniswapv3"`
+
+		// Base and Quote of the pair, ex. "OSMO" and "USDC"
+		Base  string `toml:"base" validate:"required"`
+		Quote string `toml:"quote" validate:"required"`
+
+		// PoolID is the id of the pool on the chain, ex. 1464
+		PoolID uint64 `toml:"pool_id" validate:"required_if=Provider osmosis"`
+
+		// Address is the contract of the pool on the EVM DEX providers
+		Address string `toml:"address" validate:"required_if=Provider uniswapv3,omitempty,eth_addr"`
+
+		// BaseDenom and QuoteDenom are the denoms of the pair on the chain, or
+		// the token contracts on the EVM DEX providers
+		BaseDenom  string `toml:"base_denom" validate:"required"`
+		QuoteDenom string `toml:"quote_denom" validate:"required"`
+
+		// BaseExponent and QuoteExponent are the decimals of the denoms, ex. 6
+		// for uosmo, the amounts are used as is when not set
+		BaseExponent  uint64 `toml:"base_exponent" validate:"lte=18"`
+		QuoteExponent uint64 `toml:"quote_exponent" validate:"lte=18"`
+
+		// TwapWindow is the period of the time weighted average price on the
+		// EVM DEX providers, ex. "5m"
+		TwapWindow string `toml:"twap_window"`
+
+		// AllowSpotPrice uses the spot price of the pool on the EVM DEX
+		// providers when its TWAP can't be read, the sample being skipped
+		// otherwise
+		AllowSpotPrice bool `toml:"allow_spot_price"`
+	}
+
+	// GenericProvider defines a provider declared in the config, for the
+	// sources without a dedicated provider. The values are extracted from the
+	// responses with gjson selectors, ex. "data.0.last".
+	GenericProvider struct {
+		// Name of the provider used in the currency pairs, ex. "myvenue"
+		Name string `toml:"name" validate:"required"`
+
+		// Type of the provider, "generic_rest" or "generic_ws"
+		Type string `toml:"type" validate:"required"`
+
+		// URL template requested for each pair, with the {base}, {quote} and
+		// {symbol} placeholders, ex. "https://api.myvenue.com/ticker/{symbol}",
+		// or the websocket URL, ex. "wss://ws.myvenue.com/v1"
+		URL string `toml:"url" validate:"required"`
+
+		// Headers added to every request, ex. an API key
+		Headers map[string]string `toml:"headers"`
+
+		// PollInterval is the interval between the requests of a pair, ex. "5s"
+		PollInterval string `toml:"poll_interval"`
+
+		// Price is the selector of the last price
+		Price string `toml:"price" validate:"required"`
+
+		// Volume is the selector of the 24h volume, a volume of zero is used
+		// when empty so the source is left out of the volume weighted prices
+		Volume string `toml:"volume"`
+
+		// Timestamp is the selector of the price time, the time of the request
+		// is used when empty
+		Timestamp string `toml:"timestamp"`
+
+		// TimestampUnit is the unit of numeric timestamps, "ms" or "s"
+		TimestampUnit string `toml:"timestamp_unit" validate:"omitempty,oneof=ms s"`
+
+		// SymbolSeparator is placed between the base and quote of the symbol
+		SymbolSeparator string `toml:"symbol_separator"`
+
+		// SymbolCase is the case of the symbol, "upper", "lower" or as configured
+		SymbolCase string `toml:"symbol_case" validate:"omitempty,oneof=upper lower"`
+
+		// SymbolAliases renames the base or quote on the source, ex. BTC = "XBT"
+		SymbolAliases map[string]string `toml:"symbol_aliases"`
+
+		// PairsURL is requested to validate the available pairs, the configured
+		// pairs are assumed available when empty
+		PairsURL string `toml:"pairs_url"`
+
+		// Pairs is the selector of the list of available symbols, ex. "data.#.symbol"
+		Pairs string `toml:"pairs"`
+
+		// Subscriptions are the messages sent on connect by a websocket
+		// provider. A message with the {symbols} placeholder is sent once with
+		// the JSON list of symbols, other messages are sent once per pair.
+		Subscriptions []string `toml:"subscriptions"`
+
+		// PingDuration is the interval between the pings, "0s" disables them
+		PingDuration string `toml:"ping_duration"`
+
+		// PingMessageType is the frame of the pings, "ping" or "text"
+		PingMessageType string `toml:"ping_message_type" validate:"omitempty,oneof=ping text"`
+
+		// PingMessage is the payload of the pings, ex. {"op":"ping"}
+		PingMessage string `toml:"ping_message"`
+
+		// Match are the selectors and the values a websocket message must have
+		// to be read as a price, ex. { "arg.channel" = "tickers" }
+		Match map[string]string `toml:"match"`
+
+		// Symbol is the selector of the symbol of a websocket message
+		Symbol string `toml:"symbol"`
+	}
+
+	// Plugin defines a provider served by an external binary, launched by the
+	// price feeder and speaking the plugin protocol on its stdin and stdout.
+	Plugin struct {
+		// Name of the provider used in the currency pairs, ex. "otcdesk"
+		Name string `toml:"name" validate:"required"`
+
+		// Command is the path of the plugin binary
+		Command string `toml:"command" validate:"required"`
+
+		// Args are the arguments of the plugin binary
+		Args []string `toml:"args"`
+
+		// Env are the variables added to the environment of the plugin
+		Env map[string]string `toml:"env"`
+
+		// Timeout is the maximum duration of a request to the plugin, ex. "5s"
+		Timeout string `toml:"timeout"`
+	}
+
+	// FXProvider defines a REST source of fiat rates, converting the fiat
+	// quotes to USD. Its name can also be used by the pairs of its currencies
+	// quoted in USD, to publish fiat denoms.
+	FXProvider struct {
+		// Name of the provider, used by the currency pairs
+		Name string `toml:"name" validate:"required"`
+
+		// URL requested for the rates, with the {currencies} and {api_key}
+		// placeholders, ex. "https://api.myfx.com/latest?base=USD&symbols={currencies}"
+		URL string `toml:"url" validate:"required,url"`
+
+		// APIKey of the source, replacing the {api_key} placeholder
+		APIKey string `toml:"api_key"`
+
+		// APIKeyHeader sends the api key in the header with this name
+		APIKeyHeader string `toml:"api_key_header"`
+
+		// Rates is the selector of the rates by currency, ex. "rates"
+		Rates string `toml:"rates"`
+
+		// Inverse is set when the rates are USD per unit of the currency,
+		// rather than units of the currency per USD
+		Inverse bool `toml:"inverse"`
+
+		// Timestamp is the selector of the time of the rates, the time of the
+		// request is used when empty
+		Timestamp string `toml:"timestamp"`
+
+		// TimestampUnit is the unit of numeric timestamps, "ms" or "s"
+		TimestampUnit string `toml:"timestamp_unit" validate:"omitempty,oneof=ms s"`
+
+		// Currencies are the fiat currencies requested from the source
+		Currencies []string `toml:"currencies" validate:"required,gt=0"`
+
+		// PollInterval is the interval between the requests, ex. "10m"
+		PollInterval string `toml:"poll_interval"`
 	}
 
 	Healthchecks struct {
@@ -220,9 +543,21 @@ func endpointValidation(sl validator.StructLevel) {
 	// validate the data type
 	endpoint := sl.Current().Interface().(ProviderEndpoint)
 
-	// must have at least one endpoint data
-	if len(endpoint.Name) < 1 || len(endpoint.Rest) < 1 || len(endpoint.Websocket) < 1 {
-		sl.ReportError(endpoint, "endpoint", "Endpoint", "unsupportedEndpointType", "")
+	// must have at least one endpoint data, the DEX providers are only queried
+	// over gRPC or JSON-RPC
+	switch endpoint.Name {
+	case ProviderOsmosis:
+		if len(endpoint.GRPC) < 1 {
+			sl.ReportError(endpoint, "endpoint", "Endpoint", "unsupportedEndpointType", "")
+		}
+	case ProviderUniswap:
+		if len(endpoint.JSONRPC) < 1 {
+			sl.ReportError(endpoint, "endpoint", "Endpoint", "unsupportedEndpointType", "")
+		}
+	default:
+		if len(endpoint.Name) < 1 || len(endpoint.Rest) < 1 || len(endpoint.Websocket) < 1 {
+			sl.ReportError(endpoint, "endpoint", "Endpoint", "unsupportedEndpointType", "")
+		}
 	}
 
 	// provider listed must be soported
@@ -266,6 +601,30 @@ func ParseConfig(configPath string) (Config, error) {
 		cfg.ProviderTimeout = defaultProviderTimeout.String()
 	}
 
+	// validate the providers declared in the config
+	genericProviders, err := validateGenericProviders(cfg.GenericProviders)
+	if err != nil {
+		return cfg, err
+	}
+
+	// validate the providers served by plugins
+	plugins, err := validatePlugins(cfg.Plugins, genericProviders)
+	if err != nil {
+		return cfg, err
+	}
+
+	// validate the pools of the DEX providers
+	dexPools, err := validateDexPools(cfg.DexPools)
+	if err != nil {
+		return cfg, err
+	}
+
+	// validate the sources of the fiat rates
+	fxProviders, err := validateFXProviders(cfg.FXProviders, genericProviders, plugins)
+	if err != nil {
+		return cfg, err
+	}
+
 	pairs := make(map[string]map[string]struct{})
 	coinQuotes := make(map[string]struct{})
 
@@ -278,25 +637,47 @@ func ParseConfig(configPath string) (Config, error) {
 			pairs[currencyPair.Base] = make(map[string]struct{})
 		}
 
-		// save the quote who are not USD (I must convert then on usd)
-		if strings.ToUpper(currencyPair.Quote) != DenomUSD {
-			coinQuotes[currencyPair.Quote] = struct{}{}
-		}
-
 		// validate if the selected quote is supported
-		_, ok = SupportedQuotes[strings.ToUpper(currencyPair.Quote)]
-		if !ok {
+		quote := strings.ToUpper(currencyPair.Quote)
+		_, ok = SupportedQuotes[quote]
+		_, isFiat := SupportedFiatQuotes[quote]
+		if !ok && !isFiat {
 			return cfg, fmt.Errorf("unsupported quote: %s", currencyPair.Quote)
 		}
 
+		// save the quote who are not USD (I must convert then on usd), the
+		// fiat quotes are converted with the FX providers
+		if isFiat {
+			if !hasFXProvider(fxProviders, quote) {
+				return cfg, fmt.Errorf("no fx provider for fiat quote %s", currencyPair.Quote)
+			}
+		} else if quote != DenomUSD {
+			coinQuotes[currencyPair.Quote] = struct{}{}
+		}
+
 		// iterate over the providers by currency
 		for _, provider := range currencyPair.Providers {
-			// validate the provider is supported
+			// validate the provider is supported, declared in the config or a plugin
 			_, ok = SupportedProviders[provider]
-			if !ok {
+			_, isGeneric := genericProviders[provider]
+			_, isPlugin := plugins[provider]
+			fxCurrencies, isFX := fxProviders[provider]
+			if !ok && !isGeneric && !isPlugin && !isFX {
 				return cfg, fmt.Errorf("unsupported provider: %s", provider)
 			}
 
+			// the FX providers quote their currencies in USD
+			if _, ok := fxCurrencies[strings.ToUpper(currencyPair.Base)]; isFX && (!ok || quote != DenomUSD) {
+				return cfg, fmt.Errorf("fx provider %s only quotes its currencies in USD", provider)
+			}
+
+			// the DEX providers quote the pairs from their pools
+			if _, isDex := DexProviders[provider]; isDex {
+				if _, ok := dexPools[provider+currencyPair.Base+currencyPair.Quote]; !ok {
+					return cfg, fmt.Errorf("no %s pool for %s/%s", provider, currencyPair.Base, currencyPair.Quote)
+				}
+			}
+
 			// save the providers by base denom
 			pairs[currencyPair.Base][provider] = struct{}{}
 		}
@@ -304,26 +685,14 @@ func ParseConfig(configPath string) (Config, error) {
 
 	// Use coinQuotes to ensure that any quotes can be converted to USD.
 	for quote := range coinQuotes {
-		for index, pair := range cfg.CurrencyPairs {
-
-			// validate I have the way to convert the quote to USD
-			if pair.Base == quote && pair.Quote == DenomUSD {
-				break
-			}
-
-			if index == len(cfg.CurrencyPairs)-1 {
-				return cfg, fmt.Errorf("all non-usd quotes require a conversion rate feed")
-			}
+		if !hasConversionPath(quote, cfg.CurrencyPairs) {
+			return cfg, fmt.Errorf("no conversion path from %s to USD within %d pairs", quote, MaxConversionHops)
 		}
 	}
 
-	// iterate over the pairs denom, check the minimum provider amount
-	for base, providers := range pairs {
-		// validate if we are mocking the provider
-		_, ok := pairs[base]["mock"]
-		if !ok && len(providers) < 3 {
-			return cfg, fmt.Errorf("must have at least three providers for %s", base)
-		}
+	// check the minimum provider amount by pair denom
+	if bases := BasesBelowMinimumProviders(pairs); len(bases) > 0 {
+		return cfg, fmt.Errorf("must have at least three providers for %s", bases[0])
 	}
 
 	// iterate over the deviation and check if valid
@@ -340,5 +709,516 @@ func ParseConfig(configPath string) (Config, error) {
 		}
 	}
 
+	// validate the order book settings by base
+	if err := validateOrderBooks(cfg.OrderBooks); err != nil {
+		return cfg, err
+	}
+
+	// validate the circuit breakers by base
+	if err := validateCircuitBreakers(cfg.CircuitBreakers, cfg.CurrencyPairs); err != nil {
+		return cfg, err
+	}
+
+	// validate the aggregation of the prices by base
+	if err := validateAggregations(cfg.CurrencyPairs); err != nil {
+		return cfg, err
+	}
+
+	// validate the freshness of the candles
+	if err := validateCandleAges(cfg); err != nil {
+		return cfg, err
+	}
+
 	return cfg, cfg.Validate()
 }
+
+// hasConversionPath returns true if the quote converts to USD through at most
+// MaxConversionHops pairs, the fiat quotes being converted by the FX providers.
+func hasConversionPath(quote string, currencyPairs []CurrencyPair) bool {
+	denoms := map[string]struct{}{strings.ToUpper(quote): {}}
+	for hop := 0; hop < MaxConversionHops; hop++ {
+		next := make(map[string]struct{})
+		for _, pair := range currencyPairs {
+			if _, ok := denoms[strings.ToUpper(pair.Base)]; !ok {
+				continue
+			}
+
+			pairQuote := strings.ToUpper(pair.Quote)
+			if _, isFiat := SupportedFiatQuotes[pairQuote]; isFiat || pairQuote == DenomUSD {
+				return true
+			}
+			next[pairQuote] = struct{}{}
+		}
+		denoms = next
+	}
+
+	return false
+}
+
+// validateAggregations checks the aggregation methods and outlier filters of
+// the pairs, the pairs of a base can't set different ones.
+func validateAggregations(currencyPairs []CurrencyPair) error {
+	aggregations := make(map[string]string)
+	outlierFilters := make(map[string]string)
+	for _, pair := range currencyPairs {
+		if len(pair.Aggregation) > 0 {
+			if _, ok := SupportedAggregations[pair.Aggregation]; !ok {
+				return fmt.Errorf("unsupported aggregation of %s: %s", pair.Base, pair.Aggregation)
+			}
+			if aggregation, ok := aggregations[pair.Base]; ok && aggregation != pair.Aggregation {
+				return fmt.Errorf("conflicting aggregations of %s: %s and %s", pair.Base, aggregation, pair.Aggregation)
+			}
+			aggregations[pair.Base] = pair.Aggregation
+		}
+
+		if len(pair.OutlierFilter) > 0 {
+			if _, ok := SupportedOutlierFilters[pair.OutlierFilter]; !ok {
+				return fmt.Errorf("unsupported outlier filter of %s: %s", pair.Base, pair.OutlierFilter)
+			}
+			if outlierFilter, ok := outlierFilters[pair.Base]; ok && outlierFilter != pair.OutlierFilter {
+				return fmt.Errorf("conflicting outlier filters of %s: %s and %s", pair.Base, outlierFilter, pair.OutlierFilter)
+			}
+			outlierFilters[pair.Base] = pair.OutlierFilter
+		}
+	}
+
+	return nil
+}
+
+// validateCandleAges checks the maximum candle ages and clock skew, the pairs
+// of a base can't set different ages.
+func validateCandleAges(cfg Config) error {
+	if _, err := cfg.GetMaxCandleAge(); err != nil {
+		return fmt.Errorf("invalid max candle age: %w", err)
+	}
+	if _, err := cfg.GetMaxClockSkew(); err != nil {
+		return fmt.Errorf("invalid max clock skew: %w", err)
+	}
+
+	maxAges := make(map[string]string)
+	for _, pair := range cfg.CurrencyPairs {
+		if len(pair.MaxCandleAge) == 0 {
+			continue
+		}
+		if _, err := pair.GetMaxCandleAge(); err != nil {
+			return fmt.Errorf("invalid max candle age of %s: %w", pair.Base, err)
+		}
+		if maxAge, ok := maxAges[pair.Base]; ok && maxAge != pair.MaxCandleAge {
+			return fmt.Errorf("conflicting max candle ages of %s: %s and %s", pair.Base, maxAge, pair.MaxCandleAge)
+		}
+		maxAges[pair.Base] = pair.MaxCandleAge
+	}
+
+	return nil
+}
+
+// validateOrderBooks checks the order book settings, one for each base.
+func validateOrderBooks(orderBooks []OrderBook) error {
+	bases := make(map[string]struct{}, len(orderBooks))
+	for _, orderBook := range orderBooks {
+		if _, ok := bases[orderBook.Base]; ok {
+			return fmt.Errorf("duplicated order book settings: %s", orderBook.Base)
+		}
+		bases[orderBook.Base] = struct{}{}
+
+		if _, err := orderBook.GetDepthBand(); err != nil {
+			return fmt.Errorf("invalid depth band of order book %s: %w", orderBook.Base, err)
+		}
+		if _, err := orderBook.GetMaxSpread(); err != nil {
+			return fmt.Errorf("invalid max spread of order book %s: %w", orderBook.Base, err)
+		}
+	}
+
+	return nil
+}
+
+// GetDepthBand returns the band around the mid price where the depth of the
+// order books is summed, or the default band when not set.
+func (ob OrderBook) GetDepthBand() (math.LegacyDec, error) {
+	if len(ob.DepthBand) == 0 {
+		return defaultOrderBookDepthBand, nil
+	}
+
+	return parseOrderBookRatio(ob.DepthBand)
+}
+
+// GetMaxSpread returns the widest spread of the order books, or zero when
+// there is no limit.
+func (ob OrderBook) GetMaxSpread() (math.LegacyDec, error) {
+	if len(ob.MaxSpread) == 0 {
+		return math.LegacyZeroDec(), nil
+	}
+
+	return parseOrderBookRatio(ob.MaxSpread)
+}
+
+// parseOrderBookRatio parses a ratio of the mid price, between 0 and 1.
+func parseOrderBookRatio(value string) (math.LegacyDec, error) {
+	ratio, err := math.LegacyNewDecFromStr(value)
+	if err != nil {
+		return math.LegacyDec{}, err
+	}
+	if !ratio.IsPositive() || ratio.GTE(math.LegacyOneDec()) {
+		return math.LegacyDec{}, fmt.Errorf("expected a ratio between 0 and 1, got %s", value)
+	}
+
+	return ratio, nil
+}
+
+// validateCircuitBreakers checks the circuit breakers, one for each base of
+// the currency pairs.
+func validateCircuitBreakers(circuitBreakers []CircuitBreaker, currencyPairs []CurrencyPair) error {
+	pairBases := make(map[string]struct{}, len(currencyPairs))
+	for _, pair := range currencyPairs {
+		pairBases[pair.Base] = struct{}{}
+	}
+
+	bases := make(map[string]struct{}, len(circuitBreakers))
+	for _, circuitBreaker := range circuitBreakers {
+		if _, ok := bases[circuitBreaker.Base]; ok {
+			return fmt.Errorf("duplicated circuit breaker: %s", circuitBreaker.Base)
+		}
+		bases[circuitBreaker.Base] = struct{}{}
+
+		if _, ok := pairBases[circuitBreaker.Base]; !ok {
+			return fmt.Errorf("circuit breaker %s has no currency pair", circuitBreaker.Base)
+		}
+
+		if _, err := circuitBreaker.GetMaxChange(); err != nil {
+			return fmt.Errorf("invalid max change of circuit breaker %s: %w", circuitBreaker.Base, err)
+		}
+		if circuitBreaker.Confirmations < 0 {
+			return fmt.Errorf("invalid confirmations of circuit breaker %s: must not be negative", circuitBreaker.Base)
+		}
+	}
+
+	return nil
+}
+
+// GetMaxChange returns the largest change of the price relative to the
+// reference rate.
+func (cb CircuitBreaker) GetMaxChange() (math.LegacyDec, error) {
+	maxChange, err := math.LegacyNewDecFromStr(cb.MaxChange)
+	if err != nil {
+		return math.LegacyDec{}, err
+	}
+	if !maxChange.IsPositive() {
+		return math.LegacyDec{}, fmt.Errorf("expected a positive ratio, got %s", cb.MaxChange)
+	}
+
+	return maxChange, nil
+}
+
+// validateGenericProviders checks the providers declared in the config and
+// returns their names.
+func validateGenericProviders(genericProviders []GenericProvider) (map[string]struct{}, error) {
+	names := make(map[string]struct{}, len(genericProviders))
+	for _, genericProvider := range genericProviders {
+		// the name must not shadow another provider
+		if _, ok := SupportedProviders[genericProvider.Name]; ok {
+			return nil, fmt.Errorf("generic provider %s conflicts with a supported provider", genericProvider.Name)
+		}
+		if _, ok := names[genericProvider.Name]; ok {
+			return nil, fmt.Errorf("duplicated generic provider: %s", genericProvider.Name)
+		}
+
+		switch genericProvider.Type {
+		case GenericProviderTypeREST:
+			if _, err := genericProvider.GetPollInterval(); err != nil {
+				return nil, fmt.Errorf("invalid poll interval for generic provider %s: %w", genericProvider.Name, err)
+			}
+
+		case GenericProviderTypeWS:
+			wsURL, err := url.Parse(genericProvider.URL)
+			if err != nil || (wsURL.Scheme != "ws" && wsURL.Scheme != "wss") {
+				return nil, fmt.Errorf("generic provider %s requires a ws or wss url", genericProvider.Name)
+			}
+			if _, err := genericProvider.GetPingDuration(); err != nil {
+				return nil, fmt.Errorf("invalid ping duration for generic provider %s: %w", genericProvider.Name, err)
+			}
+			if len(genericProvider.Symbol) == 0 {
+				return nil, fmt.Errorf("generic provider %s requires a symbol selector", genericProvider.Name)
+			}
+
+		default:
+			return nil, fmt.Errorf("unsupported generic provider type: %s", genericProvider.Type)
+		}
+
+		if len(genericProvider.PairsURL) > 0 && len(genericProvider.Pairs) == 0 {
+			return nil, fmt.Errorf("generic provider %s requires a pairs selector with the pairs url", genericProvider.Name)
+		}
+
+		names[genericProvider.Name] = struct{}{}
+	}
+
+	return names, nil
+}
+
+// validateFXProviders checks the sources of the fiat rates and returns their
+// currencies by name.
+func validateFXProviders(
+	fxProviders []FXProvider,
+	genericProviders map[string]struct{},
+	plugins map[string]struct{},
+) (map[string]map[string]struct{}, error) {
+	currenciesByName := make(map[string]map[string]struct{}, len(fxProviders))
+	for _, fxProvider := range fxProviders {
+		// the name must not shadow another provider
+		_, isSupported := SupportedProviders[fxProvider.Name]
+		_, isGeneric := genericProviders[fxProvider.Name]
+		_, isPlugin := plugins[fxProvider.Name]
+		if isSupported || isGeneric || isPlugin {
+			return nil, fmt.Errorf("fx provider %s conflicts with another provider", fxProvider.Name)
+		}
+		if _, ok := currenciesByName[fxProvider.Name]; ok {
+			return nil, fmt.Errorf("duplicated fx provider: %s", fxProvider.Name)
+		}
+
+		if _, err := fxProvider.GetPollInterval(); err != nil {
+			return nil, fmt.Errorf("invalid poll interval for fx provider %s: %w", fxProvider.Name, err)
+		}
+
+		currencies := make(map[string]struct{}, len(fxProvider.Currencies))
+		for _, currency := range fxProvider.Currencies {
+			if _, ok := SupportedFiatQuotes[strings.ToUpper(currency)]; !ok {
+				return nil, fmt.Errorf("unsupported fiat currency of fx provider %s: %s", fxProvider.Name, currency)
+			}
+			currencies[strings.ToUpper(currency)] = struct{}{}
+		}
+
+		currenciesByName[fxProvider.Name] = currencies
+	}
+
+	return currenciesByName, nil
+}
+
+// hasFXProvider returns whether one of the FX providers has the currency.
+func hasFXProvider(fxProviders map[string]map[string]struct{}, currency string) bool {
+	for _, currencies := range fxProviders {
+		if _, ok := currencies[currency]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetPollInterval returns the poll interval of the FX provider, or the
+// default interval when not set
+func (p FXProvider) GetPollInterval() (time.Duration, error) {
+	if len(p.PollInterval) == 0 {
+		return defaultFXPollInterval, nil
+	}
+
+	pollInterval, err := time.ParseDuration(p.PollInterval)
+	if err != nil {
+		return 0, err
+	}
+	if pollInterval <= 0 {
+		return 0, fmt.Errorf("poll interval must be positive")
+	}
+
+	return pollInterval, nil
+}
+
+// GetRates returns the selector of the rates, or the default selector when
+// not set
+func (p FXProvider) GetRates() string {
+	if len(p.Rates) == 0 {
+		return defaultFXRates
+	}
+
+	return p.Rates
+}
+
+// validateDexPools checks the pools of the DEX providers and returns them
+// indexed by provider and pair.
+func validateDexPools(dexPools []DexPool) (map[string]struct{}, error) {
+	pools := make(map[string]struct{}, len(dexPools))
+	for _, pool := range dexPools {
+		key := pool.Provider + pool.Base + pool.Quote
+		if _, ok := pools[key]; ok {
+			return nil, fmt.Errorf("duplicated %s pool for %s/%s", pool.Provider, pool.Base, pool.Quote)
+		}
+
+		// the EVM pools are between two token contracts
+		if pool.Provider == ProviderUniswap {
+			if validate.Var(pool.BaseDenom, "eth_addr") != nil || validate.Var(pool.QuoteDenom, "eth_addr") != nil {
+				return nil, fmt.Errorf("%s pool for %s/%s requires token addresses as denoms", pool.Provider, pool.Base, pool.Quote)
+			}
+			if _, err := pool.GetTwapWindow(); err != nil {
+				return nil, fmt.Errorf("invalid twap window of %s pool for %s/%s: %w", pool.Provider, pool.Base, pool.Quote, err)
+			}
+		}
+
+		pools[key] = struct{}{}
+	}
+
+	return pools, nil
+}
+
+// GetTwapWindow returns the TWAP window of the pool, or the default window
+// when not set
+func (p DexPool) GetTwapWindow() (time.Duration, error) {
+	if len(p.TwapWindow) == 0 {
+		return defaultDexTwapWindow, nil
+	}
+
+	twapWindow, err := time.ParseDuration(p.TwapWindow)
+	if err != nil {
+		return 0, err
+	}
+	if twapWindow < time.Second {
+		return 0, fmt.Errorf("twap window must be at least one second")
+	}
+
+	return twapWindow, nil
+}
+
+// GetPollInterval returns the poll interval of the provider, or the default
+// interval when not set
+func (p GenericProvider) GetPollInterval() (time.Duration, error) {
+	if len(p.PollInterval) == 0 {
+		return defaultGenericPollInterval, nil
+	}
+
+	pollInterval, err := time.ParseDuration(p.PollInterval)
+	if err != nil {
+		return 0, err
+	}
+	if pollInterval <= 0 {
+		return 0, fmt.Errorf("poll interval must be positive")
+	}
+
+	return pollInterval, nil
+}
+
+// GetPingDuration returns the ping duration of the provider, or the default
+// duration when not set
+func (p GenericProvider) GetPingDuration() (time.Duration, error) {
+	if len(p.PingDuration) == 0 {
+		return defaultGenericPingDuration, nil
+	}
+
+	pingDuration, err := time.ParseDuration(p.PingDuration)
+	if err != nil {
+		return 0, err
+	}
+	if pingDuration < 0 {
+		return 0, fmt.Errorf("ping duration must not be negative")
+	}
+
+	return pingDuration, nil
+}
+
+// validatePlugins checks the providers served by plugins and returns their
+// names.
+func validatePlugins(plugins []Plugin, genericProviders map[string]struct{}) (map[string]struct{}, error) {
+	names := make(map[string]struct{}, len(plugins))
+	for _, plugin := range plugins {
+		// the name must not shadow another provider
+		if _, ok := SupportedProviders[plugin.Name]; ok {
+			return nil, fmt.Errorf("plugin %s conflicts with a supported provider", plugin.Name)
+		}
+		if _, ok := genericProviders[plugin.Name]; ok {
+			return nil, fmt.Errorf("plugin %s conflicts with a generic provider", plugin.Name)
+		}
+		if _, ok := names[plugin.Name]; ok {
+			return nil, fmt.Errorf("duplicated plugin: %s", plugin.Name)
+		}
+
+		if _, err := plugin.GetTimeout(); err != nil {
+			return nil, fmt.Errorf("invalid timeout for plugin %s: %w", plugin.Name, err)
+		}
+
+		names[plugin.Name] = struct{}{}
+	}
+
+	return names, nil
+}
+
+// GetMaxCandleAge returns the maximum age of the newest candle of a provider,
+// or the default age when not set
+func (c Config) GetMaxCandleAge() (time.Duration, error) {
+	if len(c.MaxCandleAge) == 0 {
+		return defaultMaxCandleAge, nil
+	}
+
+	return parseMaxCandleAge(c.MaxCandleAge)
+}
+
+// GetMaxClockSkew returns how far in the future the candles of a provider can
+// be, or the default skew when not set
+func (c Config) GetMaxClockSkew() (time.Duration, error) {
+	if len(c.MaxClockSkew) == 0 {
+		return defaultMaxClockSkew, nil
+	}
+
+	maxClockSkew, err := time.ParseDuration(c.MaxClockSkew)
+	if err != nil {
+		return 0, err
+	}
+	if maxClockSkew < 0 {
+		return 0, fmt.Errorf("max clock skew must not be negative")
+	}
+
+	return maxClockSkew, nil
+}
+
+// GetMaxCandleAge returns the maximum age of the newest candle of a provider
+// for the base of the pair, or zero when not set
+func (p CurrencyPair) GetMaxCandleAge() (time.Duration, error) {
+	if len(p.MaxCandleAge) == 0 {
+		return 0, nil
+	}
+
+	return parseMaxCandleAge(p.MaxCandleAge)
+}
+
+// parseMaxCandleAge parses a positive candle age
+func parseMaxCandleAge(value string) (time.Duration, error) {
+	maxAge, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if maxAge <= 0 {
+		return 0, fmt.Errorf("max candle age must be positive")
+	}
+
+	return maxAge, nil
+}
+
+// GetTimeout returns the request timeout of the plugin, or the default
+// timeout when not set
+func (p Plugin) GetTimeout() (time.Duration, error) {
+	if len(p.Timeout) == 0 {
+		return defaultPluginTimeout, nil
+	}
+
+	timeout, err := time.ParseDuration(p.Timeout)
+	if err != nil {
+		return 0, err
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("timeout must be positive")
+	}
+
+	return timeout, nil
+}
+
+// BasesBelowMinimumProviders returns the sorted bases with less than the
+// minimum amount of providers, given the providers by base. Bases using the
+// mock provider are not checked.
+func BasesBelowMinimumProviders(providersByBase map[string]map[string]struct{}) []string {
+	var bases []string
+	for base, providers := range providersByBase {
+		// validate if we are mocking the provider
+		_, ok := providers[ProviderMock]
+		if !ok && len(providers) < MinimumProviders {
+			bases = append(bases, base)
+		}
+	}
+	sort.Strings(bases)
+
+	return bases
+}
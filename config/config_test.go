@@ -4,9 +4,12 @@ import (
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
+	"cosmossdk.io/math"
+
 	"github.com/kiichain/price-feeder/config"
 )
 
@@ -356,13 +359,18 @@ providers = [
 	require.Error(t, err)
 }
 
-func TestParseConfig_NonUSDQuote(t *testing.T) {
-	tmpFile, err := ioutil.TempFile("", "price-feeder.toml")
-	require.NoError(t, err)
-	defer os.Remove(tmpFile.Name())
+func TestParseConfig_GenericProviders(t *testing.T) {
+	pairs := `
+[main]
+enable_voting = true
+enable_server = true
 
-	content := []byte(`
-listen_addr = ""
+[server]
+listen_addr = "0.0.0.0:7171"
+read_timeout = "20s"
+write_timeout = "20s"
+enable_cors = true
+allowed_origins = ["*"]
 
 [[currency_pairs]]
 base = "ATOM"
@@ -370,31 +378,214 @@ chain_denom = "uatom"
 quote = "USDT"
 providers = [
 	"kraken",
-	"binance"
+	"binance",
+	"myvenue"
 ]
 
 [[currency_pairs]]
-base = "UMEE"
-chain_denom = "uumee"
-quote = "USDT"
+base = "USDT"
+chain_denom = "uusdt"
+quote = "USD"
 providers = [
 	"kraken",
-	"binance"
+	"binance",
+	"huobi"
 ]
-`)
-	_, err = tmpFile.Write(content)
-	require.NoError(t, err)
 
-	_, err = config.ParseConfig(tmpFile.Name())
-	require.Error(t, err)
-}
+[account]
+address = "kii15nejfgcaanqpw25ru4arvfd0fwy6j8clccvwx4"
+validator = "kiivalcons14rjlkfzp56733j5l5nfk6fphjxymgf8mj04d5p"
+chain_id = "kii-local-testnet"
+prefix = "kii"
 
-func TestParseConfig_Valid_Deviations(t *testing.T) {
-	tmpFile, err := ioutil.TempFile("", "price-feeder.toml")
-	require.NoError(t, err)
-	defer os.Remove(tmpFile.Name())
+[keyring]
+backend = "test"
+dir = "/Users/username/.kiichain"
 
-	content := []byte(`
+[rpc]
+tmrpc_endpoint = "http://localhost:26657"
+grpc_endpoint = "localhost:9090"
+rpc_timeout = "100ms"
+
+[gas]
+gas_adjustment = 1.5
+gas_prices = "0.00125akii"
+gas_limit = 2000000
+
+[telemetry]
+enabled = false
+`
+
+	testCases := []struct {
+		name             string
+		genericProviders string
+		expectErr        bool
+	}{
+		{
+			name: "valid generic provider",
+			genericProviders: `
+[[generic_providers]]
+name = "myvenue"
+type = "generic_rest"
+url = "https://api.myvenue.com/ticker/{symbol}"
+poll_interval = "10s"
+price = "data.last"
+symbol_aliases = { ATOM = "ATOM2" }
+`,
+			expectErr: false,
+		},
+		{
+			name:             "undeclared generic provider",
+			genericProviders: "",
+			expectErr:        true,
+		},
+		{
+			name: "conflicting name",
+			genericProviders: `
+[[generic_providers]]
+name = "binance"
+type = "generic_rest"
+url = "https://api.myvenue.com/ticker/{symbol}"
+price = "data.last"
+`,
+			expectErr: true,
+		},
+		{
+			name: "duplicated name",
+			genericProviders: `
+[[generic_providers]]
+name = "myvenue"
+type = "generic_rest"
+url = "https://api.myvenue.com/ticker/{symbol}"
+price = "data.last"
+
+[[generic_providers]]
+name = "myvenue"
+type = "generic_rest"
+url = "https://api.myvenue.com/ticker/{symbol}"
+price = "data.last"
+`,
+			expectErr: true,
+		},
+		{
+			name: "unsupported type",
+			genericProviders: `
+[[generic_providers]]
+name = "myvenue"
+type = "graphql"
+url = "https://api.myvenue.com/ticker/{symbol}"
+price = "data.last"
+`,
+			expectErr: true,
+		},
+		{
+			name: "invalid poll interval",
+			genericProviders: `
+[[generic_providers]]
+name = "myvenue"
+type = "generic_rest"
+url = "https://api.myvenue.com/ticker/{symbol}"
+poll_interval = "-1s"
+price = "data.last"
+`,
+			expectErr: true,
+		},
+		{
+			name: "pairs url without selector",
+			genericProviders: `
+[[generic_providers]]
+name = "myvenue"
+type = "generic_rest"
+url = "https://api.myvenue.com/ticker/{symbol}"
+price = "data.last"
+pairs_url = "https://api.myvenue.com/symbols"
+`,
+			expectErr: true,
+		},
+		{
+			name: "websocket without symbol selector",
+			genericProviders: `
+[[generic_providers]]
+name = "myvenue"
+type = "generic_ws"
+url = "wss://ws.myvenue.com/v1"
+price = "data.last"
+`,
+			expectErr: true,
+		},
+		{
+			name: "websocket with a rest url",
+			genericProviders: `
+[[generic_providers]]
+name = "myvenue"
+type = "generic_ws"
+url = "https://api.myvenue.com/ticker/{symbol}"
+price = "data.last"
+symbol = "data.symbol"
+`,
+			expectErr: true,
+		},
+		{
+			name: "websocket with invalid ping message type",
+			genericProviders: `
+[[generic_providers]]
+name = "myvenue"
+type = "generic_ws"
+url = "wss://ws.myvenue.com/v1"
+price = "data.last"
+symbol = "data.symbol"
+ping_message_type = "binary"
+`,
+			expectErr: true,
+		},
+		{
+			name: "missing price selector",
+			genericProviders: `
+[[generic_providers]]
+name = "myvenue"
+type = "generic_rest"
+url = "https://api.myvenue.com/ticker/{symbol}"
+`,
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpFile, err := ioutil.TempFile("", "price-feeder.toml")
+			require.NoError(t, err)
+			defer os.Remove(tmpFile.Name())
+
+			_, err = tmpFile.Write([]byte(pairs + tc.genericProviders))
+			require.NoError(t, err)
+
+			cfg, err := config.ParseConfig(tmpFile.Name())
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, cfg.GenericProviders, 1)
+			require.Equal(t, "ATOM2", cfg.GenericProviders[0].SymbolAliases["ATOM"])
+
+			pollInterval, err := cfg.GenericProviders[0].GetPollInterval()
+			require.NoError(t, err)
+			require.Equal(t, 10*time.Second, pollInterval)
+		})
+	}
+}
+
+func TestParseConfig_DexPools(t *testing.T) {
+	osmosisPool := `
+[[dex_pools]]
+provider = "osmosis"
+base = "OSMO"
+quote = "USDT"
+pool_id = 1263
+base_denom = "uosmo"
+quote_denom = "uusdt"
+`
+	pairs := `
 [main]
 enable_voting = true
 enable_server = true
@@ -406,37 +597,14 @@ write_timeout = "20s"
 enable_cors = true
 allowed_origins = ["*"]
 
-[gas]
-gas_adjustment = 1.5
-gas_prices = "0.00125akii"
-gas_limit = 2000000
-
-[[deviation_thresholds]]
-base = "USDT"
-threshold = "2"
-
-[[deviation_thresholds]]
-base = "ATOM"
-threshold = "1.5"
-
-[[currency_pairs]]
-base = "ATOM"
-chain_denom = "uatom"
-quote = "USDT"
-providers = [
-	"kraken",
-	"binance",
-	"huobi"
-]
-
 [[currency_pairs]]
-base = "KII"
-chain_denom = "akii"
+base = "OSMO"
+chain_denom = "uosmo"
 quote = "USDT"
 providers = [
 	"kraken",
 	"binance",
-	"huobi"
+	"osmosis"
 ]
 
 [[currency_pairs]]
@@ -458,46 +626,205 @@ prefix = "kii"
 [keyring]
 backend = "test"
 dir = "/Users/username/.kiichain"
-pass = "keyringPassword"
 
 [rpc]
 tmrpc_endpoint = "http://localhost:26657"
 grpc_endpoint = "localhost:9090"
 rpc_timeout = "100ms"
 
+[gas]
+gas_adjustment = 1.5
+gas_prices = "0.00125akii"
+gas_limit = 2000000
+
 [telemetry]
-service_name = "price-feeder"
-enabled = true
-enable_hostname = true
-enable_hostname_label = true
-enable_service_label = true
-prometheus_retention = 120
-global_labels = [["chain-id", "kii-local-testnet"]]
-`)
-	_, err = tmpFile.Write(content)
-	require.NoError(t, err)
+enabled = false
+`
 
-	cfg, err := config.ParseConfig(tmpFile.Name())
-	require.NoError(t, err)
+	testCases := []struct {
+		name      string
+		dexPools  string
+		expectErr string
+	}{
+		{
+			name: "valid pool",
+			dexPools: `
+[[provider_endpoints]]
+name = "osmosis"
+grpc = "grpc.osmosis.zone:9090"
+
+[[dex_pools]]
+provider = "osmosis"
+base = "OSMO"
+quote = "USDT"
+pool_id = 1263
+base_denom = "uosmo"
+quote_denom = "ibc/4ABBEF4C8926DDDB320AE5188CFD63267ABBCEFC0583E4AE05D6E5AA2401DDAB"
+base_exponent = 6
+quote_exponent = 6
+`,
+		},
+		{
+			name:      "missing pool",
+			dexPools:  "",
+			expectErr: "no osmosis pool for OSMO/USDT",
+		},
+		{
+			name: "pool of another pair",
+			dexPools: `
+[[dex_pools]]
+provider = "osmosis"
+base = "ATOM"
+quote = "USDT"
+pool_id = 1264
+base_denom = "uatom"
+quote_denom = "uusdt"
+`,
+			expectErr: "no osmosis pool for OSMO/USDT",
+		},
+		{
+			name: "missing pool id",
+			dexPools: `
+[[dex_pools]]
+provider = "osmosis"
+base = "OSMO"
+quote = "USDT"
+base_denom = "uosmo"
+quote_denom = "uusdt"
+`,
+			expectErr: "PoolID",
+		},
+		{
+			name: "invalid exponent",
+			dexPools: `
+[[dex_pools]]
+provider = "osmosis"
+base = "OSMO"
+quote = "USDT"
+pool_id = 1263
+base_denom = "uosmo"
+quote_denom = "uusdt"
+base_exponent = 19
+`,
+			expectErr: "BaseExponent",
+		},
+		{
+			name: "endpoint without grpc",
+			dexPools: `
+[[provider_endpoints]]
+name = "osmosis"
+rest = "https://lcd.osmosis.zone"
+websocket = "rpc.osmosis.zone"
+
+[[dex_pools]]
+provider = "osmosis"
+base = "OSMO"
+quote = "USDT"
+pool_id = 1263
+base_denom = "uosmo"
+quote_denom = "uusdt"
+`,
+			expectErr: "Endpoint",
+		},
+		{
+			name: "duplicated pool",
+			dexPools: `
+[[dex_pools]]
+provider = "osmosis"
+base = "OSMO"
+quote = "USDT"
+pool_id = 1263
+base_denom = "uosmo"
+quote_denom = "uusdt"
 
-	require.Len(t, cfg.CurrencyPairs, 3)
-	require.Equal(t, "ATOM", cfg.CurrencyPairs[0].Base)
-	require.Equal(t, "USDT", cfg.CurrencyPairs[0].Quote)
-	require.Len(t, cfg.CurrencyPairs[0].Providers, 3)
-	require.Equal(t, "kraken", cfg.CurrencyPairs[0].Providers[0])
-	require.Equal(t, "binance", cfg.CurrencyPairs[0].Providers[1])
-	require.Equal(t, "2", cfg.Deviations[0].Threshold)
-	require.Equal(t, "USDT", cfg.Deviations[0].Base)
-	require.Equal(t, "1.5", cfg.Deviations[1].Threshold)
-	require.Equal(t, "ATOM", cfg.Deviations[1].Base)
+[[dex_pools]]
+provider = "osmosis"
+base = "OSMO"
+quote = "USDT"
+pool_id = 1264
+base_denom = "uosmo"
+quote_denom = "uusdt"
+`,
+			expectErr: "duplicated osmosis pool for OSMO/USDT",
+		},
+		{
+			name: "uniswap pool without address",
+			dexPools: osmosisPool + `
+[[dex_pools]]
+provider = "uniswapv3"
+base = "WETH"
+quote = "USDC"
+base_denom = "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"
+quote_denom = "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+`,
+			expectErr: "Address",
+		},
+		{
+			name: "uniswap pool with chain denoms",
+			dexPools: osmosisPool + `
+[[dex_pools]]
+provider = "uniswapv3"
+base = "WETH"
+quote = "USDC"
+address = "0x88e6A0c2dDD26FEEb64F039a2c41296FcB3f5640"
+base_denom = "aweth"
+quote_denom = "ausdc"
+`,
+			expectErr: "uniswapv3 pool for WETH/USDC requires token addresses as denoms",
+		},
+		{
+			name: "uniswap pool with invalid twap window",
+			dexPools: osmosisPool + `
+[[dex_pools]]
+provider = "uniswapv3"
+base = "WETH"
+quote = "USDC"
+address = "0x88e6A0c2dDD26FEEb64F039a2c41296FcB3f5640"
+base_denom = "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"
+quote_denom = "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+twap_window = "500ms"
+`,
+			expectErr: "invalid twap window of uniswapv3 pool for WETH/USDC",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpFile, err := ioutil.TempFile("", "price-feeder.toml")
+			require.NoError(t, err)
+			defer os.Remove(tmpFile.Name())
+
+			_, err = tmpFile.Write([]byte(pairs + tc.dexPools))
+			require.NoError(t, err)
+
+			cfg, err := config.ParseConfig(tmpFile.Name())
+			if tc.expectErr != "" {
+				require.ErrorContains(t, err, tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, cfg.DexPools, 1)
+			require.Equal(t, uint64(1263), cfg.DexPools[0].PoolID)
+			require.Equal(t, "grpc.osmosis.zone:9090", cfg.ProviderEndpoints[0].GRPC)
+		})
+	}
 }
 
-func TestParseConfig_Invalid_Deviations(t *testing.T) {
-	tmpFile, err := ioutil.TempFile("", "price-feeder.toml")
+func TestDexPool_GetTwapWindow(t *testing.T) {
+	twapWindow, err := config.DexPool{}.GetTwapWindow()
 	require.NoError(t, err)
-	defer os.Remove(tmpFile.Name())
+	require.Equal(t, 5*time.Minute, twapWindow)
 
-	content := []byte(`
+	twapWindow, err = config.DexPool{TwapWindow: "10m"}.GetTwapWindow()
+	require.NoError(t, err)
+	require.Equal(t, 10*time.Minute, twapWindow)
+
+	_, err = config.DexPool{TwapWindow: "0s"}.GetTwapWindow()
+	require.Error(t, err)
+}
+
+func TestParseConfig_FXProviders(t *testing.T) {
+	pairs := `
 [main]
 enable_voting = true
 enable_server = true
@@ -509,76 +836,435 @@ write_timeout = "20s"
 enable_cors = true
 allowed_origins = ["*"]
 
-gas_adjustment = 1.5
-gas_limit = 2000000
-
-[server]
-listen_addr = "0.0.0.0:99999"
-read_timeout = "20s"
-verbose_cors = true
-write_timeout = "20s"
-
-[[deviation_thresholds]]
-base = "USDT"
-threshold = "4.0"
-
-[[deviation_thresholds]]
-base = "ATOM"
-threshold = "1.5"
-
-[[currency_pairs]]
-base = "ATOM"
-chain_denom = "uatom"
-quote = "USDT"
-providers = [
-	"kraken",
-	"binance",
-	"huobi"
-]
-
-[[currency_pairs]]
-base = "UMEE"
-chain_denom = "uumee"
-quote = "USDT"
-providers = [
-	"kraken",
-	"binance",
-	"huobi"
-]
-
 [[currency_pairs]]
-base = "USDT"
-chain_denom = "uusdt"
-quote = "USD"
+base = "BTC"
+chain_denom = "ubtc"
+quote = "EUR"
 providers = [
 	"kraken",
-	"binance",
-	"huobi"
+	"coinbase",
+	"bitstamp"
 ]
 
 [account]
-address = "umee15nejfgcaanqpw25ru4arvfd0fwy6j8clccvwx4"
-validator = "umeevalcons14rjlkfzp56733j5l5nfk6fphjxymgf8mj04d5p"
-chain_id = "umee-local-testnet"
+address = "kii15nejfgcaanqpw25ru4arvfd0fwy6j8clccvwx4"
+validator = "kiivalcons14rjlkfzp56733j5l5nfk6fphjxymgf8mj04d5p"
+chain_id = "kii-local-testnet"
+prefix = "kii"
 
 [keyring]
 backend = "test"
-dir = "/Users/username/.umee"
-pass = "keyringPassword"
+dir = "/Users/username/.kiichain"
 
 [rpc]
 tmrpc_endpoint = "http://localhost:26657"
 grpc_endpoint = "localhost:9090"
 rpc_timeout = "100ms"
 
+[gas]
+gas_adjustment = 1.5
+gas_prices = "0.00125akii"
+gas_limit = 2000000
+
 [telemetry]
-service_name = "price-feeder"
-enabled = true
-enable_hostname = true
-enable_hostname_label = true
-enable_service_label = true
-prometheus_retention = 120
-global_labels = [["chain-id", "umee-local-testnet"]]
+enabled = false
+`
+
+	testCases := []struct {
+		name        string
+		fxProviders string
+		expectErr   string
+	}{
+		{
+			name: "valid fx provider",
+			fxProviders: `
+[[fx_providers]]
+name = "ecb"
+url = "https://api.example.com/latest?base=USD&symbols={currencies}"
+currencies = ["eur", "GBP"]
+poll_interval = "1h"
+`,
+		},
+		{
+			name:        "missing fx provider",
+			fxProviders: "",
+			expectErr:   "no fx provider for fiat quote EUR",
+		},
+		{
+			name: "fx provider of another currency",
+			fxProviders: `
+[[fx_providers]]
+name = "ecb"
+url = "https://api.example.com/latest"
+currencies = ["GBP"]
+`,
+			expectErr: "no fx provider for fiat quote EUR",
+		},
+		{
+			name: "unsupported currency",
+			fxProviders: `
+[[fx_providers]]
+name = "ecb"
+url = "https://api.example.com/latest"
+currencies = ["EUR", "XAU"]
+`,
+			expectErr: "unsupported fiat currency of fx provider ecb: XAU",
+		},
+		{
+			name: "conflicting name",
+			fxProviders: `
+[[fx_providers]]
+name = "kraken"
+url = "https://api.example.com/latest"
+currencies = ["EUR"]
+`,
+			expectErr: "fx provider kraken conflicts with another provider",
+		},
+		{
+			name: "duplicated fx provider",
+			fxProviders: `
+[[fx_providers]]
+name = "ecb"
+url = "https://api.example.com/latest"
+currencies = ["EUR"]
+
+[[fx_providers]]
+name = "ecb"
+url = "https://api.example.com/latest"
+currencies = ["GBP"]
+`,
+			expectErr: "duplicated fx provider: ecb",
+		},
+		{
+			name: "invalid poll interval",
+			fxProviders: `
+[[fx_providers]]
+name = "ecb"
+url = "https://api.example.com/latest"
+currencies = ["EUR"]
+poll_interval = "0s"
+`,
+			expectErr: "invalid poll interval for fx provider ecb",
+		},
+		{
+			name: "fx provider of a coin pair",
+			fxProviders: `
+[[fx_providers]]
+name = "ecb"
+url = "https://api.example.com/latest"
+currencies = ["EUR"]
+
+[[currency_pairs]]
+base = "ATOM"
+chain_denom = "uatom"
+quote = "USD"
+providers = [
+	"ecb"
+]
+`,
+			expectErr: "fx provider ecb only quotes its currencies in USD",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpFile, err := ioutil.TempFile("", "price-feeder.toml")
+			require.NoError(t, err)
+			defer os.Remove(tmpFile.Name())
+
+			_, err = tmpFile.Write([]byte(pairs + tc.fxProviders))
+			require.NoError(t, err)
+
+			cfg, err := config.ParseConfig(tmpFile.Name())
+			if tc.expectErr != "" {
+				require.ErrorContains(t, err, tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, cfg.FXProviders, 1)
+
+			pollInterval, err := cfg.FXProviders[0].GetPollInterval()
+			require.NoError(t, err)
+			require.Equal(t, time.Hour, pollInterval)
+			require.Equal(t, "rates", cfg.FXProviders[0].GetRates())
+		})
+	}
+}
+
+func TestParseConfig_OrderBooks(t *testing.T) {
+	pairs := `
+[main]
+enable_voting = true
+enable_server = true
+
+[server]
+listen_addr = "0.0.0.0:7171"
+read_timeout = "20s"
+write_timeout = "20s"
+enable_cors = true
+allowed_origins = ["*"]
+
+[[currency_pairs]]
+base = "BTC"
+chain_denom = "ubtc"
+quote = "USD"
+providers = [
+	"kraken",
+	"coinbase",
+	"bitstamp"
+]
+
+[account]
+address = "kii15nejfgcaanqpw25ru4arvfd0fwy6j8clccvwx4"
+validator = "kiivalcons14rjlkfzp56733j5l5nfk6fphjxymgf8mj04d5p"
+chain_id = "kii-local-testnet"
+prefix = "kii"
+
+[keyring]
+backend = "test"
+dir = "/Users/username/.kiichain"
+
+[rpc]
+tmrpc_endpoint = "http://localhost:26657"
+grpc_endpoint = "localhost:9090"
+rpc_timeout = "100ms"
+
+[gas]
+gas_adjustment = 1.5
+gas_prices = "0.00125akii"
+gas_limit = 2000000
+
+[telemetry]
+enabled = false
+`
+
+	testCases := []struct {
+		name       string
+		orderBooks string
+		expectErr  string
+	}{
+		{
+			name: "valid order book",
+			orderBooks: `
+[[order_books]]
+base = "BTC"
+max_spread = "0.005"
+use_mid_price = true
+`,
+		},
+		{
+			name: "duplicated order book",
+			orderBooks: `
+[[order_books]]
+base = "BTC"
+
+[[order_books]]
+base = "BTC"
+max_spread = "0.005"
+`,
+			expectErr: "duplicated order book settings: BTC",
+		},
+		{
+			name: "invalid depth band",
+			orderBooks: `
+[[order_books]]
+base = "BTC"
+depth_band = "1.5"
+`,
+			expectErr: "invalid depth band of order book BTC: expected a ratio between 0 and 1, got 1.5",
+		},
+		{
+			name: "invalid max spread",
+			orderBooks: `
+[[order_books]]
+base = "BTC"
+max_spread = "wide"
+`,
+			expectErr: "invalid max spread of order book BTC",
+		},
+		{
+			name: "missing base",
+			orderBooks: `
+[[order_books]]
+max_spread = "0.005"
+`,
+			expectErr: "Field validation for 'Base' failed",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpFile, err := ioutil.TempFile("", "price-feeder.toml")
+			require.NoError(t, err)
+			defer os.Remove(tmpFile.Name())
+
+			_, err = tmpFile.Write([]byte(pairs + tc.orderBooks))
+			require.NoError(t, err)
+
+			cfg, err := config.ParseConfig(tmpFile.Name())
+			if tc.expectErr != "" {
+				require.ErrorContains(t, err, tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, cfg.OrderBooks, 1)
+
+			depthBand, err := cfg.OrderBooks[0].GetDepthBand()
+			require.NoError(t, err)
+			require.Equal(t, math.LegacyMustNewDecFromStr("0.02"), depthBand)
+
+			maxSpread, err := cfg.OrderBooks[0].GetMaxSpread()
+			require.NoError(t, err)
+			require.Equal(t, math.LegacyMustNewDecFromStr("0.005"), maxSpread)
+		})
+	}
+}
+
+func TestValidatePlugins(t *testing.T) {
+	pairs := `
+[[currency_pairs]]
+base = "ATOM"
+chain_denom = "uatom"
+quote = "USD"
+providers = [
+	"kraken",
+	"binance",
+	"otcdesk"
+]
+`
+
+	testCases := []struct {
+		name      string
+		plugins   string
+		expectErr string
+	}{
+		{
+			name: "conflicting name",
+			plugins: `
+[[plugins]]
+name = "otcdesk"
+command = "/usr/local/bin/otcdesk"
+
+[[plugins]]
+name = "kraken"
+command = "/usr/local/bin/kraken"
+`,
+			expectErr: "plugin kraken conflicts with a supported provider",
+		},
+		{
+			name: "conflicting generic provider",
+			plugins: `
+[[generic_providers]]
+name = "otcdesk"
+type = "generic_rest"
+url = "https://api.otcdesk.com/ticker/{symbol}"
+price = "last"
+
+[[plugins]]
+name = "otcdesk"
+command = "/usr/local/bin/otcdesk"
+`,
+			expectErr: "plugin otcdesk conflicts with a generic provider",
+		},
+		{
+			name: "duplicated name",
+			plugins: `
+[[plugins]]
+name = "otcdesk"
+command = "/usr/local/bin/otcdesk"
+
+[[plugins]]
+name = "otcdesk"
+command = "/usr/local/bin/otcdesk"
+`,
+			expectErr: "duplicated plugin: otcdesk",
+		},
+		{
+			name: "invalid timeout",
+			plugins: `
+[[plugins]]
+name = "otcdesk"
+command = "/usr/local/bin/otcdesk"
+timeout = "soon"
+`,
+			expectErr: "invalid timeout for plugin otcdesk",
+		},
+		{
+			name:      "undeclared plugin",
+			plugins:   "",
+			expectErr: "unsupported provider: otcdesk",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpFile, err := ioutil.TempFile("", "price-feeder.toml")
+			require.NoError(t, err)
+			defer os.Remove(tmpFile.Name())
+
+			_, err = tmpFile.Write([]byte(pairs + tc.plugins))
+			require.NoError(t, err)
+
+			_, err = config.ParseConfig(tmpFile.Name())
+			require.ErrorContains(t, err, tc.expectErr)
+		})
+	}
+}
+
+func TestPlugin_GetTimeout(t *testing.T) {
+	plugin := config.Plugin{}
+	timeout, err := plugin.GetTimeout()
+	require.NoError(t, err)
+	require.Equal(t, 5*time.Second, timeout)
+
+	plugin.Timeout = "250ms"
+	timeout, err = plugin.GetTimeout()
+	require.NoError(t, err)
+	require.Equal(t, 250*time.Millisecond, timeout)
+}
+
+func TestGenericProvider_GetPingDuration(t *testing.T) {
+	genericProvider := config.GenericProvider{}
+	pingDuration, err := genericProvider.GetPingDuration()
+	require.NoError(t, err)
+	require.Equal(t, 20*time.Second, pingDuration)
+
+	// a zero duration disables the pings
+	genericProvider.PingDuration = "0s"
+	pingDuration, err = genericProvider.GetPingDuration()
+	require.NoError(t, err)
+	require.Zero(t, pingDuration)
+
+	genericProvider.PingDuration = "-1s"
+	_, err = genericProvider.GetPingDuration()
+	require.Error(t, err)
+}
+
+func TestParseConfig_NonUSDQuote(t *testing.T) {
+	tmpFile, err := ioutil.TempFile("", "price-feeder.toml")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+
+	content := []byte(`
+listen_addr = ""
+
+[[currency_pairs]]
+base = "ATOM"
+chain_denom = "uatom"
+quote = "USDT"
+providers = [
+	"kraken",
+	"binance"
+]
+
+[[currency_pairs]]
+base = "UMEE"
+chain_denom = "uumee"
+quote = "USDT"
+providers = [
+	"kraken",
+	"binance"
+]
 `)
 	_, err = tmpFile.Write(content)
 	require.NoError(t, err)
@@ -586,3 +1272,693 @@ global_labels = [["chain-id", "umee-local-testnet"]]
 	_, err = config.ParseConfig(tmpFile.Name())
 	require.Error(t, err)
 }
+
+func TestParseConfig_Valid_Deviations(t *testing.T) {
+	tmpFile, err := ioutil.TempFile("", "price-feeder.toml")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+
+	content := []byte(`
+[main]
+enable_voting = true
+enable_server = true
+
+[server]
+listen_addr = "0.0.0.0:7171"
+read_timeout = "20s"
+write_timeout = "20s"
+enable_cors = true
+allowed_origins = ["*"]
+
+[gas]
+gas_adjustment = 1.5
+gas_prices = "0.00125akii"
+gas_limit = 2000000
+
+[[deviation_thresholds]]
+base = "USDT"
+threshold = "2"
+
+[[deviation_thresholds]]
+base = "ATOM"
+threshold = "1.5"
+
+[[currency_pairs]]
+base = "ATOM"
+chain_denom = "uatom"
+quote = "USDT"
+providers = [
+	"kraken",
+	"binance",
+	"huobi"
+]
+
+[[currency_pairs]]
+base = "KII"
+chain_denom = "akii"
+quote = "USDT"
+providers = [
+	"kraken",
+	"binance",
+	"huobi"
+]
+
+[[currency_pairs]]
+base = "USDT"
+chain_denom = "uusdt"
+quote = "USD"
+providers = [
+	"kraken",
+	"binance",
+	"huobi"
+]
+
+[account]
+address = "kii15nejfgcaanqpw25ru4arvfd0fwy6j8clccvwx4"
+validator = "kiivalcons14rjlkfzp56733j5l5nfk6fphjxymgf8mj04d5p"
+chain_id = "kii-local-testnet"
+prefix = "kii"
+
+[keyring]
+backend = "test"
+dir = "/Users/username/.kiichain"
+pass = "keyringPassword"
+
+[rpc]
+tmrpc_endpoint = "http://localhost:26657"
+grpc_endpoint = "localhost:9090"
+rpc_timeout = "100ms"
+
+[telemetry]
+service_name = "price-feeder"
+enabled = true
+enable_hostname = true
+enable_hostname_label = true
+enable_service_label = true
+prometheus_retention = 120
+global_labels = [["chain-id", "kii-local-testnet"]]
+`)
+	_, err = tmpFile.Write(content)
+	require.NoError(t, err)
+
+	cfg, err := config.ParseConfig(tmpFile.Name())
+	require.NoError(t, err)
+
+	require.Len(t, cfg.CurrencyPairs, 3)
+	require.Equal(t, "ATOM", cfg.CurrencyPairs[0].Base)
+	require.Equal(t, "USDT", cfg.CurrencyPairs[0].Quote)
+	require.Len(t, cfg.CurrencyPairs[0].Providers, 3)
+	require.Equal(t, "kraken", cfg.CurrencyPairs[0].Providers[0])
+	require.Equal(t, "binance", cfg.CurrencyPairs[0].Providers[1])
+	require.Equal(t, "2", cfg.Deviations[0].Threshold)
+	require.Equal(t, "USDT", cfg.Deviations[0].Base)
+	require.Equal(t, "1.5", cfg.Deviations[1].Threshold)
+	require.Equal(t, "ATOM", cfg.Deviations[1].Base)
+}
+
+func TestParseConfig_Invalid_Deviations(t *testing.T) {
+	tmpFile, err := ioutil.TempFile("", "price-feeder.toml")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+
+	content := []byte(`
+[main]
+enable_voting = true
+enable_server = true
+
+[server]
+listen_addr = "0.0.0.0:7171"
+read_timeout = "20s"
+write_timeout = "20s"
+enable_cors = true
+allowed_origins = ["*"]
+
+gas_adjustment = 1.5
+gas_limit = 2000000
+
+[server]
+listen_addr = "0.0.0.0:99999"
+read_timeout = "20s"
+verbose_cors = true
+write_timeout = "20s"
+
+[[deviation_thresholds]]
+base = "USDT"
+threshold = "4.0"
+
+[[deviation_thresholds]]
+base = "ATOM"
+threshold = "1.5"
+
+[[currency_pairs]]
+base = "ATOM"
+chain_denom = "uatom"
+quote = "USDT"
+providers = [
+	"kraken",
+	"binance",
+	"huobi"
+]
+
+[[currency_pairs]]
+base = "UMEE"
+chain_denom = "uumee"
+quote = "USDT"
+providers = [
+	"kraken",
+	"binance",
+	"huobi"
+]
+
+[[currency_pairs]]
+base = "USDT"
+chain_denom = "uusdt"
+quote = "USD"
+providers = [
+	"kraken",
+	"binance",
+	"huobi"
+]
+
+[account]
+address = "umee15nejfgcaanqpw25ru4arvfd0fwy6j8clccvwx4"
+validator = "umeevalcons14rjlkfzp56733j5l5nfk6fphjxymgf8mj04d5p"
+chain_id = "umee-local-testnet"
+
+[keyring]
+backend = "test"
+dir = "/Users/username/.umee"
+pass = "keyringPassword"
+
+[rpc]
+tmrpc_endpoint = "http://localhost:26657"
+grpc_endpoint = "localhost:9090"
+rpc_timeout = "100ms"
+
+[telemetry]
+service_name = "price-feeder"
+enabled = true
+enable_hostname = true
+enable_hostname_label = true
+enable_service_label = true
+prometheus_retention = 120
+global_labels = [["chain-id", "umee-local-testnet"]]
+`)
+	_, err = tmpFile.Write(content)
+	require.NoError(t, err)
+
+	_, err = config.ParseConfig(tmpFile.Name())
+	require.Error(t, err)
+}
+
+func TestBasesBelowMinimumProviders(t *testing.T) {
+	providersByBase := map[string]map[string]struct{}{
+		"ATOM": {config.ProviderBinance: {}, config.ProviderKraken: {}, config.ProviderOkx: {}},
+		"BTC":  {config.ProviderBinance: {}, config.ProviderKraken: {}},
+		"ETH":  {config.ProviderBinance: {}},
+		"KII":  {config.ProviderMock: {}},
+	}
+
+	bases := config.BasesBelowMinimumProviders(providersByBase)
+	require.Equal(t, []string{"BTC", "ETH"}, bases)
+}
+
+func TestParseConfig_Aggregations(t *testing.T) {
+	pairs := `
+[main]
+enable_voting = true
+enable_server = true
+
+[server]
+listen_addr = "0.0.0.0:7171"
+read_timeout = "20s"
+write_timeout = "20s"
+enable_cors = true
+allowed_origins = ["*"]
+
+[account]
+address = "kii15nejfgcaanqpw25ru4arvfd0fwy6j8clccvwx4"
+validator = "kiivalcons14rjlkfzp56733j5l5nfk6fphjxymgf8mj04d5p"
+chain_id = "kii-local-testnet"
+prefix = "kii"
+
+[keyring]
+backend = "test"
+dir = "/Users/username/.kiichain"
+
+[rpc]
+tmrpc_endpoint = "http://localhost:26657"
+grpc_endpoint = "localhost:9090"
+rpc_timeout = "100ms"
+
+[gas]
+gas_adjustment = 1.5
+gas_prices = "0.00125akii"
+gas_limit = 2000000
+
+[telemetry]
+enabled = false
+`
+
+	testCases := []struct {
+		name          string
+		currencyPairs string
+		expectErr     string
+	}{
+		{
+			name: "valid aggregation",
+			currencyPairs: `
+[[currency_pairs]]
+base = "BTC"
+chain_denom = "ubtc"
+quote = "USD"
+providers = ["kraken", "coinbase", "bitstamp"]
+aggregation = "weighted_median"
+outlier_filter = "mad"
+`,
+		},
+		{
+			name: "unsupported aggregation",
+			currencyPairs: `
+[[currency_pairs]]
+base = "BTC"
+chain_denom = "ubtc"
+quote = "USD"
+providers = ["kraken", "coinbase", "bitstamp"]
+aggregation = "mode"
+`,
+			expectErr: "unsupported aggregation of BTC: mode",
+		},
+		{
+			name: "unsupported outlier filter",
+			currencyPairs: `
+[[currency_pairs]]
+base = "BTC"
+chain_denom = "ubtc"
+quote = "USD"
+providers = ["kraken", "coinbase", "bitstamp"]
+outlier_filter = "iqr"
+`,
+			expectErr: "unsupported outlier filter of BTC: iqr",
+		},
+		{
+			name: "conflicting aggregations",
+			currencyPairs: `
+[[currency_pairs]]
+base = "BTC"
+chain_denom = "ubtc"
+quote = "USD"
+providers = ["kraken", "coinbase", "bitstamp"]
+aggregation = "median"
+
+[[currency_pairs]]
+base = "BTC"
+chain_denom = "ubtc"
+quote = "USDT"
+providers = ["binance"]
+aggregation = "vwap"
+
+[[currency_pairs]]
+base = "USDT"
+chain_denom = "uusdt"
+quote = "USD"
+providers = ["kraken", "coinbase", "bitstamp"]
+`,
+			expectErr: "conflicting aggregations of BTC: median and vwap",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpFile, err := ioutil.TempFile("", "price-feeder.toml")
+			require.NoError(t, err)
+			defer os.Remove(tmpFile.Name())
+
+			_, err = tmpFile.Write([]byte(pairs + tc.currencyPairs))
+			require.NoError(t, err)
+
+			cfg, err := config.ParseConfig(tmpFile.Name())
+			if tc.expectErr != "" {
+				require.ErrorContains(t, err, tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, config.AggregationWeightedMedian, cfg.CurrencyPairs[0].Aggregation)
+			require.Equal(t, config.OutlierFilterMAD, cfg.CurrencyPairs[0].OutlierFilter)
+		})
+	}
+}
+
+func TestParseConfig_ConversionPaths(t *testing.T) {
+	pairs := `
+[main]
+enable_voting = true
+enable_server = true
+
+[server]
+listen_addr = "0.0.0.0:7171"
+read_timeout = "20s"
+write_timeout = "20s"
+enable_cors = true
+allowed_origins = ["*"]
+
+[account]
+address = "kii15nejfgcaanqpw25ru4arvfd0fwy6j8clccvwx4"
+validator = "kiivalcons14rjlkfzp56733j5l5nfk6fphjxymgf8mj04d5p"
+chain_id = "kii-local-testnet"
+prefix = "kii"
+
+[keyring]
+backend = "test"
+dir = "/Users/username/.kiichain"
+
+[rpc]
+tmrpc_endpoint = "http://localhost:26657"
+grpc_endpoint = "localhost:9090"
+rpc_timeout = "100ms"
+
+[gas]
+gas_adjustment = 1.5
+gas_prices = "0.00125akii"
+gas_limit = 2000000
+
+[telemetry]
+enabled = false
+
+[[currency_pairs]]
+base = "ATOM"
+chain_denom = "uatom"
+quote = "ETH"
+providers = ["kraken", "coinbase", "bitstamp"]
+
+[[currency_pairs]]
+base = "ETH"
+chain_denom = "ueth"
+quote = "USDT"
+providers = ["kraken", "coinbase", "bitstamp"]
+`
+
+	testCases := []struct {
+		name          string
+		currencyPairs string
+		expectErr     string
+	}{
+		{
+			name: "multi-hop path",
+			currencyPairs: `
+[[currency_pairs]]
+base = "USDT"
+chain_denom = "uusdt"
+quote = "USD"
+providers = ["kraken", "coinbase", "bitstamp"]
+`,
+		},
+		{
+			name: "no path to USD",
+			currencyPairs: `
+[[currency_pairs]]
+base = "USDT"
+chain_denom = "uusdt"
+quote = "DAI"
+providers = ["kraken", "coinbase", "bitstamp"]
+`,
+			expectErr: "no conversion path from",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpFile, err := ioutil.TempFile("", "price-feeder.toml")
+			require.NoError(t, err)
+			defer os.Remove(tmpFile.Name())
+
+			_, err = tmpFile.Write([]byte(pairs + tc.currencyPairs))
+			require.NoError(t, err)
+
+			_, err = config.ParseConfig(tmpFile.Name())
+			if tc.expectErr != "" {
+				require.ErrorContains(t, err, tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestParseConfig_CandleAges(t *testing.T) {
+	pairs := `
+[main]
+enable_voting = true
+enable_server = true
+
+[server]
+listen_addr = "0.0.0.0:7171"
+read_timeout = "20s"
+write_timeout = "20s"
+enable_cors = true
+allowed_origins = ["*"]
+
+[account]
+address = "kii15nejfgcaanqpw25ru4arvfd0fwy6j8clccvwx4"
+validator = "kiivalcons14rjlkfzp56733j5l5nfk6fphjxymgf8mj04d5p"
+chain_id = "kii-local-testnet"
+prefix = "kii"
+
+[keyring]
+backend = "test"
+dir = "/Users/username/.kiichain"
+
+[rpc]
+tmrpc_endpoint = "http://localhost:26657"
+grpc_endpoint = "localhost:9090"
+rpc_timeout = "100ms"
+
+[gas]
+gas_adjustment = 1.5
+gas_prices = "0.00125akii"
+gas_limit = 2000000
+
+[telemetry]
+enabled = false
+
+[[currency_pairs]]
+base = "BTC"
+chain_denom = "ubtc"
+quote = "USD"
+providers = ["kraken", "coinbase", "bitstamp"]
+max_candle_age = "2m"
+`
+
+	testCases := []struct {
+		name      string
+		config    string
+		expectErr string
+	}{
+		{
+			name: "valid ages",
+			config: `
+max_candle_age = "3m"
+max_clock_skew = "5s"
+`,
+		},
+		{
+			name:   "default ages",
+			config: "",
+		},
+		{
+			name: "invalid max candle age",
+			config: `
+max_candle_age = "-1m"
+`,
+			expectErr: "invalid max candle age: max candle age must be positive",
+		},
+		{
+			name: "invalid max clock skew",
+			config: `
+max_clock_skew = "soon"
+`,
+			expectErr: "invalid max clock skew",
+		},
+		{
+			name: "conflicting pair ages",
+			config: `
+[[currency_pairs]]
+base = "BTC"
+chain_denom = "ubtc"
+quote = "USDT"
+providers = ["binance"]
+max_candle_age = "4m"
+
+[[currency_pairs]]
+base = "USDT"
+chain_denom = "uusdt"
+quote = "USD"
+providers = ["kraken", "coinbase", "bitstamp"]
+`,
+			expectErr: "conflicting max candle ages of BTC: 4m and 2m",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpFile, err := ioutil.TempFile("", "price-feeder.toml")
+			require.NoError(t, err)
+			defer os.Remove(tmpFile.Name())
+
+			// the top level settings are written before the tables
+			_, err = tmpFile.Write([]byte(tc.config + pairs))
+			require.NoError(t, err)
+
+			cfg, err := config.ParseConfig(tmpFile.Name())
+			if tc.expectErr != "" {
+				require.ErrorContains(t, err, tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+
+			pairMaxAge, err := cfg.CurrencyPairs[0].GetMaxCandleAge()
+			require.NoError(t, err)
+			require.Equal(t, 2*time.Minute, pairMaxAge)
+
+			_, err = cfg.GetMaxCandleAge()
+			require.NoError(t, err)
+			_, err = cfg.GetMaxClockSkew()
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestParseConfig_CircuitBreakers(t *testing.T) {
+	tmpl := `
+[main]
+enable_voting = true
+enable_server = true
+
+[server]
+listen_addr = "0.0.0.0:7171"
+read_timeout = "20s"
+write_timeout = "20s"
+enable_cors = true
+allowed_origins = ["*"]
+
+[account]
+address = "kii15nejfgcaanqpw25ru4arvfd0fwy6j8clccvwx4"
+validator = "kiivalcons14rjlkfzp56733j5l5nfk6fphjxymgf8mj04d5p"
+chain_id = "kii-local-testnet"
+prefix = "kii"
+
+[keyring]
+backend = "test"
+dir = "/Users/username/.kiichain"
+
+[rpc]
+tmrpc_endpoint = "http://localhost:26657"
+grpc_endpoint = "localhost:9090"
+rpc_timeout = "100ms"
+
+[gas]
+gas_adjustment = 1.5
+gas_prices = "0.00125akii"
+gas_limit = 2000000
+
+[telemetry]
+enabled = false
+
+[[currency_pairs]]
+base = "BTC"
+chain_denom = "ubtc"
+quote = "USD"
+providers = ["kraken", "coinbase", "bitstamp"]
+`
+
+	testCases := []struct {
+		name      string
+		config    string
+		expectErr string
+	}{
+		{
+			name: "valid circuit breakers",
+			config: `
+[[currency_pairs]]
+base = "ETH"
+chain_denom = "ueth"
+quote = "USD"
+providers = ["kraken", "coinbase", "bitstamp"]
+
+[[circuit_breakers]]
+base = "BTC"
+max_change = "0.1"
+
+[[circuit_breakers]]
+base = "ETH"
+max_change = "1.5"
+confirmations = 2
+`,
+		},
+		{
+			name: "circuit breaker without currency pair",
+			config: `
+[[circuit_breakers]]
+base = "ETH"
+max_change = "0.1"
+`,
+			expectErr: "circuit breaker ETH has no currency pair",
+		},
+		{
+			name: "duplicated circuit breaker",
+			config: `
+[[circuit_breakers]]
+base = "BTC"
+max_change = "0.1"
+
+[[circuit_breakers]]
+base = "BTC"
+max_change = "0.2"
+`,
+			expectErr: "duplicated circuit breaker: BTC",
+		},
+		{
+			name: "invalid max change",
+			config: `
+[[circuit_breakers]]
+base = "BTC"
+max_change = "0"
+`,
+			expectErr: "invalid max change of circuit breaker BTC",
+		},
+		{
+			name: "negative confirmations",
+			config: `
+[[circuit_breakers]]
+base = "BTC"
+max_change = "0.1"
+confirmations = -1
+`,
+			expectErr: "invalid confirmations of circuit breaker BTC",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpFile, err := ioutil.TempFile("", "price-feeder.toml")
+			require.NoError(t, err)
+			defer os.Remove(tmpFile.Name())
+
+			_, err = tmpFile.Write([]byte(tmpl + tc.config))
+			require.NoError(t, err)
+
+			cfg, err := config.ParseConfig(tmpFile.Name())
+			if tc.expectErr != "" {
+				require.ErrorContains(t, err, tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+
+			require.Len(t, cfg.CircuitBreakers, 2)
+			maxChange, err := cfg.CircuitBreakers[1].GetMaxChange()
+			require.NoError(t, err)
+			require.Equal(t, math.LegacyMustNewDecFromStr("1.5"), maxChange)
+			require.Equal(t, 2, cfg.CircuitBreakers[1].Confirmations)
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-metrics"
+	oracletypes "github.com/kiichain/kiichain/v3/x/oracle/types"
+
+	sdkmath "cosmossdk.io/math"
+
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// maxVoteAccuracyHistory is the amount of accuracy records kept per denom
+	maxVoteAccuracyHistory = 100
+)
+
+// votedRates defines the rates voted by the oracle on a vote period (or
+// recorded on shadow mode), waiting to be compared with the on-chain rates.
+type votedRates struct {
+	blockHeight int64
+	votePeriod  uint64
+	rates       sdk.DecCoins
+	shadow      bool
+}
+
+// VoteAccuracy defines the comparison between the rate voted by the oracle
+// for a denom and the final exchange rate stored on-chain for that vote period.
+type VoteAccuracy struct {
+	BlockHeight      int64             `json:"block_height"`
+	VotePeriod       uint64            `json:"vote_period"`
+	VotedRate        sdkmath.LegacyDec `json:"voted_rate"`
+	OnChainRate      sdkmath.LegacyDec `json:"on_chain_rate"`
+	Deviation        sdkmath.LegacyDec `json:"deviation"` // relative to the on-chain rate
+	WithinRewardBand bool              `json:"within_reward_band"`
+	Shadow           bool              `json:"shadow"`
+}
+
+// setLastVote stores the rates voted on the current vote period, so they
+// can be compared with the on-chain rates once the vote period ends
+func (o *Oracle) setLastVote(blockHeight int64, votePeriod float64, rates sdk.DecCoins) {
+	o.lastVote = &votedRates{
+		blockHeight: blockHeight,
+		votePeriod:  uint64(votePeriod),
+		rates:       rates,
+		shadow:      o.shadowMode,
+	}
+}
+
+// onChainRates defines the exchange rates queried from the chain on a vote
+// period.
+type onChainRates struct {
+	votePeriod uint64
+	rates      oracletypes.DenomOracleExchangeRatePairs
+}
+
+// checkVoteAccuracy compares the rates of the last vote with the exchange
+// rates stored on-chain. It is expected to be called once the vote period
+// of the last vote has ended, apart from the tick so a slow node never
+// delays the vote.
+func (o *Oracle) checkVoteAccuracy(
+	ctx context.Context,
+	params oracletypes.Params,
+	lastVote *votedRates,
+	votePeriod uint64,
+) {
+	// nothing to compare
+	if lastVote == nil {
+		return
+	}
+
+	// query the final rates from the chain
+	onChainRates, err := o.GetExchangeRates(ctx)
+	if err != nil {
+		o.logger.Warn().Err(err).Msg("failed to query on-chain exchange rates")
+		return
+	}
+	o.setOnChainRates(votePeriod, onChainRates)
+
+	// compare the rates and store the results
+	records := computeVoteAccuracy(*lastVote, onChainRates, params.RewardBand)
+
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+
+	if o.voteAccuracy == nil {
+		o.voteAccuracy = make(map[string][]VoteAccuracy)
+	}
+
+	for denom, record := range records {
+		// keep only the latest records
+		o.voteAccuracy[denom] = append(o.voteAccuracy[denom], record)
+		if len(o.voteAccuracy[denom]) > maxVoteAccuracyHistory {
+			o.voteAccuracy[denom] = o.voteAccuracy[denom][len(o.voteAccuracy[denom])-maxVoteAccuracyHistory:]
+		}
+
+		labels := []metrics.Label{
+			{Name: "denom", Value: denom},
+		}
+		telemetry.SetGaugeWithLabels([]string{"vote", "deviation"}, float32(record.Deviation.MustFloat64()), labels)
+
+		if !record.WithinRewardBand {
+			telemetry.IncrCounterWithLabels([]string{"vote", "outside_reward_band"}, 1, labels)
+			o.logger.Warn().
+				Str("denom", denom).
+				Str("voted_rate", record.VotedRate.String()).
+				Str("on_chain_rate", record.OnChainRate.String()).
+				Str("deviation", record.Deviation.String()).
+				Bool("shadow", record.Shadow).
+				Msg("voted rate outside of the reward band")
+		}
+	}
+}
+
+// computeVoteAccuracy compares the voted rates with the on-chain exchange rates,
+// returning an accuracy record by denom. Denoms that were not updated on-chain
+// after the vote are skipped.
+func computeVoteAccuracy(
+	vote votedRates,
+	onChainRates oracletypes.DenomOracleExchangeRatePairs,
+	rewardBand sdkmath.LegacyDec,
+) map[string]VoteAccuracy {
+	records := make(map[string]VoteAccuracy)
+
+	// the voted rate can be up to half the reward band away from the median
+	maxDeviation := rewardBand.QuoInt64(2)
+
+	for _, rate := range onChainRates {
+		if rate.OracleExchangeRate == nil || !rate.OracleExchangeRate.ExchangeRate.IsPositive() {
+			continue
+		}
+
+		// skip the rates which weren't updated by the vote period tally
+		if rate.OracleExchangeRate.LastUpdate.IsNil() || rate.OracleExchangeRate.LastUpdate.Int64() < vote.blockHeight {
+			continue
+		}
+
+		voted := vote.rates.AmountOf(rate.Denom)
+		if !voted.IsPositive() {
+			continue
+		}
+
+		onChain := rate.OracleExchangeRate.ExchangeRate
+		deviation := voted.Sub(onChain).Abs().Quo(onChain)
+
+		records[rate.Denom] = VoteAccuracy{
+			BlockHeight:      vote.blockHeight,
+			VotePeriod:       vote.votePeriod,
+			VotedRate:        voted,
+			OnChainRate:      onChain,
+			Deviation:        deviation,
+			WithinRewardBand: deviation.LTE(maxDeviation),
+			Shadow:           vote.shadow,
+		}
+	}
+
+	return records
+}
+
+// GetVoteAccuracy returns a copy of the accuracy history by denom, ordered
+// from the oldest to the newest vote.
+func (o *Oracle) GetVoteAccuracy() map[string][]VoteAccuracy {
+	o.mtx.RLock()
+	defer o.mtx.RUnlock()
+
+	accuracy := make(map[string][]VoteAccuracy, len(o.voteAccuracy))
+	for denom, history := range o.voteAccuracy {
+		accuracy[denom] = make([]VoteAccuracy, len(history))
+		copy(accuracy[denom], history)
+	}
+
+	return accuracy
+}
+
+// GetExchangeRates returns the current on-chain exchange rates of the x/oracle module.
+func (o *Oracle) GetExchangeRates(ctx context.Context) (oracletypes.DenomOracleExchangeRatePairs, error) {
+	// reuse the connection with the blockchain
+	grpcConn, err := o.getQueryConn()
+	if err != nil {
+		return nil, err
+	}
+
+	// create oracle query client
+	queryClient := oracletypes.NewQueryClient(grpcConn)
+
+	// create context with timeout
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	// query oracle module's exchange rates
+	queryResponse, err := queryClient.ExchangeRates(ctx, &oracletypes.QueryExchangeRatesRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get x/oracle exchange rates: %w", err)
+	}
+
+	return queryResponse.DenomOracleExchangeRate, nil
+}
+
+// setOnChainRates stores the exchange rates queried on the vote period.
+func (o *Oracle) setOnChainRates(votePeriod uint64, rates oracletypes.DenomOracleExchangeRatePairs) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+
+	if votePeriod >= o.onChainRates.votePeriod {
+		o.onChainRates = onChainRates{
+			votePeriod: votePeriod,
+			rates:      rates,
+		}
+	}
+}
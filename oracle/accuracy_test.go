@@ -0,0 +1,71 @@
+package oracle
+
+import (
+	"testing"
+
+	oracletypes "github.com/kiichain/kiichain/v3/x/oracle/types"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func onChainRate(denom, rate string, lastUpdate int64) oracletypes.DenomOracleExchangeRate {
+	return oracletypes.DenomOracleExchangeRate{
+		Denom: denom,
+		OracleExchangeRate: &oracletypes.OracleExchangeRate{
+			ExchangeRate: math.LegacyMustNewDecFromStr(rate),
+			LastUpdate:   math.NewInt(lastUpdate),
+		},
+	}
+}
+
+func TestComputeVoteAccuracy(t *testing.T) {
+	vote := votedRates{
+		blockHeight: 10,
+		votePeriod:  2,
+		rates: sdk.NewDecCoins(
+			sdk.NewDecCoinFromDec("ubtc", math.LegacyMustNewDecFromStr("100")),
+			sdk.NewDecCoinFromDec("ueth", math.LegacyMustNewDecFromStr("10")),
+			sdk.NewDecCoinFromDec("usol", math.LegacyMustNewDecFromStr("5")),
+		),
+	}
+
+	onChainRates := oracletypes.DenomOracleExchangeRatePairs{
+		onChainRate("ubtc", "100.5", 14), // within the reward band
+		onChainRate("ueth", "11", 14),    // outside the reward band
+		onChainRate("usol", "5", 8),      // not updated after the vote
+		onChainRate("uatom", "4.21", 14), // not voted
+	}
+
+	records := computeVoteAccuracy(vote, onChainRates, math.LegacyMustNewDecFromStr("0.02"))
+	require.Len(t, records, 2)
+
+	btc := records["ubtc"]
+	require.Equal(t, int64(10), btc.BlockHeight)
+	require.Equal(t, uint64(2), btc.VotePeriod)
+	require.Equal(t, math.LegacyMustNewDecFromStr("100"), btc.VotedRate)
+	require.Equal(t, math.LegacyMustNewDecFromStr("100.5"), btc.OnChainRate)
+	require.Equal(t, math.LegacyMustNewDecFromStr("0.004975124378109453"), btc.Deviation)
+	require.True(t, btc.WithinRewardBand)
+
+	eth := records["ueth"]
+	require.Equal(t, math.LegacyMustNewDecFromStr("0.090909090909090909"), eth.Deviation)
+	require.False(t, eth.WithinRewardBand)
+}
+
+func TestGetVoteAccuracy(t *testing.T) {
+	o := &Oracle{
+		voteAccuracy: map[string][]VoteAccuracy{
+			"ubtc": {{BlockHeight: 10}, {BlockHeight: 12}},
+		},
+	}
+
+	accuracy := o.GetVoteAccuracy()
+	require.Len(t, accuracy["ubtc"], 2)
+
+	// changing the copy must not change the oracle history
+	accuracy["ubtc"][0].BlockHeight = 1
+	require.Equal(t, int64(10), o.voteAccuracy["ubtc"][0].BlockHeight)
+}
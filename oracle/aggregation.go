@@ -0,0 +1,179 @@
+package oracle
+
+import (
+	sdkmath "cosmossdk.io/math"
+
+	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle/provider"
+	"github.com/kiichain/price-feeder/oracle/types"
+)
+
+// Aggregation defines how the prices of the providers are combined into the
+// price of an asset.
+type Aggregation struct {
+	Method        string // aggregation method, the tvwap of the candles when empty
+	OutlierFilter string // filter of the deviating providers, stddev when empty
+}
+
+// getAggregations returns the aggregations set on the pairs, by base.
+func getAggregations(currencyPairs []config.CurrencyPair) map[string]Aggregation {
+	aggregations := make(map[string]Aggregation)
+	for _, pair := range currencyPairs {
+		aggregation := aggregations[pair.Base]
+		if len(pair.Aggregation) > 0 {
+			aggregation.Method = pair.Aggregation
+		}
+		if len(pair.OutlierFilter) > 0 {
+			aggregation.OutlierFilter = pair.OutlierFilter
+		}
+		if aggregation != (Aggregation{}) {
+			aggregations[pair.Base] = aggregation
+		}
+	}
+
+	return aggregations
+}
+
+// getTickerBases returns the bases priced with the VWAP of the tickers, when
+// an asset is aggregated with it or a required asset has no candle price: the
+// bases aggregated with it and the bases without a candle price. It returns
+// nil when the tickers aren't needed.
+func getTickerBases(
+	candlePrices map[string]sdkmath.LegacyDec,
+	providerPairs map[string][]types.CurrencyPair,
+	aggregations map[string]Aggregation,
+	requiredRates map[string]struct{},
+) map[string]struct{} {
+	tickerBases := make(map[string]struct{})
+	for base, aggregation := range aggregations {
+		if aggregation.Method == config.AggregationVWAP {
+			tickerBases[base] = struct{}{}
+		}
+	}
+
+	missingRequired := false
+	for base := range requiredRates {
+		if _, ok := candlePrices[base]; !ok {
+			missingRequired = true
+		}
+	}
+	if len(tickerBases) == 0 && !missingRequired {
+		return nil
+	}
+
+	for _, pairs := range providerPairs {
+		for _, pair := range pairs {
+			if _, ok := candlePrices[pair.Base]; !ok {
+				tickerBases[pair.Base] = struct{}{}
+			}
+		}
+	}
+
+	return tickerBases
+}
+
+// getCandleTickers returns the time weighted price and volume of the candles
+// of each provider, as on the TVWAP.
+func getCandleTickers(candles provider.AggregatedProviderCandles) provider.AggregatedProviderPrices {
+	now, timePeriod := tvwapTimeWindow()
+
+	tickers := make(provider.AggregatedProviderPrices)
+	for providerName, providerCandles := range candles {
+		for base, cp := range providerCandles {
+			weightedPrice, volume := tvwapSums(cp, now, timePeriod)
+			if volume.IsZero() {
+				continue
+			}
+
+			if _, ok := tickers[providerName]; !ok {
+				tickers[providerName] = make(map[string]provider.TickerPrice)
+			}
+			tickers[providerName][base] = provider.TickerPrice{
+				Price:  weightedPrice.Quo(volume),
+				Volume: volume,
+			}
+		}
+	}
+
+	return tickers
+}
+
+// ComputeMedians computes the median of the prices of the providers for each
+// asset, weighted by their volume when weighted is set.
+func ComputeMedians(prices provider.AggregatedProviderPrices, weighted bool) map[string]sdkmath.LegacyDec {
+	var (
+		priceSlice  = make(map[string][]sdkmath.LegacyDec)
+		volumeSlice = make(map[string][]sdkmath.LegacyDec)
+	)
+
+	for _, providerPrices := range prices {
+		for base, tp := range providerPrices {
+			priceSlice[base] = append(priceSlice[base], tp.Price)
+			volumeSlice[base] = append(volumeSlice[base], tp.Volume)
+		}
+	}
+
+	medians := make(map[string]sdkmath.LegacyDec, len(priceSlice))
+	for base, ps := range priceSlice {
+		if weighted {
+			medians[base] = weightedMedian(ps, volumeSlice[base])
+		} else {
+			medians[base] = median(ps)
+		}
+	}
+
+	return medians
+}
+
+// aggregatePrices replaces the prices of the assets aggregated with a median
+// of the providers and returns the computation method of each asset. The
+// assets aggregated with the VWAP of the tickers are removed from the TVWAP
+// of the candles.
+func aggregatePrices(
+	prices map[string]sdkmath.LegacyDec,
+	providerPrices provider.AggregatedProviderPrices,
+	aggregations map[string]Aggregation,
+	defaultMethod string,
+) map[string]string {
+	medians := ComputeMedians(providerPrices, false)
+	weightedMedians := ComputeMedians(providerPrices, true)
+
+	methods := make(map[string]string, len(prices))
+	for base := range prices {
+		switch aggregations[base].Method {
+		case config.AggregationMedian:
+			if price, ok := medians[base]; ok {
+				prices[base] = price
+				methods[base] = ComputationMethodMedian
+				continue
+			}
+
+		case config.AggregationWeightedMedian:
+			if price, ok := weightedMedians[base]; ok {
+				prices[base] = price
+				methods[base] = ComputationMethodWeightedMedian
+				continue
+			}
+
+		case config.AggregationVWAP:
+			if defaultMethod == ComputationMethodTVWAP {
+				delete(prices, base)
+				continue
+			}
+		}
+
+		methods[base] = defaultMethod
+	}
+
+	return methods
+}
+
+// getBasesByMethod groups the bases by computation method.
+func getBasesByMethod(bases []string, methods map[string]string) map[string][]string {
+	basesByMethod := make(map[string][]string)
+	for _, base := range bases {
+		basesByMethod[methods[base]] = append(basesByMethod[methods[base]], base)
+	}
+
+	return basesByMethod
+}
@@ -0,0 +1,187 @@
+package oracle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/math"
+
+	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle/provider"
+	"github.com/kiichain/price-feeder/oracle/types"
+)
+
+func TestGetAggregations(t *testing.T) {
+	aggregations := getAggregations([]config.CurrencyPair{
+		{Base: "ATOM", Quote: "USDT", Aggregation: config.AggregationMedian},
+		{Base: "ATOM", Quote: "USD", OutlierFilter: config.OutlierFilterMAD},
+		{Base: "BTC", Quote: "USD"},
+	})
+
+	require.Equal(t, map[string]Aggregation{
+		"ATOM": {Method: config.AggregationMedian, OutlierFilter: config.OutlierFilterMAD},
+	}, aggregations)
+}
+
+func TestGetComputedPricesWithAggregations(t *testing.T) {
+	pair := types.CurrencyPair{Base: "ATOM", Quote: "USD"}
+	providerPairs := map[string][]types.CurrencyPair{
+		config.ProviderBinance: {pair},
+		config.ProviderKraken:  {pair},
+		config.ProviderOkx:     {pair},
+	}
+	providerPrices := provider.AggregatedProviderPrices{
+		config.ProviderBinance: {pair.Base: {Price: math.LegacyNewDec(10), Volume: math.LegacyNewDec(1)}},
+		config.ProviderKraken:  {pair.Base: {Price: math.LegacyNewDec(11), Volume: math.LegacyNewDec(1)}},
+		config.ProviderOkx:     {pair.Base: {Price: math.LegacyNewDec(12), Volume: math.LegacyNewDec(10)}},
+	}
+	providerCandles := provider.AggregatedProviderCandles{
+		config.ProviderBinance: {pair.Base: {{
+			Price:     math.LegacyNewDec(13),
+			Volume:    math.LegacyNewDec(1),
+			TimeStamp: provider.PastUnixTime(1 * time.Minute),
+		}}},
+	}
+	deviations := map[string]math.LegacyDec{pair.Base: math.LegacyNewDec(2)}
+
+	testCases := map[string]struct {
+		candles  provider.AggregatedProviderCandles
+		method   string
+		expected math.LegacyDec
+		computed string
+	}{
+		"median": {
+			method:   config.AggregationMedian,
+			expected: math.LegacyNewDec(11),
+			computed: ComputationMethodMedian,
+		},
+		"weighted median": {
+			method:   config.AggregationWeightedMedian,
+			expected: math.LegacyNewDec(12),
+			computed: ComputationMethodWeightedMedian,
+		},
+		"vwap": {
+			method:   config.AggregationVWAP,
+			expected: math.LegacyMustNewDecFromStr("11.75"),
+			computed: ComputationMethodVWAP,
+		},
+		"vwap over candles": {
+			candles:  providerCandles,
+			method:   config.AggregationVWAP,
+			expected: math.LegacyMustNewDecFromStr("11.75"),
+			computed: ComputationMethodVWAP,
+		},
+		"median of candles": {
+			candles:  providerCandles,
+			method:   config.AggregationMedian,
+			expected: math.LegacyNewDec(13),
+			computed: ComputationMethodMedian,
+		},
+		"tvwap": {
+			candles:  providerCandles,
+			expected: math.LegacyNewDec(13),
+			computed: ComputationMethodTVWAP,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			candles := tc.candles
+			if candles == nil {
+				candles = make(provider.AggregatedProviderCandles)
+			}
+
+			prices, breakdown, err := GetComputedPricesWithBreakdown(
+				zerolog.Nop(),
+				candles,
+				providerPrices,
+				providerPairs,
+				deviations,
+				map[string]Aggregation{pair.Base: {Method: tc.method}},
+				CandleFreshness{},
+				map[string]struct{}{pair.Base: {}},
+			)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, prices[pair.Base])
+			require.Equal(t, tc.computed, breakdown[pair.Base].Method)
+			require.Equal(t, tc.expected, breakdown[pair.Base].Price)
+		})
+	}
+}
+
+func TestGetComputedPricesWithTickerFailure(t *testing.T) {
+	atomPair := types.CurrencyPair{Base: "ATOM", Quote: "USD"}
+	ethPair := types.CurrencyPair{Base: "ETH", Quote: "USD"}
+	btcPair := types.CurrencyPair{Base: "BTC", Quote: "EUR"}
+	eurPair := types.CurrencyPair{Base: "EUR", Quote: "USD"}
+	providerPairs := map[string][]types.CurrencyPair{
+		config.ProviderBinance: {atomPair},
+		config.ProviderKraken:  {ethPair},
+		config.ProviderOkx:     {btcPair},
+		config.ProviderHuobi:   {eurPair},
+	}
+	providerPrices := provider.AggregatedProviderPrices{
+		config.ProviderBinance: {"ATOM": {Price: math.LegacyNewDec(10), Volume: math.LegacyNewDec(1)}},
+		config.ProviderKraken:  {"ETH": {Price: math.LegacyNewDec(2000), Volume: math.LegacyNewDec(1)}},
+		config.ProviderOkx:     {"BTC": {Price: math.LegacyNewDec(50000), Volume: math.LegacyNewDec(1)}},
+	}
+	candle := func(price int64) []provider.CandlePrice {
+		return []provider.CandlePrice{{
+			Price:     math.LegacyNewDec(price),
+			Volume:    math.LegacyNewDec(1),
+			TimeStamp: provider.PastUnixTime(1 * time.Minute),
+		}}
+	}
+	aggregations := map[string]Aggregation{"ETH": {Method: config.AggregationVWAP}}
+	requiredRates := map[string]struct{}{"ATOM": {}, "BTC": {}, "ETH": {}}
+
+	// the EUR rate is only on the candles, and BTC, priced with its candles,
+	// doesn't need to convert its ticker
+	prices, _, err := GetComputedPricesWithBreakdown(
+		zerolog.Nop(),
+		provider.AggregatedProviderCandles{
+			config.ProviderBinance: {"ATOM": candle(13)},
+			config.ProviderOkx:     {"BTC": candle(50000)},
+			config.ProviderHuobi:   {"EUR": candle(2)},
+		},
+		providerPrices,
+		providerPairs,
+		make(map[string]math.LegacyDec),
+		aggregations,
+		CandleFreshness{},
+		requiredRates,
+	)
+	require.NoError(t, err)
+	require.Equal(t, map[string]math.LegacyDec{
+		"ATOM": math.LegacyNewDec(13),
+		"BTC":  math.LegacyNewDec(100000),
+		"ETH":  math.LegacyNewDec(2000),
+		"EUR":  math.LegacyNewDec(2),
+	}, prices)
+
+	// the failed conversion of ETH keeps the prices of the candles
+	providerPairs[config.ProviderKraken] = []types.CurrencyPair{{Base: "ETH", Quote: "EUR"}}
+	prices, _, err = GetComputedPricesWithBreakdown(
+		zerolog.Nop(),
+		provider.AggregatedProviderCandles{
+			config.ProviderBinance: {"ATOM": candle(13)},
+			config.ProviderOkx:     {"BTC": candle(50000)},
+			config.ProviderHuobi:   {"EUR": candle(2)},
+		},
+		providerPrices,
+		providerPairs,
+		make(map[string]math.LegacyDec),
+		aggregations,
+		CandleFreshness{},
+		requiredRates,
+	)
+	require.NoError(t, err)
+	require.Equal(t, map[string]math.LegacyDec{
+		"ATOM": math.LegacyNewDec(13),
+		"BTC":  math.LegacyNewDec(100000),
+		"EUR":  math.LegacyNewDec(2),
+	}, prices)
+}
@@ -0,0 +1,187 @@
+package oracle
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/go-metrics"
+	oracletypes "github.com/kiichain/kiichain/v3/x/oracle/types"
+
+	sdkmath "cosmossdk.io/math"
+
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/kiichain/price-feeder/config"
+)
+
+const (
+	// circuit breaker actions on a price moving more than its maximum change
+	circuitBreakerHeld      = "held"
+	circuitBreakerConfirmed = "confirmed"
+
+	// circuitBreakerQueryTimeout bounds the query of the on-chain rates when
+	// they weren't fetched by the vote accuracy check, as it delays the vote
+	circuitBreakerQueryTimeout = 2 * time.Second
+)
+
+// CircuitBreaker defines the largest move of the price of an asset between
+// vote periods before it is held back from the vote.
+type CircuitBreaker struct {
+	MaxChange     sdkmath.LegacyDec // largest change relative to the reference rate
+	Confirmations int               // providers above the minimum to vote a larger move, held back when zero
+}
+
+// priceJump defines a rate moving more than the maximum change of its
+// circuit breaker.
+type priceJump struct {
+	denom     string
+	rate      sdkmath.LegacyDec
+	reference sdkmath.LegacyDec
+	change    sdkmath.LegacyDec // relative to the reference rate
+	providers int               // providers agreeing on the rate
+	action    string
+}
+
+// checkPriceJumps compares the rates with their reference rates, by chain
+// denom, and returns the rates to vote with the jumps found. A rate moving
+// more than the maximum change of its circuit breaker is held back, unless
+// enough providers agree on it. Rates without reference are kept.
+func checkPriceJumps(
+	rates sdk.DecCoins,
+	references map[string]sdkmath.LegacyDec,
+	circuitBreakers map[string]CircuitBreaker,
+	providers map[string]int,
+) (sdk.DecCoins, []priceJump) {
+	checkedRates := sdk.NewDecCoins()
+	jumps := []priceJump{}
+
+	for _, rate := range rates {
+		circuitBreaker, ok := circuitBreakers[rate.Denom]
+		reference, hasReference := references[rate.Denom]
+		if !ok || !hasReference || !reference.IsPositive() {
+			checkedRates = checkedRates.Add(rate)
+			continue
+		}
+
+		change := rate.Amount.Sub(reference).Abs().Quo(reference)
+		if change.LTE(circuitBreaker.MaxChange) {
+			checkedRates = checkedRates.Add(rate)
+			continue
+		}
+
+		jump := priceJump{
+			denom:     rate.Denom,
+			rate:      rate.Amount,
+			reference: reference,
+			change:    change,
+			providers: providers[rate.Denom],
+			action:    circuitBreakerHeld,
+		}
+		if circuitBreaker.Confirmations > 0 && jump.providers >= config.MinimumProviders+circuitBreaker.Confirmations {
+			jump.action = circuitBreakerConfirmed
+			checkedRates = checkedRates.Add(rate)
+		}
+		jumps = append(jumps, jump)
+	}
+
+	return checkedRates, jumps
+}
+
+// applyCircuitBreakers holds back the rates moving more than the maximum
+// change of their circuit breaker since the previous vote period. The rates
+// are compared with the last vote when it was on the previous vote period,
+// or else with the on-chain rates.
+func (o *Oracle) applyCircuitBreakers(
+	ctx context.Context,
+	rates sdk.DecCoins,
+	lastVote *votedRates,
+	votePeriod float64,
+) sdk.DecCoins {
+	o.mtx.RLock()
+	circuitBreakers := make(map[string]CircuitBreaker, len(o.circuitBreakers))
+	providers := make(map[string]int, len(o.circuitBreakers))
+	for base, circuitBreaker := range o.circuitBreakers {
+		chainDenom, ok := o.chainDenomMapping[base]
+		if !ok {
+			continue
+		}
+		circuitBreakers[chainDenom] = circuitBreaker
+
+		// the providers left after the outlier filter agree on the rate
+		for _, source := range o.priceBreakdown[base].Sources {
+			if !source.Filtered {
+				providers[chainDenom]++
+			}
+		}
+	}
+	o.mtx.RUnlock()
+
+	if len(circuitBreakers) == 0 {
+		return rates
+	}
+
+	references := make(map[string]sdkmath.LegacyDec, len(circuitBreakers))
+	if lastVote != nil && lastVote.votePeriod+1 == uint64(votePeriod) {
+		for _, rate := range lastVote.rates {
+			if _, ok := circuitBreakers[rate.Denom]; ok {
+				references[rate.Denom] = rate.Amount
+			}
+		}
+	}
+
+	// only use the on-chain rates for the denoms missing from the last vote
+	if len(references) < len(circuitBreakers) {
+		for _, onChainRate := range o.getOnChainRates(ctx, uint64(votePeriod)) {
+			if _, ok := circuitBreakers[onChainRate.Denom]; !ok || onChainRate.OracleExchangeRate == nil {
+				continue
+			}
+			if _, ok := references[onChainRate.Denom]; !ok {
+				references[onChainRate.Denom] = onChainRate.OracleExchangeRate.ExchangeRate
+			}
+		}
+	}
+
+	checkedRates, jumps := checkPriceJumps(rates, references, circuitBreakers, providers)
+	for _, jump := range jumps {
+		telemetry.IncrCounterWithLabels([]string{"vote", "price_jump"}, 1, []metrics.Label{
+			{Name: "denom", Value: jump.denom},
+			{Name: "action", Value: jump.action},
+		})
+		o.logger.Warn().
+			Str("denom", jump.denom).
+			Str("rate", jump.rate.String()).
+			Str("reference", jump.reference.String()).
+			Str("change", jump.change.String()).
+			Int("providers", jump.providers).
+			Str("action", jump.action).
+			Msg("rate moved more than the maximum change of its circuit breaker")
+	}
+
+	return checkedRates
+}
+
+// getOnChainRates returns the on-chain rates stored by the vote accuracy
+// check on the vote period, or else queries them with a short timeout and
+// stores them, so the rates of a previous vote period are never reused.
+func (o *Oracle) getOnChainRates(ctx context.Context, votePeriod uint64) oracletypes.DenomOracleExchangeRatePairs {
+	o.mtx.RLock()
+	cached := o.onChainRates
+	o.mtx.RUnlock()
+
+	if cached.rates != nil && cached.votePeriod == votePeriod {
+		return cached.rates
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, circuitBreakerQueryTimeout)
+	defer cancel()
+
+	rates, err := o.GetExchangeRates(ctx)
+	if err != nil {
+		o.logger.Warn().Err(err).Msg("failed to query on-chain exchange rates for the circuit breakers")
+		return nil
+	}
+	o.setOnChainRates(votePeriod, rates)
+
+	return rates
+}
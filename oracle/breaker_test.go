@@ -0,0 +1,125 @@
+package oracle
+
+import (
+	"context"
+	"testing"
+
+	oracletypes "github.com/kiichain/kiichain/v3/x/oracle/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/kiichain/price-feeder/config"
+)
+
+func TestCheckPriceJumps(t *testing.T) {
+	rates := sdk.NewDecCoins(
+		sdk.NewDecCoinFromDec("ubtc", math.LegacyMustNewDecFromStr("105")),
+		sdk.NewDecCoinFromDec("ueth", math.LegacyMustNewDecFromStr("5")),
+		sdk.NewDecCoinFromDec("usol", math.LegacyMustNewDecFromStr("20")),
+		sdk.NewDecCoinFromDec("uatom", math.LegacyMustNewDecFromStr("8")),
+		sdk.NewDecCoinFromDec("ukii", math.LegacyMustNewDecFromStr("2")),
+	)
+	references := map[string]math.LegacyDec{
+		"ubtc":  math.LegacyNewDec(100),
+		"ueth":  math.LegacyNewDec(10),
+		"usol":  math.LegacyNewDec(10),
+		"uatom": math.LegacyNewDec(4),
+	}
+	maxChange := math.LegacyMustNewDecFromStr("0.1")
+	circuitBreakers := map[string]CircuitBreaker{
+		"ubtc":  {MaxChange: maxChange},
+		"ueth":  {MaxChange: maxChange},
+		"usol":  {MaxChange: maxChange, Confirmations: 2},
+		"uatom": {MaxChange: maxChange, Confirmations: 2},
+		"ukii":  {MaxChange: maxChange},
+	}
+	providers := map[string]int{
+		"usol":  config.MinimumProviders + 2,
+		"uatom": config.MinimumProviders + 1,
+	}
+
+	checkedRates, jumps := checkPriceJumps(rates, references, circuitBreakers, providers)
+
+	// btc is within the max change, eth is held back, sol has enough providers
+	// to confirm its jump while atom hasn't, and kii has no reference
+	require.Equal(t, sdk.NewDecCoins(
+		sdk.NewDecCoinFromDec("ubtc", math.LegacyMustNewDecFromStr("105")),
+		sdk.NewDecCoinFromDec("usol", math.LegacyMustNewDecFromStr("20")),
+		sdk.NewDecCoinFromDec("ukii", math.LegacyMustNewDecFromStr("2")),
+	), checkedRates)
+
+	require.Len(t, jumps, 3)
+	actions := make(map[string]string, len(jumps))
+	for _, jump := range jumps {
+		actions[jump.denom] = jump.action
+	}
+	require.Equal(t, map[string]string{
+		"ueth":  circuitBreakerHeld,
+		"usol":  circuitBreakerConfirmed,
+		"uatom": circuitBreakerHeld,
+	}, actions)
+}
+
+func TestApplyCircuitBreakers(t *testing.T) {
+	o := &Oracle{
+		logger:            zerolog.Nop(),
+		chainDenomMapping: map[string]string{"BTC": "ubtc", "ETH": "ueth"},
+		circuitBreakers: map[string]CircuitBreaker{
+			"BTC": {MaxChange: math.LegacyMustNewDecFromStr("0.1"), Confirmations: 1},
+			"ETH": {MaxChange: math.LegacyMustNewDecFromStr("0.1"), Confirmations: 1},
+		},
+		priceBreakdown: PriceBreakdown{
+			"BTC": {Sources: map[string]PriceSource{
+				config.ProviderBinance: {},
+				config.ProviderKraken:  {},
+				config.ProviderOkx:     {},
+				config.ProviderHuobi:   {Filtered: true},
+			}},
+		},
+	}
+	rates := sdk.NewDecCoins(
+		sdk.NewDecCoinFromDec("ubtc", math.LegacyNewDec(50)),
+		sdk.NewDecCoinFromDec("ueth", math.LegacyNewDec(10)),
+	)
+	lastVote := &votedRates{
+		votePeriod: 4,
+		rates: sdk.NewDecCoins(
+			sdk.NewDecCoinFromDec("ubtc", math.LegacyNewDec(100)),
+			sdk.NewDecCoinFromDec("ueth", math.LegacyNewDec(10)),
+		),
+	}
+
+	// btc halved since the last vote and only three providers agree on it
+	checkedRates := o.applyCircuitBreakers(context.Background(), rates, lastVote, 5)
+	require.Equal(t, sdk.NewDecCoins(sdk.NewDecCoinFromDec("ueth", math.LegacyNewDec(10))), checkedRates)
+
+	// a fourth provider confirms the move
+	o.priceBreakdown["BTC"].Sources[config.ProviderHuobi] = PriceSource{}
+	checkedRates = o.applyCircuitBreakers(context.Background(), rates, lastVote, 5)
+	require.Equal(t, rates, checkedRates)
+
+	// without a last vote on the previous vote period, the on-chain rates
+	// stored by the vote accuracy check are used
+	o.priceBreakdown["BTC"].Sources[config.ProviderHuobi] = PriceSource{Filtered: true}
+	o.onChainRates = onChainRates{
+		votePeriod: 5,
+		rates:      oracletypes.DenomOracleExchangeRatePairs{onChainRate("ubtc", "100", 10)},
+	}
+	checkedRates = o.applyCircuitBreakers(context.Background(), rates, nil, 5)
+	require.Equal(t, sdk.NewDecCoins(sdk.NewDecCoinFromDec("ueth", math.LegacyNewDec(10))), checkedRates)
+
+	// the rates stored on a previous vote period are stale, and the rates
+	// can't be queried so they are kept
+	o.onChainRates.votePeriod = 4
+	checkedRates = o.applyCircuitBreakers(context.Background(), rates, nil, 5)
+	require.Equal(t, rates, checkedRates)
+
+	// without circuit breakers the rates are kept
+	o.circuitBreakers = nil
+	checkedRates = o.applyCircuitBreakers(context.Background(), rates, nil, 5)
+	require.Equal(t, rates, checkedRates)
+}
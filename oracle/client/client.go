@@ -246,16 +246,8 @@ func (oc OracleClient) BroadcastTx(
 // CreateClientContext creates an SDK client Context instance used for transaction
 // generation, signing and broadcasting.
 func (oc OracleClient) CreateClientContext() (client.Context, error) {
-	// get keyring password from selected input
-	var keyringInput io.Reader
-	if len(oc.KeyringPass) > 0 {
-		keyringInput = newPassReader(oc.KeyringPass)
-	} else {
-		keyringInput = os.Stdin
-	}
-
 	// create a new keyring
-	kr, err := keyring.New("kiichain", oc.KeyringBackend, oc.KeyringDir, keyringInput, oc.Encoding.Marshaler, evmkeyring.Option())
+	kr, err := NewKeyring(oc.KeyringBackend, oc.KeyringDir, oc.KeyringPass)
 	if err != nil {
 		return client.Context{}, err
 	}
@@ -308,6 +300,20 @@ func (oc OracleClient) CreateClientContext() (client.Context, error) {
 	return clientCtx, nil
 }
 
+// NewKeyring opens the keyring used to sign the transactions, reading the
+// password from stdin when it isn't given.
+func NewKeyring(backend, dir, pass string) (keyring.Keyring, error) {
+	// get keyring password from selected input
+	var keyringInput io.Reader
+	if len(pass) > 0 {
+		keyringInput = newPassReader(pass)
+	} else {
+		keyringInput = os.Stdin
+	}
+
+	return keyring.New("kiichain", backend, dir, keyringInput, encodingConfig.Marshaler, evmkeyring.Option())
+}
+
 // CreateTxFactory creates an SDK Factory instance used for transaction
 // generation, signing and broadcasting.
 func (oc OracleClient) CreateTxFactory() (tx.Factory, error) {
@@ -2,6 +2,7 @@ package client
 
 import (
 	kiiparams "github.com/kiichain/kiichain/v3/app/params"
+	oracletypes "github.com/kiichain/kiichain/v3/x/oracle/types"
 
 	cryptocodec "github.com/cosmos/cosmos-sdk/crypto/codec"
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
@@ -17,6 +18,9 @@ func init() {
 	// Register cosmos-sdk interfaces
 	authtypes.RegisterInterfaces(encodingConfig.InterfaceRegistry)
 
+	// Register the oracle messages, so the transactions can be encoded as JSON
+	oracletypes.RegisterInterfaces(encodingConfig.InterfaceRegistry)
+
 	// Register the pubkey for EVM and Cosmos interface
 	evmcryptocodec.RegisterInterfaces(encodingConfig.InterfaceRegistry)
 	cryptocodec.RegisterInterfaces(encodingConfig.InterfaceRegistry)
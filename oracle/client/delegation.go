@@ -0,0 +1,80 @@
+package client
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// NewDelegationClient creates an OracleClient which signs the transactions with
+// the validator owner account, used to delegate the feeder consent. Unlike
+// NewOracleClient, it doesn't track the chain height.
+func NewDelegationClient(
+	logger zerolog.Logger,
+	chainID string,
+	keyringBackend string,
+	keyringDir string,
+	keyringPass string,
+	tmRPC string,
+	rpcTimeout time.Duration,
+	validatorAddrString string,
+	grpcEndpoint string,
+	gasAdjustment float64,
+	gasPrices string,
+	gasLimit uint64,
+) (OracleClient, error) {
+	// the validator owner account signs the delegation
+	validatorAddr, err := sdk.ValAddressFromBech32(validatorAddrString)
+	if err != nil {
+		return OracleClient{}, err
+	}
+	ownerAddr := sdk.AccAddress(validatorAddr)
+
+	return OracleClient{
+		Logger:              logger.With().Str("module", "oracle_client").Logger(),
+		ChainID:             chainID,
+		KeyringBackend:      keyringBackend,
+		KeyringDir:          keyringDir,
+		KeyringPass:         keyringPass,
+		TMRPC:               tmRPC,
+		RPCTimeout:          rpcTimeout,
+		OracleAddr:          ownerAddr,
+		OracleAddrString:    ownerAddr.String(),
+		ValidatorAddr:       validatorAddr,
+		ValidatorAddrString: validatorAddrString,
+		Encoding:            encodingConfig,
+		GasAdjustment:       gasAdjustment,
+		GRPCEndpoint:        grpcEndpoint,
+		GasPrices:           gasPrices,
+		GasLimit:            gasLimit,
+	}, nil
+}
+
+// GenerateUnsignedTx builds the transaction with the given set of messages,
+// the fees and the gas limit, returning its JSON without signing it. It
+// needs neither the keyring nor the chain.
+func (oc OracleClient) GenerateUnsignedTx(msgs ...sdk.Msg) ([]byte, error) {
+	gasPrices, err := sdk.ParseDecCoins(oc.GasPrices)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gas prices: %w", err)
+	}
+
+	// Initialize the tx builder
+	txBuilder := oc.Encoding.TxConfig.NewTxBuilder()
+	err = txBuilder.SetMsgs(msgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Calculate the fee for the TX and set the gas limit
+	fees, _ := gasPrices.MulDec(math.LegacyNewDec(int64(oc.GasLimit))).TruncateDecimal()
+	txBuilder.SetFeeAmount(fees)
+	txBuilder.SetGasLimit(oc.GasLimit)
+
+	return oc.Encoding.TxConfig.TxJSONEncoder()(txBuilder.GetTx())
+}
@@ -0,0 +1,204 @@
+package oracle
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"cosmossdk.io/math"
+
+	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle/provider"
+	"github.com/kiichain/price-feeder/oracle/types"
+)
+
+// conversionEdge defines the rate of a base on a quote, aggregated over the
+// providers of the pair.
+type conversionEdge struct {
+	quote     string
+	rate      math.LegacyDec // price of the base on the quote
+	volume    math.LegacyDec // base volume of the providers
+	providers int
+}
+
+// conversionGraph defines the conversion edges of the pairs, by base.
+type conversionGraph map[string][]conversionEdge
+
+// ConversionRoute defines a path of pairs from a quote to USD.
+type ConversionRoute struct {
+	Path      []string       `json:"path"`      // from the quote to USD, ex. [ETH USDT USD]
+	Rate      math.LegacyDec `json:"rate"`      // USD rate of the quote through the path
+	Liquidity math.LegacyDec `json:"liquidity"` // lowest USD volume of the hops
+	Providers int            `json:"providers"` // fewest providers of the hops
+}
+
+// Conversion defines the USD rate of a quote, combining the rates of its
+// routes weighted by their liquidity.
+type Conversion struct {
+	Rate   math.LegacyDec
+	Path   []string // path of the most liquid route
+	Routes []ConversionRoute
+}
+
+// getConversionProviders returns the providers of the pairs reachable from
+// the non-USD quotes, by base and quote. These are the pairs used to convert
+// the quotes to USD.
+func getConversionProviders(providerPairs map[string][]types.CurrencyPair) map[string]map[string]map[string]struct{} {
+	allProviders := make(map[string]map[string]map[string]struct{})
+	quotes := []string{}
+	for providerName, pairs := range providerPairs {
+		for _, pair := range pairs {
+			base, quote := pair.Base, pair.Quote
+			if _, ok := allProviders[base]; !ok {
+				allProviders[base] = make(map[string]map[string]struct{})
+			}
+			if _, ok := allProviders[base][quote]; !ok {
+				allProviders[base][quote] = make(map[string]struct{})
+			}
+			allProviders[base][quote][providerName] = struct{}{}
+
+			if strings.ToUpper(quote) != config.DenomUSD {
+				quotes = append(quotes, quote)
+			}
+		}
+	}
+
+	// keep the pairs of the denoms reachable from the quotes
+	conversionProviders := make(map[string]map[string]map[string]struct{})
+	for len(quotes) > 0 {
+		denom := quotes[0]
+		quotes = quotes[1:]
+		if _, ok := conversionProviders[denom]; ok || strings.ToUpper(denom) == config.DenomUSD {
+			continue
+		}
+
+		conversionProviders[denom] = allProviders[denom]
+		for quote := range allProviders[denom] {
+			quotes = append(quotes, quote)
+		}
+	}
+
+	return conversionProviders
+}
+
+// add stores the edge of the base on the quote, using the volume of the
+// provider prices.
+func (g conversionGraph) add(base, quote string, rate math.LegacyDec, prices provider.AggregatedProviderPrices) {
+	edge := conversionEdge{
+		quote:  quote,
+		rate:   rate,
+		volume: math.LegacyZeroDec(),
+	}
+	for _, providerPrices := range prices {
+		if tp, ok := providerPrices[base]; ok {
+			edge.volume = edge.volume.Add(tp.Volume)
+			edge.providers++
+		}
+	}
+
+	g[base] = append(g[base], edge)
+}
+
+// routes returns the paths of at most maxHops pairs from the denom to USD,
+// from the most liquid one.
+func (g conversionGraph) routes(denom string, maxHops int) []ConversionRoute {
+	routes := []ConversionRoute{}
+
+	var walk func(path []string, edges []conversionEdge)
+	walk = func(path []string, edges []conversionEdge) {
+		last := path[len(path)-1]
+		if strings.ToUpper(last) == config.DenomUSD {
+			routes = append(routes, newConversionRoute(path, edges))
+			return
+		}
+		if len(edges) == maxHops {
+			return
+		}
+
+		for _, edge := range g[last] {
+			if containsDenom(path, edge.quote) {
+				continue
+			}
+			walk(append(path[:len(path):len(path)], edge.quote), append(edges[:len(edges):len(edges)], edge))
+		}
+	}
+	walk([]string{denom}, nil)
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		if !routes[i].Liquidity.Equal(routes[j].Liquidity) {
+			return routes[i].Liquidity.GT(routes[j].Liquidity)
+		}
+		if routes[i].Providers != routes[j].Providers {
+			return routes[i].Providers > routes[j].Providers
+		}
+		if len(routes[i].Path) != len(routes[j].Path) {
+			return len(routes[i].Path) < len(routes[j].Path)
+		}
+		return strings.Join(routes[i].Path, "/") < strings.Join(routes[j].Path, "/")
+	})
+
+	return routes
+}
+
+// convert returns the USD rate of the denom, the average of the rates of its
+// routes weighted by their liquidity, or the rate of the most reliable route
+// when none has a volume.
+func (g conversionGraph) convert(denom string, maxHops int) (Conversion, error) {
+	routes := g.routes(denom, maxHops)
+	if len(routes) == 0 {
+		return Conversion{}, fmt.Errorf("there are no valid conversion rates for %s", denom)
+	}
+
+	weightedRate := math.LegacyZeroDec()
+	totalLiquidity := math.LegacyZeroDec()
+	for _, route := range routes {
+		weightedRate = weightedRate.Add(route.Rate.Mul(route.Liquidity))
+		totalLiquidity = totalLiquidity.Add(route.Liquidity)
+	}
+
+	conversion := Conversion{
+		Rate:   routes[0].Rate,
+		Path:   routes[0].Path,
+		Routes: routes,
+	}
+	if totalLiquidity.IsPositive() {
+		conversion.Rate = weightedRate.Quo(totalLiquidity)
+	}
+
+	return conversion, nil
+}
+
+// newConversionRoute returns the route through the edges of the path. The
+// liquidity of each hop is its base volume at the USD rate of its base.
+func newConversionRoute(path []string, edges []conversionEdge) ConversionRoute {
+	route := ConversionRoute{
+		Path: path,
+		Rate: math.LegacyOneDec(),
+	}
+
+	// walk back from USD, so the rate is the USD rate of the hop base
+	for i := len(edges) - 1; i >= 0; i-- {
+		route.Rate = route.Rate.Mul(edges[i].rate)
+
+		liquidity := edges[i].volume.Mul(route.Rate)
+		if i == len(edges)-1 || liquidity.LT(route.Liquidity) {
+			route.Liquidity = liquidity
+		}
+		if i == len(edges)-1 || edges[i].providers < route.Providers {
+			route.Providers = edges[i].providers
+		}
+	}
+
+	return route
+}
+
+// containsDenom returns true if the path already goes through the denom.
+func containsDenom(path []string, denom string) bool {
+	for _, d := range path {
+		if d == denom {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,74 @@
+package oracle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/math"
+
+	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle/provider"
+)
+
+func TestConversionGraph(t *testing.T) {
+	volume := func(base string, volumes map[string]int64) provider.AggregatedProviderPrices {
+		prices := make(provider.AggregatedProviderPrices)
+		for providerName, v := range volumes {
+			prices[providerName] = map[string]provider.TickerPrice{base: {Volume: math.LegacyNewDec(v)}}
+		}
+		return prices
+	}
+
+	graph := make(conversionGraph)
+	graph.add("USDT", "USD", math.LegacyOneDec(), volume("USDT", map[string]int64{
+		config.ProviderKraken: 1000,
+		config.ProviderGate:   1000,
+	}))
+	graph.add("USDT", "EUR", math.LegacyMustNewDecFromStr("0.9"), volume("USDT", map[string]int64{
+		config.ProviderBitstamp: 1000,
+	}))
+	graph.add("EUR", "USD", math.LegacyMustNewDecFromStr("1.1"), volume("EUR", map[string]int64{
+		"ecbfx": 1,
+	}))
+	graph.add("EUR", "USDT", math.LegacyMustNewDecFromStr("1.1"), volume("EUR", map[string]int64{
+		config.ProviderKraken: 500,
+	}))
+
+	// the routes going back through USDT are skipped
+	routes := graph.routes("USDT", config.MaxConversionHops)
+	require.Len(t, routes, 2)
+	require.Equal(t, ConversionRoute{
+		Path:      []string{"USDT", "USD"},
+		Rate:      math.LegacyOneDec(),
+		Liquidity: math.LegacyNewDec(2000),
+		Providers: 2,
+	}, routes[0])
+
+	// the fx hop has the lowest liquidity of the route, 1 EUR at 1.1 USD
+	require.Equal(t, []string{"USDT", "EUR", "USD"}, routes[1].Path)
+	require.Equal(t, math.LegacyMustNewDecFromStr("0.99"), routes[1].Rate)
+	require.Equal(t, math.LegacyMustNewDecFromStr("1.1"), routes[1].Liquidity)
+	require.Equal(t, 1, routes[1].Providers)
+
+	conversion, err := graph.convert("USDT", config.MaxConversionHops)
+	require.NoError(t, err)
+	require.Equal(t, []string{"USDT", "USD"}, conversion.Path)
+	require.Equal(
+		t,
+		math.LegacyMustNewDecFromStr("2001.089").Quo(math.LegacyMustNewDecFromStr("2001.1")),
+		conversion.Rate,
+	)
+
+	// EUR→USDT→USD is more liquid than the direct fx rate
+	conversion, err = graph.convert("EUR", config.MaxConversionHops)
+	require.NoError(t, err)
+	require.Equal(t, []string{"EUR", "USDT", "USD"}, conversion.Path)
+	require.Len(t, conversion.Routes, 2)
+
+	// a single hop only keeps the direct route
+	require.Len(t, graph.routes("EUR", 1), 1)
+
+	_, err = graph.convert("ETH", config.MaxConversionHops)
+	require.EqualError(t, err, "there are no valid conversion rates for ETH")
+}
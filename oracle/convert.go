@@ -1,7 +1,7 @@
 package oracle
 
 import (
-	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/rs/zerolog"
@@ -13,28 +13,10 @@ import (
 	"github.com/kiichain/price-feeder/oracle/types"
 )
 
-// getUSDBasedProviders retrieves which providers for an asset have a USD-based pair,
-// given the asset and the map of providers to currency pairs.
-func getUSDBasedProviders(asset string, providerPairs map[string][]types.CurrencyPair) (map[string]struct{}, error) {
-	conversionProviders := make(map[string]struct{})
-
-	for provider, pairs := range providerPairs {
-		for _, pair := range pairs {
-			if strings.ToUpper(pair.Quote) == config.DenomUSD && strings.ToUpper(pair.Base) == asset {
-				conversionProviders[provider] = struct{}{}
-			}
-		}
-	}
-	if len(conversionProviders) == 0 {
-		return nil, fmt.Errorf("no providers have a usd conversion for this asset")
-	}
-
-	return conversionProviders, nil
-}
-
 // ConvertCandlesToUSD converts any candles which are not quoted in USD
-// to USD by other price feeds. It will also filter out any candles not
-// within the deviation threshold set by the config.
+// to USD by other price feeds, through the routes of the conversion graph.
+// It will also filter out any candles not within the deviation threshold set
+// by the config, and returns the conversion of each quote.
 //
 // Ref: https://github.com/umee-network/umee/blob/4348c3e433df8c37dd98a690e96fc275de609bc1/price-feeder/oracle/filter.go#L41
 func convertCandlesToUSD(
@@ -42,83 +24,79 @@ func convertCandlesToUSD(
 	candles provider.AggregatedProviderCandles,
 	providerPairs map[string][]types.CurrencyPair,
 	deviationThresholds map[string]math.LegacyDec,
-) (provider.AggregatedProviderCandles, error) {
+	aggregations map[string]Aggregation,
+) (provider.AggregatedProviderCandles, map[string]Conversion, error) {
 	if len(candles) == 0 {
-		return candles, nil
+		return candles, nil, nil
 	}
 
-	conversionRates := make(map[string]math.LegacyDec)
-	requiredConversions := make(map[string]types.CurrencyPair)
-
-	for pairProviderName, pairs := range providerPairs {
-		for _, pair := range pairs {
-			if strings.ToUpper(pair.Quote) != config.DenomUSD {
-				// Get valid providers and use them to generate a USD-based price for this asset.
-				validProviders, err := getUSDBasedProviders(pair.Quote, providerPairs)
-				if err != nil {
-					return nil, err
-				}
-
-				// Find candles which we can use for conversion, and calculate the tvwap
-				// to find the conversion rate.
-				validCandleList := provider.AggregatedProviderCandles{}
-				for providerName, candleSet := range candles {
-					if _, ok := validProviders[providerName]; ok {
-						for base, candle := range candleSet {
-							if base == pair.Quote {
-								if _, ok := validCandleList[providerName]; !ok {
-									validCandleList[providerName] = make(map[string][]provider.CandlePrice)
-								}
-
-								validCandleList[providerName][base] = candle
-							}
-						}
-					}
-				}
-
-				if len(validCandleList) == 0 {
-					return nil, fmt.Errorf("there are no valid conversion rates for %s", pair.Quote)
+	// Find the candles of each pair which we can use for conversion, and
+	// calculate the tvwap to find the rate of the pair.
+	graph := make(conversionGraph)
+	for base, quotes := range getConversionProviders(providerPairs) {
+		for quote, providers := range quotes {
+			validCandleList := provider.AggregatedProviderCandles{}
+			for providerName := range providers {
+				if candle, ok := candles[providerName][base]; ok {
+					validCandleList[providerName] = map[string][]provider.CandlePrice{base: candle}
 				}
+			}
+			if len(validCandleList) == 0 {
+				continue
+			}
 
-				filteredCandles, err := FilterCandleDeviations(
-					logger,
-					validCandleList,
-					deviationThresholds,
-				)
-				if err != nil {
-					return nil, err
-				}
+			filteredCandles, err := FilterCandleDeviations(
+				logger,
+				validCandleList,
+				deviationThresholds,
+				aggregations,
+			)
+			if err != nil {
+				return nil, nil, err
+			}
 
-				tvwap, err := ComputeTVWAP(filteredCandles)
-				if err != nil {
-					return nil, err
-				}
+			tvwap, err := ComputeTVWAP(filteredCandles)
+			if err != nil {
+				return nil, nil, err
+			}
 
-				conversionRates[pair.Quote] = tvwap[pair.Quote]
-				requiredConversions[pairProviderName] = pair
+			if rate, ok := tvwap[base]; ok {
+				graph.add(base, quote, rate, getCandleTickers(filteredCandles))
 			}
 		}
 	}
 
+	conversions, err := getConversions(logger, graph, providerPairs)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// Convert assets to USD.
-	for provider, assetMap := range candles {
-		for asset, assetCandles := range assetMap {
-			if requiredConversions[provider].Base == asset {
-				for i := range assetCandles {
-					assetCandles[i].Price = assetCandles[i].Price.Mul(
-						conversionRates[requiredConversions[provider].Quote],
-					)
-				}
+	for providerName, assetMap := range candles {
+		for _, pair := range providerPairs[providerName] {
+			conversion, ok := conversions[pair.Quote]
+			if !ok {
+				continue
+			}
+			assetCandles, ok := assetMap[pair.Base]
+			if !ok {
+				continue
+			}
+			for i := range assetCandles {
+				assetCandles[i].Price = assetCandles[i].Price.Mul(conversion.Rate)
 			}
 		}
 	}
 
-	return candles, nil
+	return candles, conversions, nil
 }
 
 // convertTickersToUSD converts any tickers which are not quoted in USD to USD,
-// using the conversion rates of other tickers. It will also filter out any tickers
-// not within the deviation threshold set by the config.
+// using the conversion rates of other tickers through the routes of the
+// conversion graph. It will also filter out any tickers not within the
+// deviation threshold set by the config, and returns the conversion of each
+// quote. Only the tickers of the given bases are converted and returned, or
+// all of them when the bases are nil.
 //
 // Ref: https://github.com/umee-network/umee/blob/4348c3e433df8c37dd98a690e96fc275de609bc1/price-feeder/oracle/filter.go#L41
 func convertTickersToUSD(
@@ -126,78 +104,158 @@ func convertTickersToUSD(
 	tickers provider.AggregatedProviderPrices,
 	providerPairs map[string][]types.CurrencyPair,
 	deviationThresholds map[string]math.LegacyDec,
-) (provider.AggregatedProviderPrices, error) {
+	aggregations map[string]Aggregation,
+	bases map[string]struct{},
+) (provider.AggregatedProviderPrices, map[string]Conversion, error) {
 	if len(tickers) == 0 {
-		return tickers, nil
+		return tickers, nil, nil
 	}
 
-	conversionRates := make(map[string]math.LegacyDec)
-	requiredConversions := make(map[string]types.CurrencyPair)
-
-	for pairProviderName, pairs := range providerPairs {
-		for _, pair := range pairs {
-			if strings.ToUpper(pair.Quote) != config.DenomUSD {
-				// Get valid providers and use them to generate a USD-based price for this asset.
-				validProviders, err := getUSDBasedProviders(pair.Quote, providerPairs)
-				if err != nil {
-					return nil, err
+	// Find the tickers of each pair which we can use for conversion, and
+	// calculate the vwap to find the rate of the pair.
+	graph := make(conversionGraph)
+	for base, quotes := range getConversionProviders(providerPairs) {
+		for quote, providers := range quotes {
+			validTickerList := provider.AggregatedProviderPrices{}
+			for providerName := range providers {
+				if ticker, ok := tickers[providerName][base]; ok {
+					validTickerList[providerName] = map[string]provider.TickerPrice{base: ticker}
 				}
+			}
+			if len(validTickerList) == 0 {
+				continue
+			}
 
-				// Find valid candles, and then let's re-compute the tvwap.
-				validTickerList := provider.AggregatedProviderPrices{}
-				for providerName, candleSet := range tickers {
-					// Find tickers which we can use for conversion, and calculate the vwap
-					// to find the conversion rate.
-					if _, ok := validProviders[providerName]; ok {
-						for base, ticker := range candleSet {
-							if base == pair.Quote {
-								if _, ok := validTickerList[providerName]; !ok {
-									validTickerList[providerName] = make(map[string]provider.TickerPrice)
-								}
-
-								validTickerList[providerName][base] = ticker
-							}
-						}
-					}
-				}
+			filteredTickers, err := FilterTickerDeviations(
+				logger,
+				validTickerList,
+				deviationThresholds,
+				aggregations,
+			)
+			if err != nil {
+				return nil, nil, err
+			}
 
-				if len(validTickerList) == 0 {
-					return nil, fmt.Errorf("there are no valid conversion rates for %s", pair.Quote)
-				}
+			vwap, err := ComputeVWAP(filteredTickers)
+			if err != nil {
+				return nil, nil, err
+			}
 
-				filteredTickers, err := FilterTickerDeviations(
-					logger,
-					validTickerList,
-					deviationThresholds,
-				)
-				if err != nil {
-					return nil, err
-				}
+			if rate, ok := vwap[base]; ok {
+				graph.add(base, quote, rate, filteredTickers)
+			}
+		}
+	}
 
-				vwap, err := ComputeVWAP(filteredTickers)
-				if err != nil {
-					return nil, err
-				}
+	// the rates of every pair are on the graph, but only the quotes of the
+	// given bases must be converted
+	convertedPairs := providerPairs
+	if bases != nil {
+		convertedPairs = getPairsOfBases(providerPairs, bases)
+		tickers = getTickersOfBases(tickers, bases)
+	}
+
+	conversions, err := getConversions(logger, graph, convertedPairs)
+	if err != nil {
+		return nil, nil, err
+	}
 
-				conversionRates[pair.Quote] = vwap[pair.Quote]
-				requiredConversions[pairProviderName] = pair
+	// Convert assets to USD.
+	for providerName, assetMap := range tickers {
+		for _, pair := range convertedPairs[providerName] {
+			conversion, ok := conversions[pair.Quote]
+			if !ok {
+				continue
+			}
+			ticker, ok := assetMap[pair.Base]
+			if !ok {
+				continue
+			}
+			assetMap[pair.Base] = provider.TickerPrice{
+				Price:  ticker.Price.Mul(conversion.Rate),
+				Volume: ticker.Volume,
 			}
 		}
 	}
 
-	// Convert assets to USD.
+	return tickers, conversions, nil
+}
+
+// getPairsOfBases returns the pairs of each provider with one of the bases
+func getPairsOfBases(
+	providerPairs map[string][]types.CurrencyPair,
+	bases map[string]struct{},
+) map[string][]types.CurrencyPair {
+	pairsOfBases := make(map[string][]types.CurrencyPair)
+	for providerName, pairs := range providerPairs {
+		for _, pair := range pairs {
+			if _, ok := bases[pair.Base]; ok {
+				pairsOfBases[providerName] = append(pairsOfBases[providerName], pair)
+			}
+		}
+	}
+
+	return pairsOfBases
+}
+
+// getTickersOfBases returns the tickers of each provider for the bases
+func getTickersOfBases(
+	tickers provider.AggregatedProviderPrices,
+	bases map[string]struct{},
+) provider.AggregatedProviderPrices {
+	tickersOfBases := make(provider.AggregatedProviderPrices)
 	for providerName, assetMap := range tickers {
-		for asset := range assetMap {
-			if requiredConversions[providerName].Base == asset {
-				assetMap[asset] = provider.TickerPrice{
-					Price: assetMap[asset].Price.Mul(
-						conversionRates[requiredConversions[providerName].Quote],
-					),
-					Volume: assetMap[asset].Volume,
-				}
+		for base, ticker := range assetMap {
+			if _, ok := bases[base]; !ok {
+				continue
 			}
+			if _, ok := tickersOfBases[providerName]; !ok {
+				tickersOfBases[providerName] = make(map[string]provider.TickerPrice)
+			}
+			tickersOfBases[providerName][base] = ticker
 		}
 	}
 
-	return tickers, nil
+	return tickersOfBases
+}
+
+// getConversions returns the conversion to USD of the non-USD quotes of the
+// pairs, through the routes of the graph.
+func getConversions(
+	logger zerolog.Logger,
+	graph conversionGraph,
+	providerPairs map[string][]types.CurrencyPair,
+) (map[string]Conversion, error) {
+	// convert the quotes in order, so the same quote fails first
+	quoteSet := make(map[string]struct{})
+	for _, pairs := range providerPairs {
+		for _, pair := range pairs {
+			if strings.ToUpper(pair.Quote) != config.DenomUSD {
+				quoteSet[pair.Quote] = struct{}{}
+			}
+		}
+	}
+	quotes := make([]string, 0, len(quoteSet))
+	for quote := range quoteSet {
+		quotes = append(quotes, quote)
+	}
+	sort.Strings(quotes)
+
+	conversions := make(map[string]Conversion, len(quotes))
+	for _, quote := range quotes {
+		conversion, err := graph.convert(quote, config.MaxConversionHops)
+		if err != nil {
+			return nil, err
+		}
+		conversions[quote] = conversion
+
+		logger.Debug().
+			Str("quote", quote).
+			Str("rate", conversion.Rate.String()).
+			Str("path", strings.Join(conversion.Path, "→")).
+			Int("routes", len(conversion.Routes)).
+			Msg("converted quote to USD")
+	}
+
+	return conversions, nil
 }
@@ -30,50 +30,19 @@ var (
 	}
 )
 
-func TestGetUSDBasedProviders(t *testing.T) {
-	providerPairs := make(map[string][]types.CurrencyPair, 3)
-	providerPairs["coinbase"] = []types.CurrencyPair{
-		{
-			Base:  "FOO",
-			Quote: "USD",
-		},
-	}
-	providerPairs["huobi"] = []types.CurrencyPair{
-		{
-			Base:  "FOO",
-			Quote: "USD",
-		},
-	}
-	providerPairs["kraken"] = []types.CurrencyPair{
-		{
-			Base:  "FOO",
-			Quote: "USDT",
-		},
-	}
-	providerPairs["binance"] = []types.CurrencyPair{
-		{
-			Base:  "USDT",
-			Quote: "USD",
-		},
-	}
-
-	pairs, err := getUSDBasedProviders("FOO", providerPairs)
-	require.NoError(t, err)
-	expectedPairs := map[string]struct{}{
-		"coinbase": {},
-		"huobi":    {},
-	}
-	require.Equal(t, pairs, expectedPairs)
-
-	pairs, err = getUSDBasedProviders("USDT", providerPairs)
-	require.NoError(t, err)
-	expectedPairs = map[string]struct{}{
-		"binance": {},
+func TestGetConversionProviders(t *testing.T) {
+	providerPairs := map[string][]types.CurrencyPair{
+		config.ProviderCoinbase: {{Base: "FOO", Quote: "ETH"}},
+		config.ProviderHuobi:    {{Base: "FOO", Quote: "USD"}, {Base: "ETH", Quote: "USDT"}},
+		config.ProviderKraken:   {{Base: "ETH", Quote: "USDT"}, {Base: "BAR", Quote: "USD"}},
+		config.ProviderBinance:  {{Base: "USDT", Quote: "USD"}},
 	}
-	require.Equal(t, pairs, expectedPairs)
 
-	_, err = getUSDBasedProviders("BAR", providerPairs)
-	require.Error(t, err)
+	// only the pairs reachable from the ETH quote are kept
+	require.Equal(t, map[string]map[string]map[string]struct{}{
+		"ETH":  {"USDT": {config.ProviderHuobi: {}, config.ProviderKraken: {}}},
+		"USDT": {"USD": {config.ProviderBinance: {}}},
+	}, getConversionProviders(providerPairs))
 }
 
 func TestConvertCandlesToUSD(t *testing.T) {
@@ -102,11 +71,12 @@ func TestConvertCandlesToUSD(t *testing.T) {
 		config.ProviderKraken:  {usdtPair},
 	}
 
-	convertedCandles, err := convertCandlesToUSD(
+	convertedCandles, _, err := convertCandlesToUSD(
 		zerolog.Nop(),
 		providerCandles,
 		providerPairs,
 		make(map[string]math.LegacyDec),
+		nil,
 	)
 	require.NoError(t, err)
 
@@ -163,11 +133,12 @@ func TestConvertCandlesToUSDFiltering(t *testing.T) {
 		config.ProviderOkx:     {usdtPair},
 	}
 
-	convertedCandles, err := convertCandlesToUSD(
+	convertedCandles, _, err := convertCandlesToUSD(
 		zerolog.Nop(),
 		providerCandles,
 		providerPairs,
 		make(map[string]math.LegacyDec),
+		nil,
 	)
 	require.NoError(t, err)
 
@@ -202,11 +173,13 @@ func TestConvertTickersToUSD(t *testing.T) {
 		config.ProviderKraken:  {usdtPair},
 	}
 
-	convertedTickers, err := convertTickersToUSD(
+	convertedTickers, _, err := convertTickersToUSD(
 		zerolog.Nop(),
 		providerPrices,
 		providerPairs,
 		make(map[string]math.LegacyDec),
+		nil,
+		nil,
 	)
 	require.NoError(t, err)
 
@@ -256,11 +229,13 @@ func TestConvertTickersToUSDFiltering(t *testing.T) {
 		config.ProviderHuobi:   {usdtPair},
 	}
 
-	covertedDeviation, err := convertTickersToUSD(
+	covertedDeviation, _, err := convertTickersToUSD(
 		zerolog.Nop(),
 		providerPrices,
 		providerPairs,
 		make(map[string]math.LegacyDec),
+		nil,
+		nil,
 	)
 	require.NoError(t, err)
 
@@ -270,3 +245,98 @@ func TestConvertTickersToUSDFiltering(t *testing.T) {
 		covertedDeviation["binance"]["ATOM"].Price,
 	)
 }
+
+func TestConvertTickersToUSD_MultiHop(t *testing.T) {
+	ethPrice := math.LegacyMustNewDecFromStr("2500")
+
+	providerPrices := provider.AggregatedProviderPrices{
+		config.ProviderKraken: {
+			"ATOM": {Price: math.LegacyMustNewDecFromStr("0.004"), Volume: atomVolume},
+		},
+		config.ProviderBinance: {
+			"ETH": {Price: ethPrice, Volume: math.LegacyNewDec(100)},
+		},
+		config.ProviderOkx: {
+			"USDT": {Price: usdtPrice, Volume: usdtVolume},
+		},
+	}
+	providerPairs := map[string][]types.CurrencyPair{
+		config.ProviderKraken:  {{Base: "ATOM", Quote: "ETH"}},
+		config.ProviderBinance: {{Base: "ETH", Quote: "USDT"}},
+		config.ProviderOkx:     {usdtPair},
+	}
+
+	convertedTickers, conversions, err := convertTickersToUSD(
+		zerolog.Nop(),
+		providerPrices,
+		providerPairs,
+		make(map[string]math.LegacyDec),
+		nil,
+		nil,
+	)
+	require.NoError(t, err)
+
+	// ATOM→ETH→USDT→USD
+	require.Equal(t, []string{"ETH", "USDT", "USD"}, conversions["ETH"].Path)
+	require.Equal(t, ethPrice.Mul(usdtPrice), conversions["ETH"].Rate)
+	require.Equal(
+		t,
+		math.LegacyMustNewDecFromStr("0.004").Mul(ethPrice.Mul(usdtPrice)),
+		convertedTickers[config.ProviderKraken]["ATOM"].Price,
+	)
+	require.Equal(t, ethPrice.Mul(usdtPrice), convertedTickers[config.ProviderBinance]["ETH"].Price)
+
+	// without the USDT rate there is no route left, ETH being converted
+	// before USDT
+	delete(providerPrices, config.ProviderOkx)
+	_, _, err = convertTickersToUSD(
+		zerolog.Nop(),
+		providerPrices,
+		providerPairs,
+		make(map[string]math.LegacyDec),
+		nil,
+		nil,
+	)
+	require.EqualError(t, err, "there are no valid conversion rates for ETH")
+}
+
+func TestConvertTickersToUSD_FiatQuotes(t *testing.T) {
+	eurPrice := math.LegacyMustNewDecFromStr("1.08")
+	btcPrice := math.LegacyMustNewDecFromStr("55000")
+
+	providerPrices := provider.AggregatedProviderPrices{
+		config.ProviderKraken: {
+			"ATOM": {Price: atomPrice, Volume: atomVolume},
+			"BTC":  {Price: btcPrice, Volume: math.LegacyOneDec()},
+		},
+		config.ProviderBinance: {
+			"USDT": {Price: usdtPrice, Volume: usdtVolume},
+		},
+		"ecbfx": {
+			"EUR": {Price: eurPrice, Volume: math.LegacyOneDec()},
+		},
+	}
+
+	// the provider quotes a pair in a fiat currency and a pair in a coin
+	providerPairs := map[string][]types.CurrencyPair{
+		config.ProviderKraken: {
+			atomPair,
+			{Base: "BTC", Quote: "EUR"},
+		},
+		config.ProviderBinance: {usdtPair},
+		"ecbfx":                {{Base: "EUR", Quote: "USD"}},
+	}
+
+	convertedTickers, _, err := convertTickersToUSD(
+		zerolog.Nop(),
+		providerPrices,
+		providerPairs,
+		make(map[string]math.LegacyDec),
+		nil,
+		nil,
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, atomPrice.Mul(usdtPrice), convertedTickers[config.ProviderKraken]["ATOM"].Price)
+	require.Equal(t, btcPrice.Mul(eurPrice), convertedTickers[config.ProviderKraken]["BTC"].Price)
+}
@@ -0,0 +1,45 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	oracletypes "github.com/kiichain/kiichain/v3/x/oracle/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// QueryFeederDelegation returns the feeder account delegated by the validator.
+// The validator's own account is returned when there is no delegation.
+func QueryFeederDelegation(ctx context.Context, grpcEndpoint string, validatorAddr string) (string, error) {
+	// create grpc connection with the blockchain
+	grpcConn, err := grpc.NewClient(
+		grpcEndpoint,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(dialerFunc),
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to dial Cosmos gRPC service: %w", err)
+	}
+
+	defer grpcConn.Close()
+
+	// create oracle query client
+	queryClient := oracletypes.NewQueryClient(grpcConn)
+
+	// create context with timeout
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	// query the feeder delegated by the validator
+	queryResponse, err := queryClient.FeederDelegation(
+		ctx,
+		&oracletypes.QueryFeederDelegationRequest{ValidatorAddr: validatorAddr},
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to get x/oracle feeder delegation: %w", err)
+	}
+
+	return queryResponse.FeedAddr, nil
+}
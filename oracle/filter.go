@@ -8,20 +8,31 @@ import (
 
 	"github.com/cosmos/cosmos-sdk/telemetry"
 
+	"github.com/kiichain/price-feeder/config"
 	"github.com/kiichain/price-feeder/oracle/provider"
 )
 
-// defaultDeviationThreshold defines how many 𝜎 a provider can be away
-// from the mean without being considered faulty. This can be overridden
-// in the config.
-var defaultDeviationThreshold = math.LegacyMustNewDecFromStr("1.0")
+var (
+	// defaultDeviationThreshold defines how many 𝜎 a provider can be away
+	// from the mean without being considered faulty. This can be overridden
+	// in the config.
+	defaultDeviationThreshold = math.LegacyMustNewDecFromStr("1.0")
+
+	// madScale scales the median absolute deviation to the 𝜎 of normally
+	// distributed prices, so the thresholds keep their meaning with the MAD
+	// filter.
+	madScale = math.LegacyMustNewDecFromStr("1.4826")
+)
 
 // FilterTickerDeviations finds the standard deviations of the prices of
 // all assets, and filters out any providers that are not within 2𝜎 of the mean.
+// The assets with the MAD filter use the median and the scaled median
+// absolute deviation instead.
 func FilterTickerDeviations(
 	logger zerolog.Logger,
 	prices provider.AggregatedProviderPrices,
 	deviationThresholds map[string]math.LegacyDec,
+	aggregations map[string]Aggregation,
 ) (provider.AggregatedProviderPrices, error) {
 	var (
 		filteredPrices = make(provider.AggregatedProviderPrices)
@@ -39,7 +50,7 @@ func FilterTickerDeviations(
 		}
 	}
 
-	deviations, means, err := StandardDeviation(priceMap)
+	centers, margins, err := deviationBounds(priceMap, deviationThresholds, aggregations)
 	if err != nil {
 		return nil, err
 	}
@@ -49,12 +60,7 @@ func FilterTickerDeviations(
 	// or defaulted to 1.
 	for providerName, priceTickers := range prices {
 		for base, tp := range priceTickers {
-			t := defaultDeviationThreshold
-			if _, ok := deviationThresholds[base]; ok {
-				t = deviationThresholds[base]
-			}
-
-			if d, ok := deviations[base]; !ok || isBetween(tp.Price, means[base], d.Mul(t)) {
+			if m, ok := margins[base]; !ok || isBetween(tp.Price, centers[base], m) {
 				p, ok := filteredPrices[providerName]
 				if !ok {
 					p = map[string]provider.TickerPrice{}
@@ -82,10 +88,13 @@ func FilterTickerDeviations(
 
 // FilterCandleDeviations finds the standard deviations of the tvwaps of
 // all assets, and filters out any providers that are not within 2𝜎 of the mean.
+// The assets with the MAD filter use the median and the scaled median
+// absolute deviation instead.
 func FilterCandleDeviations(
 	logger zerolog.Logger,
 	candles provider.AggregatedProviderCandles,
 	deviationThresholds map[string]math.LegacyDec,
+	aggregations map[string]Aggregation,
 ) (provider.AggregatedProviderCandles, error) {
 	var (
 		filteredCandles = make(provider.AggregatedProviderCandles)
@@ -118,7 +127,7 @@ func FilterCandleDeviations(
 		}
 	}
 
-	deviations, means, err := StandardDeviation(tvwaps)
+	centers, margins, err := deviationBounds(tvwaps, deviationThresholds, aggregations)
 	if err != nil {
 		return nil, err
 	}
@@ -128,12 +137,7 @@ func FilterCandleDeviations(
 	// or defaulted to 1.
 	for providerName, priceMap := range tvwaps {
 		for base, price := range priceMap {
-			t := defaultDeviationThreshold
-			if _, ok := deviationThresholds[base]; ok {
-				t = deviationThresholds[base]
-			}
-
-			if d, ok := deviations[base]; !ok || isBetween(price, means[base], d.Mul(t)) {
+			if m, ok := margins[base]; !ok || isBetween(price, centers[base], m) {
 				p, ok := filteredCandles[providerName]
 				if !ok {
 					p = map[string][]provider.CandlePrice{}
@@ -159,6 +163,47 @@ func FilterCandleDeviations(
 	return filteredCandles, nil
 }
 
+// deviationBounds returns the center and the margin of the accepted prices of
+// each asset, T𝜎 around the mean or T scaled MADs around the median with the
+// MAD filter, unless the MAD is zero. The assets with less than 3 prices have
+// no bounds.
+func deviationBounds(
+	prices map[string]map[string]math.LegacyDec,
+	deviationThresholds map[string]math.LegacyDec,
+	aggregations map[string]Aggregation,
+) (centers, margins map[string]math.LegacyDec, err error) {
+	deviations, means, err := StandardDeviation(prices)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mads, medians, err := MedianAbsoluteDeviation(prices)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	centers = make(map[string]math.LegacyDec, len(deviations))
+	margins = make(map[string]math.LegacyDec, len(deviations))
+	for base, d := range deviations {
+		t := defaultDeviationThreshold
+		if _, ok := deviationThresholds[base]; ok {
+			t = deviationThresholds[base]
+		}
+
+		// a MAD of zero would filter out every price away from the median
+		if aggregations[base].OutlierFilter == config.OutlierFilterMAD && mads[base].IsPositive() {
+			centers[base] = medians[base]
+			margins[base] = mads[base].Mul(madScale).Mul(t)
+			continue
+		}
+
+		centers[base] = means[base]
+		margins[base] = d.Mul(t)
+	}
+
+	return centers, margins, nil
+}
+
 func isBetween(p, mean, margin math.LegacyDec) bool {
 	return p.GTE(mean.Sub(margin)) &&
 		p.LTE(mean.Add(margin))
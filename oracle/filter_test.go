@@ -55,6 +55,7 @@ func TestSuccessFilterCandleDeviations(t *testing.T) {
 		zerolog.Nop(),
 		providerCandles,
 		make(map[string]math.LegacyDec),
+		nil,
 	)
 
 	_, ok := pricesFiltered[config.ProviderCoinbase]
@@ -68,6 +69,7 @@ func TestSuccessFilterCandleDeviations(t *testing.T) {
 		zerolog.Nop(),
 		providerCandles,
 		customDeviations,
+		nil,
 	)
 
 	_, ok = pricesFilteredCustom[config.ProviderCoinbase]
@@ -110,6 +112,7 @@ func TestSuccessFilterTickerDeviations(t *testing.T) {
 		zerolog.Nop(),
 		providerTickers,
 		make(map[string]math.LegacyDec),
+		nil,
 	)
 
 	_, ok := pricesFiltered[config.ProviderCoinbase]
@@ -123,9 +126,44 @@ func TestSuccessFilterTickerDeviations(t *testing.T) {
 		zerolog.Nop(),
 		providerTickers,
 		customDeviations,
+		nil,
 	)
 
 	_, ok = pricesFilteredCustom[config.ProviderCoinbase]
 	require.NoError(t, err, "It should successfully not filter out coinbase")
 	require.True(t, ok, "The filtered candle deviation price of coinbase should remain")
 }
+
+func TestFilterTickerDeviationsMAD(t *testing.T) {
+	atomVolume := math.LegacyMustNewDecFromStr("1994674.34000000")
+	providerTickers := provider.AggregatedProviderPrices{
+		config.ProviderBinance:  {"ATOM": {Price: math.LegacyMustNewDecFromStr("10"), Volume: atomVolume}},
+		config.ProviderKraken:   {"ATOM": {Price: math.LegacyMustNewDecFromStr("10.1"), Volume: atomVolume}},
+		config.ProviderHuobi:    {"ATOM": {Price: math.LegacyMustNewDecFromStr("9.95"), Volume: atomVolume}},
+		config.ProviderCoinbase: {"ATOM": {Price: math.LegacyMustNewDecFromStr("9.7"), Volume: atomVolume}},
+		config.ProviderOkx:      {"ATOM": {Price: math.LegacyMustNewDecFromStr("20"), Volume: atomVolume}},
+	}
+
+	// the outlier widens 𝜎 enough to keep coinbase
+	pricesFiltered, err := FilterTickerDeviations(
+		zerolog.Nop(),
+		providerTickers,
+		make(map[string]math.LegacyDec),
+		nil,
+	)
+	require.NoError(t, err)
+	require.Len(t, pricesFiltered, 4)
+	require.NotContains(t, pricesFiltered, config.ProviderOkx)
+
+	// the median of 10 with a MAD of 0.1 only accepts 10 ± 0.14826
+	pricesFiltered, err = FilterTickerDeviations(
+		zerolog.Nop(),
+		providerTickers,
+		make(map[string]math.LegacyDec),
+		map[string]Aggregation{"ATOM": {OutlierFilter: config.OutlierFilterMAD}},
+	)
+	require.NoError(t, err)
+	require.Len(t, pricesFiltered, 3)
+	require.NotContains(t, pricesFiltered, config.ProviderOkx)
+	require.NotContains(t, pricesFiltered, config.ProviderCoinbase)
+}
@@ -0,0 +1,122 @@
+package oracle
+
+import (
+	"time"
+
+	"github.com/hashicorp/go-metrics"
+	"github.com/rs/zerolog"
+
+	"github.com/kiichain/price-feeder/oracle/provider"
+)
+
+const (
+	// the timestamps of the candles are in milliseconds, those below are in
+	// seconds and those above in microseconds or nanoseconds
+	maxSecondsTimestamp      = int64(1e11)
+	minMicrosecondsTimestamp = int64(1e14)
+	minNanosecondsTimestamp  = int64(1e17)
+)
+
+// CandleFreshness defines how old, or how far in the future, the candles of
+// a provider can be.
+type CandleFreshness struct {
+	MaxAge       time.Duration            // maximum age of the newest candle, tvwap period when zero
+	MaxAges      map[string]time.Duration // maximum age of the newest candle, by base
+	MaxClockSkew time.Duration            // how far in the future a candle can be
+}
+
+// maxAge returns the maximum age of the newest candle of the base.
+func (f CandleFreshness) maxAge(base string) time.Duration {
+	if maxAge, ok := f.MaxAges[base]; ok && maxAge > 0 {
+		return maxAge
+	}
+	if f.MaxAge > 0 {
+		return f.MaxAge
+	}
+
+	return tvwapCandlePeriod
+}
+
+// FilterStaleCandles normalizes the timestamps of the candles to milliseconds
+// and removes the candles of the providers whose newest candle of an asset is
+// older than its maximum age, or further in the future than the maximum clock
+// skew.
+func FilterStaleCandles(
+	logger zerolog.Logger,
+	candles provider.AggregatedProviderCandles,
+	freshness CandleFreshness,
+) provider.AggregatedProviderCandles {
+	now := time.Now()
+	filteredCandles := make(provider.AggregatedProviderCandles)
+
+	for providerName, providerCandles := range candles {
+		for base, cp := range providerCandles {
+			if len(cp) == 0 {
+				continue
+			}
+
+			normalizedCandles := make([]provider.CandlePrice, len(cp))
+			newest := int64(0)
+			for i, candle := range cp {
+				candle.TimeStamp = normalizeTimestamp(candle.TimeStamp)
+				if candle.TimeStamp != cp[i].TimeStamp {
+					logger.Debug().
+						Str("base", base).
+						Str("provider", providerName).
+						Int64("timestamp", cp[i].TimeStamp).
+						Msg("normalized candle timestamp to milliseconds")
+				}
+				if candle.TimeStamp > newest {
+					newest = candle.TimeStamp
+				}
+				normalizedCandles[i] = candle
+			}
+
+			reason := ""
+			switch {
+			case newest > now.Add(freshness.MaxClockSkew).UnixMilli():
+				reason = "clock_skew"
+			case newest < now.Add(-freshness.maxAge(base)).UnixMilli():
+				reason = "stale"
+			}
+
+			if len(reason) > 0 {
+				sendProviderFailureMetric([]string{"failure", "provider"}, 1, []metrics.Label{
+					{Name: "type", Value: "candle"},
+					{Name: "reason", Value: reason},
+					{Name: "base", Value: base},
+					{Name: "provider", Value: providerName},
+				})
+				logger.Warn().
+					Str("base", base).
+					Str("provider", providerName).
+					Str("reason", reason).
+					Time("newest", time.UnixMilli(newest)).
+					Msg("excluding provider candles with an invalid timestamp")
+				continue
+			}
+
+			if _, ok := filteredCandles[providerName]; !ok {
+				filteredCandles[providerName] = make(map[string][]provider.CandlePrice)
+			}
+			filteredCandles[providerName][base] = normalizedCandles
+		}
+	}
+
+	return filteredCandles
+}
+
+// normalizeTimestamp returns in milliseconds a timestamp in seconds,
+// milliseconds, microseconds or nanoseconds.
+func normalizeTimestamp(timeStamp int64) int64 {
+	switch {
+	case timeStamp < maxSecondsTimestamp:
+		return timeStamp * int64(time.Second/time.Millisecond)
+	case timeStamp >= minNanosecondsTimestamp:
+		return timeStamp / int64(time.Millisecond)
+	case timeStamp >= minMicrosecondsTimestamp:
+		return timeStamp / int64(time.Microsecond)
+	}
+
+	return timeStamp
+}
@@ -0,0 +1,66 @@
+package oracle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/math"
+
+	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle/provider"
+)
+
+func TestNormalizeTimestamp(t *testing.T) {
+	now := time.Now()
+
+	require.Equal(t, now.Unix()*1000, normalizeTimestamp(now.Unix()))
+	require.Equal(t, now.UnixMilli(), normalizeTimestamp(now.UnixMilli()))
+	require.Equal(t, now.UnixMilli(), normalizeTimestamp(now.UnixMicro()))
+	require.Equal(t, now.UnixMilli(), normalizeTimestamp(now.UnixNano()))
+}
+
+func TestFilterStaleCandles(t *testing.T) {
+	telemetryMock := resetMockTelemetry()
+
+	candle := func(timeStamp int64) []provider.CandlePrice {
+		return []provider.CandlePrice{{
+			Price:     math.LegacyOneDec(),
+			Volume:    math.LegacyOneDec(),
+			TimeStamp: timeStamp,
+		}}
+	}
+
+	candles := provider.AggregatedProviderCandles{
+		config.ProviderBinance: {
+			"ATOM": candle(provider.PastUnixTime(time.Minute)),
+			"BTC":  candle(provider.PastUnixTime(3 * time.Minute)),
+		},
+		// coinbase sends seconds instead of milliseconds
+		config.ProviderCoinbase: {"ATOM": candle(time.Now().Add(-time.Minute).Unix())},
+		config.ProviderOkx:      {"ATOM": candle(provider.PastUnixTime(10 * time.Minute))},
+		config.ProviderGate:     {"ATOM": candle(time.Now().Add(time.Hour).UnixMilli())},
+	}
+
+	filteredCandles := FilterStaleCandles(zerolog.Nop(), candles, CandleFreshness{
+		MaxAge:       5 * time.Minute,
+		MaxAges:      map[string]time.Duration{"BTC": 2 * time.Minute},
+		MaxClockSkew: 10 * time.Second,
+	})
+
+	require.Len(t, filteredCandles, 2)
+	require.Contains(t, filteredCandles[config.ProviderBinance], "ATOM")
+	require.NotContains(t, filteredCandles[config.ProviderBinance], "BTC")
+	require.Equal(
+		t,
+		candles[config.ProviderCoinbase]["ATOM"][0].TimeStamp*1000,
+		filteredCandles[config.ProviderCoinbase]["ATOM"][0].TimeStamp,
+	)
+
+	require.Equal(t, 3, telemetryMock.Len())
+	telemetryMock.AssertProviderError(t, config.ProviderBinance, "BTC", "stale", "candle")
+	telemetryMock.AssertProviderError(t, config.ProviderOkx, "ATOM", "stale", "candle")
+	telemetryMock.AssertProviderError(t, config.ProviderGate, "ATOM", "clock_skew", "candle")
+}
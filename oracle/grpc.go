@@ -2,14 +2,52 @@ package oracle
 
 import (
 	"context"
+	"fmt"
 	"net"
 	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
 func dialerFunc(_ context.Context, addr string) (net.Conn, error) {
 	return Connect(addr)
 }
 
+// getQueryConn returns the gRPC connection to the chain shared by the
+// queries of the oracle, created on its first use
+func (o *Oracle) getQueryConn() (*grpc.ClientConn, error) {
+	o.queryConnMtx.Lock()
+	defer o.queryConnMtx.Unlock()
+
+	if o.queryConn != nil {
+		return o.queryConn, nil
+	}
+
+	grpcConn, err := grpc.NewClient(
+		o.oracleClient.GRPCEndpoint,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(dialerFunc),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial Cosmos gRPC service: %w", err)
+	}
+	o.queryConn = grpcConn
+
+	return o.queryConn, nil
+}
+
+// closeQueryConn closes the shared gRPC connection, if any
+func (o *Oracle) closeQueryConn() {
+	o.queryConnMtx.Lock()
+	defer o.queryConnMtx.Unlock()
+
+	if o.queryConn != nil {
+		o.queryConn.Close()
+		o.queryConn = nil
+	}
+}
+
 // Connect dials the given address and returns a net.Conn. The protoAddr
 // argument should be prefixed with the protocol,
 // eg. "tcp://127.0.0.1:8080" or "unix:///tmp/test.sock".
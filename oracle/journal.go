@@ -0,0 +1,381 @@
+package oracle
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// defaultJournalLimit is the amount of entries returned by page when no limit is given
+	defaultJournalLimit = 50
+
+	// MaxJournalLimit is the largest amount of entries returned by page
+	MaxJournalLimit = 500
+
+	// maxJournalLineSize is the maximum size of a single journal entry
+	maxJournalLineSize = 4 * 1024 * 1024
+
+	// journalChunkSize is the amount of bytes read at once from the journal
+	journalChunkSize = 64 * 1024
+)
+
+// ErrJournalDisabled is returned when reading the journal while it isn't configured
+var ErrJournalDisabled = errors.New("vote journal is disabled")
+
+// JournalEntry defines a vote attempted by the oracle, with the inputs used
+// to compute it and its outcome.
+type JournalEntry struct {
+	BlockHeight   int64          `json:"block_height"`
+	VotePeriod    uint64         `json:"vote_period"`
+	Timestamp     time.Time      `json:"timestamp"`
+	Feeder        string         `json:"feeder"`
+	Validator     string         `json:"validator"`
+	ExchangeRates string         `json:"exchange_rates"` // final rates voted
+	Breakdown     PriceBreakdown `json:"breakdown"`      // how the prices were computed
+	Shadow        bool           `json:"shadow"`
+	TxHash        string         `json:"tx_hash,omitempty"`
+	ResponseCode  int64          `json:"response_code"` // -1 when the tx wasn't answered
+	Error         string         `json:"error,omitempty"`
+	TickDuration  int64          `json:"tick_duration"` // in milliseconds
+}
+
+// JournalQuery defines the filter and pagination used to read the journal.
+type JournalQuery struct {
+	Height int64 // only entries of this block height, ignored when zero
+	Offset int
+	Limit  int
+}
+
+// Journal is an append-only file which stores every vote attempted by the
+// oracle, one JSON entry per line.
+type Journal struct {
+	mtx   sync.Mutex
+	path  string
+	count journalCount // entries counted so far, so only the new ones are counted
+}
+
+// journalCount defines the amount of entries within the first bytes of the
+// journal.
+type journalCount struct {
+	size    int64
+	entries int
+}
+
+// NewJournal creates the journal file (and its directory) if it doesn't
+// exist. An entry torn by a crash at the end of the journal is dropped.
+func NewJournal(path string) (*Journal, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create journal directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	end, err := journalEnd(file, info.Size())
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+	if end < info.Size() {
+		if err := file.Truncate(end); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to drop torn journal entry: %w", err)
+		}
+	}
+
+	if err := file.Close(); err != nil {
+		return nil, err
+	}
+
+	return &Journal{path: path}, nil
+}
+
+// Path returns the journal file path
+func (j *Journal) Path() string {
+	return j.path
+}
+
+// Append writes the entry at the end of the journal
+func (j *Journal) Append(entry JournalEntry) error {
+	bz, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	j.mtx.Lock()
+	defer j.mtx.Unlock()
+
+	file, err := os.OpenFile(j.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(bz, '\n')); err != nil {
+		return fmt.Errorf("failed to write journal entry: %w", err)
+	}
+
+	return file.Sync()
+}
+
+// Entries returns a page of the journal entries matching the query, from the
+// newest to the oldest, and the total amount of matching entries
+func (j *Journal) Entries(query JournalQuery) ([]JournalEntry, int, error) {
+	j.mtx.Lock()
+	defer j.mtx.Unlock()
+
+	return readJournal(j.path, query, &j.count)
+}
+
+// ReadJournal reads a page of the entries of the journal file matching the query,
+// from the newest to the oldest, and the total amount of matching entries
+func ReadJournal(path string, query JournalQuery) ([]JournalEntry, int, error) {
+	return readJournal(path, query, &journalCount{})
+}
+
+// readJournal reads the page from the end of the journal, decoding only the
+// entries of the page. The entries are counted from the end of the counted
+// bytes, and the entries of a height are contiguous as the heights only
+// increase along the journal. A trailing line without a line break is an
+// entry still being written, or torn by a crash, and is skipped.
+func readJournal(path string, query JournalQuery, count *journalCount) ([]JournalEntry, int, error) {
+	if query.Offset < 0 || query.Limit < 0 || query.Limit > MaxJournalLimit {
+		return nil, 0, fmt.Errorf("invalid journal pagination: offset %d, limit %d", query.Offset, query.Limit)
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultJournalLimit
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read journal: %w", err)
+	}
+	end, err := journalEnd(file, info.Size())
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read journal: %w", err)
+	}
+
+	entries := []JournalEntry{}
+	reader := &journalReader{file: file, offset: end}
+	matches := 0
+	for {
+		line, err := reader.prev()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to read journal: %w", err)
+		}
+
+		if query.Height != 0 {
+			height, err := journalEntryHeight(line)
+			if err != nil {
+				return nil, 0, fmt.Errorf("failed to decode journal entry: %w", err)
+			}
+			if height > query.Height {
+				continue
+			}
+			if height < query.Height {
+				break
+			}
+		}
+
+		if matches >= query.Offset && len(entries) < query.Limit {
+			var entry JournalEntry
+			if err := json.Unmarshal(line, &entry); err != nil {
+				return nil, 0, fmt.Errorf("failed to decode journal entry: %w", err)
+			}
+			entries = append(entries, entry)
+		}
+		matches++
+
+		// without filter the total is the amount of lines
+		if query.Height == 0 && len(entries) == query.Limit {
+			break
+		}
+	}
+
+	if query.Height != 0 {
+		return entries, matches, nil
+	}
+
+	if count.size > end {
+		*count = journalCount{}
+	}
+	newEntries, err := countJournalEntries(file, count.size, end)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read journal: %w", err)
+	}
+	count.size = end
+	count.entries += newEntries
+
+	return entries, count.entries, nil
+}
+
+// journalReader reads the lines of a journal from the end.
+type journalReader struct {
+	file   *os.File
+	offset int64  // start of the bytes read
+	buf    []byte // bytes read before the lines returned
+}
+
+// prev returns the previous non empty line, or io.EOF at the start of the
+// journal.
+func (r *journalReader) prev() ([]byte, error) {
+	for {
+		r.buf = bytes.TrimRight(r.buf, "\n")
+		if i := bytes.LastIndexByte(r.buf, '\n'); i >= 0 {
+			line := r.buf[i+1:]
+			r.buf = r.buf[:i]
+			return line, nil
+		}
+
+		if r.offset == 0 {
+			if len(r.buf) == 0 {
+				return nil, io.EOF
+			}
+			line := r.buf
+			r.buf = nil
+			return line, nil
+		}
+		if len(r.buf) > maxJournalLineSize {
+			return nil, fmt.Errorf("journal entry larger than %d bytes", maxJournalLineSize)
+		}
+
+		// read the previous chunk of the journal
+		size := int64(journalChunkSize)
+		if size > r.offset {
+			size = r.offset
+		}
+		r.offset -= size
+		chunk := make([]byte, size, int(size)+len(r.buf))
+		if _, err := r.file.ReadAt(chunk, r.offset); err != nil {
+			return nil, err
+		}
+		r.buf = append(chunk, r.buf...)
+	}
+}
+
+// journalEnd returns the end of the last line break of the journal, the
+// bytes after it being a torn or unfinished entry.
+func journalEnd(file *os.File, size int64) (int64, error) {
+	chunk := make([]byte, journalChunkSize)
+	for end := size; end > 0; {
+		start := end - journalChunkSize
+		if start < 0 {
+			start = 0
+		}
+		if _, err := file.ReadAt(chunk[:end-start], start); err != nil {
+			return 0, err
+		}
+		if i := bytes.LastIndexByte(chunk[:end-start], '\n'); i >= 0 {
+			return start + int64(i) + 1, nil
+		}
+		end = start
+	}
+
+	return 0, nil
+}
+
+// countJournalEntries counts the entries between two offsets of the journal.
+func countJournalEntries(file *os.File, start, end int64) (int, error) {
+	entries := 0
+	chunk := make([]byte, journalChunkSize)
+	for start < end {
+		size := end - start
+		if size > journalChunkSize {
+			size = journalChunkSize
+		}
+		if _, err := file.ReadAt(chunk[:size], start); err != nil {
+			return 0, err
+		}
+		entries += bytes.Count(chunk[:size], []byte{'\n'})
+		start += size
+	}
+
+	return entries, nil
+}
+
+// journalEntryHeight returns the block height of the entry, read from the
+// start of the line when it begins with it, as it is written.
+func journalEntryHeight(line []byte) (int64, error) {
+	prefix := []byte(`{"block_height":`)
+	if bytes.HasPrefix(line, prefix) {
+		value := line[len(prefix):]
+		if i := bytes.IndexByte(value, ','); i > 0 {
+			if height, err := strconv.ParseInt(string(value[:i]), 10, 64); err == nil {
+				return height, nil
+			}
+		}
+	}
+
+	var entry struct {
+		BlockHeight int64 `json:"block_height"`
+	}
+	if err := json.Unmarshal(line, &entry); err != nil {
+		return 0, err
+	}
+
+	return entry.BlockHeight, nil
+}
+
+// newJournalEntry creates a journal entry with the inputs of the vote
+func (o *Oracle) newJournalEntry(blockHeight int64, votePeriod float64, rates sdk.DecCoins) JournalEntry {
+	return JournalEntry{
+		BlockHeight:   blockHeight,
+		VotePeriod:    uint64(votePeriod),
+		Timestamp:     time.Now().UTC(),
+		Feeder:        o.oracleClient.OracleAddrString,
+		Validator:     o.oracleClient.ValidatorAddrString,
+		ExchangeRates: GenerateExchangeRatesString(rates),
+		Breakdown:     o.GetPriceBreakdown(),
+		Shadow:        o.shadowMode,
+		ResponseCode:  -1,
+	}
+}
+
+// journalVote appends the vote to the journal, if enabled. A failure to write
+// the journal is only logged, it never stops the vote.
+func (o *Oracle) journalVote(entry JournalEntry, startTime time.Time) {
+	if o.journal == nil {
+		return
+	}
+
+	entry.TickDuration = time.Since(startTime).Milliseconds()
+	if err := o.journal.Append(entry); err != nil {
+		o.logger.Warn().Err(err).Int64("height", entry.BlockHeight).Msg("failed to write the vote journal")
+	}
+}
+
+// GetJournalEntries returns a page of the vote journal entries matching the query
+func (o *Oracle) GetJournalEntries(query JournalQuery) ([]JournalEntry, int, error) {
+	if o.journal == nil {
+		return nil, 0, ErrJournalDisabled
+	}
+
+	return o.journal.Entries(query)
+}
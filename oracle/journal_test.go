@@ -0,0 +1,161 @@
+package oracle
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJournal(t *testing.T) {
+	journal, err := NewJournal(filepath.Join(t.TempDir(), "data", "journal.jsonl"))
+	require.NoError(t, err)
+
+	// an empty journal has no entries
+	entries, total, err := journal.Entries(JournalQuery{})
+	require.NoError(t, err)
+	require.Empty(t, entries)
+	require.Equal(t, 0, total)
+
+	for height := int64(1); height <= 5; height++ {
+		require.NoError(t, journal.Append(JournalEntry{
+			BlockHeight:   height,
+			ExchangeRates: "1.000000000000000000ubtc",
+			TxHash:        "0xhash",
+		}))
+	}
+
+	testCases := map[string]struct {
+		query           JournalQuery
+		expectedHeights []int64
+		expectedTotal   int
+		expectedErr     bool
+	}{
+		"Default limit": {
+			query:           JournalQuery{},
+			expectedHeights: []int64{5, 4, 3, 2, 1},
+			expectedTotal:   5,
+		},
+		"First page": {
+			query:           JournalQuery{Limit: 2},
+			expectedHeights: []int64{5, 4},
+			expectedTotal:   5,
+		},
+		"Last page": {
+			query:           JournalQuery{Offset: 4, Limit: 2},
+			expectedHeights: []int64{1},
+			expectedTotal:   5,
+		},
+		"Offset out of range": {
+			query:           JournalQuery{Offset: 10},
+			expectedHeights: []int64{},
+			expectedTotal:   5,
+		},
+		"Filter by height": {
+			query:           JournalQuery{Height: 3},
+			expectedHeights: []int64{3},
+			expectedTotal:   1,
+		},
+		"Invalid pagination": {
+			query:       JournalQuery{Offset: -1},
+			expectedErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			entries, total, err := journal.Entries(tc.query)
+			if tc.expectedErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedTotal, total)
+
+			heights := make([]int64, 0, len(entries))
+			for _, entry := range entries {
+				heights = append(heights, entry.BlockHeight)
+			}
+			require.Equal(t, tc.expectedHeights, heights)
+		})
+	}
+}
+
+func TestJournalTornEntry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	journal, err := NewJournal(path)
+	require.NoError(t, err)
+
+	for height := int64(1); height <= 3; height++ {
+		require.NoError(t, journal.Append(JournalEntry{BlockHeight: height}))
+	}
+
+	// a crash while appending leaves an entry without its line break
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"block_height":4,"vote_per`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	entries, total, err := ReadJournal(path, JournalQuery{})
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	require.Equal(t, 3, total)
+
+	// the torn entry is dropped when the journal is opened again
+	journal, err = NewJournal(path)
+	require.NoError(t, err)
+	require.NoError(t, journal.Append(JournalEntry{BlockHeight: 4}))
+
+	entries, total, err = journal.Entries(JournalQuery{Limit: 1})
+	require.NoError(t, err)
+	require.Equal(t, int64(4), entries[0].BlockHeight)
+	require.Equal(t, 4, total)
+}
+
+func TestJournalChunks(t *testing.T) {
+	journal, err := NewJournal(filepath.Join(t.TempDir(), "journal.jsonl"))
+	require.NoError(t, err)
+
+	// entries larger than the chunks read from the end of the journal
+	for height := int64(1); height <= 10; height++ {
+		for i := 0; i < 2; i++ {
+			require.NoError(t, journal.Append(JournalEntry{
+				BlockHeight:   height,
+				ExchangeRates: strings.Repeat("1", journalChunkSize/3),
+			}))
+		}
+	}
+
+	entries, total, err := journal.Entries(JournalQuery{Offset: 3, Limit: 2})
+	require.NoError(t, err)
+	require.Equal(t, 20, total)
+	require.Equal(t, int64(9), entries[0].BlockHeight)
+	require.Equal(t, int64(8), entries[1].BlockHeight)
+
+	entries, total, err = journal.Entries(JournalQuery{Height: 2})
+	require.NoError(t, err)
+	require.Equal(t, 2, total)
+	require.Len(t, entries, 2)
+
+	// only the new entries are counted
+	require.NoError(t, journal.Append(JournalEntry{BlockHeight: 11}))
+	_, total, err = journal.Entries(JournalQuery{})
+	require.NoError(t, err)
+	require.Equal(t, 21, total)
+
+	// the pages are bounded
+	_, _, err = journal.Entries(JournalQuery{Limit: MaxJournalLimit + 1})
+	require.Error(t, err)
+}
+
+func TestGetJournalEntriesDisabled(t *testing.T) {
+	o := &Oracle{}
+
+	_, _, err := o.GetJournalEntries(JournalQuery{})
+	require.ErrorIs(t, err, ErrJournalDisabled)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
 
 	sdkmath "cosmossdk.io/math"
 
@@ -40,19 +42,43 @@ type Oracle struct {
 	chainDenomMapping  map[string]string // map with the chain-denom by base name
 	previousVotePeriod float64
 	priceProviders     map[string]provider.Provider
-	failedProviders    map[string]error
+	providerCancels    map[string]context.CancelFunc // closes the connections of each provider
+	supervisor         *ProviderSupervisor
 	oracleClient       client.OracleClient
 	deviations         map[string]sdkmath.LegacyDec
+	aggregations       map[string]Aggregation // aggregation of the prices, by base
 	endpoints          map[string]config.ProviderEndpoint
+	genericProviders   map[string]config.GenericProvider // providers declared in the config, by name
+	plugins            map[string]config.Plugin          // providers served by plugins, by name
+	dexPools           map[string][]config.DexPool       // pools quoting the pairs of the DEX providers, by provider
+	fxProviders        map[string]config.FXProvider      // sources of the fiat rates, by name
+	orderBooks         map[string]OrderBookSettings      // order book settings, by base
+	candleFreshness    CandleFreshness                   // maximum age and clock skew of the candles
+	circuitBreakers    map[string]CircuitBreaker         // largest price moves between vote periods, by base
+	shadowMode         bool                              // compute votes without broadcasting them
+	reloads            chan ReloadConfig                 // configurations waiting to be applied
+	pairValidations    chan pairValidationResult         // pair validations waiting to be applied
 
 	// variables store and handle the prices
 	mtx             sync.RWMutex
 	lastPriceSyncTS time.Time
 	prices          map[string]sdkmath.LegacyDec // map with the prices to be requested
+	priceBreakdown  PriceBreakdown               // how the prices were computed, by base
 	paramCache      ParamCache
 	jailCache       JailCache
+	penaltyCache    PenaltyCache
+	pairValidation  PairValidation // pairs dropped after checking the providers
 	healthchecks    map[string]http.Client
+	shadowVotes     []ShadowVote                    // votes recorded on shadow mode
+	journal         *Journal                        // vote journal, nil when disabled
+	lastVote        *votedRates                     // last vote waiting to be compared with on-chain rates
+	onChainRates    onChainRates                    // latest exchange rates queried from the chain
+	voteAccuracy    map[string][]VoteAccuracy       // accuracy history by denom
 	mockSetPrices   func(ctx context.Context) error // used for testing
+
+	// connection shared by the queries to the chain
+	queryConnMtx sync.Mutex
+	queryConn    *grpc.ClientConn
 }
 
 // createMappingsFromPairs is a helper function to initialize maps from currencyPairs
@@ -81,23 +107,62 @@ func createMappingsFromPairs(currencyPairs []config.CurrencyPair) (map[string]st
 	return chainDenomMapping, providerPairs
 }
 
+// addFXPairs adds the fiat quotes of the currency pairs in USD to the pairs of
+// the FX providers with their rates, so the fiat quotes are converted to USD
+// like the other quotes
+func addFXPairs(
+	providerPairs map[string][]types.CurrencyPair,
+	currencyPairs []config.CurrencyPair,
+	fxProviders map[string]config.FXProvider,
+) {
+	for _, pair := range currencyPairs {
+		quote := strings.ToUpper(pair.Quote)
+		if _, ok := config.SupportedFiatQuotes[quote]; !ok {
+			continue
+		}
+
+		fxPair := types.CurrencyPair{Base: quote, Quote: config.DenomUSD}
+		for name, fxProvider := range fxProviders {
+			for _, currency := range fxProvider.Currencies {
+				if strings.ToUpper(currency) == quote && !containsPair(providerPairs[name], fxPair) {
+					providerPairs[name] = append(providerPairs[name], fxPair)
+				}
+			}
+		}
+	}
+}
+
+// containsPair returns whether the pairs contain the pair
+func containsPair(pairs []types.CurrencyPair, pair types.CurrencyPair) bool {
+	for _, p := range pairs {
+		if p == pair {
+			return true
+		}
+	}
+	return false
+}
+
+// Options holds the settings of the oracle, the ones which can change without
+// a restart being applied again on the reloads
+type Options struct {
+	ReloadConfig
+
+	ProviderTimeout time.Duration         // maximum duration of the requests to the providers
+	Healthchecks    []config.Healthchecks // urls pinged after each vote
+	ShadowMode      bool                  // compute votes without broadcasting them
+	Journal         *Journal              // vote journal, nil when disabled
+}
+
 // New creates a new instance of the Oracle struct and
 // extract the currencie pairs per denom
-func New(
-	logger zerolog.Logger,
-	oc client.OracleClient,
-	currencyPairs []config.CurrencyPair,
-	providerTimeout time.Duration,
-	deviations map[string]sdkmath.LegacyDec,
-	endpoints map[string]config.ProviderEndpoint,
-	healthchecksConfig []config.Healthchecks,
-) *Oracle {
+func New(logger zerolog.Logger, oc client.OracleClient, opts Options) *Oracle {
 	// get the currencies and pairs on the registered providers
-	chainDenomMapping, providerPairs := createMappingsFromPairs(currencyPairs)
+	chainDenomMapping, providerPairs := createMappingsFromPairs(opts.CurrencyPairs)
+	addFXPairs(providerPairs, opts.CurrencyPairs, opts.FXProviders)
 
 	// iterate over the health list and check their health
 	healthchecks := make(map[string]http.Client)
-	for _, healthcheck := range healthchecksConfig {
+	for _, healthcheck := range opts.Healthchecks {
 		// get the timeout per provider
 		timeout, err := time.ParseDuration(healthcheck.Timeout)
 
@@ -118,13 +183,28 @@ func New(
 		providerPairs:     providerPairs,
 		chainDenomMapping: chainDenomMapping,
 		priceProviders:    make(map[string]provider.Provider),
-		providerTimeout:   providerTimeout,
-		deviations:        deviations,
+		providerCancels:   make(map[string]context.CancelFunc),
+		providerTimeout:   opts.ProviderTimeout,
+		deviations:        opts.Deviations,
+		aggregations:      getAggregations(opts.CurrencyPairs),
 		paramCache:        ParamCache{},
 		jailCache:         JailCache{},
-		failedProviders:   make(map[string]error),
-		endpoints:         endpoints,
+		penaltyCache:      PenaltyCache{},
+		pairValidation:    newPairValidation(),
+		supervisor:        NewProviderSupervisor(logger, defaultInitialBackoff, defaultMaxBackoff),
+		endpoints:         opts.Endpoints,
+		genericProviders:  opts.GenericProviders,
+		plugins:           opts.Plugins,
+		dexPools:          opts.DexPools,
+		fxProviders:       opts.FXProviders,
+		orderBooks:        opts.OrderBooks,
+		candleFreshness:   opts.CandleFreshness,
+		circuitBreakers:   opts.CircuitBreakers,
 		healthchecks:      healthchecks,
+		shadowMode:        opts.ShadowMode,
+		journal:           opts.Journal,
+		reloads:           make(chan ReloadConfig, 1),
+		pairValidations:   make(chan pairValidationResult),
 	}
 }
 
@@ -136,6 +216,9 @@ func (o *Oracle) Start(ctx context.Context) error {
 		return err
 	}
 
+	// validate the configured pairs against the providers before the first tick
+	o.ValidateProviders(ctx)
+
 	var previousBlockHeight int64
 
 	for {
@@ -144,6 +227,14 @@ func (o *Oracle) Start(ctx context.Context) error {
 		case <-ctx.Done():
 			o.closer.Close()
 
+		// apply the reloaded configuration between ticks
+		case reloadConfig := <-o.reloads:
+			o.applyReload(ctx, reloadConfig)
+
+		// drop the unsupported pairs between ticks
+		case result := <-o.pairValidations:
+			o.applyPairValidation(result)
+
 		default:
 			o.logger.Debug().Msg("starting oracle tick")
 
@@ -179,6 +270,7 @@ func (o *Oracle) Start(ctx context.Context) error {
 func (o *Oracle) Stop() {
 	o.closer.Close()  // stop the close flag channel
 	<-o.closer.Done() // wait until the channel is successfully closed
+	o.closeQueryConn()
 }
 
 // GetLastPriceSyncTimestamp returns the latest timestamp at which prices where
@@ -202,8 +294,12 @@ func (o *Oracle) GetPrices() sdk.DecCoins {
 
 	// iterate over the cached prices
 	for base, price := range o.prices {
-		// get the stored chainDenom
-		chainDenom := o.chainDenomMapping[base]
+		// get the stored chainDenom, the fiat rates only used to convert the
+		// quotes have none
+		chainDenom, ok := o.chainDenomMapping[base]
+		if !ok {
+			continue
+		}
 
 		// Fills in the prices with each value in the oracle
 		prices = prices.Add(sdk.NewDecCoinFromDec(chainDenom, price))
@@ -243,6 +339,9 @@ func reportPriceErrMetrics[V any](providerName string, priceType string, prices
 // to determine prices. If candles are not available, uses the most recent prices
 // with VWAP. Warns the user of any missing prices, and filters out any faulty
 // providers which do not report prices or candles within 2𝜎 of the others.
+// The providers with an order book spread above the limit of an asset are
+// filtered out, and the assets using the mid price are priced with the depth
+// weighted mid of the order books.
 func (o *Oracle) SetPrices(ctx context.Context) error {
 	if o.mockSetPrices != nil {
 		return o.mockSetPrices(ctx)
@@ -252,6 +351,7 @@ func (o *Oracle) SetPrices(ctx context.Context) error {
 	mtx := new(sync.Mutex)
 	providerPrices := make(provider.AggregatedProviderPrices)
 	providerCandles := make(provider.AggregatedProviderCandles)
+	providerOrderBooks := make(provider.AggregatedProviderOrderBooks)
 	requiredRates := make(map[string]struct{})
 
 	// iterate over the pairs by provider
@@ -269,6 +369,12 @@ func (o *Oracle) SetPrices(ctx context.Context) error {
 			continue // don't block everything on one provider having an issue
 		}
 
+		// the unsupported pairs are dropped when the provider is created
+		currencyPairs = o.providerPairs[providerName]
+		if len(currencyPairs) == 0 {
+			continue
+		}
+
 		for _, pair := range currencyPairs {
 			if _, ok := requiredRates[pair.Base]; !ok {
 				if o.paramCache.params.Whitelist.Contains(o.chainDenomMapping[pair.Base]) {
@@ -280,6 +386,7 @@ func (o *Oracle) SetPrices(ctx context.Context) error {
 		group.Go(func() error {
 			prices := make(map[string]provider.TickerPrice, 0)
 			candles := make(map[string][]provider.CandlePrice, 0)
+			orderBooks := make(map[string]provider.OrderBook, 0)
 			ch := make(chan struct{})
 
 			go func() {
@@ -295,6 +402,17 @@ func (o *Oracle) SetPrices(ctx context.Context) error {
 					o.logger.Debug().Err(err).Msg("failed to get candle prices from provider")
 				}
 				reportPriceErrMetrics(providerName, "candle", candles, currencyPairs)
+
+				orderBookProvider, ok := priceProvider.(provider.OrderBookProvider)
+				orderBookPairs := getOrderBookPairs(currencyPairs, o.orderBooks)
+				if !ok || len(orderBookPairs) == 0 {
+					return
+				}
+				orderBooks, err = orderBookProvider.GetOrderBooks(orderBookPairs...)
+				if err != nil {
+					o.logger.Debug().Err(err).Msg("failed to get order books from provider")
+				}
+				reportPriceErrMetrics(providerName, "depth", orderBooks, orderBookPairs)
 			}()
 
 			select {
@@ -328,7 +446,25 @@ func (o *Oracle) SetPrices(ctx context.Context) error {
 				}
 			}
 
+			for _, pair := range currencyPairs {
+				orderBook, ok := orderBooks[pair.String()]
+				if !ok {
+					continue
+				}
+				if err := orderBook.Validate(); err != nil {
+					o.logger.Debug().Err(err).Str("provider", providerName).Str("pair", pair.String()).Msg("invalid order book")
+					continue
+				}
+				if _, ok := providerOrderBooks[providerName]; !ok {
+					providerOrderBooks[providerName] = make(map[string]provider.OrderBook)
+				}
+				providerOrderBooks[providerName][pair.Base] = orderBook
+			}
+
 			mtx.Unlock()
+
+			// a recovering provider is healthy once it returns prices
+			o.supervisor.RecordPrices(providerName)
 			return nil
 		})
 	}
@@ -339,40 +475,93 @@ func (o *Oracle) SetPrices(ctx context.Context) error {
 		o.logger.Error().Err(err).Msg("set-prices errgroup returned an error")
 	}
 
-	computedPrices, err := GetComputedPrices(
+	FilterStaleOrderBooks(o.logger, providerOrderBooks)
+	FilterOrderBookSpreads(o.logger, providerOrderBooks, providerPrices, providerCandles, o.orderBooks)
+
+	computedPrices, breakdown, err := GetComputedPricesWithBreakdown(
 		o.logger,
 		providerCandles,
 		providerPrices,
 		o.providerPairs,
 		o.deviations,
+		o.aggregations,
+		o.candleFreshness,
 		requiredRates,
 	)
 	if err != nil {
 		return err
 	}
 
+	err = computeOrderBookPrices(
+		o.logger,
+		providerOrderBooks,
+		o.providerPairs,
+		o.deviations,
+		o.aggregations,
+		o.orderBooks,
+		computedPrices,
+		breakdown,
+	)
+	if err != nil {
+		return err
+	}
+
 	for base := range requiredRates {
 		if _, ok := computedPrices[base]; !ok {
 			return fmt.Errorf("reported prices were not equal to required rates, missed: %s", base)
 		}
 	}
 
+	o.mtx.Lock()
 	o.prices = computedPrices
+	o.priceBreakdown = breakdown
+	o.mtx.Unlock()
+
 	return nil
 }
 
 // GetComputedPrices gets the candle and ticker prices and computes it.
 // It returns candles' TVWAP if possible, if not possible (not available
 // or due to some staleness) it will use the most recent ticker prices
-// and the VWAP formula instead.
+// and the VWAP formula instead. The assets with an aggregation use the
+// median of the providers, or the VWAP of the tickers even with candles.
 func GetComputedPrices(
 	logger zerolog.Logger,
 	providerCandles provider.AggregatedProviderCandles,
 	providerPrices provider.AggregatedProviderPrices,
 	providerPairs map[string][]types.CurrencyPair,
 	deviations map[string]sdkmath.LegacyDec,
+	aggregations map[string]Aggregation,
+	candleFreshness CandleFreshness,
 	requiredRates map[string]struct{},
 ) (prices map[string]sdkmath.LegacyDec, err error) {
+	prices, _, err = GetComputedPricesWithBreakdown(
+		logger,
+		providerCandles,
+		providerPrices,
+		providerPairs,
+		deviations,
+		aggregations,
+		candleFreshness,
+		requiredRates,
+	)
+	return prices, err
+}
+
+// GetComputedPricesWithBreakdown computes the prices like GetComputedPrices,
+// also returning the price of each provider used on the computation.
+func GetComputedPricesWithBreakdown(
+	logger zerolog.Logger,
+	providerCandles provider.AggregatedProviderCandles,
+	providerPrices provider.AggregatedProviderPrices,
+	providerPairs map[string][]types.CurrencyPair,
+	deviations map[string]sdkmath.LegacyDec,
+	aggregations map[string]Aggregation,
+	candleFreshness CandleFreshness,
+	requiredRates map[string]struct{},
+) (prices map[string]sdkmath.LegacyDec, breakdown PriceBreakdown, err error) {
+	breakdown = make(PriceBreakdown)
+
 	// only do asset provider map logic is log level is debug
 	if logger.GetLevel() == zerolog.DebugLevel {
 		assetProviderMap := make(map[string][]string)
@@ -383,7 +572,7 @@ func GetComputedPrices(
 		}
 		assetProviderJSON, err := json.Marshal(assetProviderMap)
 		if err != nil {
-			return nil, err
+			return nil, breakdown, err
 		}
 		logger.Debug().Msg(fmt.Sprintf("Asset Provider Coverage Map: %s", string(assetProviderJSON)))
 
@@ -395,85 +584,152 @@ func GetComputedPrices(
 		}
 		candleProviderJSON, err := json.Marshal(candleProviderMap)
 		if err != nil {
-			return nil, err
+			return nil, breakdown, err
 		}
 		logger.Debug().Msg(fmt.Sprintf("Candle Provider Coverage Map: %s", string(candleProviderJSON)))
 	}
+	// exclude the candles of the providers with a stale or skewed timestamp
+	providerCandles = FilterStaleCandles(logger, providerCandles, candleFreshness)
+
+	// keep the raw prices, the conversion to USD updates them in place
+	rawCandles := getRawCandleSources(providerCandles, providerPairs)
+	rawTickers := getRawTickerSources(providerPrices, providerPairs)
+
 	// convert any non-USD denominated candles into USD
-	convertedCandles, err := convertCandlesToUSD(
+	convertedCandles, candleConversions, err := convertCandlesToUSD(
 		logger,
 		providerCandles,
 		providerPairs,
 		deviations,
+		aggregations,
 	)
 	if err != nil {
-		return nil, err
+		return nil, breakdown, err
 	}
+	rawCandles.setConversions(candleConversions)
 
 	// filter out any erroneous candles
 	filteredCandles, err := FilterCandleDeviations(
 		logger,
 		convertedCandles,
 		deviations,
+		aggregations,
 	)
 	if err != nil {
-		return nil, err
+		return nil, breakdown, err
 	}
 
 	// attempt to use candles for TVWAP calculations
 	computedPrices, err := ComputeTVWAP(filteredCandles)
 	if err != nil {
-		return nil, err
+		return nil, breakdown, err
 	}
 
+	// use the aggregation method of each asset
+	candleMethods := aggregatePrices(computedPrices, getCandleTickers(filteredCandles), aggregations, ComputationMethodTVWAP)
+
 	candleAssets := []string{}
 	tickerAssets := []string{}
 	for base := range computedPrices {
 		candleAssets = append(candleAssets, base)
 	}
-	allRequiredAssetsPresent := true
-	for asset := range requiredRates {
-		if _, ok := computedPrices[asset]; !ok {
-			allRequiredAssetsPresent = false
-		}
+
+	// keep how the candles of each provider were used
+	for method, bases := range getBasesByMethod(candleAssets, candleMethods) {
+		breakdown.addCandleSources(rawCandles, convertedCandles, filteredCandles, computedPrices, bases, method)
 	}
 	// If we're missing some assets, calculate tickers too to fill the gaps
-	// use most recent prices & VWAP instead.
-	if !allRequiredAssetsPresent {
-		logger.Debug().Msg("Evaluating tickers because some required rates were not provided via candles")
-		convertedTickers, err := convertTickersToUSD(
+	// use most recent prices & VWAP instead. The tickers are also used by the
+	// assets aggregated with their VWAP, and only the assets without a candle
+	// price are converted. A failure of the tickers keeps the candle prices.
+	tickerBases := getTickerBases(computedPrices, providerPairs, aggregations, requiredRates)
+	if tickerBases != nil {
+		logger.Debug().Msg("Evaluating tickers for the assets not provided via candles")
+		vwapPrices, tickerSources, err := computeTickerPrices(
 			logger,
 			providerPrices,
 			providerPairs,
 			deviations,
+			aggregations,
+			tickerBases,
 		)
 		if err != nil {
-			return nil, err
-		}
-
-		filteredProviderPrices, err := FilterTickerDeviations(
-			logger,
-			convertedTickers,
-			deviations,
-		)
-		if err != nil {
-			return nil, err
-		}
+			logger.Error().Err(err).Msg("failed to compute the prices of the tickers, keeping the prices of the candles")
+		} else {
+			rawTickers.setConversions(tickerSources.conversions)
 
-		vwapPrices, err := ComputeVWAP(filteredProviderPrices)
-		if err != nil {
-			return nil, err
-		}
+			for asset, price := range vwapPrices {
+				if _, ok := computedPrices[asset]; !ok {
+					tickerAssets = append(tickerAssets, asset)
+					computedPrices[asset] = price
+				}
+			}
 
-		for asset, price := range vwapPrices {
-			if _, ok := computedPrices[asset]; !ok {
-				tickerAssets = append(tickerAssets, asset)
-				computedPrices[asset] = price
+			// keep how the tickers of each provider were used
+			for method, bases := range getBasesByMethod(tickerAssets, tickerSources.methods) {
+				breakdown.addTickerSources(rawTickers, tickerSources.converted, tickerSources.filtered, computedPrices, bases, method)
 			}
 		}
 	}
 	logger.Debug().Msg(fmt.Sprint("Assets using Candle TVWAP: ", candleAssets, " Assets using Ticker VWAP: ", tickerAssets))
-	return computedPrices, nil
+	return computedPrices, breakdown, nil
+}
+
+// tickerSources defines how the tickers were used to compute the prices
+type tickerSources struct {
+	converted   provider.AggregatedProviderPrices
+	filtered    provider.AggregatedProviderPrices
+	conversions map[string]Conversion
+	methods     map[string]string
+}
+
+// computeTickerPrices computes the prices of the bases with the tickers
+// converted to USD, filtered for deviation and aggregated with the method of
+// each asset.
+func computeTickerPrices(
+	logger zerolog.Logger,
+	providerPrices provider.AggregatedProviderPrices,
+	providerPairs map[string][]types.CurrencyPair,
+	deviations map[string]sdkmath.LegacyDec,
+	aggregations map[string]Aggregation,
+	bases map[string]struct{},
+) (map[string]sdkmath.LegacyDec, tickerSources, error) {
+	convertedTickers, tickerConversions, err := convertTickersToUSD(
+		logger,
+		providerPrices,
+		providerPairs,
+		deviations,
+		aggregations,
+		bases,
+	)
+	if err != nil {
+		return nil, tickerSources{}, err
+	}
+
+	filteredProviderPrices, err := FilterTickerDeviations(
+		logger,
+		convertedTickers,
+		deviations,
+		aggregations,
+	)
+	if err != nil {
+		return nil, tickerSources{}, err
+	}
+
+	vwapPrices, err := ComputeVWAP(filteredProviderPrices)
+	if err != nil {
+		return nil, tickerSources{}, err
+	}
+
+	// use the aggregation method of each asset
+	tickerMethods := aggregatePrices(vwapPrices, filteredProviderPrices, aggregations, ComputationMethodVWAP)
+
+	return vwapPrices, tickerSources{
+		converted:   convertedTickers,
+		filtered:    filteredProviderPrices,
+		conversions: tickerConversions,
+		methods:     tickerMethods,
+	}, nil
 }
 
 // SetProviderTickerPricesAndCandles flattens and collects prices for
@@ -508,35 +764,89 @@ func SetProviderTickerPricesAndCandles(
 }
 
 func (o *Oracle) getOrSetProvider(ctx context.Context, providerName string) (provider.Provider, error) {
-	var (
-		priceProvider provider.Provider
-		ok            bool
-	)
+	priceProvider, created, err := o.initProvider(ctx, providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	// drop the pairs the provider doesn't support once they are fetched
+	if created {
+		o.validateProviderPairs(ctx, providerName, priceProvider, o.providerPairs[providerName])
+	}
+
+	return priceProvider, nil
+}
+
+// initProvider returns the provider, creating it when it doesn't exist yet,
+// and whether it was created.
+func (o *Oracle) initProvider(ctx context.Context, providerName string) (provider.Provider, bool, error) {
+	// skip the failed provider until its next retry
+	if err := o.supervisor.CheckRetry(providerName); err != nil {
+		return nil, false, errors.Wrap(err, "failed to init (skipping provider)")
+	}
 
-	// TODO: replace with a exponential backoff mechanism
-	if err, ok := o.failedProviders[providerName]; ok {
-		return nil, errors.Wrap(err, "failed at first init (skipping provider)")
+	if priceProvider, ok := o.priceProviders[providerName]; ok {
+		return priceProvider, false, nil
 	}
 
-	priceProvider, ok = o.priceProviders[providerName]
-	if !ok {
-		newProvider, err := NewProvider(
+	// the provider connections are closed with its own context
+	providerCtx, cancel := context.WithCancel(ctx)
+	priceProvider, err := o.newProvider(providerCtx, providerName)
+	if err != nil {
+		cancel()
+		o.supervisor.RecordFailure(providerName, err)
+
+		// the failed provider no longer counts toward the minimum
+		o.mtx.Lock()
+		o.pairValidation.UnderProvided = o.basesBelowMinimumProviders()
+		o.mtx.Unlock()
+
+		return nil, false, err
+	}
+
+	o.priceProviders[providerName] = priceProvider
+	o.providerCancels[providerName] = cancel
+	o.supervisor.RecordSuccess(providerName)
+
+	return priceProvider, true, nil
+}
+
+// newProvider creates the provider declared in the config or served by the
+// plugin with the name, or the supported provider otherwise
+func (o *Oracle) newProvider(ctx context.Context, providerName string) (provider.Provider, error) {
+	if genericProvider, ok := o.genericProviders[providerName]; ok {
+		return provider.NewGenericProvider(ctx, o.logger, genericProvider, o.providerPairs[providerName]...)
+	}
+	if plugin, ok := o.plugins[providerName]; ok {
+		return provider.NewPluginProvider(ctx, o.logger, plugin, o.providerPairs[providerName]...)
+	}
+	if fxProvider, ok := o.fxProviders[providerName]; ok {
+		return provider.NewFXProvider(ctx, o.logger, fxProvider, o.providerPairs[providerName]...)
+	}
+
+	// the DEX providers query the pools of the config over gRPC or JSON-RPC
+	switch providerName {
+	case config.ProviderOsmosis:
+		return provider.NewOsmosisProvider(
 			ctx,
-			providerName,
 			o.logger,
 			o.endpoints[providerName],
+			o.dexPools[providerName],
+			dialerFunc,
 			o.providerPairs[providerName]...,
 		)
-		if err != nil {
-			o.failedProviders[providerName] = err
-			return nil, err
-		}
-		priceProvider = newProvider
 
-		o.priceProviders[providerName] = priceProvider
+	case config.ProviderUniswap:
+		return provider.NewUniswapProvider(
+			ctx,
+			o.logger,
+			o.endpoints[providerName],
+			o.dexPools[providerName],
+			o.providerPairs[providerName]...,
+		)
 	}
 
-	return priceProvider, nil
+	return NewProvider(ctx, providerName, o.logger, o.endpoints[providerName], o.providerPairs[providerName]...)
 }
 
 // Create various providers to pull price data for oracle price feeds
@@ -572,6 +882,18 @@ func NewProvider(
 	case config.ProviderGate:
 		return provider.NewGateProvider(ctx, logger, endpoint, providerPairs...)
 
+	case config.ProviderBybit:
+		return provider.NewBybitProvider(ctx, logger, endpoint, providerPairs...)
+
+	case config.ProviderKucoin:
+		return provider.NewKucoinProvider(ctx, logger, endpoint, providerPairs...)
+
+	case config.ProviderBitstamp:
+		return provider.NewBitstampProvider(ctx, logger, endpoint, providerPairs...)
+
+	case config.ProviderGemini:
+		return provider.NewGeminiProvider(ctx, logger, endpoint, providerPairs...)
+
 	case config.ProviderMock:
 		return provider.NewMockProvider(), nil
 	}
@@ -625,6 +947,11 @@ func (o *Oracle) tick(
 		return err
 	}
 
+	// get the cached validator's penalty status, failing to get it shouldn't stop the vote
+	if _, err := o.GetCachedPenaltyStatus(ctx, blockHeight, oracleParams); err != nil {
+		o.logger.Warn().Err(err).Msg("failed to get the validator's vote penalty status")
+	}
+
 	// get exchange rates //TODO: Check
 	err = o.SetPrices(ctx)
 	if err != nil {
@@ -651,6 +978,12 @@ func (o *Oracle) tick(
 		return nil
 	}
 
+	// the previous vote period has ended, compare its vote with the on-chain
+	// rates in the background
+	lastVote := o.lastVote
+	o.lastVote = nil
+	go o.checkVoteAccuracy(ctx, oracleParams, lastVote, uint64(currentVotePeriod))
+
 	// get validator address
 	valAddr, err := sdk.ValAddressFromBech32(o.oracleClient.ValidatorAddrString)
 	if err != nil {
@@ -663,6 +996,9 @@ func (o *Oracle) tick(
 	// filter for whitelisted denominations so that extra oracle prices are not penalized
 	filteredPrices := filterPricesByDenomList(prices, oracleParams.Whitelist)
 
+	// hold back the prices jumping since the previous vote period
+	filteredPrices = o.applyCircuitBreakers(ctx, filteredPrices, lastVote, currentVotePeriod)
+
 	// convert rates to string (sorted string)
 	exchangeRatesStr := GenerateExchangeRatesString(filteredPrices)
 
@@ -677,6 +1013,29 @@ func (o *Oracle) tick(
 		Str("exchange_rates", GenerateExchangeRatesString(prices)).
 		Msg("pre-filtered prices")
 
+	// keep the vote inputs to journal its outcome
+	journalEntry := o.newJournalEntry(blockHeight, currentVotePeriod, filteredPrices)
+
+	// on shadow mode the vote is only recorded, never broadcasted
+	if o.shadowMode {
+		o.recordShadowVote(blockHeight, currentVotePeriod, voteMsg)
+		o.setLastVote(blockHeight, currentVotePeriod, filteredPrices)
+		o.journalVote(journalEntry, startTime)
+
+		o.logger.Info().
+			Str("exchange_rates", voteMsg.ExchangeRates).
+			Str("validator", voteMsg.Validator).
+			Str("feeder", voteMsg.Feeder).
+			Float64("vote_period", currentVotePeriod).
+			Int64("tick_duration", time.Since(startTime).Milliseconds()).
+			Msg("shadow mode enabled, recorded vote without broadcasting")
+		telemetry.IncrCounter(1, "shadow", "vote")
+
+		// update the vote period voted
+		o.previousVotePeriod = currentVotePeriod
+		return nil
+	}
+
 	o.logger.Info().
 		Str("exchange_rates", voteMsg.ExchangeRates).
 		Str("validator", voteMsg.Validator).
@@ -687,7 +1046,13 @@ func (o *Oracle) tick(
 
 	// broadcast transaction
 	resp, err := o.oracleClient.BroadcastTx(clientCtx, voteMsg)
+	if resp != nil {
+		journalEntry.TxHash = resp.TxHash
+		journalEntry.ResponseCode = int64(resp.Code)
+	}
 	if err != nil {
+		journalEntry.Error = err.Error()
+		o.journalVote(journalEntry, startTime)
 		o.logResponseError(err, resp, startTime, blockHeight)
 		telemetry.IncrCounter(1, "failure", "broadcast")
 		return err
@@ -700,6 +1065,10 @@ func (o *Oracle) tick(
 		Int64("tick_duration", time.Since(startTime).Milliseconds()).
 		Msg(fmt.Sprintf("broadcasted for height %d", blockHeight))
 	telemetry.IncrCounter(1, "success", "broadcast")
+	o.journalVote(journalEntry, startTime)
+
+	// keep the vote to compare it with the on-chain rates
+	o.setLastVote(blockHeight, currentVotePeriod, filteredPrices)
 
 	// update the vote period voted
 	o.previousVotePeriod = currentVotePeriod
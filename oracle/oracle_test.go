@@ -3,6 +3,7 @@ package oracle
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -167,44 +168,48 @@ func (ots *OracleTestSuite) SetupSuite() {
 		// set to debug to hit the debug-only code paths
 		zerolog.Nop().Level(zerolog.DebugLevel),
 		client.OracleClient{},
-		[]config.CurrencyPair{
-			{
-				Base:       "UMEE",
-				ChainDenom: "uumee",
-				Quote:      "USDT",
-				Providers:  []string{config.ProviderBinance},
-			},
-			{
-				Base:       "UMEE",
-				ChainDenom: "uumee",
-				Quote:      "USDC",
-				Providers:  []string{config.ProviderKraken},
-			},
-			{
-				Base:       "XBT",
-				ChainDenom: "uxbt",
-				Quote:      "USDT",
-				Providers:  []string{config.ProviderOkx},
-			},
-			{
-				Base:       "USDC",
-				ChainDenom: "uusdc",
-				Quote:      "USD",
-				Providers:  []string{config.ProviderHuobi},
+		Options{
+			ReloadConfig: ReloadConfig{
+				CurrencyPairs: []config.CurrencyPair{
+					{
+						Base:       "UMEE",
+						ChainDenom: "uumee",
+						Quote:      "USDT",
+						Providers:  []string{config.ProviderBinance},
+					},
+					{
+						Base:       "UMEE",
+						ChainDenom: "uumee",
+						Quote:      "USDC",
+						Providers:  []string{config.ProviderKraken},
+					},
+					{
+						Base:       "XBT",
+						ChainDenom: "uxbt",
+						Quote:      "USDT",
+						Providers:  []string{config.ProviderOkx},
+					},
+					{
+						Base:       "USDC",
+						ChainDenom: "uusdc",
+						Quote:      "USD",
+						Providers:  []string{config.ProviderHuobi},
+					},
+					{
+						Base:       "USDT",
+						ChainDenom: "uusdt",
+						Quote:      "USD",
+						Providers:  []string{config.ProviderCoinbase},
+					},
+				},
+				Deviations: make(map[string]math.LegacyDec),
+				Endpoints:  make(map[string]config.ProviderEndpoint),
 			},
-			{
-				Base:       "USDT",
-				ChainDenom: "uusdt",
-				Quote:      "USD",
-				Providers:  []string{config.ProviderCoinbase},
+			ProviderTimeout: time.Millisecond * 100,
+			Healthchecks: []config.Healthchecks{
+				{URL: "https://hc-ping.com/HEALTHCHECK-UUID", Timeout: "200ms"},
 			},
 		},
-		time.Millisecond*100,
-		make(map[string]math.LegacyDec),
-		make(map[string]config.ProviderEndpoint),
-		[]config.Healthchecks{
-			{URL: "https://hc-ping.com/HEALTHCHECK-UUID", Timeout: "200ms"},
-		},
 	)
 }
 
@@ -478,9 +483,7 @@ func (ots *OracleTestSuite) TestPrices() {
 	ots.Require().Equal(math.LegacyMustNewDecFromStr("1"), prices.AmountOf("uusdt"))
 
 	// if a provider never initialized correctly, verify it doesn't prevent future updates
-	ots.oracle.failedProviders = map[string]error{
-		config.ProviderBinance: fmt.Errorf("test error"),
-	}
+	ots.oracle.supervisor.RecordFailure(config.ProviderBinance, fmt.Errorf("test error"))
 	// a non-whitelisted entry fails (ubxt), but the rest succeed
 	ots.oracle.priceProviders = map[string]provider.Provider{
 		config.ProviderBinance: failingProvider{
@@ -566,10 +569,12 @@ func TestTickScenarios(t *testing.T) {
 		previousVotePeriod float64
 		votePeriod         uint64
 		mockBroadcastErr   error
+		shadowMode         bool
 
 		// expectations
-		expectedVoteMsg *oracletypes.MsgAggregateExchangeRateVote
-		expectedErr     error
+		expectedVoteMsg     *oracletypes.MsgAggregateExchangeRateVote
+		expectedShadowVotes int
+		expectedErr         error
 	}{
 		{
 			name:               "Filtered prices, should broadcast all entries, none filtered",
@@ -642,6 +647,24 @@ func TestTickScenarios(t *testing.T) {
 			mockBroadcastErr: fmt.Errorf("test error"),
 			expectedErr:      fmt.Errorf("test error"),
 		},
+		{
+			name:               "Shadow mode should record the vote without broadcasting",
+			isJailed:           false,
+			blockHeight:        1,
+			previousVotePeriod: 0,
+			votePeriod:         1,
+			shadowMode:         true,
+			pairs: []config.CurrencyPair{
+				{Base: "USDT", ChainDenom: "uusdt", Quote: "USD"},
+				{Base: "BTC", ChainDenom: "ubtc", Quote: "USD"},
+			},
+			prices: map[string]math.LegacyDec{
+				"USDT": math.LegacyMustNewDecFromStr("1.1"),
+				"BTC":  math.LegacyMustNewDecFromStr("2.2"),
+			},
+			whitelist:           denomList("uusdt", "ubtc"),
+			expectedShadowVotes: 1,
+		},
 		{
 			name:               "Same voting period should avoid broadcasting without error",
 			isJailed:           false,
@@ -672,8 +695,14 @@ func TestTickScenarios(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			var setPriceCount int
 			var broadcastCount int
+
+			// Create the journal of the votes
+			journal, err := NewJournal(filepath.Join(t.TempDir(), "journal.jsonl"))
+			require.NoError(t, err)
+
 			// Create the oracle instance
 			oracle := &Oracle{
+				journal: journal,
 				jailCache: JailCache{
 					isJailed: test.isJailed,
 				},
@@ -682,6 +711,7 @@ func TestTickScenarios(t *testing.T) {
 					return nil
 				},
 				previousVotePeriod: test.previousVotePeriod,
+				shadowMode:         test.shadowMode,
 				chainDenomMapping:  cdm,
 				prices:             test.prices,
 				paramCache: ParamCache{
@@ -718,7 +748,7 @@ func TestTickScenarios(t *testing.T) {
 			}
 
 			// execute the tick function
-			err := oracle.tick(ctx, sdkclient.Context{}, test.blockHeight)
+			err = oracle.tick(ctx, sdkclient.Context{}, test.blockHeight)
 
 			if test.expectedErr != nil {
 				require.Equal(t, test.expectedErr, err, test.name)
@@ -734,6 +764,12 @@ func TestTickScenarios(t *testing.T) {
 				// should not call broadcast
 				require.Equal(t, 0, broadcastCount, test.name)
 			}
+			require.Len(t, oracle.GetShadowVotes(), test.expectedShadowVotes, test.name)
+
+			// every attempted vote is journaled
+			_, total, err := oracle.GetJournalEntries(JournalQuery{})
+			require.NoError(t, err, test.name)
+			require.Equal(t, broadcastCount+test.expectedShadowVotes, total, test.name)
 		})
 	}
 }
@@ -803,6 +839,34 @@ func TestFilterPricesWithDenomList(t *testing.T) {
 	}
 }
 
+func TestAddFXPairs(t *testing.T) {
+	providerPairs := map[string][]types.CurrencyPair{
+		config.ProviderKraken: {{Base: "BTC", Quote: "EUR"}},
+		"ecb":                 {{Base: "EUR", Quote: "USD"}},
+	}
+	currencyPairs := []config.CurrencyPair{
+		{Base: "BTC", Quote: "EUR"},
+		{Base: "ETH", Quote: "eur"},
+		{Base: "ATOM", Quote: "GBP"},
+		{Base: "ATOM", Quote: "USDT"},
+	}
+	fxProviders := map[string]config.FXProvider{
+		"ecb":  {Name: "ecb", Currencies: []string{"EUR", "GBP"}},
+		"boj":  {Name: "boj", Currencies: []string{"jpy"}},
+		"myfx": {Name: "myfx", Currencies: []string{"gbp"}},
+	}
+
+	addFXPairs(providerPairs, currencyPairs, fxProviders)
+
+	require.Equal(t, []types.CurrencyPair{
+		{Base: "EUR", Quote: "USD"},
+		{Base: "GBP", Quote: "USD"},
+	}, providerPairs["ecb"])
+	require.Equal(t, []types.CurrencyPair{{Base: "GBP", Quote: "USD"}}, providerPairs["myfx"])
+	require.NotContains(t, providerPairs, "boj")
+	require.Len(t, providerPairs[config.ProviderKraken], 1)
+}
+
 func TestGenerateExchangeRatesString(t *testing.T) {
 	testCases := map[string]struct {
 		input    sdk.DecCoins
@@ -921,6 +985,8 @@ func TestSuccessGetComputedPricesCandles(t *testing.T) {
 		make(provider.AggregatedProviderPrices, 1),
 		providerPair,
 		make(map[string]math.LegacyDec),
+		nil,
+		CandleFreshness{},
 		map[string]struct{}{
 			"ATOM": {},
 		},
@@ -957,6 +1023,8 @@ func TestSuccessGetComputedPricesTickers(t *testing.T) {
 		providerPrices,
 		providerPair,
 		make(map[string]math.LegacyDec),
+		nil,
+		CandleFreshness{},
 		map[string]struct{}{
 			"ATOM": {},
 		},
@@ -1056,6 +1124,8 @@ func TestGetComputedPricesCandlesConversion(t *testing.T) {
 		make(provider.AggregatedProviderPrices, 1),
 		providerPair,
 		make(map[string]math.LegacyDec),
+		nil,
+		CandleFreshness{},
 		map[string]struct{}{
 			"BTC": {},
 		},
@@ -1147,6 +1217,8 @@ func TestGetComputedPricesTickersConversion(t *testing.T) {
 		providerPrices,
 		providerPair,
 		make(map[string]math.LegacyDec),
+		nil,
+		CandleFreshness{},
 		map[string]struct{}{
 			"BTC": {},
 		},
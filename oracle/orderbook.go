@@ -0,0 +1,206 @@
+package oracle
+
+import (
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-metrics"
+	"github.com/rs/zerolog"
+
+	sdkmath "cosmossdk.io/math"
+
+	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle/provider"
+	"github.com/kiichain/price-feeder/oracle/types"
+)
+
+// orderBookMaxAge is the age of an order book after which it is considered
+// stale, its depth stream having stalled.
+const orderBookMaxAge = time.Minute
+
+// OrderBookSettings defines how the order books of the providers are used on
+// the price of an asset.
+type OrderBookSettings struct {
+	DepthBand   sdkmath.LegacyDec // band around the mid price where the depth is summed
+	MaxSpread   sdkmath.LegacyDec // widest spread of a provider, zero for no limit
+	UseMidPrice bool              // use the depth weighted mid price of the books
+}
+
+// getOrderBookPairs returns the pairs with order book settings.
+func getOrderBookPairs(
+	pairs []types.CurrencyPair,
+	settings map[string]OrderBookSettings,
+) []types.CurrencyPair {
+	orderBookPairs := []types.CurrencyPair{}
+	for _, pair := range pairs {
+		if _, ok := settings[pair.Base]; ok {
+			orderBookPairs = append(orderBookPairs, pair)
+		}
+	}
+
+	return orderBookPairs
+}
+
+// FilterStaleOrderBooks removes the order books not updated within the
+// maximum age, so a stalled depth stream never serves a frozen book.
+func FilterStaleOrderBooks(logger zerolog.Logger, orderBooks provider.AggregatedProviderOrderBooks) {
+	staleTime := provider.PastUnixTime(orderBookMaxAge)
+	for providerName, providerOrderBooks := range orderBooks {
+		for base, orderBook := range providerOrderBooks {
+			if orderBook.TimeStamp >= staleTime {
+				continue
+			}
+
+			delete(providerOrderBooks, base)
+
+			sendProviderFailureMetric([]string{"failure", "provider"}, 1, []metrics.Label{
+				{Name: "type", Value: "depth"},
+				{Name: "reason", Value: "stale"},
+				{Name: "base", Value: base},
+				{Name: "provider", Value: providerName},
+			})
+			logger.Warn().
+				Str("base", base).
+				Str("provider", providerName).
+				Time("timestamp", time.UnixMilli(orderBook.TimeStamp)).
+				Msg("provider order book is stale")
+		}
+	}
+}
+
+// FilterOrderBookSpreads removes the tickers and candles of the providers
+// with an order book spread wider than the maximum spread of the asset.
+func FilterOrderBookSpreads(
+	logger zerolog.Logger,
+	orderBooks provider.AggregatedProviderOrderBooks,
+	prices provider.AggregatedProviderPrices,
+	candles provider.AggregatedProviderCandles,
+	settings map[string]OrderBookSettings,
+) {
+	for providerName, providerOrderBooks := range orderBooks {
+		for base, orderBook := range providerOrderBooks {
+			maxSpread := settings[base].MaxSpread
+			if maxSpread.IsNil() || !maxSpread.IsPositive() || orderBook.Spread().LTE(maxSpread) {
+				continue
+			}
+
+			delete(prices[providerName], base)
+			delete(candles[providerName], base)
+			delete(providerOrderBooks, base)
+
+			sendProviderFailureMetric([]string{"failure", "provider"}, 1, []metrics.Label{
+				{Name: "type", Value: "depth"},
+				{Name: "reason", Value: "spread"},
+				{Name: "base", Value: base},
+				{Name: "provider", Value: providerName},
+			})
+			logger.Warn().
+				Str("base", base).
+				Str("provider", providerName).
+				Str("spread", orderBook.Spread().String()).
+				Msg("provider spread wider than the maximum spread")
+		}
+	}
+}
+
+// computeOrderBookPrices replaces the prices of the assets using the mid
+// price with the VWAP of the depth weighted mid prices of the books. The mid
+// prices are converted to USD with the computed prices and weighted by the
+// depth within the band. The computed price is kept when less than the
+// minimum amount of providers have a valid order book.
+func computeOrderBookPrices(
+	logger zerolog.Logger,
+	orderBooks provider.AggregatedProviderOrderBooks,
+	providerPairs map[string][]types.CurrencyPair,
+	deviations map[string]sdkmath.LegacyDec,
+	aggregations map[string]Aggregation,
+	settings map[string]OrderBookSettings,
+	prices map[string]sdkmath.LegacyDec,
+	breakdown PriceBreakdown,
+) error {
+	raw := make(rawSources)
+	midTickers := make(provider.AggregatedProviderPrices)
+
+	for providerName, providerOrderBooks := range orderBooks {
+		for base, orderBook := range providerOrderBooks {
+			if !settings[base].UseMidPrice {
+				continue
+			}
+
+			bidDepth, askDepth := orderBook.Depth(settings[base].DepthBand)
+			depth := bidDepth.Add(askDepth)
+			if !depth.IsPositive() {
+				continue
+			}
+
+			quote := getPairQuote(providerPairs[providerName], base)
+			rate := sdkmath.LegacyOneDec()
+			if strings.ToUpper(quote) != config.DenomUSD {
+				var ok bool
+				if rate, ok = prices[quote]; !ok {
+					logger.Debug().
+						Str("base", base).
+						Str("quote", quote).
+						Str("provider", providerName).
+						Msg("no conversion rate for the order book")
+					continue
+				}
+			}
+
+			midPrice := orderBook.DepthWeightedMid(settings[base].DepthBand)
+			raw.add(providerName, base, rawSource{
+				quote:  quote,
+				price:  midPrice,
+				volume: depth,
+			})
+
+			if _, ok := midTickers[providerName]; !ok {
+				midTickers[providerName] = make(map[string]provider.TickerPrice)
+			}
+			midTickers[providerName][base] = provider.TickerPrice{
+				Price:  midPrice.Mul(rate),
+				Volume: depth,
+			}
+		}
+	}
+
+	if len(midTickers) == 0 {
+		return nil
+	}
+
+	filteredTickers, err := FilterTickerDeviations(logger, midTickers, deviations, aggregations)
+	if err != nil {
+		return err
+	}
+
+	midPrices, err := ComputeVWAP(filteredTickers)
+	if err != nil {
+		return err
+	}
+
+	bookProviders := make(map[string]int, len(midPrices))
+	for _, providerTickers := range filteredTickers {
+		for base := range providerTickers {
+			bookProviders[base]++
+		}
+	}
+
+	midAssets := make([]string, 0, len(midPrices))
+	for base, price := range midPrices {
+		if bookProviders[base] < config.MinimumProviders {
+			logger.Debug().
+				Str("base", base).
+				Int("providers", bookProviders[base]).
+				Msg("not enough order books to use the mid price")
+			continue
+		}
+
+		midAssets = append(midAssets, base)
+		prices[base] = price
+	}
+
+	breakdown.addTickerSources(raw, midTickers, filteredTickers, prices, midAssets, ComputationMethodOrderBookMid)
+	logger.Debug().Msgf("Assets using order book mid prices: %v", midAssets)
+
+	return nil
+}
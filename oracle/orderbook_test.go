@@ -0,0 +1,144 @@
+package oracle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/math"
+
+	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle/provider"
+	"github.com/kiichain/price-feeder/oracle/types"
+)
+
+func newTestOrderBook(bid, bidSize, ask, askSize string) provider.OrderBook {
+	return provider.OrderBook{
+		Bids:      []provider.OrderBookLevel{{Price: math.LegacyMustNewDecFromStr(bid), Size: math.LegacyMustNewDecFromStr(bidSize)}},
+		Asks:      []provider.OrderBookLevel{{Price: math.LegacyMustNewDecFromStr(ask), Size: math.LegacyMustNewDecFromStr(askSize)}},
+		TimeStamp: time.Now().UnixMilli(),
+	}
+}
+
+func TestFilterStaleOrderBooks(t *testing.T) {
+	telemetryMock := resetMockTelemetry()
+
+	staleOrderBook := newTestOrderBook("9.99", "1", "10.01", "1")
+	staleOrderBook.TimeStamp = time.Now().Add(-2 * orderBookMaxAge).UnixMilli()
+	orderBooks := provider.AggregatedProviderOrderBooks{
+		config.ProviderBinance: {"ATOM": newTestOrderBook("9.99", "1", "10.01", "1")},
+		config.ProviderKraken:  {"ATOM": staleOrderBook},
+	}
+
+	FilterStaleOrderBooks(zerolog.Nop(), orderBooks)
+
+	require.Contains(t, orderBooks[config.ProviderBinance], "ATOM")
+	require.NotContains(t, orderBooks[config.ProviderKraken], "ATOM")
+
+	require.Equal(t, 1, telemetryMock.Len())
+	telemetryMock.AssertProviderError(t, config.ProviderKraken, "ATOM", "stale", "depth")
+}
+
+func TestFilterOrderBookSpreads(t *testing.T) {
+	telemetryMock := resetMockTelemetry()
+
+	ticker := provider.TickerPrice{Price: math.LegacyNewDec(10), Volume: math.LegacyOneDec()}
+	prices := provider.AggregatedProviderPrices{
+		config.ProviderBinance: {"ATOM": ticker, "BTC": ticker},
+		config.ProviderKraken:  {"ATOM": ticker},
+	}
+	candles := provider.AggregatedProviderCandles{
+		config.ProviderBinance: {"ATOM": {{Price: ticker.Price}}},
+		config.ProviderKraken:  {"ATOM": {{Price: ticker.Price}}},
+	}
+	orderBooks := provider.AggregatedProviderOrderBooks{
+		config.ProviderBinance: {
+			"ATOM": newTestOrderBook("9.99", "1", "10.01", "1"),
+			"BTC":  newTestOrderBook("9", "1", "11", "1"),
+		},
+		config.ProviderKraken: {"ATOM": newTestOrderBook("9.5", "1", "10.5", "1")},
+	}
+
+	FilterOrderBookSpreads(zerolog.Nop(), orderBooks, prices, candles, map[string]OrderBookSettings{
+		"ATOM": {MaxSpread: math.LegacyMustNewDecFromStr("0.01")},
+		"BTC":  {MaxSpread: math.LegacyZeroDec()},
+	})
+
+	// the kraken spread of 10% is above the limit, the btc spread has no limit
+	require.Contains(t, prices[config.ProviderBinance], "ATOM")
+	require.Contains(t, prices[config.ProviderBinance], "BTC")
+	require.NotContains(t, prices[config.ProviderKraken], "ATOM")
+	require.NotContains(t, candles[config.ProviderKraken], "ATOM")
+	require.NotContains(t, orderBooks[config.ProviderKraken], "ATOM")
+
+	require.Equal(t, 1, telemetryMock.Len())
+	telemetryMock.AssertProviderError(t, config.ProviderKraken, "ATOM", "spread", "depth")
+}
+
+func TestComputeOrderBookPrices(t *testing.T) {
+	settings := map[string]OrderBookSettings{
+		"ATOM": {DepthBand: math.LegacyMustNewDecFromStr("0.02"), UseMidPrice: true},
+		"BTC":  {DepthBand: math.LegacyMustNewDecFromStr("0.02")},
+	}
+	providerPairs := map[string][]types.CurrencyPair{
+		config.ProviderBinance: {{Base: "ATOM", Quote: "USDT"}, {Base: "BTC", Quote: "USDT"}},
+		config.ProviderKraken:  {{Base: "ATOM", Quote: "USD"}},
+		config.ProviderOkx:     {{Base: "ATOM", Quote: "EUR"}},
+	}
+	orderBooks := provider.AggregatedProviderOrderBooks{
+		config.ProviderBinance: {
+			"ATOM": newTestOrderBook("9.9", "3", "10.1", "1"),
+			"BTC":  newTestOrderBook("9.9", "1", "10.1", "1"),
+		},
+		config.ProviderKraken: {"ATOM": newTestOrderBook("10.3", "2", "10.5", "2")},
+		config.ProviderOkx:    {"ATOM": newTestOrderBook("9.7", "2", "9.8", "2")},
+	}
+	prices := map[string]math.LegacyDec{
+		"ATOM": math.LegacyNewDec(12),
+		"BTC":  math.LegacyNewDec(12),
+		"USDT": math.LegacyMustNewDecFromStr("1.02"),
+		"EUR":  math.LegacyMustNewDecFromStr("1.1"),
+	}
+	deviations := map[string]math.LegacyDec{"ATOM": math.LegacyNewDec(2)}
+	breakdown := make(PriceBreakdown)
+
+	err := computeOrderBookPrices(zerolog.Nop(), orderBooks, providerPairs, deviations, nil, settings, prices, breakdown)
+	require.NoError(t, err)
+
+	// binance mid leans to the ask with 10.05 USDT, or 10.251 USD, kraken has
+	// 10.4 USD and okx 9.75 EUR, or 10.725 USD, all with a depth of 4
+	require.Equal(t, math.LegacyMustNewDecFromStr("10.458666666666666667"), prices["ATOM"])
+	require.Equal(t, math.LegacyNewDec(12), prices["BTC"])
+
+	require.Equal(t, ComputationMethodOrderBookMid, breakdown["ATOM"].Method)
+	require.Len(t, breakdown["ATOM"].Sources, 3)
+	require.Equal(t, "USDT", breakdown["ATOM"].Sources[config.ProviderBinance].Quote)
+	require.Equal(t, math.LegacyMustNewDecFromStr("10.05"), breakdown["ATOM"].Sources[config.ProviderBinance].RawPrice)
+	require.Equal(t, "EUR", breakdown["ATOM"].Sources[config.ProviderOkx].Quote)
+	require.NotContains(t, breakdown, "BTC")
+}
+
+func TestComputeOrderBookPricesBelowMinimumProviders(t *testing.T) {
+	settings := map[string]OrderBookSettings{
+		"ATOM": {DepthBand: math.LegacyMustNewDecFromStr("0.02"), UseMidPrice: true},
+	}
+	providerPairs := map[string][]types.CurrencyPair{
+		config.ProviderBinance: {{Base: "ATOM", Quote: "USD"}},
+		config.ProviderKraken:  {{Base: "ATOM", Quote: "USD"}},
+	}
+	orderBooks := provider.AggregatedProviderOrderBooks{
+		config.ProviderBinance: {"ATOM": newTestOrderBook("9.9", "1", "10.1", "1")},
+		config.ProviderKraken:  {"ATOM": newTestOrderBook("10.3", "1", "10.5", "1")},
+	}
+	prices := map[string]math.LegacyDec{"ATOM": math.LegacyNewDec(12)}
+	breakdown := make(PriceBreakdown)
+
+	err := computeOrderBookPrices(zerolog.Nop(), orderBooks, providerPairs, nil, nil, settings, prices, breakdown)
+	require.NoError(t, err)
+
+	// two order books are below the minimum amount of providers
+	require.Equal(t, math.LegacyNewDec(12), prices["ATOM"])
+	require.NotContains(t, breakdown, "ATOM")
+}
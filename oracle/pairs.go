@@ -0,0 +1,231 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle/provider"
+	"github.com/kiichain/price-feeder/oracle/types"
+)
+
+// PairValidation is the result of validating the configured pairs against
+// the pairs available on each provider
+type PairValidation struct {
+	Unsupported   map[string][]string `json:"unsupported,omitempty"`    // pairs dropped, by provider
+	Errors        map[string]string   `json:"errors,omitempty"`         // providers whose pairs couldn't be validated
+	UnderProvided []string            `json:"under_provided,omitempty"` // bases below the minimum amount of providers
+}
+
+// newPairValidation creates an empty pair validation
+func newPairValidation() PairValidation {
+	return PairValidation{
+		Unsupported: make(map[string][]string),
+		Errors:      make(map[string]string),
+	}
+}
+
+// ValidateProviderPairs returns the pairs which are not available on the
+// provider. A provider which can't list its pairs returns nil available pairs,
+// keeping all of them, while an empty listing can't be trusted to validate them.
+func ValidateProviderPairs(priceProvider provider.Provider, pairs []types.CurrencyPair) ([]types.CurrencyPair, error) {
+	availablePairs, err := priceProvider.GetAvailablePairs()
+	if err != nil || availablePairs == nil {
+		return nil, err
+	}
+	if len(availablePairs) == 0 {
+		return nil, fmt.Errorf("no available pairs listed")
+	}
+
+	var unavailablePairs []types.CurrencyPair
+	for _, pair := range pairs {
+		if _, ok := availablePairs[pair.String()]; !ok {
+			unavailablePairs = append(unavailablePairs, pair)
+		}
+	}
+
+	return unavailablePairs, nil
+}
+
+// pairValidationResult defines the pairs not available on a provider,
+// applied by the oracle between ticks.
+type pairValidationResult struct {
+	providerName     string
+	unavailablePairs []types.CurrencyPair
+	err              error
+}
+
+// ValidateProviders initializes every provider, their pairs being validated
+// in the background
+func (o *Oracle) ValidateProviders(ctx context.Context) {
+	for _, providerName := range o.GetProviderNames() {
+		if _, err := o.getOrSetProvider(ctx, providerName); err != nil {
+			o.logger.Warn().Err(err).Str("provider", providerName).Msg("failed to initialize provider")
+		}
+	}
+}
+
+// validateProviderPairs fetches the pairs available on the provider in the
+// background, so a slow endpoint never delays a tick. The result is applied
+// between ticks.
+func (o *Oracle) validateProviderPairs(
+	ctx context.Context,
+	providerName string,
+	priceProvider provider.Provider,
+	pairs []types.CurrencyPair,
+) {
+	go func() {
+		unavailablePairs, err := ValidateProviderPairs(priceProvider, pairs)
+
+		select {
+		case o.pairValidations <- pairValidationResult{
+			providerName:     providerName,
+			unavailablePairs: unavailablePairs,
+			err:              err,
+		}:
+		case <-ctx.Done():
+		}
+	}()
+}
+
+// applyPairValidation drops the pairs not available on a provider and
+// checks the minimum amount of providers on what remains. The pairs are kept
+// when the available pairs couldn't be fetched.
+func (o *Oracle) applyPairValidation(result pairValidationResult) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+
+	providerName := result.providerName
+	if result.err != nil {
+		o.logger.Warn().Err(result.err).Str("provider", providerName).Msg("failed to get available pairs, skipping pair validation")
+		o.pairValidation.Errors[providerName] = result.err.Error()
+	} else if pairs, ok := o.providerPairs[providerName]; ok && len(result.unavailablePairs) > 0 {
+		unsupported := []string{}
+		for _, pair := range result.unavailablePairs {
+			if containsPair(pairs, pair) {
+				unsupported = append(unsupported, pair.String())
+			}
+		}
+		if len(unsupported) > 0 {
+			o.logger.Warn().Str("provider", providerName).Strs("pairs", unsupported).Msg("dropped pairs not available on provider")
+			o.pairValidation.Unsupported[providerName] = append(o.pairValidation.Unsupported[providerName], unsupported...)
+			o.providerPairs[providerName] = removePairs(pairs, result.unavailablePairs)
+		}
+	}
+
+	// only warn about the bases newly below the minimum
+	underProvided := o.basesBelowMinimumProviders()
+	for _, base := range underProvided {
+		if !containsString(o.pairValidation.UnderProvided, base) {
+			o.logger.Warn().Str("base", base).Msgf("less than %d providers support the pair", config.MinimumProviders)
+		}
+	}
+	o.pairValidation.UnderProvided = underProvided
+}
+
+// containsString returns whether the values contain the value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// removePairs returns the pairs which are not on the removed pairs
+func removePairs(pairs []types.CurrencyPair, removed []types.CurrencyPair) []types.CurrencyPair {
+	removedSet := make(map[string]struct{}, len(removed))
+	for _, pair := range removed {
+		removedSet[pair.String()] = struct{}{}
+	}
+
+	var remaining []types.CurrencyPair
+	for _, pair := range pairs {
+		if _, ok := removedSet[pair.String()]; !ok {
+			remaining = append(remaining, pair)
+		}
+	}
+
+	return remaining
+}
+
+// basesBelowMinimumProviders re-runs the minimum providers check of the
+// config on the pairs of the providers which didn't fail
+func (o *Oracle) basesBelowMinimumProviders() []string {
+	providersByBase := make(map[string]map[string]struct{}, len(o.chainDenomMapping))
+	for base := range o.chainDenomMapping {
+		providersByBase[base] = make(map[string]struct{})
+	}
+
+	for providerName, pairs := range o.providerPairs {
+		if o.supervisor.IsFailed(providerName) {
+			continue
+		}
+		for _, pair := range pairs {
+			if _, ok := providersByBase[pair.Base]; ok {
+				providersByBase[pair.Base][providerName] = struct{}{}
+			}
+		}
+	}
+
+	return config.BasesBelowMinimumProviders(providersByBase)
+}
+
+// GetPairValidation returns a copy of the pair validation result
+func (o *Oracle) GetPairValidation() *PairValidation {
+	o.mtx.RLock()
+	defer o.mtx.RUnlock()
+
+	validation := PairValidation{
+		Unsupported:   make(map[string][]string, len(o.pairValidation.Unsupported)),
+		Errors:        make(map[string]string, len(o.pairValidation.Errors)),
+		UnderProvided: append([]string(nil), o.pairValidation.UnderProvided...),
+	}
+	for providerName, pairs := range o.pairValidation.Unsupported {
+		validation.Unsupported[providerName] = append([]string(nil), pairs...)
+	}
+	for providerName, err := range o.pairValidation.Errors {
+		validation.Errors[providerName] = err
+	}
+
+	return &validation
+}
+
+// CheckProviderPairs connects to the provider and returns the configured
+// pairs which are not available on it. The pairs are validated right away, as
+// the oracle may not be started to apply the background validations.
+func (o *Oracle) CheckProviderPairs(ctx context.Context, providerName string) ([]string, error) {
+	priceProvider, _, err := o.initProvider(ctx, providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	unavailablePairs, err := ValidateProviderPairs(priceProvider, o.providerPairs[providerName])
+	o.applyPairValidation(pairValidationResult{
+		providerName:     providerName,
+		unavailablePairs: unavailablePairs,
+		err:              err,
+	})
+
+	o.mtx.RLock()
+	defer o.mtx.RUnlock()
+
+	if err, ok := o.pairValidation.Errors[providerName]; ok {
+		return nil, fmt.Errorf("failed to get available pairs: %s", err)
+	}
+
+	return append([]string(nil), o.pairValidation.Unsupported[providerName]...), nil
+}
+
+// GetProviderNames returns the sorted names of the configured providers
+func (o *Oracle) GetProviderNames() []string {
+	providerNames := make([]string, 0, len(o.providerPairs))
+	for providerName := range o.providerPairs {
+		providerNames = append(providerNames, providerName)
+	}
+	sort.Strings(providerNames)
+
+	return providerNames
+}
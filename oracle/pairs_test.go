@@ -0,0 +1,152 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/math"
+
+	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle/client"
+	"github.com/kiichain/price-feeder/oracle/types"
+)
+
+type availablePairsProvider struct {
+	mockProvider
+	availablePairs map[string]struct{}
+	err            error
+}
+
+func (p availablePairsProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	return p.availablePairs, p.err
+}
+
+func TestValidateProviderPairs(t *testing.T) {
+	atomUSDT := types.CurrencyPair{Base: "ATOM", Quote: "USDT"}
+	btcUSDT := types.CurrencyPair{Base: "BTC", Quote: "USDT"}
+
+	testCases := map[string]struct {
+		provider    availablePairsProvider
+		pairs       []types.CurrencyPair
+		unavailable []types.CurrencyPair
+		expectErr   bool
+	}{
+		"all pairs available": {
+			provider: availablePairsProvider{
+				availablePairs: map[string]struct{}{"ATOMUSDT": {}, "BTCUSDT": {}},
+			},
+			pairs: []types.CurrencyPair{atomUSDT, btcUSDT},
+		},
+		"unavailable pair": {
+			provider: availablePairsProvider{
+				availablePairs: map[string]struct{}{"BTCUSDT": {}},
+			},
+			pairs:       []types.CurrencyPair{atomUSDT, btcUSDT},
+			unavailable: []types.CurrencyPair{atomUSDT},
+		},
+		"empty listing": {
+			provider: availablePairsProvider{
+				availablePairs: map[string]struct{}{},
+			},
+			pairs:     []types.CurrencyPair{atomUSDT},
+			expectErr: true,
+		},
+		"provider error": {
+			provider: availablePairsProvider{
+				err: fmt.Errorf("unable to get available pairs"),
+			},
+			pairs:     []types.CurrencyPair{atomUSDT},
+			expectErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			unavailable, err := ValidateProviderPairs(tc.provider, tc.pairs)
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.unavailable, unavailable)
+		})
+	}
+}
+
+func TestValidateProviderPairsDropsUnsupported(t *testing.T) {
+	providers := []string{config.ProviderBinance, config.ProviderKraken, config.ProviderOkx}
+	o := New(zerolog.Nop(), client.OracleClient{}, Options{
+		ReloadConfig: ReloadConfig{
+			CurrencyPairs: []config.CurrencyPair{
+				{Base: "ATOM", ChainDenom: "uatom", Quote: "USDT", Providers: providers},
+				{Base: "BTC", ChainDenom: "ubtc", Quote: "USDT", Providers: providers},
+			},
+			Deviations: make(map[string]math.LegacyDec),
+			Endpoints:  make(map[string]config.ProviderEndpoint),
+		},
+		ProviderTimeout: time.Second,
+	})
+
+	// kraken doesn't list ATOM, so ATOM is left with two providers once the
+	// validation is applied
+	o.validateProviderPairs(context.Background(), config.ProviderKraken, availablePairsProvider{
+		availablePairs: map[string]struct{}{"BTCUSDT": {}},
+	}, o.providerPairs[config.ProviderKraken])
+	require.Len(t, o.providerPairs[config.ProviderKraken], 2)
+
+	o.applyPairValidation(<-o.pairValidations)
+	require.Equal(t, []types.CurrencyPair{{Base: "BTC", Quote: "USDT"}}, o.providerPairs[config.ProviderKraken])
+
+	validation := o.GetPairValidation()
+	require.Equal(t, []string{"ATOMUSDT"}, validation.Unsupported[config.ProviderKraken])
+	require.Equal(t, []string{"ATOM"}, validation.UnderProvided)
+
+	// the pairs are kept when the available pairs can't be fetched
+	o.validateProviderPairs(context.Background(), config.ProviderOkx, availablePairsProvider{
+		err: fmt.Errorf("unable to get available pairs"),
+	}, o.providerPairs[config.ProviderOkx])
+	o.applyPairValidation(<-o.pairValidations)
+	require.Len(t, o.providerPairs[config.ProviderOkx], 2)
+
+	validation = o.GetPairValidation()
+	require.Contains(t, validation.Errors, config.ProviderOkx)
+	require.Equal(t, []string{"ATOM"}, validation.UnderProvided)
+}
+
+func TestCheckProviderPairs(t *testing.T) {
+	providers := []string{config.ProviderBinance, config.ProviderKraken, config.ProviderOkx}
+	o := New(zerolog.Nop(), client.OracleClient{}, Options{
+		ReloadConfig: ReloadConfig{
+			CurrencyPairs: []config.CurrencyPair{
+				{Base: "ATOM", ChainDenom: "uatom", Quote: "USDT", Providers: providers},
+				{Base: "BTC", ChainDenom: "ubtc", Quote: "USDT", Providers: providers},
+			},
+			Deviations: make(map[string]math.LegacyDec),
+			Endpoints:  make(map[string]config.ProviderEndpoint),
+		},
+		ProviderTimeout: time.Second,
+	})
+	o.priceProviders[config.ProviderKraken] = availablePairsProvider{
+		availablePairs: map[string]struct{}{"BTCUSDT": {}},
+	}
+	o.priceProviders[config.ProviderOkx] = availablePairsProvider{
+		err: fmt.Errorf("unable to get available pairs"),
+	}
+
+	// the pairs are validated without starting the oracle
+	unavailablePairs, err := o.CheckProviderPairs(context.Background(), config.ProviderKraken)
+	require.NoError(t, err)
+	require.Equal(t, []string{"ATOMUSDT"}, unavailablePairs)
+	require.Equal(t, []string{"ATOM"}, o.GetPairValidation().UnderProvided)
+
+	_, err = o.CheckProviderPairs(context.Background(), config.ProviderOkx)
+	require.ErrorContains(t, err, "unable to get available pairs")
+}
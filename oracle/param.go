@@ -3,6 +3,7 @@ package oracle
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	oracletypes "github.com/kiichain/kiichain/v3/x/oracle/types"
@@ -109,6 +110,16 @@ func (o *Oracle) GetParams(ctx context.Context) (oracletypes.Params, error) {
 // checkWhitelist validates the denoms on the params' whitelist
 // is on the oracle client chainDenomMapping
 func (o *Oracle) checkWhitelist(params oracletypes.Params) {
+	missingPrices, _ := o.CompareWhitelist(params)
+	for _, denom := range missingPrices {
+		o.logger.Warn().Str("denom", denom).Msg("price missing for required denom")
+	}
+}
+
+// CompareWhitelist compares the params' whitelist with the chain denoms of
+// the configured currency pairs. It returns the whitelisted denoms without a
+// configured price, and the configured denoms which are not whitelisted.
+func (o *Oracle) CompareWhitelist(params oracletypes.Params) (missingPrices []string, notWhitelisted []string) {
 	// iterate over the cached denom mapping
 	chainDenomSet := make(map[string]struct{})
 	for _, denom := range o.chainDenomMapping {
@@ -117,10 +128,21 @@ func (o *Oracle) checkWhitelist(params oracletypes.Params) {
 
 	// iterate over the params's whitelist and validate every denom on
 	// the whitelist is mapped on oracle client chainDenomMapping
+	whitelistSet := make(map[string]struct{})
 	for _, denom := range params.Whitelist {
-		_, ok := chainDenomSet[denom.Name]
-		if !ok {
-			o.logger.Warn().Str("denom", denom.Name).Msg("price missing for required denom")
+		whitelistSet[denom.Name] = struct{}{}
+		if _, ok := chainDenomSet[denom.Name]; !ok {
+			missingPrices = append(missingPrices, denom.Name)
 		}
 	}
+
+	// validate every configured denom is on the whitelist
+	for denom := range chainDenomSet {
+		if _, ok := whitelistSet[denom]; !ok {
+			notWhitelisted = append(notWhitelisted, denom)
+		}
+	}
+	sort.Strings(notWhitelisted)
+
+	return missingPrices, notWhitelisted
 }
@@ -63,3 +63,25 @@ func TestParamCacheIsOutdated(t *testing.T) {
 		})
 	}
 }
+
+func TestCompareWhitelist(t *testing.T) {
+	o := &Oracle{
+		chainDenomMapping: map[string]string{
+			"BTC":  "ubtc",
+			"ETH":  "ueth",
+			"USDT": "uusdt",
+		},
+	}
+
+	params := oracletypes.Params{
+		Whitelist: oracletypes.DenomList{
+			{Name: "ubtc"},
+			{Name: "ueth"},
+			{Name: "usol"},
+		},
+	}
+
+	missingPrices, notWhitelisted := o.CompareWhitelist(params)
+	require.Equal(t, []string{"usol"}, missingPrices)
+	require.Equal(t, []string{"uusdt"}, notWhitelisted)
+}
@@ -0,0 +1,245 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	oracletypes "github.com/kiichain/kiichain/v3/x/oracle/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	sdkmath "cosmossdk.io/math"
+
+	"github.com/cosmos/cosmos-sdk/telemetry"
+)
+
+const (
+	// penaltyCacheIntervalBlocks represents the amount of blocks
+	// during which we will cache the validator's vote penalty counter.
+	penaltyCacheIntervalBlocks = int64(50)
+)
+
+// slashWarningMarginRatio is the portion of the allowed misses on a slash window
+// under which the feeder is considered to be heading toward a slash
+var slashWarningMarginRatio = sdkmath.LegacyNewDecWithPrec(25, 2)
+
+// PenaltyStatus defines the validator's vote penalty counter on the current
+// slash window and how far it is from being slashed.
+type PenaltyStatus struct {
+	MissCount            uint64            `json:"miss_count"`
+	AbstainCount         uint64            `json:"abstain_count"`
+	SuccessCount         uint64            `json:"success_count"`
+	WindowProgress       uint64            `json:"window_progress"`         // vote periods elapsed on the slash window
+	VotePeriodsPerWindow uint64            `json:"vote_periods_per_window"` // vote periods on a full slash window
+	AllowedMisses        int64             `json:"allowed_misses"`          // misses and abstains allowed on a full slash window
+	RemainingMargin      int64             `json:"remaining_margin"`        // misses and abstains left before being slashed
+	ValidVoteRate        sdkmath.LegacyDec `json:"valid_vote_rate"`
+	AtRisk               bool              `json:"at_risk"`
+}
+
+// PenaltyCache is used to cache the validator's penalty status for
+// an amount of blocks, defined by penaltyCacheIntervalBlocks.
+type PenaltyCache struct {
+	status           *PenaltyStatus
+	lastUpdatedBlock int64
+}
+
+// Update updates the lastUpdatedBlock with the most recently block height analized
+// and the penalty status of the validator
+func (penaltyCache *PenaltyCache) Update(currentBlockHeight int64, status PenaltyStatus) {
+	penaltyCache.lastUpdatedBlock = currentBlockHeight
+	penaltyCache.status = &status
+}
+
+// IsOutdated checks if the last analyzed block is further than 50 blocks
+func (penaltyCache *PenaltyCache) IsOutdated(currentBlockHeight int64) bool {
+	if currentBlockHeight < penaltyCacheIntervalBlocks {
+		return false
+	}
+
+	// the current height is lower than the last updated block, update it again
+	if currentBlockHeight < penaltyCache.lastUpdatedBlock {
+		return true
+	}
+
+	return (currentBlockHeight - penaltyCache.lastUpdatedBlock) > penaltyCacheIntervalBlocks
+}
+
+// ComputePenaltyStatus computes how far the validator is from being slashed at
+// the end of the slash window. The validator is slashed when its success votes
+// over the total votes is lower than the MinValidPerWindow param.
+func ComputePenaltyStatus(
+	counter oracletypes.VotePenaltyCounter,
+	windowProgress uint64,
+	params oracletypes.Params,
+) PenaltyStatus {
+	status := PenaltyStatus{
+		MissCount:      counter.MissCount,
+		AbstainCount:   counter.AbstainCount,
+		SuccessCount:   counter.SuccessCount,
+		WindowProgress: windowProgress,
+		ValidVoteRate:  sdkmath.LegacyOneDec(),
+	}
+
+	// without a vote period there is nothing to compute
+	if params.VotePeriod == 0 {
+		return status
+	}
+
+	// current valid vote rate (success votes / total votes)
+	totalVotes := counter.MissCount + counter.AbstainCount + counter.SuccessCount
+	if totalVotes > 0 {
+		status.ValidVoteRate = sdkmath.LegacyNewDec(int64(counter.SuccessCount)).QuoInt64(int64(totalVotes))
+	}
+
+	// success votes needed on a full window, the remaining votes can be missed
+	status.VotePeriodsPerWindow = params.SlashWindow / params.VotePeriod
+	minValidVotes := params.MinValidPerWindow.MulInt64(int64(status.VotePeriodsPerWindow)).Ceil().TruncateInt64()
+	status.AllowedMisses = int64(status.VotePeriodsPerWindow) - minValidVotes
+	status.RemainingMargin = status.AllowedMisses - int64(counter.MissCount+counter.AbstainCount)
+
+	// the feeder is heading toward a slash once most of the allowed misses are used
+	warningMargin := slashWarningMarginRatio.MulInt64(status.AllowedMisses).TruncateInt64()
+	status.AtRisk = status.RemainingMargin < warningMargin
+
+	return status
+}
+
+// GetCachedPenaltyStatus returns the cached penalty status of the validator,
+// querying it again when the cache is outdated.
+func (o *Oracle) GetCachedPenaltyStatus(
+	ctx context.Context,
+	currentBlockHeight int64,
+	params oracletypes.Params,
+) (*PenaltyStatus, error) {
+	// check if the cached info is outdated (if no, return the cached data)
+	if !o.penaltyCache.IsOutdated(currentBlockHeight) {
+		return o.penaltyCache.status, nil
+	}
+
+	// query the validator's counter and the slash window progress
+	counter, err := o.GetVotePenaltyCounter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	windowProgress, err := o.GetSlashWindowProgress(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	status := ComputePenaltyStatus(counter, windowProgress, params)
+
+	// publish the status
+	telemetry.SetGauge(float32(status.MissCount), "vote_penalty", "miss")
+	telemetry.SetGauge(float32(status.AbstainCount), "vote_penalty", "abstain")
+	telemetry.SetGauge(float32(status.SuccessCount), "vote_penalty", "success")
+	telemetry.SetGauge(float32(status.RemainingMargin), "vote_penalty", "remaining_margin")
+
+	if status.AtRisk {
+		telemetry.IncrCounter(1, "vote_penalty", "slash_risk")
+		o.logger.Warn().
+			Uint64("miss_count", status.MissCount).
+			Uint64("abstain_count", status.AbstainCount).
+			Uint64("success_count", status.SuccessCount).
+			Uint64("window_progress", status.WindowProgress).
+			Uint64("vote_periods_per_window", status.VotePeriodsPerWindow).
+			Int64("remaining_margin", status.RemainingMargin).
+			Msg("validator is heading toward an oracle slash")
+	}
+
+	// update the cached info
+	o.mtx.Lock()
+	o.penaltyCache.Update(currentBlockHeight, status)
+	o.mtx.Unlock()
+
+	return &status, nil
+}
+
+// GetPenaltyStatus returns a copy of the last penalty status of the validator,
+// or nil if it wasn't queried yet.
+func (o *Oracle) GetPenaltyStatus() *PenaltyStatus {
+	o.mtx.RLock()
+	defer o.mtx.RUnlock()
+
+	if o.penaltyCache.status == nil {
+		return nil
+	}
+
+	status := *o.penaltyCache.status
+	return &status
+}
+
+// GetVotePenaltyCounter returns the current on-chain vote penalty counter of the validator
+func (o *Oracle) GetVotePenaltyCounter(ctx context.Context) (oracletypes.VotePenaltyCounter, error) {
+	// create grpc connection with the blockchain
+	grpcConn, err := grpc.NewClient(
+		o.oracleClient.GRPCEndpoint,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(dialerFunc),
+	)
+	if err != nil {
+		return oracletypes.VotePenaltyCounter{}, fmt.Errorf("failed to dial Cosmos gRPC service: %w", err)
+	}
+
+	defer grpcConn.Close()
+
+	// create oracle query client
+	queryClient := oracletypes.NewQueryClient(grpcConn)
+
+	// create context with timeout
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	// query the validator's vote penalty counter
+	queryResponse, err := queryClient.VotePenaltyCounter(
+		ctx,
+		&oracletypes.QueryVotePenaltyCounterRequest{ValidatorAddr: o.oracleClient.ValidatorAddrString},
+	)
+	if err != nil {
+		// the counter is removed at the end of every slash window, until the next tally
+		if strings.Contains(err.Error(), "not found") {
+			return oracletypes.VotePenaltyCounter{}, nil
+		}
+		return oracletypes.VotePenaltyCounter{}, fmt.Errorf("failed to get x/oracle vote penalty counter: %w", err)
+	}
+
+	// the counter may be empty until the validator takes part on a tally
+	if queryResponse.VotePenaltyCounter == nil {
+		return oracletypes.VotePenaltyCounter{}, nil
+	}
+
+	return *queryResponse.VotePenaltyCounter, nil
+}
+
+// GetSlashWindowProgress returns the amount of vote periods elapsed on the current slash window
+func (o *Oracle) GetSlashWindowProgress(ctx context.Context) (uint64, error) {
+	// create grpc connection with the blockchain
+	grpcConn, err := grpc.NewClient(
+		o.oracleClient.GRPCEndpoint,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(dialerFunc),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to dial Cosmos gRPC service: %w", err)
+	}
+
+	defer grpcConn.Close()
+
+	// create oracle query client
+	queryClient := oracletypes.NewQueryClient(grpcConn)
+
+	// create context with timeout
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	// query the slash window progress
+	queryResponse, err := queryClient.SlashWindow(ctx, &oracletypes.QuerySlashWindowRequest{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get x/oracle slash window: %w", err)
+	}
+
+	return queryResponse.WindowProgress, nil
+}
@@ -0,0 +1,113 @@
+package oracle
+
+import (
+	"testing"
+
+	oracletypes "github.com/kiichain/kiichain/v3/x/oracle/types"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/math"
+)
+
+func TestComputePenaltyStatus(t *testing.T) {
+	params := oracletypes.Params{
+		VotePeriod:        10,
+		SlashWindow:       1000,
+		MinValidPerWindow: math.LegacyMustNewDecFromStr("0.05"),
+	}
+
+	testCases := map[string]struct {
+		counter                 oracletypes.VotePenaltyCounter
+		params                  oracletypes.Params
+		expectedAllowedMisses   int64
+		expectedRemainingMargin int64
+		expectedAtRisk          bool
+	}{
+		"Healthy": {
+			counter:                 oracletypes.VotePenaltyCounter{MissCount: 10, SuccessCount: 40},
+			params:                  params,
+			expectedAllowedMisses:   95,
+			expectedRemainingMargin: 85,
+			expectedAtRisk:          false,
+		},
+		"Heading toward a slash": {
+			counter:                 oracletypes.VotePenaltyCounter{MissCount: 60, AbstainCount: 15, SuccessCount: 5},
+			params:                  params,
+			expectedAllowedMisses:   95,
+			expectedRemainingMargin: 20,
+			expectedAtRisk:          true,
+		},
+		"Allowed misses exceeded": {
+			counter:                 oracletypes.VotePenaltyCounter{MissCount: 96},
+			params:                  params,
+			expectedAllowedMisses:   95,
+			expectedRemainingMargin: -1,
+			expectedAtRisk:          true,
+		},
+		"No misses allowed": {
+			counter: oracletypes.VotePenaltyCounter{SuccessCount: 10},
+			params: oracletypes.Params{
+				VotePeriod:        10,
+				SlashWindow:       1000,
+				MinValidPerWindow: math.LegacyOneDec(),
+			},
+			expectedAllowedMisses:   0,
+			expectedRemainingMargin: 0,
+			expectedAtRisk:          false,
+		},
+		"Zero vote period": {
+			counter:                 oracletypes.VotePenaltyCounter{MissCount: 10},
+			params:                  oracletypes.Params{},
+			expectedAllowedMisses:   0,
+			expectedRemainingMargin: 0,
+			expectedAtRisk:          false,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			status := ComputePenaltyStatus(tc.counter, 50, tc.params)
+			require.Equal(t, tc.expectedAllowedMisses, status.AllowedMisses)
+			require.Equal(t, tc.expectedRemainingMargin, status.RemainingMargin)
+			require.Equal(t, tc.expectedAtRisk, status.AtRisk)
+			require.Equal(t, uint64(50), status.WindowProgress)
+		})
+	}
+}
+
+func TestPenaltyCacheIsOutdated(t *testing.T) {
+	testCases := map[string]struct {
+		penaltyCache       PenaltyCache
+		currentBlockHeight int64
+		expected           bool
+	}{
+		"currentBlockHeight < penaltyCacheIntervalBlocks": {
+			penaltyCache:       PenaltyCache{},
+			currentBlockHeight: 49,
+			expected:           false,
+		},
+		"currentBlockHeight < lastUpdatedBlock": {
+			penaltyCache:       PenaltyCache{lastUpdatedBlock: 205},
+			currentBlockHeight: 203,
+			expected:           true,
+		},
+		"Outdated": {
+			penaltyCache:       PenaltyCache{lastUpdatedBlock: 100},
+			currentBlockHeight: 151,
+			expected:           true,
+		},
+		"Limit to keep in cache": {
+			penaltyCache:       PenaltyCache{lastUpdatedBlock: 100},
+			currentBlockHeight: 150,
+			expected:           false,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.penaltyCache.IsOutdated(tc.currentBlockHeight))
+		})
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"net/url"
 	"strings"
 	"sync"
@@ -24,9 +23,16 @@ const (
 	binanceWSPath   = "/ws/umeestream"
 	binanceRestHost = "https://api1.binance.com"
 	binanceRestPath = "/api/v3/ticker/price"
+
+	// binanceDepthStream is the suffix of the partial book depth streams, with
+	// the best 20 levels of each side
+	binanceDepthStream = "@depth20"
 )
 
-var _ Provider = (*BinanceProvider)(nil)
+var (
+	_ Provider          = (*BinanceProvider)(nil)
+	_ OrderBookProvider = (*BinanceProvider)(nil)
+)
 
 type (
 	// BinanceProvider defines an Oracle provider implemented by the Binance public
@@ -34,6 +40,7 @@ type (
 	//
 	// REF: https://binance-docs.github.io/apidocs/spot/en/#individual-symbol-mini-ticker-stream
 	// REF: https://binance-docs.github.io/apidocs/spot/en/#kline-candlestick-streams
+	// REF: https://binance-docs.github.io/apidocs/spot/en/#partial-book-depth-streams
 	BinanceProvider struct {
 		wsURL           url.URL
 		wsClient        *websocket.Conn
@@ -42,6 +49,7 @@ type (
 		endpoints       config.ProviderEndpoint
 		tickers         map[string]BinanceTicker      // Symbol => BinanceTicker
 		candles         map[string][]BinanceCandle    // Symbol => BinanceCandle
+		orderBooks      map[string]OrderBook          // Symbol => OrderBook
 		subscribedPairs map[string]types.CurrencyPair // Symbol => types.CurrencyPair
 	}
 
@@ -70,6 +78,27 @@ type (
 		Metadata BinanceCandleMetadata `json:"k"` // Metadata for candle
 	}
 
+	// BinanceDepth partial book depth response, the stream is only named on
+	// the combined stream payloads.
+	BinanceDepth struct {
+		LastUpdateID int64      `json:"lastUpdateId"` // Last update id ex.: 160
+		Bids         [][]string `json:"bids"`         // Bids as [price, size] ex.: [["0.0024", "10"]]
+		Asks         [][]string `json:"asks"`         // Asks as [price, size] ex.: [["0.0026", "100"]]
+	}
+
+	// BinanceCombinedMsg wraps the payloads of the combined streams.
+	BinanceCombinedMsg struct {
+		Stream string          `json:"stream"` // Stream name ex.: atomusdt@depth20
+		Data   json.RawMessage `json:"data"`   // Raw stream payload
+	}
+
+	// BinancePropertyMsg Msg to set a property of the connection.
+	BinancePropertyMsg struct {
+		Method string        `json:"method"` // SET_PROPERTY
+		Params []interface{} `json:"params"` // property and value ex.: ["combined", true]
+		ID     uint16        `json:"id"`     // identify messages going back and forth
+	}
+
 	// BinanceSubscribeMsg Msg to subscribe all the tickers channels.
 	BinanceSubscriptionMsg struct {
 		Method string   `json:"method"` // SUBSCRIBE/UNSUBSCRIBE
@@ -121,6 +150,7 @@ func NewBinanceProvider(
 		endpoints:       endpoints,
 		tickers:         map[string]BinanceTicker{},
 		candles:         map[string][]BinanceCandle{},
+		orderBooks:      map[string]OrderBook{},
 		subscribedPairs: map[string]types.CurrencyPair{},
 	}
 
@@ -167,6 +197,21 @@ func (p *BinanceProvider) GetCandlePrices(pairs ...types.CurrencyPair) (map[stri
 	return candlePrices, nil
 }
 
+// GetOrderBooks returns the order books of the provided pairs.
+func (p *BinanceProvider) GetOrderBooks(pairs ...types.CurrencyPair) (map[string]OrderBook, error) {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	orderBooks := make(map[string]OrderBook, len(pairs))
+	for _, cp := range pairs {
+		if orderBook, ok := p.orderBooks[cp.String()]; ok {
+			orderBooks[cp.String()] = orderBook
+		}
+	}
+
+	return orderBooks, nil
+}
+
 // SubscribeCurrencyPairs subscribe all currency pairs into ticker and candle channels.
 func (p *BinanceProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) error {
 	if len(cps) == 0 {
@@ -181,13 +226,23 @@ func (p *BinanceProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) erro
 	return nil
 }
 
-// subscribeChannels subscribe to the ticker and candle channels for all currency pairs.
+// subscribeChannels subscribe to the ticker, candle and depth channels for all
+// currency pairs. The depth payloads don't have their symbol, so the payloads
+// are received wrapped with their stream name.
 func (p *BinanceProvider) subscribeChannels(cps ...types.CurrencyPair) error {
+	if err := p.wsClient.WriteJSON(newBinanceCombinedPropertyMsg()); err != nil {
+		return err
+	}
+
 	if err := p.subscribeTickers(cps...); err != nil {
 		return err
 	}
 
-	return p.subscribeCandles(cps...)
+	if err := p.subscribeCandles(cps...); err != nil {
+		return err
+	}
+
+	return p.subscribeDepths(cps...)
 }
 
 // subscribeTickers subscribe to the ticker channel for all currency pairs.
@@ -212,6 +267,18 @@ func (p *BinanceProvider) subscribeCandles(cps ...types.CurrencyPair) error {
 	return p.subscribePairs(pairs...)
 }
 
+// subscribeDepths subscribe to the partial book depth channel for all currency
+// pairs.
+func (p *BinanceProvider) subscribeDepths(cps ...types.CurrencyPair) error {
+	pairs := make([]string, len(cps))
+
+	for i, cp := range cps {
+		pairs[i] = currencyPairToBinanceDepthPair(cp)
+	}
+
+	return p.subscribePairs(pairs...)
+}
+
 // subscribedPairsToSlice returns the map of subscribed pairs as a slice.
 func (p *BinanceProvider) subscribedPairsToSlice() []types.CurrencyPair {
 	p.mtx.RLock()
@@ -258,12 +325,22 @@ func (p *BinanceProvider) messageReceived(messageType int, bz []byte) {
 	}
 
 	var (
-		tickerResp BinanceTicker
-		tickerErr  error
-		candleResp BinanceCandle
-		candleErr  error
+		combinedResp BinanceCombinedMsg
+		tickerResp   BinanceTicker
+		tickerErr    error
+		candleResp   BinanceCandle
+		candleErr    error
 	)
 
+	// unwrap the payloads of the combined streams
+	if err := json.Unmarshal(bz, &combinedResp); err == nil && len(combinedResp.Data) != 0 {
+		if strings.HasSuffix(combinedResp.Stream, binanceDepthStream) {
+			p.messageReceivedDepth(combinedResp.Stream, combinedResp.Data)
+			return
+		}
+		bz = combinedResp.Data
+	}
+
 	tickerErr = json.Unmarshal(bz, &tickerResp)
 	if len(tickerResp.LastPrice) != 0 {
 		p.setTickerPair(tickerResp)
@@ -301,6 +378,39 @@ func (p *BinanceProvider) messageReceived(messageType int, bz []byte) {
 		Msg("Error on receive message")
 }
 
+// messageReceivedDepth handles the partial book depth of a stream.
+func (p *BinanceProvider) messageReceivedDepth(stream string, bz []byte) {
+	var depthResp BinanceDepth
+	if err := json.Unmarshal(bz, &depthResp); err != nil {
+		p.logger.Err(err).Str("stream", stream).Msg("could not unmarshal depth message")
+		return
+	}
+
+	symbol := strings.ToUpper(strings.TrimSuffix(stream, binanceDepthStream))
+	orderBook, err := newOrderBook("Binance", symbol, depthResp.Bids, depthResp.Asks, time.Now().UnixMilli())
+	if err != nil {
+		p.logger.Err(err).Msg("could not parse depth message")
+		return
+	}
+
+	p.setOrderBook(symbol, orderBook)
+	telemetry.IncrCounter(
+		1,
+		"websocket",
+		"message",
+		"type",
+		"depth",
+		"provider",
+		config.ProviderBinance,
+	)
+}
+
+func (p *BinanceProvider) setOrderBook(symbol string, orderBook OrderBook) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.orderBooks[symbol] = orderBook
+}
+
 func (p *BinanceProvider) setTickerPair(ticker BinanceTicker) {
 	p.mtx.Lock()
 	defer p.mtx.Unlock()
@@ -434,14 +544,8 @@ func (p *BinanceProvider) subscribePairs(pairs ...string) error {
 // GetAvailablePairs returns all pairs to which the provider can subscribe.
 // ex.: map["ATOMUSDT" => {}, "UMEEUSDC" => {}].
 func (p *BinanceProvider) GetAvailablePairs() (map[string]struct{}, error) {
-	resp, err := http.Get(p.endpoints.Rest + binanceRestPath)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
 	var pairsSummary []BinancePairSummary
-	if err := json.NewDecoder(resp.Body).Decode(&pairsSummary); err != nil {
+	if err := getAvailablePairsJSON(p.endpoints.Rest+binanceRestPath, &pairsSummary); err != nil {
 		return nil, err
 	}
 
@@ -465,6 +569,22 @@ func currencyPairToBinanceCandlePair(cp types.CurrencyPair) string {
 	return strings.ToLower(cp.String() + "@kline_1m")
 }
 
+// currencyPairToBinanceDepthPair receives a currency pair and return binance
+// depth symbol atomusdt@depth20.
+func currencyPairToBinanceDepthPair(cp types.CurrencyPair) string {
+	return strings.ToLower(cp.String() + binanceDepthStream)
+}
+
+// newBinanceCombinedPropertyMsg returns the Msg receiving the payloads wrapped
+// with their stream name.
+func newBinanceCombinedPropertyMsg() BinancePropertyMsg {
+	return BinancePropertyMsg{
+		Method: "SET_PROPERTY",
+		Params: []interface{}{"combined", true},
+		ID:     2,
+	}
+}
+
 // newBinanceSubscriptionMsg returns a new subscription Msg.
 func newBinanceSubscriptionMsg(params ...string) BinanceSubscriptionMsg {
 	return BinanceSubscriptionMsg{
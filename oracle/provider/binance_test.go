@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/gorilla/websocket"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
 
@@ -116,3 +117,33 @@ func TestBinanceCurrencyPairToBinancePair(t *testing.T) {
 	binanceSymbol := currencyPairToBinanceTickerPair(cp)
 	require.Equal(t, binanceSymbol, "atomusdt@ticker")
 }
+
+func TestBinanceProvider_MessageReceivedDepth(t *testing.T) {
+	p := &BinanceProvider{
+		logger:     zerolog.Nop(),
+		tickers:    map[string]BinanceTicker{},
+		orderBooks: map[string]OrderBook{},
+	}
+
+	p.messageReceived(websocket.TextMessage, []byte(`{
+		"stream": "atomusdt@depth20",
+		"data": {
+			"lastUpdateId": 160,
+			"bids": [["9.98", "12"], ["9.99", "10"]],
+			"asks": [["10.01", "8"], ["10.02", "20"]]
+		}
+	}`))
+
+	orderBooks, err := p.GetOrderBooks(types.CurrencyPair{Base: "ATOM", Quote: "USDT"})
+	require.NoError(t, err)
+	require.Len(t, orderBooks, 1)
+	require.Equal(t, math.LegacyMustNewDecFromStr("9.99"), orderBooks["ATOMUSDT"].BestBid())
+	require.Equal(t, math.LegacyMustNewDecFromStr("10.01"), orderBooks["ATOMUSDT"].BestAsk())
+
+	// the tickers are unwrapped from the combined stream
+	p.messageReceived(websocket.TextMessage, []byte(`{
+		"stream": "atomusdt@ticker",
+		"data": {"s": "ATOMUSDT", "c": "10", "v": "1000"}
+	}`))
+	require.Equal(t, "10", p.tickers["ATOMUSDT"].LastPrice)
+}
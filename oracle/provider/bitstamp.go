@@ -0,0 +1,404 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+
+	"github.com/cosmos/cosmos-sdk/telemetry"
+
+	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle/types"
+)
+
+const (
+	bitstampWSHost              = "ws.bitstamp.net"
+	bitstampRestHost            = "https://www.bitstamp.net"
+	bitstampRestPath            = "/api/v2/trading-pairs-info/"
+	bitstampTickerPath          = "/api/v2/ticker/"
+	bitstampTradeChannelPrefix  = "live_trades_"
+	bitstampSubscribeEvent      = "bts:subscribe"
+	bitstampTradeEvent          = "trade"
+	bitstampErrorEvent          = "bts:error"
+	bitstampReconnectEvent      = "bts:request_reconnect"
+	bitstampTradingEnabled      = "Enabled"
+	bitstampPingDuration        = 30 * time.Second
+	bitstampTickerPollInterval  = 10 * time.Second
+	bitstampMicrosecondsPerMsec = 1000
+)
+
+var _ Provider = (*BitstampProvider)(nil)
+
+type (
+	// BitstampProvider defines an Oracle provider implemented by the Bitstamp public
+	// API. The candles are built from the live trades, and the tickers are
+	// polled from the REST API, as the websocket has no ticker channel.
+	//
+	// REF: https://www.bitstamp.net/websocket/v2/
+	BitstampProvider struct {
+		wsc             *WebsocketController
+		logger          zerolog.Logger
+		mtx             sync.RWMutex
+		endpoint        config.ProviderEndpoint
+		client          *http.Client
+		tickers         map[string]TickerPrice        // Symbol => TickerPrice
+		trades          map[string][]TradePrice       // Symbol => []TradePrice
+		subscribedPairs map[string]types.CurrencyPair // Symbol => types.CurrencyPair
+	}
+
+	BitstampMessage struct {
+		Event   string          `json:"event"`   // ex.: trade, bts:subscription_succeeded
+		Channel string          `json:"channel"` // ex.: live_trades_btcusd
+		Data    json.RawMessage `json:"data"`
+	}
+	BitstampTrade struct {
+		ID             int64  `json:"id"`             // Trade id
+		Price          string `json:"price_str"`      // ex.: 26000.5
+		Amount         string `json:"amount_str"`     // Size of the trade ex.: 0.01
+		Microtimestamp string `json:"microtimestamp"` // Trade time in microseconds
+	}
+	BitstampError struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+
+	BitstampTicker struct {
+		Pair   string `json:"pair"`   // ex.: BTC/USD
+		Last   string `json:"last"`   // Last traded price
+		Volume string `json:"volume"` // Volume over the last 24 hours
+	}
+
+	BitstampSubscriptionMsg struct {
+		Event string                   `json:"event"` // bts:subscribe, bts:unsubscribe
+		Data  BitstampSubscriptionData `json:"data"`
+	}
+	BitstampSubscriptionData struct {
+		Channel string `json:"channel"` // ex.: live_trades_btcusd
+	}
+
+	BitstampPairSummary struct {
+		Name    string `json:"name"`    // ex.: BTC/USD
+		Trading string `json:"trading"` // Enabled or Disabled
+	}
+)
+
+func NewBitstampProvider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	endpoint config.ProviderEndpoint,
+	pairs ...types.CurrencyPair,
+) (*BitstampProvider, error) {
+	if endpoint.Name != config.ProviderBitstamp {
+		endpoint = config.ProviderEndpoint{
+			Name:      config.ProviderBitstamp,
+			Rest:      bitstampRestHost,
+			Websocket: bitstampWSHost,
+		}
+	}
+
+	wsURL := url.URL{
+		Scheme: "wss",
+		Host:   endpoint.Websocket,
+	}
+
+	provider := &BitstampProvider{
+		logger:          logger.With().Str("provider", "bitstamp").Logger(),
+		endpoint:        endpoint,
+		client:          newDefaultHTTPClient(),
+		tickers:         map[string]TickerPrice{},
+		trades:          map[string][]TradePrice{},
+		subscribedPairs: map[string]types.CurrencyPair{},
+	}
+
+	provider.setSubscribedPairs(pairs...)
+
+	provider.wsc = NewWebsocketController(
+		ctx,
+		config.ProviderBitstamp,
+		wsURL,
+		provider.getSubscriptionMsgs(pairs...),
+		provider.messageReceived,
+		bitstampPingDuration,
+		websocket.PingMessage,
+		provider.logger,
+	)
+
+	go provider.wsc.Start()
+	go provider.pollTickers(ctx)
+
+	return provider, nil
+}
+
+// pollTickers refreshes the tickers periodically, so the oracle reads them
+// without waiting on the REST API
+func (p *BitstampProvider) pollTickers(ctx context.Context) {
+	pollTicker := time.NewTicker(bitstampTickerPollInterval)
+	defer pollTicker.Stop()
+
+	for {
+		if err := p.refreshTickers(); err != nil {
+			p.logger.Warn().Err(err).Msg("failed to refresh tickers")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-pollTicker.C:
+		}
+	}
+}
+
+// refreshTickers requests the tickers of all pairs from the REST API
+func (p *BitstampProvider) refreshTickers() error {
+	resp, err := p.client.Get(p.endpoint.Rest + bitstampTickerPath)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var tickers []BitstampTicker
+	if err := json.NewDecoder(resp.Body).Decode(&tickers); err != nil {
+		return err
+	}
+
+	tickerPrices := make(map[string]TickerPrice, len(tickers))
+	for _, ticker := range tickers {
+		symbol := strings.ToLower(strings.ReplaceAll(ticker.Pair, "/", ""))
+		tickerPrice, err := newTickerPrice(config.ProviderBitstamp, symbol, ticker.Last, ticker.Volume)
+		if err != nil {
+			p.logger.Debug().Err(err).Msg("bitstamp: failed to parse ticker")
+			continue
+		}
+		tickerPrices[symbol] = tickerPrice
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.tickers = tickerPrices
+	return nil
+}
+
+func (p *BitstampProvider) getSubscriptionMsgs(cps ...types.CurrencyPair) []interface{} {
+	subscriptionMsgs := make([]interface{}, 0, len(cps))
+	for _, cp := range cps {
+		channel := bitstampTradeChannelPrefix + currencyPairToBitstampPair(cp)
+		subscriptionMsgs = append(subscriptionMsgs, newBitstampSubscriptionMsg(channel))
+	}
+	return subscriptionMsgs
+}
+
+// SubscribeCurrencyPairs sends the new subscription messages to the websocket
+// and adds them to the providers subscribedPairs array
+func (p *BitstampProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	newPairs := []types.CurrencyPair{}
+	for _, cp := range cps {
+		if _, ok := p.subscribedPairs[cp.String()]; !ok {
+			newPairs = append(newPairs, cp)
+		}
+	}
+
+	newSubscriptionMsgs := p.getSubscriptionMsgs(newPairs...)
+	if err := p.wsc.AddSubscriptionMsgs(newSubscriptionMsgs); err != nil {
+		return err
+	}
+
+	p.setSubscribedPairs(newPairs...)
+	return nil
+}
+
+// GetTickerPrices returns the tickerPrices based on the saved map.
+func (p *BitstampProvider) GetTickerPrices(pairs ...types.CurrencyPair) (map[string]TickerPrice, error) {
+	tickerPrices := make(map[string]TickerPrice, len(pairs))
+
+	for _, cp := range pairs {
+		key := currencyPairToBitstampPair(cp)
+		price, err := p.getTickerPrice(key)
+		if err != nil {
+			p.logger.Debug().AnErr("err", err).Msg(fmt.Sprint("failed to fetch tickers for pair ", cp))
+			continue
+		}
+		tickerPrices[cp.String()] = price
+	}
+
+	return tickerPrices, nil
+}
+
+// GetCandlePrices returns the one minute candles built from the saved trades.
+func (p *BitstampProvider) GetCandlePrices(pairs ...types.CurrencyPair) (map[string][]CandlePrice, error) {
+	candlePrices := make(map[string][]CandlePrice, len(pairs))
+
+	for _, cp := range pairs {
+		key := currencyPairToBitstampPair(cp)
+		trades, err := p.getTradePrices(key)
+		if err != nil {
+			p.logger.Debug().AnErr("err", err).Msg(fmt.Sprint("failed to fetch candles for pair ", cp))
+			continue
+		}
+		candlePrices[cp.String()] = tradesToCandles(trades)
+	}
+
+	return candlePrices, nil
+}
+
+func (p *BitstampProvider) getTickerPrice(key string) (TickerPrice, error) {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	ticker, ok := p.tickers[key]
+	if !ok {
+		return TickerPrice{}, fmt.Errorf("%s ticker not found for %s", config.ProviderBitstamp, key)
+	}
+
+	return ticker, nil
+}
+
+func (p *BitstampProvider) getTradePrices(key string) ([]TradePrice, error) {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	trades, ok := p.trades[key]
+	if !ok {
+		return []TradePrice{}, fmt.Errorf("%s trades not found for %s", config.ProviderBitstamp, key)
+	}
+
+	return trades, nil
+}
+
+func (p *BitstampProvider) messageReceived(messageType int, bz []byte) {
+	if messageType != websocket.TextMessage {
+		return
+	}
+
+	var msg BitstampMessage
+	if err := json.Unmarshal(bz, &msg); err != nil {
+		p.logger.Error().
+			Int("length", len(bz)).
+			AnErr("message", err).
+			Msg("Error on receive message")
+		return
+	}
+
+	switch msg.Event {
+	case bitstampTradeEvent:
+		var trade BitstampTrade
+		if err := json.Unmarshal(msg.Data, &trade); err != nil {
+			p.logger.Error().Err(err).Str("channel", msg.Channel).Msg("failed to unmarshal trade")
+			return
+		}
+		p.setTradePair(strings.TrimPrefix(msg.Channel, bitstampTradeChannelPrefix), trade)
+		telemetry.IncrCounter(
+			1,
+			"websocket",
+			"message",
+			"type",
+			"trade",
+			"provider",
+			config.ProviderBitstamp,
+		)
+
+	case bitstampErrorEvent:
+		var bitstampErr BitstampError
+		if err := json.Unmarshal(msg.Data, &bitstampErr); err != nil {
+			p.logger.Debug().Err(err).Msg("unable to unmarshal error response")
+		}
+		p.logger.Error().Int("code", bitstampErr.Code).Msg(bitstampErr.Message)
+
+	case bitstampReconnectEvent:
+		// the server closes the connection afterwards, and the controller
+		// reconnects
+		p.logger.Info().Msg("bitstamp requested a reconnection")
+	}
+}
+
+func (p *BitstampProvider) setTradePair(symbol string, bitstampTrade BitstampTrade) {
+	microtimestamp, err := strconv.ParseInt(bitstampTrade.Microtimestamp, 10, 64)
+	if err != nil {
+		p.logger.Warn().Err(err).Msg("bitstamp: failed to parse trade time")
+		return
+	}
+
+	trade, err := newTradePrice(
+		config.ProviderBitstamp,
+		symbol,
+		bitstampTrade.ID,
+		bitstampTrade.Price,
+		bitstampTrade.Amount,
+		microtimestamp/bitstampMicrosecondsPerMsec,
+	)
+	if err != nil {
+		p.logger.Warn().Err(err).Msg("bitstamp: failed to parse trade")
+		return
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.trades[symbol] = appendTrade(p.trades[symbol], trade)
+}
+
+// setSubscribedPairs sets N currency pairs to the map of subscribed pairs.
+func (p *BitstampProvider) setSubscribedPairs(cps ...types.CurrencyPair) {
+	for _, cp := range cps {
+		p.subscribedPairs[cp.String()] = cp
+	}
+}
+
+// GetAvailablePairs returns all pairs to which the provider can subscribe.
+// ex.: map["BTCUSD" => {}, "ETHUSD" => {}].
+func (p *BitstampProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	var pairsSummary []BitstampPairSummary
+	if err := getAvailablePairsJSON(p.endpoint.Rest+bitstampRestPath, &pairsSummary); err != nil {
+		return nil, err
+	}
+
+	availablePairs := make(map[string]struct{}, len(pairsSummary))
+	for _, pair := range pairsSummary {
+		if pair.Trading != bitstampTradingEnabled {
+			continue
+		}
+
+		splitName := strings.Split(pair.Name, "/")
+		if len(splitName) != 2 {
+			continue
+		}
+
+		cp := types.CurrencyPair{
+			Base:  strings.ToUpper(splitName[0]),
+			Quote: strings.ToUpper(splitName[1]),
+		}
+
+		availablePairs[cp.String()] = struct{}{}
+	}
+
+	return availablePairs, nil
+}
+
+// currencyPairToBitstampPair receives a currency pair and return bitstamp
+// symbol ex.: btcusd.
+func currencyPairToBitstampPair(cp types.CurrencyPair) string {
+	return strings.ToLower(cp.Base + cp.Quote)
+}
+
+// newBitstampSubscriptionMsg returns a new subscription Msg.
+func newBitstampSubscriptionMsg(channel string) BitstampSubscriptionMsg {
+	return BitstampSubscriptionMsg{
+		Event: bitstampSubscribeEvent,
+		Data: BitstampSubscriptionData{
+			Channel: channel,
+		},
+	}
+}
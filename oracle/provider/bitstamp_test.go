@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/math"
+
+	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle/types"
+)
+
+func TestBitstampProvider_GetTickerPrices(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		require.Equal(t, bitstampTickerPath, req.URL.String())
+		resp := `[
+{"pair":"BTC/USD","last":"62410","volume":"1532.31"},
+{"pair":"ETH/USD","last":"2450.1","volume":"10250.4"}
+]`
+		_, err := rw.Write([]byte(resp))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	p := &BitstampProvider{
+		logger: zerolog.Nop(),
+		endpoint: config.ProviderEndpoint{
+			Name: config.ProviderBitstamp,
+			Rest: server.URL,
+		},
+		client:  server.Client(),
+		tickers: map[string]TickerPrice{},
+	}
+	require.NoError(t, p.refreshTickers())
+
+	t.Run("valid_request_multi_ticker", func(t *testing.T) {
+		prices, err := p.GetTickerPrices(
+			types.CurrencyPair{Base: "BTC", Quote: "USD"},
+			types.CurrencyPair{Base: "ETH", Quote: "USD"},
+		)
+		require.NoError(t, err)
+		require.Len(t, prices, 2)
+		require.Equal(t, math.LegacyMustNewDecFromStr("62410"), prices["BTCUSD"].Price)
+		require.Equal(t, math.LegacyMustNewDecFromStr("1532.31"), prices["BTCUSD"].Volume)
+		require.Equal(t, math.LegacyMustNewDecFromStr("2450.1"), prices["ETHUSD"].Price)
+		require.Equal(t, math.LegacyMustNewDecFromStr("10250.4"), prices["ETHUSD"].Volume)
+	})
+
+	t.Run("invalid_request_invalid_ticker", func(t *testing.T) {
+		prices, err := p.GetTickerPrices(types.CurrencyPair{Base: "FOO", Quote: "BAR"})
+		require.NoError(t, err)
+		require.Zero(t, len(prices))
+	})
+}
+
+func TestBitstampProvider_GetCandlePrices(t *testing.T) {
+	server := NewMockProviderServer()
+	server.Start()
+	defer server.Close()
+
+	p, err := NewBitstampProvider(
+		context.TODO(),
+		zerolog.Nop(),
+		config.ProviderEndpoint{
+			Name:      config.ProviderBitstamp,
+			Rest:      "",
+			Websocket: server.GetBaseURL(),
+		},
+		types.CurrencyPair{Base: "BTC", Quote: "USD"},
+	)
+	require.NoError(t, err)
+
+	t.Run("valid_trade_messages", func(t *testing.T) {
+		microtimestamp := time.Now().UnixMicro()
+		first := `{"event":"trade","channel":"live_trades_btcusd","data":{"price_str":"62400","amount_str":"0.25","microtimestamp":"` +
+			strconv.FormatInt(microtimestamp, 10) + `"}}`
+		second := `{"event":"trade","channel":"live_trades_btcusd","data":{"price_str":"62410","amount_str":"0.5","microtimestamp":"` +
+			strconv.FormatInt(microtimestamp+1000000, 10) + `"}}`
+		p.messageReceived(websocket.TextMessage, []byte(first))
+		p.messageReceived(websocket.TextMessage, []byte(second))
+
+		prices, err := p.GetCandlePrices(types.CurrencyPair{Base: "BTC", Quote: "USD"})
+		require.NoError(t, err)
+		require.Len(t, prices["BTCUSD"], 1)
+		require.Equal(t, math.LegacyMustNewDecFromStr("62410"), prices["BTCUSD"][0].Price)
+		require.Equal(t, math.LegacyMustNewDecFromStr("0.75"), prices["BTCUSD"][0].Volume)
+		require.Equal(t, microtimestamp/1000+1000, prices["BTCUSD"][0].TimeStamp)
+	})
+
+	t.Run("invalid_request_invalid_candle", func(t *testing.T) {
+		prices, err := p.GetCandlePrices(types.CurrencyPair{Base: "FOO", Quote: "BAR"})
+		require.NoError(t, err)
+		require.Zero(t, len(prices))
+	})
+}
+
+func TestBitstampProvider_GetAvailablePairs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		require.Equal(t, bitstampRestPath, req.URL.String())
+		resp := `[
+{"name":"BTC/USD","url_symbol":"btcusd","trading":"Enabled"},
+{"name":"ETH/USD","url_symbol":"ethusd","trading":"Enabled"},
+{"name":"OLD/USD","url_symbol":"oldusd","trading":"Disabled"}
+]`
+		_, err := rw.Write([]byte(resp))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	p := &BitstampProvider{
+		endpoint: config.ProviderEndpoint{
+			Name: config.ProviderBitstamp,
+			Rest: server.URL,
+		},
+	}
+
+	pairs, err := p.GetAvailablePairs()
+	require.NoError(t, err)
+	require.Equal(t, map[string]struct{}{"BTCUSD": {}, "ETHUSD": {}}, pairs)
+}
+
+func TestBitstampCurrencyPairToBitstampPair(t *testing.T) {
+	cp := types.CurrencyPair{Base: "BTC", Quote: "USD"}
+	bitstampSymbol := currencyPairToBitstampPair(cp)
+	require.Equal(t, bitstampSymbol, "btcusd")
+}
@@ -0,0 +1,408 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+
+	"github.com/cosmos/cosmos-sdk/telemetry"
+
+	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle/types"
+)
+
+const (
+	bybitWSHost           = "stream.bybit.com"
+	bybitWSPath           = "/v5/public/spot"
+	bybitRestHost         = "https://api.bybit.com"
+	bybitRestPath         = "/v5/market/instruments-info?category=spot"
+	bybitTickerMsgPrefix  = "tickers."
+	bybitCandleMsgPrefix  = "kline.1."
+	bybitSubscribeOp      = "subscribe"
+	bybitTradingStatus    = "Trading"
+	bybitSuccessCode      = 0
+	bybitPingDuration     = 20 * time.Second
+	bybitPingMsg          = `{"op":"ping"}`
+	bybitMaxSubscribeArgs = 10
+)
+
+var _ Provider = (*BybitProvider)(nil)
+
+type (
+	// BybitProvider defines an Oracle provider implemented by the Bybit v5 public
+	// API.
+	//
+	// REF: https://bybit-exchange.github.io/docs/v5/ws/connect
+	BybitProvider struct {
+		wsc             *WebsocketController
+		logger          zerolog.Logger
+		mtx             sync.RWMutex
+		endpoint        config.ProviderEndpoint
+		tickers         map[string]TickerPrice        // Symbol => TickerPrice
+		candles         map[string][]CandlePrice      // Symbol => CandlePrice
+		subscribedPairs map[string]types.CurrencyPair // Symbol => types.CurrencyPair
+	}
+
+	// BybitMessage is any message received from the websocket, the data is
+	// decoded according to the topic
+	BybitMessage struct {
+		Topic   string          `json:"topic"`   // ex.: tickers.BTCUSDT
+		Op      string          `json:"op"`      // ex.: subscribe, set on operation responses
+		Success bool            `json:"success"` // result of the operation
+		RetMsg  string          `json:"ret_msg"` // error message of a failed operation
+		Data    json.RawMessage `json:"data"`
+	}
+	BybitTicker struct {
+		Symbol    string `json:"symbol"`    // Symbol ex.: BTCUSDT
+		LastPrice string `json:"lastPrice"` // Last traded price
+		Volume    string `json:"volume24h"` // Volume over the last 24 hours
+	}
+	BybitCandle struct {
+		End    int64  `json:"end"`    // End time of the candle in milliseconds
+		Close  string `json:"close"`  // Price at close
+		Volume string `json:"volume"` // Volume during the interval
+	}
+
+	BybitSubscriptionMsg struct {
+		Op   string   `json:"op"`   // subscribe, unsubscribe
+		Args []string `json:"args"` // Topics to be subscribed ex. tickers.BTCUSDT
+	}
+
+	BybitPairsSummary struct {
+		RetCode int              `json:"retCode"` // 0 on success
+		RetMsg  string           `json:"retMsg"`
+		Result  BybitInstruments `json:"result"`
+	}
+	BybitInstruments struct {
+		List []BybitInstrument `json:"list"`
+	}
+	BybitInstrument struct {
+		Symbol    string `json:"symbol"`    // Symbol ex.: BTCUSDT
+		BaseCoin  string `json:"baseCoin"`  // Base ex.: BTC
+		QuoteCoin string `json:"quoteCoin"` // Quote ex.: USDT
+		Status    string `json:"status"`    // Trading, PreLaunch, ...
+	}
+)
+
+func NewBybitProvider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	endpoint config.ProviderEndpoint,
+	pairs ...types.CurrencyPair,
+) (*BybitProvider, error) {
+	if endpoint.Name != config.ProviderBybit {
+		endpoint = config.ProviderEndpoint{
+			Name:      config.ProviderBybit,
+			Rest:      bybitRestHost,
+			Websocket: bybitWSHost,
+		}
+	}
+
+	wsURL := url.URL{
+		Scheme: "wss",
+		Host:   endpoint.Websocket,
+		Path:   bybitWSPath,
+	}
+
+	provider := &BybitProvider{
+		logger:          logger.With().Str("provider", "bybit").Logger(),
+		endpoint:        endpoint,
+		tickers:         map[string]TickerPrice{},
+		candles:         map[string][]CandlePrice{},
+		subscribedPairs: map[string]types.CurrencyPair{},
+	}
+
+	provider.setSubscribedPairs(pairs...)
+
+	// bybit drops connections without a heartbeat, a ping operation every 20
+	// seconds keeps it alive and is answered as an operation response
+	provider.wsc = NewWebsocketController(
+		ctx,
+		config.ProviderBybit,
+		wsURL,
+		provider.getSubscriptionMsgs(pairs...),
+		provider.messageReceived,
+		bybitPingDuration,
+		websocket.TextMessage,
+		provider.logger,
+	)
+	provider.wsc.SetPingMessage([]byte(bybitPingMsg))
+
+	go provider.wsc.Start()
+
+	return provider, nil
+}
+
+// getSubscriptionMsgs subscribes to the ticker and 1m kline topics of each
+// pair, in messages of at most 10 topics as limited by the spot websocket
+func (p *BybitProvider) getSubscriptionMsgs(cps ...types.CurrencyPair) []interface{} {
+	topics := make([]string, 0, len(cps)*2)
+	for _, cp := range cps {
+		bybitPair := currencyPairToBybitPair(cp)
+		topics = append(topics, bybitTickerMsgPrefix+bybitPair, bybitCandleMsgPrefix+bybitPair)
+	}
+
+	subscriptionMsgs := make([]interface{}, 0, len(topics)/bybitMaxSubscribeArgs+1)
+	for start := 0; start < len(topics); start += bybitMaxSubscribeArgs {
+		end := start + bybitMaxSubscribeArgs
+		if end > len(topics) {
+			end = len(topics)
+		}
+		subscriptionMsgs = append(subscriptionMsgs, newBybitSubscriptionMsg(topics[start:end]))
+	}
+	return subscriptionMsgs
+}
+
+// SubscribeCurrencyPairs sends the new subscription messages to the websocket
+// and adds them to the providers subscribedPairs array
+func (p *BybitProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	newPairs := []types.CurrencyPair{}
+	for _, cp := range cps {
+		if _, ok := p.subscribedPairs[cp.String()]; !ok {
+			newPairs = append(newPairs, cp)
+		}
+	}
+
+	newSubscriptionMsgs := p.getSubscriptionMsgs(newPairs...)
+	if err := p.wsc.AddSubscriptionMsgs(newSubscriptionMsgs); err != nil {
+		return err
+	}
+
+	p.setSubscribedPairs(newPairs...)
+	return nil
+}
+
+// GetTickerPrices returns the tickerPrices based on the saved map.
+func (p *BybitProvider) GetTickerPrices(pairs ...types.CurrencyPair) (map[string]TickerPrice, error) {
+	tickerPrices := make(map[string]TickerPrice, len(pairs))
+
+	for _, cp := range pairs {
+		key := currencyPairToBybitPair(cp)
+		price, err := p.getTickerPrice(key)
+		if err != nil {
+			p.logger.Debug().AnErr("err", err).Msg(fmt.Sprint("failed to fetch tickers for pair ", cp))
+			continue
+		}
+		tickerPrices[cp.String()] = price
+	}
+
+	return tickerPrices, nil
+}
+
+// GetCandlePrices returns the candlePrices based on the saved map
+func (p *BybitProvider) GetCandlePrices(pairs ...types.CurrencyPair) (map[string][]CandlePrice, error) {
+	candlePrices := make(map[string][]CandlePrice, len(pairs))
+
+	for _, cp := range pairs {
+		key := currencyPairToBybitPair(cp)
+		prices, err := p.getCandlePrices(key)
+		if err != nil {
+			p.logger.Debug().AnErr("err", err).Msg(fmt.Sprint("failed to fetch candles for pair ", cp))
+			continue
+		}
+		candlePrices[cp.String()] = prices
+	}
+
+	return candlePrices, nil
+}
+
+func (p *BybitProvider) getTickerPrice(key string) (TickerPrice, error) {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	ticker, ok := p.tickers[key]
+	if !ok {
+		return TickerPrice{}, fmt.Errorf("%s ticker not found for %s", config.ProviderBybit, key)
+	}
+
+	return ticker, nil
+}
+
+func (p *BybitProvider) getCandlePrices(key string) ([]CandlePrice, error) {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	candles, ok := p.candles[key]
+	if !ok {
+		return []CandlePrice{}, fmt.Errorf("%s candle not found for %s", config.ProviderBybit, key)
+	}
+
+	candleList := []CandlePrice{}
+	candleList = append(candleList, candles...)
+
+	return candleList, nil
+}
+
+func (p *BybitProvider) messageReceived(messageType int, bz []byte) {
+	if messageType != websocket.TextMessage {
+		return
+	}
+
+	var msg BybitMessage
+	if err := json.Unmarshal(bz, &msg); err != nil {
+		p.logger.Error().
+			Int("length", len(bz)).
+			AnErr("message", err).
+			Msg("Error on receive message")
+		return
+	}
+
+	switch {
+	case msg.Op != "":
+		// operation responses, ex.: the subscription result
+		if !msg.Success {
+			p.logger.Error().Str("op", msg.Op).Str("ret_msg", msg.RetMsg).Msg("bybit operation failed")
+		}
+
+	case strings.HasPrefix(msg.Topic, bybitTickerMsgPrefix):
+		var ticker BybitTicker
+		if err := json.Unmarshal(msg.Data, &ticker); err != nil {
+			p.logger.Error().Err(err).Str("topic", msg.Topic).Msg("failed to unmarshal ticker")
+			return
+		}
+		p.setTickerPair(ticker)
+		telemetry.IncrCounter(
+			1,
+			"websocket",
+			"message",
+			"type",
+			"ticker",
+			"provider",
+			config.ProviderBybit,
+		)
+
+	case strings.HasPrefix(msg.Topic, bybitCandleMsgPrefix):
+		var candles []BybitCandle
+		if err := json.Unmarshal(msg.Data, &candles); err != nil {
+			p.logger.Error().Err(err).Str("topic", msg.Topic).Msg("failed to unmarshal candle")
+			return
+		}
+		symbol := strings.TrimPrefix(msg.Topic, bybitCandleMsgPrefix)
+		for _, candle := range candles {
+			p.setCandlePair(symbol, candle)
+			telemetry.IncrCounter(
+				1,
+				"websocket",
+				"message",
+				"type",
+				"candle",
+				"provider",
+				config.ProviderBybit,
+			)
+		}
+
+	default:
+		p.logger.Error().
+			Int("length", len(bz)).
+			Str("topic", msg.Topic).
+			Msg("Error on receive message")
+	}
+}
+
+func (p *BybitProvider) setTickerPair(ticker BybitTicker) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	tickerPrice, err := newTickerPrice(
+		config.ProviderBybit,
+		ticker.Symbol,
+		ticker.LastPrice,
+		ticker.Volume,
+	)
+	if err != nil {
+		p.logger.Warn().Err(err).Msg("bybit: failed to parse ticker")
+		return
+	}
+
+	p.tickers[ticker.Symbol] = tickerPrice
+}
+
+// setCandlePair stores the candle, replacing the updates received earlier for
+// the same minute, as the kline topic pushes the open candle several times
+func (p *BybitProvider) setCandlePair(symbol string, bybitCandle BybitCandle) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	candle, err := newCandlePrice(
+		config.ProviderBybit,
+		symbol,
+		bybitCandle.Close,
+		bybitCandle.Volume,
+		bybitCandle.End,
+	)
+	if err != nil {
+		p.logger.Warn().Err(err).Msg("bybit: failed to parse candle")
+		return
+	}
+
+	staleTime := PastUnixTime(providerCandlePeriod)
+	candleList := []CandlePrice{}
+	candleList = append(candleList, candle)
+
+	for _, c := range p.candles[symbol] {
+		if staleTime < c.TimeStamp && c.TimeStamp != candle.TimeStamp {
+			candleList = append(candleList, c)
+		}
+	}
+
+	p.candles[symbol] = candleList
+}
+
+// setSubscribedPairs sets N currency pairs to the map of subscribed pairs.
+func (p *BybitProvider) setSubscribedPairs(cps ...types.CurrencyPair) {
+	for _, cp := range cps {
+		p.subscribedPairs[cp.String()] = cp
+	}
+}
+
+// GetAvailablePairs returns all pairs to which the provider can subscribe.
+// ex.: map["ATOMUSDT" => {}, "UMEEUSDC" => {}].
+func (p *BybitProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	var pairsSummary BybitPairsSummary
+	if err := getAvailablePairsJSON(p.endpoint.Rest+bybitRestPath, &pairsSummary); err != nil {
+		return nil, err
+	}
+	if pairsSummary.RetCode != bybitSuccessCode {
+		return nil, fmt.Errorf("failed to get %s available pairs: %d %s", config.ProviderBybit, pairsSummary.RetCode, pairsSummary.RetMsg)
+	}
+
+	availablePairs := make(map[string]struct{}, len(pairsSummary.Result.List))
+	for _, instrument := range pairsSummary.Result.List {
+		if instrument.Status != bybitTradingStatus {
+			continue
+		}
+
+		cp := types.CurrencyPair{
+			Base:  strings.ToUpper(instrument.BaseCoin),
+			Quote: strings.ToUpper(instrument.QuoteCoin),
+		}
+
+		availablePairs[cp.String()] = struct{}{}
+	}
+
+	return availablePairs, nil
+}
+
+// currencyPairToBybitPair receives a currency pair and return bybit
+// symbol ex.: ATOMUSDT.
+func currencyPairToBybitPair(cp types.CurrencyPair) string {
+	return strings.ToUpper(cp.Base + cp.Quote)
+}
+
+// newBybitSubscriptionMsg returns a new subscription Msg.
+func newBybitSubscriptionMsg(topics []string) BybitSubscriptionMsg {
+	return BybitSubscriptionMsg{
+		Op:   bybitSubscribeOp,
+		Args: topics,
+	}
+}
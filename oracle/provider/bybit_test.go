@@ -0,0 +1,252 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/math"
+
+	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle/types"
+)
+
+func TestBybitProvider_GetTickerPrices(t *testing.T) {
+	server := NewMockProviderServer()
+	server.Start()
+	defer server.Close()
+
+	p, err := NewBybitProvider(
+		context.TODO(),
+		zerolog.Nop(),
+		config.ProviderEndpoint{
+			Name:      config.ProviderBybit,
+			Rest:      "",
+			Websocket: server.GetBaseURL(),
+		},
+		types.CurrencyPair{Base: "BTC", Quote: "USDT"},
+	)
+	require.NoError(t, err)
+
+	t.Run("valid_request_single_ticker", func(t *testing.T) {
+		lastPrice := math.LegacyMustNewDecFromStr("62410.50")
+		volume := math.LegacyMustNewDecFromStr("12895.231")
+
+		tickerMap := map[string]TickerPrice{}
+		tickerMap["BTCUSDT"] = TickerPrice{
+			Price:  lastPrice,
+			Volume: volume,
+		}
+
+		p.tickers = tickerMap
+
+		prices, err := p.GetTickerPrices(types.CurrencyPair{Base: "BTC", Quote: "USDT"})
+		require.NoError(t, err)
+		require.Len(t, prices, 1)
+		require.Equal(t, lastPrice, prices["BTCUSDT"].Price)
+		require.Equal(t, volume, prices["BTCUSDT"].Volume)
+	})
+
+	t.Run("valid_request_multi_ticker", func(t *testing.T) {
+		lastPriceBtc := math.LegacyMustNewDecFromStr("62410.50")
+		lastPriceEth := math.LegacyMustNewDecFromStr("2450.13")
+		volume := math.LegacyMustNewDecFromStr("12895.231")
+
+		tickerMap := map[string]TickerPrice{}
+		tickerMap["BTCUSDT"] = TickerPrice{
+			Price:  lastPriceBtc,
+			Volume: volume,
+		}
+
+		tickerMap["ETHUSDT"] = TickerPrice{
+			Price:  lastPriceEth,
+			Volume: volume,
+		}
+
+		p.tickers = tickerMap
+		prices, err := p.GetTickerPrices(
+			types.CurrencyPair{Base: "BTC", Quote: "USDT"},
+			types.CurrencyPair{Base: "ETH", Quote: "USDT"},
+		)
+		require.NoError(t, err)
+		require.Len(t, prices, 2)
+		require.Equal(t, lastPriceBtc, prices["BTCUSDT"].Price)
+		require.Equal(t, volume, prices["BTCUSDT"].Volume)
+		require.Equal(t, lastPriceEth, prices["ETHUSDT"].Price)
+		require.Equal(t, volume, prices["ETHUSDT"].Volume)
+	})
+
+	t.Run("valid_ticker_message", func(t *testing.T) {
+		msg := `{"topic":"tickers.SOLUSDT","type":"snapshot","data":{"symbol":"SOLUSDT","lastPrice":"145.32","volume24h":"981234.5"}}`
+		p.messageReceived(websocket.TextMessage, []byte(msg))
+
+		prices, err := p.GetTickerPrices(types.CurrencyPair{Base: "SOL", Quote: "USDT"})
+		require.NoError(t, err)
+		require.Len(t, prices, 1)
+		require.Equal(t, math.LegacyMustNewDecFromStr("145.32"), prices["SOLUSDT"].Price)
+		require.Equal(t, math.LegacyMustNewDecFromStr("981234.5"), prices["SOLUSDT"].Volume)
+	})
+
+	t.Run("invalid_request_invalid_ticker", func(t *testing.T) {
+		prices, err := p.GetTickerPrices(types.CurrencyPair{Base: "FOO", Quote: "BAR"})
+		require.NoError(t, err)
+		require.Zero(t, len(prices))
+	})
+}
+
+func TestBybitProvider_GetCandlePrices(t *testing.T) {
+	server := NewMockProviderServer()
+	server.Start()
+	defer server.Close()
+
+	p, err := NewBybitProvider(
+		context.TODO(),
+		zerolog.Nop(),
+		config.ProviderEndpoint{
+			Name:      config.ProviderBybit,
+			Rest:      "",
+			Websocket: server.GetBaseURL(),
+		},
+		types.CurrencyPair{Base: "BTC", Quote: "USDT"},
+	)
+	require.NoError(t, err)
+
+	t.Run("valid_request_single_candle", func(t *testing.T) {
+		price := "62410.500000000000000000"
+		volume := "12.345000000000000000"
+		timeStamp := time.Now().UnixMilli()
+
+		candle := BybitCandle{
+			Volume: volume,
+			Close:  price,
+			End:    timeStamp,
+		}
+
+		p.setCandlePair("BTCUSDT", candle)
+
+		prices, err := p.GetCandlePrices(types.CurrencyPair{Base: "BTC", Quote: "USDT"})
+		require.NoError(t, err)
+		require.Len(t, prices, 1)
+		require.Len(t, prices["BTCUSDT"], 1)
+		require.Equal(t, math.LegacyMustNewDecFromStr(price), prices["BTCUSDT"][0].Price)
+		require.Equal(t, math.LegacyMustNewDecFromStr(volume), prices["BTCUSDT"][0].Volume)
+		require.Equal(t, timeStamp, prices["BTCUSDT"][0].TimeStamp)
+	})
+
+	t.Run("valid_candle_message_replaces_open_candle", func(t *testing.T) {
+		end := time.Now().UnixMilli()
+		first := `{"topic":"kline.1.ETHUSDT","type":"snapshot","data":[{"end":` + strconv.FormatInt(end, 10) + `,"close":"2450.10","volume":"10","confirm":false}]}`
+		second := `{"topic":"kline.1.ETHUSDT","type":"snapshot","data":[{"end":` + strconv.FormatInt(end, 10) + `,"close":"2451.20","volume":"15","confirm":false}]}`
+		p.messageReceived(websocket.TextMessage, []byte(first))
+		p.messageReceived(websocket.TextMessage, []byte(second))
+
+		prices, err := p.GetCandlePrices(types.CurrencyPair{Base: "ETH", Quote: "USDT"})
+		require.NoError(t, err)
+		require.Len(t, prices["ETHUSDT"], 1)
+		require.Equal(t, math.LegacyMustNewDecFromStr("2451.20"), prices["ETHUSDT"][0].Price)
+		require.Equal(t, math.LegacyMustNewDecFromStr("15"), prices["ETHUSDT"][0].Volume)
+	})
+
+	t.Run("invalid_request_invalid_candle", func(t *testing.T) {
+		prices, err := p.GetCandlePrices(types.CurrencyPair{Base: "FOO", Quote: "BAR"})
+		require.NoError(t, err)
+		require.Zero(t, len(prices))
+	})
+}
+
+func TestBybitProvider_GetAvailablePairs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		require.Equal(t, bybitRestPath, req.URL.String())
+		resp := `{"retCode":0,"retMsg":"OK","result":{"category":"spot","list":[
+{"symbol":"BTCUSDT","baseCoin":"BTC","quoteCoin":"USDT","status":"Trading"},
+{"symbol":"XRPUSDC","baseCoin":"XRP","quoteCoin":"USDC","status":"Trading"},
+{"symbol":"NEWUSDT","baseCoin":"NEW","quoteCoin":"USDT","status":"PreLaunch"}
+]}}`
+		_, err := rw.Write([]byte(resp))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	p := &BybitProvider{
+		endpoint: config.ProviderEndpoint{
+			Name: config.ProviderBybit,
+			Rest: server.URL,
+		},
+	}
+
+	pairs, err := p.GetAvailablePairs()
+	require.NoError(t, err)
+	require.Equal(t, map[string]struct{}{"BTCUSDT": {}, "XRPUSDC": {}}, pairs)
+}
+
+func TestBybitProvider_GetAvailablePairsErrors(t *testing.T) {
+	testCases := map[string]struct {
+		status int
+		resp   string
+	}{
+		"forbidden status": {
+			status: http.StatusForbidden,
+			resp:   `{"retCode":10009,"retMsg":"forbidden"}`,
+		},
+		"error code": {
+			status: http.StatusOK,
+			resp:   `{"retCode":10006,"retMsg":"Too many visits!","result":{}}`,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+				rw.WriteHeader(tc.status)
+				_, err := rw.Write([]byte(tc.resp))
+				require.NoError(t, err)
+			}))
+			defer server.Close()
+
+			p := &BybitProvider{
+				endpoint: config.ProviderEndpoint{
+					Name: config.ProviderBybit,
+					Rest: server.URL,
+				},
+			}
+
+			pairs, err := p.GetAvailablePairs()
+			require.Error(t, err)
+			require.Nil(t, pairs)
+		})
+	}
+}
+
+func TestBybitProvider_GetSubscriptionMsgs(t *testing.T) {
+	p := &BybitProvider{}
+
+	msgs := p.getSubscriptionMsgs(
+		types.CurrencyPair{Base: "BTC", Quote: "USDT"},
+		types.CurrencyPair{Base: "ETH", Quote: "USDT"},
+		types.CurrencyPair{Base: "SOL", Quote: "USDT"},
+		types.CurrencyPair{Base: "XRP", Quote: "USDT"},
+		types.CurrencyPair{Base: "ATOM", Quote: "USDT"},
+		types.CurrencyPair{Base: "KII", Quote: "USDT"},
+	)
+	require.Len(t, msgs, 2)
+	require.Equal(t, BybitSubscriptionMsg{
+		Op:   "subscribe",
+		Args: []string{"tickers.KIIUSDT", "kline.1.KIIUSDT"},
+	}, msgs[1])
+	require.Len(t, msgs[0].(BybitSubscriptionMsg).Args, 10)
+}
+
+func TestBybitCurrencyPairToBybitPair(t *testing.T) {
+	cp := types.CurrencyPair{Base: "ATOM", Quote: "USDT"}
+	bybitSymbol := currencyPairToBybitPair(cp)
+	require.Equal(t, bybitSymbol, "ATOMUSDT")
+}
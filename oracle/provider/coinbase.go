@@ -4,9 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"net/url"
-	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -14,8 +12,6 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/rs/zerolog"
 
-	"cosmossdk.io/math"
-
 	"github.com/cosmos/cosmos-sdk/telemetry"
 
 	"github.com/kiichain/price-feeder/config"
@@ -28,7 +24,6 @@ const (
 	coinbaseRestHost  = "https://api.exchange.coinbase.com"
 	coinbaseRestPath  = "/products"
 	timeLayout        = "2006-01-02T15:04:05.000000Z"
-	unixMinute        = 60000
 )
 
 var _ Provider = (*CoinbaseProvider)(nil)
@@ -45,7 +40,7 @@ type (
 		reconnectTimer  *time.Ticker
 		mtx             sync.RWMutex
 		endpoints       config.ProviderEndpoint
-		trades          map[string][]CoinbaseTrade    // Symbol => []CoinbaseTrade
+		trades          map[string][]TradePrice       // Symbol => []TradePrice
 		tickers         map[string]CoinbaseTicker     // Symbol => CoinbaseTicker
 		subscribedPairs map[string]types.CurrencyPair // Symbol => types.CurrencyPair
 	}
@@ -60,20 +55,13 @@ type (
 	// CoinbaseMatchResponse defines the response body for coinbase trades.
 	CoinbaseTradeResponse struct {
 		Type      string `json:"type"`       // "last_match" or "match"
+		TradeID   int64  `json:"trade_id"`   // ex.: 10
 		ProductID string `json:"product_id"` // ex.: ATOM-USDT
 		Time      string `json:"time"`       // Time in format 2006-01-02T15:04:05.000000Z
 		Size      string `json:"size"`       // Size of the trade ex.: 10.41
 		Price     string `json:"price"`      // ex.: 14.02
 	}
 
-	// CoinbaseTrade defines the trade info we'd like to save.
-	CoinbaseTrade struct {
-		ProductID string // ex.: ATOM-USDT
-		Time      int64  // Time in unix epoch ex.: 164732388700
-		Size      string // Size of the trade ex.: 10.41
-		Price     string // ex.: 14.02
-	}
-
 	// CoinbaseTicker defines the ticker info we'd like to save.
 	CoinbaseTicker struct {
 		ProductID string `json:"product_id"` // ex.: ATOM-USDT
@@ -129,7 +117,7 @@ func NewCoinbaseProvider(
 		logger:          logger.With().Str("provider", "coinbase").Logger(),
 		reconnectTimer:  time.NewTicker(coinbasePingCheck),
 		endpoints:       endpoints,
-		trades:          map[string][]CoinbaseTrade{},
+		trades:          map[string][]TradePrice{},
 		tickers:         map[string]CoinbaseTicker{},
 		subscribedPairs: map[string]types.CurrencyPair{},
 	}
@@ -164,7 +152,7 @@ func (p *CoinbaseProvider) GetTickerPrices(pairs ...types.CurrencyPair) (map[str
 // GetCandlePrices returns candles based off of the saved trades map.
 // Candles need to be cut up into one-minute intervals.
 func (p *CoinbaseProvider) GetCandlePrices(pairs ...types.CurrencyPair) (map[string][]CandlePrice, error) {
-	tradeMap := make(map[string][]CoinbaseTrade, len(pairs))
+	tradeMap := make(map[string][]TradePrice, len(pairs))
 
 	for _, cp := range pairs {
 		key := currencyPairToCoinbasePair(cp)
@@ -180,53 +168,8 @@ func (p *CoinbaseProvider) GetCandlePrices(pairs ...types.CurrencyPair) (map[str
 	}
 
 	candles := make(map[string][]CandlePrice)
-
-	for cp := range tradeMap {
-		trades := tradeMap[cp]
-		// sort oldest -> newest
-		sort.Slice(trades, func(i, j int) bool {
-			return time.Unix(trades[i].Time, 0).Before(time.Unix(trades[j].Time, 0))
-		})
-
-		candleSlice := []CandlePrice{
-			{
-				Price:  math.LegacyZeroDec(),
-				Volume: math.LegacyZeroDec(),
-			},
-		}
-		startTime := trades[0].Time
-		index := 0
-
-		// divide into chunks by minute
-		for _, trade := range trades {
-			// every minute, reset the time period
-			if trade.Time-startTime > unixMinute {
-				index++
-				startTime = trade.Time
-				candleSlice = append(candleSlice, CandlePrice{
-					Price:  math.LegacyZeroDec(),
-					Volume: math.LegacyZeroDec(),
-				})
-			}
-
-			size, err := math.LegacyNewDecFromStr(trade.Size)
-			if err != nil {
-				return nil, err
-			}
-			price, err := math.LegacyNewDecFromStr(trade.Price)
-			if err != nil {
-				return nil, err
-			}
-
-			volume := candleSlice[index].Volume.Add(size)
-			candleSlice[index] = CandlePrice{
-				Volume:    volume,     // aggregate size
-				Price:     price,      // most recent price
-				TimeStamp: trade.Time, // most recent timestamp
-			}
-		}
-
-		candles[coinbasePairToCurrencyPair(cp)] = candleSlice
+	for cp, trades := range tradeMap {
+		candles[coinbasePairToCurrencyPair(cp)] = tradesToCandles(trades)
 	}
 
 	return candles, nil
@@ -256,14 +199,8 @@ func (p *CoinbaseProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) err
 
 // GetAvailablePairs returns all pairs to which the provider can subscribe.
 func (p *CoinbaseProvider) GetAvailablePairs() (map[string]struct{}, error) {
-	resp, err := http.Get(p.endpoints.Rest + coinbaseRestPath)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
 	var pairsSummary []CoinbasePairSummary
-	if err := json.NewDecoder(resp.Body).Decode(&pairsSummary); err != nil {
+	if err := getAvailablePairsJSON(p.endpoints.Rest+coinbaseRestPath, &pairsSummary); err != nil {
 		return nil, err
 	}
 
@@ -313,13 +250,13 @@ func (p *CoinbaseProvider) getTickerPrice(cp types.CurrencyPair) (TickerPrice, e
 	return TickerPrice{}, fmt.Errorf("failed to get ticker price for %s", gp)
 }
 
-func (p *CoinbaseProvider) getTradePrices(key string) ([]CoinbaseTrade, error) {
+func (p *CoinbaseProvider) getTradePrices(key string) ([]TradePrice, error) {
 	p.mtx.RLock()
 	defer p.mtx.RUnlock()
 
 	trades, ok := p.trades[key]
 	if !ok {
-		return []CoinbaseTrade{}, fmt.Errorf("failed to get trades for %s", key)
+		return []TradePrice{}, fmt.Errorf("failed to get trades for %s", key)
 	}
 
 	return trades, nil
@@ -420,15 +357,6 @@ func (tr CoinbaseTradeResponse) timeToUnix() int64 {
 	return t.UnixMilli()
 }
 
-func (tr CoinbaseTradeResponse) toTrade() CoinbaseTrade {
-	return CoinbaseTrade{
-		Time:      tr.timeToUnix(),
-		Price:     tr.Price,
-		ProductID: tr.ProductID,
-		Size:      tr.Size,
-	}
-}
-
 func (p *CoinbaseProvider) setTickerPair(ticker CoinbaseTicker) {
 	p.mtx.Lock()
 	defer p.mtx.Unlock()
@@ -437,22 +365,26 @@ func (p *CoinbaseProvider) setTickerPair(ticker CoinbaseTicker) {
 }
 
 // setTradePair takes a CoinbaseTradeResponse, converts its date into unix epoch,
-// and then will add it to a copy of the trade slice. Then it filters out any
-// "stale" trades, and sets the trade slice in memory to the copy.
+// and then will add it to a copy of the trade slice, filtering out any "stale"
+// or already received trades.
 func (p *CoinbaseProvider) setTradePair(tradeResponse CoinbaseTradeResponse) {
+	trade, err := newTradePrice(
+		config.ProviderCoinbase,
+		tradeResponse.ProductID,
+		tradeResponse.TradeID,
+		tradeResponse.Price,
+		tradeResponse.Size,
+		tradeResponse.timeToUnix(),
+	)
+	if err != nil {
+		p.logger.Warn().Err(err).Msg("coinbase: failed to parse trade")
+		return
+	}
+
 	p.mtx.Lock()
 	defer p.mtx.Unlock()
-	staleTime := PastUnixTime(providerCandlePeriod)
-	tradeList := []CoinbaseTrade{
-		tradeResponse.toTrade(),
-	}
 
-	for _, t := range p.trades[tradeResponse.ProductID] {
-		if staleTime < t.Time {
-			tradeList = append(tradeList, t)
-		}
-	}
-	p.trades[tradeResponse.ProductID] = tradeList
+	p.trades[tradeResponse.ProductID] = appendTrade(p.trades[tradeResponse.ProductID], trade)
 }
 
 // subscribePairs write the subscription msg to the provider.
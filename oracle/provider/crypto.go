@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"net/url"
 	"strings"
 	"sync"
@@ -388,14 +387,8 @@ func (p *CryptoProvider) setSubscribedPairs(cps ...types.CurrencyPair) {
 // GetAvailablePairs returns all pairs to which the provider can subscribe.
 // ex.: map["ATOMUSDT" => {}, "UMEEUSDC" => {}].
 func (p *CryptoProvider) GetAvailablePairs() (map[string]struct{}, error) {
-	resp, err := http.Get(p.endpoint.Rest + cryptoRestPath)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
 	var pairsSummary CryptoPairsSummary
-	if err := json.NewDecoder(resp.Body).Decode(&pairsSummary); err != nil {
+	if err := getAvailablePairsJSON(p.endpoint.Rest+cryptoRestPath, &pairsSummary); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,284 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/tidwall/gjson"
+
+	"cosmossdk.io/math"
+
+	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle/types"
+)
+
+const (
+	// fxMaxRateAge keeps the last rates through the closed weekend markets
+	fxMaxRateAge = 96 * time.Hour
+)
+
+var _ Provider = (*FXProvider)(nil)
+
+type (
+	// FXProvider defines an Oracle provider of fiat rates declared in the
+	// config, polling the USD rates of its currencies from a REST API.
+	//
+	// The fiat rates change slowly and are polled at long intervals, so the
+	// last rate of each currency is returned as the ticker and as a candle
+	// stamped with the time of the rate.
+	FXProvider struct {
+		logger          zerolog.Logger
+		mtx             sync.RWMutex
+		cfg             config.FXProvider
+		client          *http.Client
+		pollInterval    time.Duration
+		rates           map[string]fxRate             // Currency => USD rate
+		subscribedPairs map[string]types.CurrencyPair // CurrencyPair => types.CurrencyPair
+	}
+
+	fxRate struct {
+		rate      math.LegacyDec
+		updatedAt time.Time // time of the rate on the source, or of the request
+	}
+)
+
+// NewFXProvider returns a new FX provider polling the rates of its currencies
+// in the background.
+func NewFXProvider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	cfg config.FXProvider,
+	pairs ...types.CurrencyPair,
+) (*FXProvider, error) {
+	pollInterval, err := cfg.GetPollInterval()
+	if err != nil {
+		return nil, err
+	}
+
+	provider := &FXProvider{
+		logger:          logger.With().Str("provider", cfg.Name).Logger(),
+		cfg:             cfg,
+		client:          newDefaultHTTPClient(),
+		pollInterval:    pollInterval,
+		rates:           map[string]fxRate{},
+		subscribedPairs: map[string]types.CurrencyPair{},
+	}
+
+	if err := provider.SubscribeCurrencyPairs(pairs...); err != nil {
+		return nil, err
+	}
+
+	go provider.poll(ctx)
+
+	return provider, nil
+}
+
+// poll refreshes the rates periodically until the context is done.
+func (p *FXProvider) poll(ctx context.Context) {
+	pollTicker := time.NewTicker(p.pollInterval)
+	defer pollTicker.Stop()
+
+	for {
+		if err := p.refreshRates(ctx); err != nil {
+			p.logger.Warn().Err(err).Msg("failed to refresh fx rates")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-pollTicker.C:
+		}
+	}
+}
+
+// refreshRates requests the rates of all the currencies of the provider.
+func (p *FXProvider) refreshRates(ctx context.Context) error {
+	currencies := make([]string, len(p.cfg.Currencies))
+	for i, currency := range p.cfg.Currencies {
+		currencies[i] = strings.ToUpper(currency)
+	}
+
+	url := strings.NewReplacer(
+		"{currencies}", strings.Join(currencies, ","),
+		"{api_key}", p.cfg.APIKey,
+	).Replace(p.cfg.URL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	if len(p.cfg.APIKeyHeader) > 0 {
+		req.Header.Set(p.cfg.APIKeyHeader, p.cfg.APIKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", p.cfg.Name, resp.StatusCode)
+	}
+
+	bz, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	updatedAt := time.Now()
+	if len(p.cfg.Timestamp) > 0 {
+		timeStamp, err := genericTimestamp(gjson.GetBytes(bz, p.cfg.Timestamp), p.cfg.TimestampUnit)
+		if err != nil {
+			return fmt.Errorf("%s timestamp: %w", p.cfg.Name, err)
+		}
+		updatedAt = time.UnixMilli(timeStamp)
+	}
+
+	rates := make(map[string]fxRate, len(currencies))
+	for _, currency := range currencies {
+		rate, err := fxDecimal(gjson.GetBytes(bz, p.cfg.GetRates()+"."+currency))
+		if err != nil {
+			p.logger.Warn().Err(err).Str("currency", currency).Msg("invalid fx rate")
+			continue
+		}
+
+		// the rates are converted to USD per unit of the currency
+		if !p.cfg.Inverse {
+			rate = math.LegacyOneDec().Quo(rate)
+		}
+		rates[currency] = fxRate{rate: rate, updatedAt: updatedAt}
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	for currency, rate := range rates {
+		p.rates[currency] = rate
+	}
+
+	return nil
+}
+
+// getRate returns the last rate of the currency of the pair, unless it is too
+// old.
+func (p *FXProvider) getRate(cp types.CurrencyPair) (fxRate, bool) {
+	if strings.ToUpper(cp.Quote) != config.DenomUSD {
+		return fxRate{}, false
+	}
+
+	rate, ok := p.rates[strings.ToUpper(cp.Base)]
+	if !ok || time.Since(rate.updatedAt) > fxMaxRateAge {
+		return fxRate{}, false
+	}
+
+	return rate, true
+}
+
+// SubscribeCurrencyPairs adds the pairs to the returned pairs, every pair must
+// be one of the currencies in USD.
+func (p *FXProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) error {
+	availablePairs, _ := p.GetAvailablePairs()
+	for _, cp := range cps {
+		if _, ok := availablePairs[cp.String()]; !ok {
+			return fmt.Errorf("%s has no rate for %s", p.cfg.Name, cp)
+		}
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.setSubscribedPairs(cps...)
+	return nil
+}
+
+// GetTickerPrices returns the last rates of the pairs, with a volume of one.
+func (p *FXProvider) GetTickerPrices(pairs ...types.CurrencyPair) (map[string]TickerPrice, error) {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	tickerPrices := make(map[string]TickerPrice, len(pairs))
+	for _, cp := range pairs {
+		if rate, ok := p.getRate(cp); ok {
+			tickerPrices[cp.String()] = TickerPrice{
+				Price:  rate.rate,
+				Volume: math.LegacyOneDec(),
+			}
+		}
+	}
+
+	return tickerPrices, nil
+}
+
+// GetCandlePrices returns the last rates of the pairs as candles stamped with
+// the time of the rates, with a volume of one, so the maximum candle age of
+// the pairs applies to the rates.
+func (p *FXProvider) GetCandlePrices(pairs ...types.CurrencyPair) (map[string][]CandlePrice, error) {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	candlePrices := make(map[string][]CandlePrice, len(pairs))
+	for _, cp := range pairs {
+		if rate, ok := p.getRate(cp); ok {
+			candlePrices[cp.String()] = []CandlePrice{{
+				Price:     rate.rate,
+				Volume:    math.LegacyOneDec(),
+				TimeStamp: rate.updatedAt.UnixMilli(),
+			}}
+		}
+	}
+
+	return candlePrices, nil
+}
+
+// setSubscribedPairs sets N currency pairs to the map of subscribed pairs.
+func (p *FXProvider) setSubscribedPairs(cps ...types.CurrencyPair) {
+	for _, cp := range cps {
+		p.subscribedPairs[cp.String()] = cp
+	}
+}
+
+// GetAvailablePairs returns the currencies of the provider in USD.
+// ex.: map["EURUSD" => {}, "JPYUSD" => {}].
+func (p *FXProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	availablePairs := make(map[string]struct{}, len(p.cfg.Currencies))
+	for _, currency := range p.cfg.Currencies {
+		availablePairs[strings.ToUpper(currency)+config.DenomUSD] = struct{}{}
+	}
+
+	return availablePairs, nil
+}
+
+// fxDecimal returns the positive decimal of a selected number or string,
+// including the numbers in exponent notation.
+func fxDecimal(result gjson.Result) (math.LegacyDec, error) {
+	value, err := genericDecimal(result)
+	if err != nil {
+		return math.LegacyDec{}, err
+	}
+
+	dec, err := math.LegacyNewDecFromStr(value)
+	if err != nil {
+		r, ok := new(big.Rat).SetString(value)
+		if !ok {
+			return math.LegacyDec{}, err
+		}
+		dec, err = math.LegacyNewDecFromStr(r.FloatString(math.LegacyPrecision))
+		if err != nil {
+			return math.LegacyDec{}, err
+		}
+	}
+	if !dec.IsPositive() {
+		return math.LegacyDec{}, fmt.Errorf("expected a positive rate, got %s", value)
+	}
+
+	return dec, nil
+}
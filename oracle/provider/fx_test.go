@@ -0,0 +1,151 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"cosmossdk.io/math"
+
+	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle/types"
+)
+
+func TestFXProvider_RefreshRates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		require.Equal(t, "/latest?base=USD&symbols=EUR,JPY,BRL&key=secret", req.URL.String())
+		require.Equal(t, "secret", req.Header.Get("X-Api-Key"))
+
+		_, err := rw.Write([]byte(`{"base":"USD","data":{"EUR":0.8,"JPY":"160","BRL":-1}}`))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	p, err := NewFXProvider(
+		context.TODO(),
+		zerolog.Nop(),
+		config.FXProvider{
+			Name:         "myfx",
+			URL:          server.URL + "/latest?base=USD&symbols={currencies}&key={api_key}",
+			APIKey:       "secret",
+			APIKeyHeader: "X-Api-Key",
+			Rates:        "data",
+			Currencies:   []string{"EUR", "jpy", "BRL"},
+			PollInterval: "1h",
+		},
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.refreshRates(context.TODO()))
+
+	eurUSD := types.CurrencyPair{Base: "EUR", Quote: "USD"}
+	jpyUSD := types.CurrencyPair{Base: "JPY", Quote: "USD"}
+	brlUSD := types.CurrencyPair{Base: "BRL", Quote: "USD"}
+
+	prices, err := p.GetTickerPrices(eurUSD, jpyUSD, brlUSD)
+	require.NoError(t, err)
+	require.Len(t, prices, 2)
+	require.Equal(t, math.LegacyMustNewDecFromStr("1.25"), prices["EURUSD"].Price)
+	require.Equal(t, math.LegacyMustNewDecFromStr("0.00625"), prices["JPYUSD"].Price)
+	require.Equal(t, math.LegacyOneDec(), prices["EURUSD"].Volume)
+
+	// the last rate is stamped with the time of the request
+	candles, err := p.GetCandlePrices(eurUSD)
+	require.NoError(t, err)
+	require.Len(t, candles["EURUSD"], 1)
+	require.Equal(t, math.LegacyMustNewDecFromStr("1.25"), candles["EURUSD"][0].Price)
+	require.Greater(t, candles["EURUSD"][0].TimeStamp, PastUnixTime(providerCandlePeriod))
+}
+
+func TestFXProvider_Inverse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		_, err := rw.Write([]byte(`{"rates":{"EUR":1.0845e0}}`))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	p, err := NewFXProvider(
+		context.TODO(),
+		zerolog.Nop(),
+		config.FXProvider{
+			Name:       "myfx",
+			URL:        server.URL,
+			Inverse:    true,
+			Currencies: []string{"EUR"},
+		},
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.refreshRates(context.TODO()))
+
+	prices, err := p.GetTickerPrices(types.CurrencyPair{Base: "EUR", Quote: "USD"})
+	require.NoError(t, err)
+	require.Equal(t, math.LegacyMustNewDecFromStr("1.0845"), prices["EURUSD"].Price)
+}
+
+func TestFXProvider_Timestamp(t *testing.T) {
+	updatedAt := time.Now().Add(-time.Hour).Unix()
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		_, err := rw.Write([]byte(fmt.Sprintf(`{"timestamp":%d,"rates":{"EUR":0.8}}`, updatedAt)))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	p, err := NewFXProvider(
+		context.TODO(),
+		zerolog.Nop(),
+		config.FXProvider{
+			Name:          "myfx",
+			URL:           server.URL,
+			Timestamp:     "timestamp",
+			TimestampUnit: "s",
+			Currencies:    []string{"EUR"},
+		},
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.refreshRates(context.TODO()))
+
+	// the candle keeps the time of the rate on the source
+	candles, err := p.GetCandlePrices(types.CurrencyPair{Base: "EUR", Quote: "USD"})
+	require.NoError(t, err)
+	require.Len(t, candles["EURUSD"], 1)
+	require.Equal(t, updatedAt*1000, candles["EURUSD"][0].TimeStamp)
+}
+
+func TestFXProvider_SubscribeCurrencyPairs(t *testing.T) {
+	p, err := NewFXProvider(
+		context.TODO(),
+		zerolog.Nop(),
+		config.FXProvider{Name: "myfx", URL: "http://127.0.0.1:1", Currencies: []string{"EUR"}},
+		types.CurrencyPair{Base: "EUR", Quote: "USD"},
+	)
+	require.NoError(t, err)
+
+	err = p.SubscribeCurrencyPairs(types.CurrencyPair{Base: "EUR", Quote: "USDT"})
+	require.EqualError(t, err, "myfx has no rate for EURUSDT")
+
+	pairs, err := p.GetAvailablePairs()
+	require.NoError(t, err)
+	require.Equal(t, map[string]struct{}{"EURUSD": {}}, pairs)
+}
+
+func TestFXDecimal(t *testing.T) {
+	dec, err := fxDecimal(gjson.Parse(`1.5e-3`))
+	require.NoError(t, err)
+	require.Equal(t, math.LegacyMustNewDecFromStr("0.0015"), dec)
+
+	dec, err = fxDecimal(gjson.Parse(`"0.92"`))
+	require.NoError(t, err)
+	require.Equal(t, math.LegacyMustNewDecFromStr("0.92"), dec)
+
+	_, err = fxDecimal(gjson.Parse(`0`))
+	require.Error(t, err)
+
+	_, err = fxDecimal(gjson.Parse(`"foo"`))
+	require.Error(t, err)
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
@@ -28,7 +27,10 @@ const (
 	gateRestPath  = "/api/v4/spot/currency_pairs"
 )
 
-var _ Provider = (*GateProvider)(nil)
+var (
+	_ Provider          = (*GateProvider)(nil)
+	_ OrderBookProvider = (*GateProvider)(nil)
+)
 
 type (
 	// GateProvider defines an Oracle provider implemented by the Gate public
@@ -44,6 +46,7 @@ type (
 		endpoints       config.ProviderEndpoint
 		tickers         map[string]GateTicker         // Symbol => GateTicker
 		candles         map[string][]GateCandle       // Symbol => GateCandle
+		orderBooks      map[string]OrderBook          // Symbol => OrderBook
 		subscribedPairs map[string]types.CurrencyPair // Symbol => types.CurrencyPair
 	}
 
@@ -81,6 +84,15 @@ type (
 		ID      uint16   `json:"id"`      // identify messages going back and forth
 	}
 
+	// GateOrderBookSubscriptionMsg Msg to subscribe to an order book channel.
+	GateOrderBookSubscriptionMsg struct {
+		Time    int64    `json:"time"`
+		Channel string   `json:"channel"` // spot.order_book
+		Event   string   `json:"event"`   // subscribe
+		Payload []string `json:"payload"` // pair, levels and interval ex.: ["BOT_USDT", "20", "1000ms"]
+		ID      uint16   `json:"id"`      // identify messages going back and forth
+	}
+
 	// GateTickerResponse defines the response body for gate tickers.
 	GateTickerResponse struct {
 		Time    int64            `json:"time"`
@@ -127,6 +139,24 @@ type (
 		BaseTradingAmount string `json:"a"`
 	}
 
+	// GateOrderBookResponse defines the response body for gate order books.
+	GateOrderBookResponse struct {
+		Time    int64               `json:"time"`
+		TimeMS  int64               `json:"time_ms"`
+		Channel string              `json:"channel"`
+		Event   string              `json:"event"`
+		Result  GateOrderBookResult `json:"result"`
+	}
+
+	// GateOrderBookResult defines the response body for gate order book
+	// snapshots, the levels are [price, amount].
+	GateOrderBookResult struct {
+		TimeStamp int64      `json:"t"` // Snapshot timestamp in milliseconds
+		Symbol    string     `json:"s"` // Symbol ex.: ATOM_USDT
+		Bids      [][]string `json:"bids"`
+		Asks      [][]string `json:"asks"`
+	}
+
 	// GateEvent defines the response body for gate subscription statuses.
 	GateEvent struct {
 		ID     int             `json:"id"`     // subscription id, ex.: 123
@@ -183,6 +213,7 @@ func NewGateProvider(
 		endpoints:       endpoints,
 		tickers:         map[string]GateTicker{},
 		candles:         map[string][]GateCandle{},
+		orderBooks:      map[string]OrderBook{},
 		subscribedPairs: map[string]types.CurrencyPair{},
 	}
 	provider.wsClient.SetPongHandler(provider.pongHandler)
@@ -251,6 +282,21 @@ func (p *GateProvider) getCandlePrices(key string) ([]CandlePrice, error) {
 	return candleList, nil
 }
 
+// GetOrderBooks returns the order books of the provided pairs.
+func (p *GateProvider) GetOrderBooks(pairs ...types.CurrencyPair) (map[string]OrderBook, error) {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	orderBooks := make(map[string]OrderBook, len(pairs))
+	for _, cp := range pairs {
+		if orderBook, ok := p.orderBooks[currencyPairToGatePair(cp)]; ok {
+			orderBooks[cp.String()] = orderBook
+		}
+	}
+
+	return orderBooks, nil
+}
+
 // SubscribeCurrencyPairs subscribe to ticker and candle channels for all pairs.
 func (p *GateProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) error {
 	if len(cps) == 0 {
@@ -263,6 +309,9 @@ func (p *GateProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) error {
 	if err := p.subscribeCandles(cps...); err != nil {
 		return err
 	}
+	if err := p.subscribeOrderBooks(cps...); err != nil {
+		return err
+	}
 	p.setSubscribedPairs(cps...)
 	telemetry.IncrCounter(
 		float32(len(cps)),
@@ -310,6 +359,19 @@ func (p *GateProvider) subscribeCandles(cps ...types.CurrencyPair) error {
 	return nil
 }
 
+// subscribeOrderBooks subscribes to the order book channels for all pairs
+// one-by-one.
+func (p *GateProvider) subscribeOrderBooks(cps ...types.CurrencyPair) error {
+	for _, cp := range cps {
+		msg := newGateOrderBookSubscription(currencyPairToGatePair(cp))
+		if err := p.wsClient.WriteJSON(msg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (p *GateProvider) subscribedPairsToSlice() []types.CurrencyPair {
 	p.mtx.RLock()
 	defer p.mtx.RUnlock()
@@ -366,10 +428,11 @@ func (p *GateProvider) messageReceived(messageType int, bz []byte) {
 	}
 
 	var (
-		gateEvent GateEvent
-		gateErr   error
-		tickerErr error
-		candleErr error
+		gateEvent    GateEvent
+		gateErr      error
+		tickerErr    error
+		candleErr    error
+		orderBookErr error
 	)
 
 	gateErr = json.Unmarshal(bz, &gateEvent)
@@ -402,10 +465,16 @@ func (p *GateProvider) messageReceived(messageType int, bz []byte) {
 		return
 	}
 
+	orderBookErr = p.messageReceivedOrderBook(bz)
+	if orderBookErr == nil {
+		return
+	}
+
 	p.logger.Error().
 		Int("length", len(bz)).
 		AnErr("ticker", tickerErr).
 		AnErr("candle", candleErr).
+		AnErr("depth", orderBookErr).
 		AnErr("event", gateErr).
 		Msg("Error on receive message")
 }
@@ -525,6 +594,43 @@ func (p *GateProvider) messageReceivedCandle(bz []byte) error {
 	return nil
 }
 
+// messageReceivedOrderBook handles the order book snapshot msg.
+//
+// REF: https://www.gate.io/docs/developers/apiv4/ws/en/#limited-level-full-order-book-snapshot
+func (p *GateProvider) messageReceivedOrderBook(bz []byte) error {
+	var orderBookMessage GateOrderBookResponse
+	if err := json.Unmarshal(bz, &orderBookMessage); err != nil {
+		return err
+	}
+	if orderBookMessage.Channel != "spot.order_book" || orderBookMessage.Event != "update" {
+		return fmt.Errorf("message is not an order book update")
+	}
+
+	result := orderBookMessage.Result
+	orderBook, err := newOrderBook("Gate", result.Symbol, result.Bids, result.Asks, result.TimeStamp)
+	if err != nil {
+		return err
+	}
+
+	p.setOrderBook(result.Symbol, orderBook)
+	telemetry.IncrCounter(
+		1,
+		"websocket",
+		"message",
+		"type",
+		"depth",
+		"provider",
+		config.ProviderGate,
+	)
+	return nil
+}
+
+func (p *GateProvider) setOrderBook(symbol string, orderBook OrderBook) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.orderBooks[symbol] = orderBook
+}
+
 func (p *GateProvider) setTickerPair(ticker GateTicker) {
 	p.mtx.Lock()
 	defer p.mtx.Unlock()
@@ -622,14 +728,8 @@ func (p *GateProvider) pongHandler(_ string) error {
 
 // GetAvailablePairs returns all pairs to which the provider can subscribe.
 func (p *GateProvider) GetAvailablePairs() (map[string]struct{}, error) {
-	resp, err := http.Get(p.endpoints.Rest + gateRestPath)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
 	var pairsSummary []GatePairSummary
-	if err := json.NewDecoder(resp.Body).Decode(&pairsSummary); err != nil {
+	if err := getAvailablePairsJSON(p.endpoints.Rest+gateRestPath, &pairsSummary); err != nil {
 		return nil, err
 	}
 
@@ -689,3 +789,16 @@ func newGateCandleSubscription(gatePair string) GateCandleSubscriptionMsg {
 		ID:      2,
 	}
 }
+
+// newGateOrderBookSubscription returns a new subscription topic for the 20
+// best levels of an order book.
+func newGateOrderBookSubscription(gatePair string) GateOrderBookSubscriptionMsg {
+	timeSecs := time.Now().Unix()
+	return GateOrderBookSubscriptionMsg{
+		Time:    timeSecs,
+		Channel: "spot.order_book",
+		Event:   "subscribe",
+		Payload: []string{gatePair, "20", "1000ms"},
+		ID:      3,
+	}
+}
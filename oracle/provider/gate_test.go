@@ -100,3 +100,29 @@ func TestGateCurrencyPairToGatePair(t *testing.T) {
 	GateSymbol := currencyPairToGatePair(cp)
 	require.Equal(t, GateSymbol, "ATOM_USDT")
 }
+
+func TestGateProvider_MessageReceivedOrderBook(t *testing.T) {
+	p := &GateProvider{logger: zerolog.Nop(), orderBooks: map[string]OrderBook{}}
+
+	require.NoError(t, p.messageReceivedOrderBook([]byte(`{
+		"time": 1606295412,
+		"time_ms": 1606295412213,
+		"channel": "spot.order_book",
+		"event": "update",
+		"result": {
+			"t": 1606295412123,
+			"lastUpdateId": 48791820,
+			"s": "ATOM_USDT",
+			"bids": [["9.99", "10"], ["9.98", "12"]],
+			"asks": [["10.01", "8"], ["10.02", "20"]]
+		}
+	}`)))
+
+	orderBooks, err := p.GetOrderBooks(types.CurrencyPair{Base: "ATOM", Quote: "USDT"})
+	require.NoError(t, err)
+	require.Len(t, orderBooks, 1)
+	require.Equal(t, math.LegacyMustNewDecFromStr("9.99"), orderBooks["ATOMUSDT"].BestBid())
+	require.Equal(t, int64(1606295412123), orderBooks["ATOMUSDT"].TimeStamp)
+
+	require.EqualError(t, p.messageReceivedOrderBook([]byte(`{"channel": "spot.tickers", "event": "update"}`)), "message is not an order book update")
+}
@@ -0,0 +1,394 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+
+	"github.com/cosmos/cosmos-sdk/telemetry"
+
+	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle/types"
+)
+
+const (
+	geminiWSHost             = "api.gemini.com"
+	geminiWSPath             = "/v2/marketdata"
+	geminiRestHost           = "https://api.gemini.com"
+	geminiRestPath           = "/v1/symbols"
+	geminiTickerPath         = "/v1/pubticker/"
+	geminiL2Subscription     = "l2"
+	geminiTradeType          = "trade"
+	geminiL2UpdatesType      = "l2_updates"
+	geminiPingDuration       = 30 * time.Second
+	geminiTickerPollInterval = 10 * time.Second
+	geminiSubscriptionType   = "subscribe"
+)
+
+var _ Provider = (*GeminiProvider)(nil)
+
+type (
+	// GeminiProvider defines an Oracle provider implemented by the Gemini public
+	// API. The candles are built from the trades of the level 2 market data
+	// stream, and the tickers are polled from the REST API, as the websocket has
+	// no ticker channel.
+	//
+	// REF: https://docs.gemini.com/websocket-api/#market-data-version-2
+	GeminiProvider struct {
+		wsc             *WebsocketController
+		logger          zerolog.Logger
+		mtx             sync.RWMutex
+		endpoint        config.ProviderEndpoint
+		client          *http.Client
+		tickers         map[string]TickerPrice        // Symbol => TickerPrice
+		trades          map[string][]TradePrice       // Symbol => []TradePrice
+		subscribedPairs map[string]types.CurrencyPair // Symbol => types.CurrencyPair
+	}
+
+	// GeminiMessage is any message received from the websocket, the initial
+	// l2_updates message of a symbol carries its most recent trades
+	GeminiMessage struct {
+		Type   string        `json:"type"`   // ex.: trade, l2_updates, heartbeat
+		Trades []GeminiTrade `json:"trades"` // recent trades of l2_updates
+		GeminiTrade
+	}
+	GeminiTrade struct {
+		ID        int64  `json:"tid"`       // Trade id
+		Symbol    string `json:"symbol"`    // ex.: BTCUSD
+		Price     string `json:"price"`     // ex.: 26000.5
+		Quantity  string `json:"quantity"`  // Size of the trade ex.: 0.01
+		Timestamp int64  `json:"timestamp"` // Trade time in milliseconds
+	}
+
+	GeminiTicker struct {
+		Last   string                 `json:"last"`   // Last traded price
+		Volume map[string]interface{} `json:"volume"` // Volume over the last 24 hours by currency
+	}
+
+	GeminiSubscriptionMsg struct {
+		Type          string               `json:"type"` // subscribe, unsubscribe
+		Subscriptions []GeminiSubscription `json:"subscriptions"`
+	}
+	GeminiSubscription struct {
+		Name    string   `json:"name"`    // ex.: l2
+		Symbols []string `json:"symbols"` // ex.: ["BTCUSD"]
+	}
+)
+
+func NewGeminiProvider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	endpoint config.ProviderEndpoint,
+	pairs ...types.CurrencyPair,
+) (*GeminiProvider, error) {
+	if endpoint.Name != config.ProviderGemini {
+		endpoint = config.ProviderEndpoint{
+			Name:      config.ProviderGemini,
+			Rest:      geminiRestHost,
+			Websocket: geminiWSHost,
+		}
+	}
+
+	wsURL := url.URL{
+		Scheme: "wss",
+		Host:   endpoint.Websocket,
+		Path:   geminiWSPath,
+	}
+
+	provider := &GeminiProvider{
+		logger:          logger.With().Str("provider", "gemini").Logger(),
+		endpoint:        endpoint,
+		client:          newDefaultHTTPClient(),
+		tickers:         map[string]TickerPrice{},
+		trades:          map[string][]TradePrice{},
+		subscribedPairs: map[string]types.CurrencyPair{},
+	}
+
+	provider.setSubscribedPairs(pairs...)
+
+	provider.wsc = NewWebsocketController(
+		ctx,
+		config.ProviderGemini,
+		wsURL,
+		provider.getSubscriptionMsgs(pairs...),
+		provider.messageReceived,
+		geminiPingDuration,
+		websocket.PingMessage,
+		provider.logger,
+	)
+
+	go provider.wsc.Start()
+	go provider.pollTickers(ctx)
+
+	return provider, nil
+}
+
+// pollTickers refreshes the tickers periodically, so the oracle reads them
+// without waiting on the REST API
+func (p *GeminiProvider) pollTickers(ctx context.Context) {
+	pollTicker := time.NewTicker(geminiTickerPollInterval)
+	defer pollTicker.Stop()
+
+	for {
+		p.refreshTickers()
+
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-pollTicker.C:
+		}
+	}
+}
+
+// refreshTickers requests the ticker of each subscribed pair from the REST API
+func (p *GeminiProvider) refreshTickers() {
+	p.mtx.RLock()
+	pairs := types.MapPairsToSlice(p.subscribedPairs)
+	p.mtx.RUnlock()
+
+	for _, cp := range pairs {
+		tickerPrice, err := p.requestTickerPrice(cp)
+		if err != nil {
+			p.logger.Warn().Err(err).Msg(fmt.Sprint("failed to refresh ticker for pair ", cp))
+			continue
+		}
+
+		p.mtx.Lock()
+		p.tickers[currencyPairToGeminiPair(cp)] = tickerPrice
+		p.mtx.Unlock()
+	}
+}
+
+func (p *GeminiProvider) getSubscriptionMsgs(cps ...types.CurrencyPair) []interface{} {
+	if len(cps) == 0 {
+		return []interface{}{}
+	}
+
+	symbols := make([]string, 0, len(cps))
+	for _, cp := range cps {
+		symbols = append(symbols, currencyPairToGeminiPair(cp))
+	}
+
+	return []interface{}{newGeminiSubscriptionMsg(symbols)}
+}
+
+// SubscribeCurrencyPairs sends the new subscription messages to the websocket
+// and adds them to the providers subscribedPairs array
+func (p *GeminiProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	newPairs := []types.CurrencyPair{}
+	for _, cp := range cps {
+		if _, ok := p.subscribedPairs[cp.String()]; !ok {
+			newPairs = append(newPairs, cp)
+		}
+	}
+
+	newSubscriptionMsgs := p.getSubscriptionMsgs(newPairs...)
+	if err := p.wsc.AddSubscriptionMsgs(newSubscriptionMsgs); err != nil {
+		return err
+	}
+
+	p.setSubscribedPairs(newPairs...)
+	return nil
+}
+
+// GetTickerPrices returns the tickerPrices based on the saved map.
+func (p *GeminiProvider) GetTickerPrices(pairs ...types.CurrencyPair) (map[string]TickerPrice, error) {
+	tickerPrices := make(map[string]TickerPrice, len(pairs))
+
+	for _, cp := range pairs {
+		key := currencyPairToGeminiPair(cp)
+		price, err := p.getTickerPrice(key)
+		if err != nil {
+			p.logger.Debug().AnErr("err", err).Msg(fmt.Sprint("failed to fetch tickers for pair ", cp))
+			continue
+		}
+		tickerPrices[cp.String()] = price
+	}
+
+	return tickerPrices, nil
+}
+
+func (p *GeminiProvider) getTickerPrice(key string) (TickerPrice, error) {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	ticker, ok := p.tickers[key]
+	if !ok {
+		return TickerPrice{}, fmt.Errorf("%s ticker not found for %s", config.ProviderGemini, key)
+	}
+
+	return ticker, nil
+}
+
+// requestTickerPrice requests the ticker of the pair from the REST API
+func (p *GeminiProvider) requestTickerPrice(cp types.CurrencyPair) (TickerPrice, error) {
+	resp, err := p.client.Get(p.endpoint.Rest + geminiTickerPath + strings.ToLower(currencyPairToGeminiPair(cp)))
+	if err != nil {
+		return TickerPrice{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return TickerPrice{}, fmt.Errorf("%s ticker request for %s failed: %s", config.ProviderGemini, cp, resp.Status)
+	}
+
+	var ticker GeminiTicker
+	if err := json.NewDecoder(resp.Body).Decode(&ticker); err != nil {
+		return TickerPrice{}, err
+	}
+
+	// the volume is listed by currency, the base volume is the traded amount
+	volume, ok := ticker.Volume[strings.ToUpper(cp.Base)].(string)
+	if !ok {
+		return TickerPrice{}, fmt.Errorf("%s volume not found for %s", config.ProviderGemini, cp)
+	}
+
+	return newTickerPrice(config.ProviderGemini, cp.String(), ticker.Last, volume)
+}
+
+// GetCandlePrices returns the one minute candles built from the saved trades.
+func (p *GeminiProvider) GetCandlePrices(pairs ...types.CurrencyPair) (map[string][]CandlePrice, error) {
+	candlePrices := make(map[string][]CandlePrice, len(pairs))
+
+	for _, cp := range pairs {
+		key := currencyPairToGeminiPair(cp)
+		trades, err := p.getTradePrices(key)
+		if err != nil {
+			p.logger.Debug().AnErr("err", err).Msg(fmt.Sprint("failed to fetch candles for pair ", cp))
+			continue
+		}
+		candlePrices[cp.String()] = tradesToCandles(trades)
+	}
+
+	return candlePrices, nil
+}
+
+func (p *GeminiProvider) getTradePrices(key string) ([]TradePrice, error) {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	trades, ok := p.trades[key]
+	if !ok {
+		return []TradePrice{}, fmt.Errorf("%s trades not found for %s", config.ProviderGemini, key)
+	}
+
+	return trades, nil
+}
+
+func (p *GeminiProvider) messageReceived(messageType int, bz []byte) {
+	if messageType != websocket.TextMessage {
+		return
+	}
+
+	var msg GeminiMessage
+	if err := json.Unmarshal(bz, &msg); err != nil {
+		p.logger.Error().
+			Int("length", len(bz)).
+			AnErr("message", err).
+			Msg("Error on receive message")
+		return
+	}
+
+	var trades []GeminiTrade
+	switch msg.Type {
+	case geminiTradeType:
+		trades = []GeminiTrade{msg.GeminiTrade}
+
+	case geminiL2UpdatesType:
+		// only the first update of a symbol carries trades, sent again on
+		// each subscription
+		trades = msg.Trades
+
+	default:
+		// heartbeats
+		return
+	}
+
+	for _, trade := range trades {
+		p.setTradePair(trade)
+		telemetry.IncrCounter(
+			1,
+			"websocket",
+			"message",
+			"type",
+			"trade",
+			"provider",
+			config.ProviderGemini,
+		)
+	}
+}
+
+func (p *GeminiProvider) setTradePair(geminiTrade GeminiTrade) {
+	trade, err := newTradePrice(
+		config.ProviderGemini,
+		geminiTrade.Symbol,
+		geminiTrade.ID,
+		geminiTrade.Price,
+		geminiTrade.Quantity,
+		geminiTrade.Timestamp,
+	)
+	if err != nil {
+		p.logger.Warn().Err(err).Msg("gemini: failed to parse trade")
+		return
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.trades[geminiTrade.Symbol] = appendTrade(p.trades[geminiTrade.Symbol], trade)
+}
+
+// setSubscribedPairs sets N currency pairs to the map of subscribed pairs.
+func (p *GeminiProvider) setSubscribedPairs(cps ...types.CurrencyPair) {
+	for _, cp := range cps {
+		p.subscribedPairs[cp.String()] = cp
+	}
+}
+
+// GetAvailablePairs returns all pairs to which the provider can subscribe.
+// ex.: map["BTCUSD" => {}, "ETHUSD" => {}].
+func (p *GeminiProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	var symbols []string
+	if err := getAvailablePairsJSON(p.endpoint.Rest+geminiRestPath, &symbols); err != nil {
+		return nil, err
+	}
+
+	// the symbols are the concatenated base and quote, ex.: btcusd
+	availablePairs := make(map[string]struct{}, len(symbols))
+	for _, symbol := range symbols {
+		availablePairs[strings.ToUpper(symbol)] = struct{}{}
+	}
+
+	return availablePairs, nil
+}
+
+// currencyPairToGeminiPair receives a currency pair and return gemini
+// symbol ex.: BTCUSD.
+func currencyPairToGeminiPair(cp types.CurrencyPair) string {
+	return strings.ToUpper(cp.Base + cp.Quote)
+}
+
+// newGeminiSubscriptionMsg returns a new subscription Msg.
+func newGeminiSubscriptionMsg(symbols []string) GeminiSubscriptionMsg {
+	return GeminiSubscriptionMsg{
+		Type: geminiSubscriptionType,
+		Subscriptions: []GeminiSubscription{
+			{
+				Name:    geminiL2Subscription,
+				Symbols: symbols,
+			},
+		},
+	}
+}
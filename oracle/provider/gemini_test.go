@@ -0,0 +1,147 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/math"
+
+	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle/types"
+)
+
+func TestGeminiProvider_GetTickerPrices(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		var resp string
+		switch req.URL.String() {
+		case geminiTickerPath + "btcusd":
+			resp = `{"bid":"62400","ask":"62420","volume":{"BTC":"812.5","USD":"50700000","timestamp":1700000000000},"last":"62410"}`
+		case geminiTickerPath + "ethusd":
+			resp = `{"bid":"2450","ask":"2451","volume":{"ETH":"9120.1","USD":"22300000","timestamp":1700000000000},"last":"2450.5"}`
+		default:
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, err := rw.Write([]byte(resp))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	p := &GeminiProvider{
+		logger: zerolog.Nop(),
+		endpoint: config.ProviderEndpoint{
+			Name: config.ProviderGemini,
+			Rest: server.URL,
+		},
+		client:          server.Client(),
+		tickers:         map[string]TickerPrice{},
+		subscribedPairs: map[string]types.CurrencyPair{},
+	}
+	p.setSubscribedPairs(
+		types.CurrencyPair{Base: "BTC", Quote: "USD"},
+		types.CurrencyPair{Base: "ETH", Quote: "USD"},
+		types.CurrencyPair{Base: "FOO", Quote: "BAR"},
+	)
+	p.refreshTickers()
+
+	t.Run("valid_request_multi_ticker", func(t *testing.T) {
+		prices, err := p.GetTickerPrices(
+			types.CurrencyPair{Base: "BTC", Quote: "USD"},
+			types.CurrencyPair{Base: "ETH", Quote: "USD"},
+		)
+		require.NoError(t, err)
+		require.Len(t, prices, 2)
+		require.Equal(t, math.LegacyMustNewDecFromStr("62410"), prices["BTCUSD"].Price)
+		require.Equal(t, math.LegacyMustNewDecFromStr("812.5"), prices["BTCUSD"].Volume)
+		require.Equal(t, math.LegacyMustNewDecFromStr("2450.5"), prices["ETHUSD"].Price)
+		require.Equal(t, math.LegacyMustNewDecFromStr("9120.1"), prices["ETHUSD"].Volume)
+	})
+
+	t.Run("invalid_request_invalid_ticker", func(t *testing.T) {
+		prices, err := p.GetTickerPrices(types.CurrencyPair{Base: "FOO", Quote: "BAR"})
+		require.NoError(t, err)
+		require.Zero(t, len(prices))
+	})
+}
+
+func TestGeminiProvider_GetCandlePrices(t *testing.T) {
+	server := NewMockProviderServer()
+	server.Start()
+	defer server.Close()
+
+	p, err := NewGeminiProvider(
+		context.TODO(),
+		zerolog.Nop(),
+		config.ProviderEndpoint{
+			Name:      config.ProviderGemini,
+			Rest:      "",
+			Websocket: server.GetBaseURL(),
+		},
+		types.CurrencyPair{Base: "BTC", Quote: "USD"},
+	)
+	require.NoError(t, err)
+
+	t.Run("valid_trade_messages", func(t *testing.T) {
+		now := time.Now().UnixMilli()
+		l2Updates := `{"type":"l2_updates","symbol":"BTCUSD","changes":[["buy","62400","1.5"]],"trades":[
+{"type":"trade","symbol":"BTCUSD","event_id":1,"tid":101,"timestamp":` + strconv.FormatInt(now-2*unixMinute, 10) + `,"price":"62300","quantity":"1","side":"buy"}
+]}`
+		trade := `{"type":"trade","symbol":"BTCUSD","event_id":2,"tid":102,"timestamp":` + strconv.FormatInt(now, 10) + `,"price":"62410","quantity":"0.5","side":"sell"}`
+		heartbeat := `{"type":"heartbeat","timestamp":` + strconv.FormatInt(now, 10) + `}`
+		p.messageReceived(websocket.TextMessage, []byte(l2Updates))
+		p.messageReceived(websocket.TextMessage, []byte(trade))
+		p.messageReceived(websocket.TextMessage, []byte(heartbeat))
+
+		// the recent trades are sent again after a reconnection
+		p.messageReceived(websocket.TextMessage, []byte(l2Updates))
+
+		prices, err := p.GetCandlePrices(types.CurrencyPair{Base: "BTC", Quote: "USD"})
+		require.NoError(t, err)
+		require.Len(t, prices["BTCUSD"], 2)
+		require.Equal(t, math.LegacyMustNewDecFromStr("62300"), prices["BTCUSD"][0].Price)
+		require.Equal(t, math.LegacyMustNewDecFromStr("1"), prices["BTCUSD"][0].Volume)
+		require.Equal(t, math.LegacyMustNewDecFromStr("62410"), prices["BTCUSD"][1].Price)
+		require.Equal(t, math.LegacyMustNewDecFromStr("0.5"), prices["BTCUSD"][1].Volume)
+		require.Equal(t, now, prices["BTCUSD"][1].TimeStamp)
+	})
+
+	t.Run("invalid_request_invalid_candle", func(t *testing.T) {
+		prices, err := p.GetCandlePrices(types.CurrencyPair{Base: "FOO", Quote: "BAR"})
+		require.NoError(t, err)
+		require.Zero(t, len(prices))
+	})
+}
+
+func TestGeminiProvider_GetAvailablePairs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		require.Equal(t, geminiRestPath, req.URL.String())
+		_, err := rw.Write([]byte(`["btcusd","ethusd","solusd"]`))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	p := &GeminiProvider{
+		endpoint: config.ProviderEndpoint{
+			Name: config.ProviderGemini,
+			Rest: server.URL,
+		},
+	}
+
+	pairs, err := p.GetAvailablePairs()
+	require.NoError(t, err)
+	require.Equal(t, map[string]struct{}{"BTCUSD": {}, "ETHUSD": {}, "SOLUSD": {}}, pairs)
+}
+
+func TestGeminiCurrencyPairToGeminiPair(t *testing.T) {
+	cp := types.CurrencyPair{Base: "BTC", Quote: "USD"}
+	geminiSymbol := currencyPairToGeminiPair(cp)
+	require.Equal(t, geminiSymbol, "BTCUSD")
+}
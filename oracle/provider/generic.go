@@ -0,0 +1,318 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/tidwall/gjson"
+
+	"cosmossdk.io/math"
+
+	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle/types"
+)
+
+// NewGenericProvider returns the provider declared in the config by its type.
+func NewGenericProvider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	cfg config.GenericProvider,
+	pairs ...types.CurrencyPair,
+) (Provider, error) {
+	switch cfg.Type {
+	case config.GenericProviderTypeREST:
+		return NewGenericRESTProvider(ctx, logger, cfg, pairs...)
+
+	case config.GenericProviderTypeWS:
+		return NewGenericWSProvider(ctx, logger, cfg, pairs...)
+
+	default:
+		return nil, fmt.Errorf("unsupported generic provider type: %s", cfg.Type)
+	}
+}
+
+// genericPriceStore keeps the tickers and candles of a provider declared in
+// the config, by currency pair.
+type genericPriceStore struct {
+	mtx     sync.RWMutex
+	tickers map[string]TickerPrice    // CurrencyPair => TickerPrice
+	candles map[string][]CandlePrice  // CurrencyPair => []CandlePrice
+	volumes map[string]math.LegacyDec // CurrencyPair => last 24h volume
+}
+
+func newGenericPriceStore() *genericPriceStore {
+	return &genericPriceStore{
+		tickers: map[string]TickerPrice{},
+		candles: map[string][]CandlePrice{},
+		volumes: map[string]math.LegacyDec{},
+	}
+}
+
+// GetTickerPrices returns the tickerPrices based on the saved map.
+func (s *genericPriceStore) GetTickerPrices(pairs ...types.CurrencyPair) (map[string]TickerPrice, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	tickerPrices := make(map[string]TickerPrice, len(pairs))
+	for _, cp := range pairs {
+		if ticker, ok := s.tickers[cp.String()]; ok {
+			tickerPrices[cp.String()] = ticker
+		}
+	}
+
+	return tickerPrices, nil
+}
+
+// GetCandlePrices returns the candlePrices based on the saved map.
+func (s *genericPriceStore) GetCandlePrices(pairs ...types.CurrencyPair) (map[string][]CandlePrice, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	candlePrices := make(map[string][]CandlePrice, len(pairs))
+	for _, cp := range pairs {
+		if candles, ok := s.candles[cp.String()]; ok {
+			candleList := []CandlePrice{}
+			candleList = append(candleList, candles...)
+			candlePrices[cp.String()] = candleList
+		}
+	}
+
+	return candlePrices, nil
+}
+
+// setPrice saves the price as the ticker of the pair and as a candle,
+// replacing the candle of the same minute and the stale ones. The ticker has
+// the 24h volume of the source, while the candle of the minute has the volume
+// added to it since the first price of the minute.
+func (s *genericPriceStore) setPrice(cp types.CurrencyPair, ticker TickerPrice, candle CandlePrice) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	key := cp.String()
+	s.tickers[key] = ticker
+
+	candle.Volume = math.LegacyZeroDec()
+	if lastVolume, ok := s.volumes[key]; ok && ticker.Volume.GT(lastVolume) {
+		candle.Volume = ticker.Volume.Sub(lastVolume)
+	}
+	s.volumes[key] = ticker.Volume
+
+	// the candle of the same minute is replaced, keeping its volume
+	staleTime := PastUnixTime(providerCandlePeriod)
+	candleList := []CandlePrice{}
+	for _, c := range s.candles[key] {
+		switch {
+		case c.TimeStamp/unixMinute == candle.TimeStamp/unixMinute:
+			candle.Volume = candle.Volume.Add(c.Volume)
+		case staleTime < c.TimeStamp:
+			candleList = append(candleList, c)
+		}
+	}
+	s.candles[key] = append([]CandlePrice{candle}, candleList...)
+}
+
+// genericAssets returns the base and quote of the pair on the source,
+// applying the aliases and case of the config
+func genericAssets(cfg config.GenericProvider, cp types.CurrencyPair) (string, string) {
+	base, quote := cp.Base, cp.Quote
+	if alias, ok := cfg.SymbolAliases[base]; ok {
+		base = alias
+	}
+	if alias, ok := cfg.SymbolAliases[quote]; ok {
+		quote = alias
+	}
+
+	switch cfg.SymbolCase {
+	case "upper":
+		return strings.ToUpper(base), strings.ToUpper(quote)
+	case "lower":
+		return strings.ToLower(base), strings.ToLower(quote)
+	default:
+		return base, quote
+	}
+}
+
+// genericSymbol returns the symbol of the pair on the source, ex. XBT-USD
+func genericSymbol(cfg config.GenericProvider, cp types.CurrencyPair) string {
+	base, quote := genericAssets(cfg, cp)
+	return base + cfg.SymbolSeparator + quote
+}
+
+// genericTemplate replaces the {base}, {quote} and {symbol} placeholders
+// with the assets of the pair on the source
+func genericTemplate(cfg config.GenericProvider, template string, cp types.CurrencyPair) string {
+	base, quote := genericAssets(cfg, cp)
+	return strings.NewReplacer(
+		"{base}", base,
+		"{quote}", quote,
+		"{symbol}", base+cfg.SymbolSeparator+quote,
+	).Replace(template)
+}
+
+// genericPairKey returns the currency pair of a symbol listed by the source,
+// ex. XBT-USD => BTCUSD, reverting the aliases of the config
+func genericPairKey(cfg config.GenericProvider, symbol string) string {
+	unalias := func(asset string) string {
+		for name, alias := range cfg.SymbolAliases {
+			if strings.EqualFold(alias, asset) {
+				return name
+			}
+		}
+		return asset
+	}
+
+	if len(cfg.SymbolSeparator) > 0 {
+		if base, quote, ok := strings.Cut(symbol, cfg.SymbolSeparator); ok {
+			return strings.ToUpper(unalias(base) + unalias(quote))
+		}
+	}
+
+	// without a separator only the prefix and suffix can be aliased
+	key := strings.ToUpper(symbol)
+	for name, alias := range cfg.SymbolAliases {
+		alias = strings.ToUpper(alias)
+		if strings.HasPrefix(key, alias) {
+			key = strings.ToUpper(name) + strings.TrimPrefix(key, alias)
+		}
+		if strings.HasSuffix(key, alias) {
+			key = strings.TrimSuffix(key, alias) + strings.ToUpper(name)
+		}
+	}
+
+	return key
+}
+
+// genericRequest returns the body of a GET request with the configured headers
+func genericRequest(ctx context.Context, client *http.Client, cfg config.GenericProvider, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range cfg.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status %d for %s", cfg.Name, resp.StatusCode, url)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// genericAvailablePairs returns the pairs listed by the pairs url, or nil when
+// the config has no pairs url so the configured pairs are all kept
+func genericAvailablePairs(client *http.Client, cfg config.GenericProvider) (map[string]struct{}, error) {
+	if len(cfg.PairsURL) == 0 {
+		return nil, nil
+	}
+
+	bz, err := genericRequest(context.Background(), client, cfg, cfg.PairsURL)
+	if err != nil {
+		return nil, err
+	}
+
+	symbols := gjson.GetBytes(bz, cfg.Pairs).Array()
+	availablePairs := make(map[string]struct{}, len(symbols))
+	for _, symbol := range symbols {
+		availablePairs[genericPairKey(cfg, symbol.String())] = struct{}{}
+	}
+
+	return availablePairs, nil
+}
+
+// extractGenericPrice extracts the price, 24h volume and timestamp of a
+// response with the selectors of the config. Without a volume selector the
+// volume is zero, leaving the source out of the volume weighted prices.
+func extractGenericPrice(cfg config.GenericProvider, cp types.CurrencyPair, bz []byte) (TickerPrice, CandlePrice, error) {
+	price, err := genericDecimal(gjson.GetBytes(bz, cfg.Price))
+	if err != nil {
+		return TickerPrice{}, CandlePrice{}, fmt.Errorf("%s price of %s: %w", cfg.Name, cp, err)
+	}
+
+	volume := "0"
+	if len(cfg.Volume) > 0 {
+		volume, err = genericDecimal(gjson.GetBytes(bz, cfg.Volume))
+		if err != nil {
+			return TickerPrice{}, CandlePrice{}, fmt.Errorf("%s volume of %s: %w", cfg.Name, cp, err)
+		}
+	}
+
+	timeStamp := time.Now().UnixMilli()
+	if len(cfg.Timestamp) > 0 {
+		timeStamp, err = genericTimestamp(gjson.GetBytes(bz, cfg.Timestamp), cfg.TimestampUnit)
+		if err != nil {
+			return TickerPrice{}, CandlePrice{}, fmt.Errorf("%s timestamp of %s: %w", cfg.Name, cp, err)
+		}
+	}
+
+	ticker, err := newTickerPrice(cfg.Name, cp.String(), price, volume)
+	if err != nil {
+		return TickerPrice{}, CandlePrice{}, err
+	}
+
+	candle, err := newCandlePrice(cfg.Name, cp.String(), price, volume, timeStamp)
+	if err != nil {
+		return TickerPrice{}, CandlePrice{}, err
+	}
+
+	return ticker, candle, nil
+}
+
+// genericDecimal returns the decimal of a selected number or string, keeping
+// the precision of the source. The exponent notation, ex. 1e-5, is expanded.
+func genericDecimal(result gjson.Result) (string, error) {
+	var number string
+	switch result.Type {
+	case gjson.Number:
+		number = result.Raw
+	case gjson.String:
+		number = result.Str
+	default:
+		return "", fmt.Errorf("expected a number, got %q", result.Raw)
+	}
+
+	if !strings.ContainsAny(number, "eE") {
+		return number, nil
+	}
+
+	rat, ok := new(big.Rat).SetString(number)
+	if !ok {
+		return "", fmt.Errorf("expected a number, got %q", number)
+	}
+	return rat.FloatString(math.LegacyPrecision), nil
+}
+
+// genericTimestamp returns the millisecond timestamp of a selected number, in
+// the given unit, or of a RFC3339 string
+func genericTimestamp(result gjson.Result, unit string) (int64, error) {
+	switch result.Type {
+	case gjson.Number:
+		if unit == "s" {
+			return int64(result.Float() * 1000), nil
+		}
+		return result.Int(), nil
+
+	case gjson.String:
+		t, err := time.Parse(time.RFC3339Nano, result.Str)
+		if err != nil {
+			return 0, err
+		}
+		return t.UnixMilli(), nil
+
+	default:
+		return 0, fmt.Errorf("expected a timestamp, got %q", result.Raw)
+	}
+}
@@ -0,0 +1,132 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle/types"
+)
+
+var _ Provider = (*GenericRESTProvider)(nil)
+
+type (
+	// GenericRESTProvider defines an Oracle provider declared in the config,
+	// polling the price of each pair from a REST API.
+	//
+	// The tickers and candles are built from the polled prices and read by the
+	// oracle without waiting on the API.
+	GenericRESTProvider struct {
+		*genericPriceStore
+
+		logger          zerolog.Logger
+		mtx             sync.RWMutex
+		cfg             config.GenericProvider
+		client          *http.Client
+		pollInterval    time.Duration
+		subscribedPairs map[string]types.CurrencyPair // CurrencyPair => types.CurrencyPair
+	}
+)
+
+// NewGenericRESTProvider returns a new generic REST provider polling the
+// prices of the pairs in the background.
+func NewGenericRESTProvider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	cfg config.GenericProvider,
+	pairs ...types.CurrencyPair,
+) (*GenericRESTProvider, error) {
+	pollInterval, err := cfg.GetPollInterval()
+	if err != nil {
+		return nil, err
+	}
+
+	provider := &GenericRESTProvider{
+		genericPriceStore: newGenericPriceStore(),
+		logger:            logger.With().Str("provider", cfg.Name).Logger(),
+		cfg:               cfg,
+		client:            newDefaultHTTPClient(),
+		pollInterval:      pollInterval,
+		subscribedPairs:   map[string]types.CurrencyPair{},
+	}
+
+	provider.setSubscribedPairs(pairs...)
+
+	go provider.poll(ctx)
+
+	return provider, nil
+}
+
+// poll refreshes the prices periodically until the context is done.
+func (p *GenericRESTProvider) poll(ctx context.Context) {
+	pollTicker := time.NewTicker(p.pollInterval)
+	defer pollTicker.Stop()
+
+	for {
+		p.refreshPrices(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-pollTicker.C:
+		}
+	}
+}
+
+// refreshPrices requests the price of each subscribed pair.
+func (p *GenericRESTProvider) refreshPrices(ctx context.Context) {
+	p.mtx.RLock()
+	pairs := types.MapPairsToSlice(p.subscribedPairs)
+	p.mtx.RUnlock()
+
+	for _, cp := range pairs {
+		if err := p.refreshPrice(ctx, cp); err != nil {
+			p.logger.Warn().Err(err).Msg(fmt.Sprint("failed to refresh price for pair ", cp))
+		}
+	}
+}
+
+// refreshPrice requests the price of the pair and saves it in the store.
+func (p *GenericRESTProvider) refreshPrice(ctx context.Context, cp types.CurrencyPair) error {
+	bz, err := genericRequest(ctx, p.client, p.cfg, genericTemplate(p.cfg, p.cfg.URL, cp))
+	if err != nil {
+		return err
+	}
+
+	ticker, candle, err := extractGenericPrice(p.cfg, cp, bz)
+	if err != nil {
+		return err
+	}
+
+	p.setPrice(cp, ticker, candle)
+	return nil
+}
+
+// SubscribeCurrencyPairs adds the new pairs to the polled pairs.
+func (p *GenericRESTProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.setSubscribedPairs(cps...)
+	return nil
+}
+
+// setSubscribedPairs sets N currency pairs to the map of subscribed pairs.
+func (p *GenericRESTProvider) setSubscribedPairs(cps ...types.CurrencyPair) {
+	for _, cp := range cps {
+		p.subscribedPairs[cp.String()] = cp
+	}
+}
+
+// GetAvailablePairs returns the pairs listed by the pairs url, or nil when
+// the config has no pairs url so the configured pairs are all kept.
+// ex.: map["BTCUSD" => {}, "ETHUSD" => {}].
+func (p *GenericRESTProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	return genericAvailablePairs(p.client, p.cfg)
+}
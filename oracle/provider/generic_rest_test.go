@@ -0,0 +1,208 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/math"
+
+	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle/types"
+)
+
+func TestGenericRESTProvider_GetTickerPrices(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		require.Equal(t, "secret", req.Header.Get("X-Api-Key"))
+
+		var resp string
+		switch req.URL.String() {
+		case "/ticker/xbt-usd":
+			resp = `{"data":[{"last":"62410.5","vol":1532.31,"ts":1700000000}]}`
+		case "/ticker/eth-usd":
+			resp = `{"data":[{"last":2450.1,"vol":"10250.4","ts":1700000060}]}`
+		case "/ticker/foo-bar":
+			resp = `{"data":[]}`
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, err := rw.Write([]byte(resp))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	cfg := config.GenericProvider{
+		Name:            "myvenue",
+		Type:            config.GenericProviderTypeREST,
+		URL:             server.URL + "/ticker/{symbol}",
+		Headers:         map[string]string{"X-Api-Key": "secret"},
+		Price:           "data.0.last",
+		Volume:          "data.0.vol",
+		Timestamp:       "data.0.ts",
+		TimestampUnit:   "s",
+		SymbolSeparator: "-",
+		SymbolCase:      "lower",
+		SymbolAliases:   map[string]string{"BTC": "XBT"},
+	}
+
+	p := &GenericRESTProvider{
+		genericPriceStore: newGenericPriceStore(),
+		logger:            zerolog.Nop(),
+		cfg:               cfg,
+		client:            server.Client(),
+		subscribedPairs:   map[string]types.CurrencyPair{},
+	}
+	p.setSubscribedPairs(
+		types.CurrencyPair{Base: "BTC", Quote: "USD"},
+		types.CurrencyPair{Base: "ETH", Quote: "USD"},
+		types.CurrencyPair{Base: "FOO", Quote: "BAR"},
+	)
+	p.refreshPrices(context.TODO())
+
+	t.Run("valid_request_multi_ticker", func(t *testing.T) {
+		prices, err := p.GetTickerPrices(
+			types.CurrencyPair{Base: "BTC", Quote: "USD"},
+			types.CurrencyPair{Base: "ETH", Quote: "USD"},
+		)
+		require.NoError(t, err)
+		require.Len(t, prices, 2)
+		require.Equal(t, math.LegacyMustNewDecFromStr("62410.5"), prices["BTCUSD"].Price)
+		require.Equal(t, math.LegacyMustNewDecFromStr("1532.31"), prices["BTCUSD"].Volume)
+		require.Equal(t, math.LegacyMustNewDecFromStr("2450.1"), prices["ETHUSD"].Price)
+		require.Equal(t, math.LegacyMustNewDecFromStr("10250.4"), prices["ETHUSD"].Volume)
+	})
+
+	t.Run("valid_request_candles", func(t *testing.T) {
+		// the timestamps are outside of the candle period, only the last price
+		// of each pair is kept
+		prices, err := p.GetCandlePrices(types.CurrencyPair{Base: "ETH", Quote: "USD"})
+		require.NoError(t, err)
+		require.Len(t, prices["ETHUSD"], 1)
+		require.Equal(t, int64(1700000060000), prices["ETHUSD"][0].TimeStamp)
+	})
+
+	t.Run("invalid_request_invalid_ticker", func(t *testing.T) {
+		prices, err := p.GetTickerPrices(types.CurrencyPair{Base: "FOO", Quote: "BAR"})
+		require.NoError(t, err)
+		require.Zero(t, len(prices))
+	})
+}
+
+func TestGenericRESTProvider_GetAvailablePairs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		require.Equal(t, "/symbols", req.URL.String())
+		_, err := rw.Write([]byte(`{"symbols":[{"name":"XBTUSD"},{"name":"ETHUSD"},{"name":"USDTUSD"}]}`))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	p := &GenericRESTProvider{
+		cfg: config.GenericProvider{
+			Name:          "myvenue",
+			PairsURL:      server.URL + "/symbols",
+			Pairs:         "symbols.#.name",
+			SymbolAliases: map[string]string{"BTC": "XBT"},
+		},
+		client: server.Client(),
+	}
+
+	pairs, err := p.GetAvailablePairs()
+	require.NoError(t, err)
+	require.Equal(t, map[string]struct{}{"BTCUSD": {}, "ETHUSD": {}, "USDTUSD": {}}, pairs)
+
+	// without a pairs url the pairs can't be listed
+	p.cfg.PairsURL = ""
+	pairs, err = p.GetAvailablePairs()
+	require.NoError(t, err)
+	require.Nil(t, pairs)
+}
+
+func TestGenericPairKey(t *testing.T) {
+	cfg := config.GenericProvider{
+		SymbolAliases: map[string]string{"BTC": "XBT"},
+	}
+	require.Equal(t, "BTCUSD", genericPairKey(cfg, "xbtusd"))
+	require.Equal(t, "USDBTC", genericPairKey(cfg, "USDXBT"))
+
+	cfg.SymbolSeparator = "_"
+	require.Equal(t, "BTCUSDT", genericPairKey(cfg, "XBT_USDT"))
+}
+
+func TestGenericTimestamp(t *testing.T) {
+	cfg := config.GenericProvider{
+		Name:  "myvenue",
+		Price: "price",
+	}
+	cp := types.CurrencyPair{Base: "BTC", Quote: "USD"}
+
+	cfg.Timestamp = "time"
+	_, candle, err := extractGenericPrice(cfg, cp, []byte(`{"price":"1","time":"2023-11-14T22:13:20.5Z"}`))
+	require.NoError(t, err)
+	require.Equal(t, int64(1700000000500), candle.TimeStamp)
+	require.Equal(t, math.LegacyZeroDec(), candle.Volume)
+
+	_, candle, err = extractGenericPrice(cfg, cp, []byte(`{"price":"1","time":1700000000500}`))
+	require.NoError(t, err)
+	require.Equal(t, int64(1700000000500), candle.TimeStamp)
+
+	_, _, err = extractGenericPrice(cfg, cp, []byte(`{"price":"1","time":true}`))
+	require.Error(t, err)
+}
+
+func TestGenericDecimal(t *testing.T) {
+	cfg := config.GenericProvider{
+		Name:   "myvenue",
+		Price:  "price",
+		Volume: "volume",
+	}
+	cp := types.CurrencyPair{Base: "BTC", Quote: "USD"}
+
+	ticker, _, err := extractGenericPrice(cfg, cp, []byte(`{"price":1e-5,"volume":"2.5E3"}`))
+	require.NoError(t, err)
+	require.Equal(t, math.LegacyMustNewDecFromStr("0.00001"), ticker.Price)
+	require.Equal(t, math.LegacyMustNewDecFromStr("2500"), ticker.Volume)
+
+	_, _, err = extractGenericPrice(cfg, cp, []byte(`{"price":"1e-5x","volume":"1"}`))
+	require.Error(t, err)
+}
+
+func TestGenericPriceStoreVolume(t *testing.T) {
+	s := newGenericPriceStore()
+	cp := types.CurrencyPair{Base: "BTC", Quote: "USD"}
+	now := PastUnixTime(0)
+
+	setPrice := func(timeStamp int64, volume string) {
+		s.setPrice(
+			cp,
+			TickerPrice{Price: math.LegacyOneDec(), Volume: math.LegacyMustNewDecFromStr(volume)},
+			CandlePrice{Price: math.LegacyOneDec(), Volume: math.LegacyMustNewDecFromStr(volume), TimeStamp: timeStamp},
+		)
+	}
+
+	// the candles have the 24h volume added since the previous price, the
+	// first price and a decreasing volume adding none
+	setPrice(now-2*unixMinute, "1000")
+	setPrice(now-unixMinute, "1010")
+	setPrice(now, "1005")
+
+	candles, err := s.GetCandlePrices(cp)
+	require.NoError(t, err)
+	volumes := map[int64]math.LegacyDec{}
+	for _, candle := range candles[cp.String()] {
+		volumes[candle.TimeStamp] = candle.Volume
+	}
+	require.Equal(t, map[int64]math.LegacyDec{
+		now - 2*unixMinute: math.LegacyZeroDec(),
+		now - unixMinute:   math.LegacyNewDec(10),
+		now:                math.LegacyZeroDec(),
+	}, volumes)
+
+	tickers, err := s.GetTickerPrices(cp)
+	require.NoError(t, err)
+	require.Equal(t, math.LegacyMustNewDecFromStr("1005"), tickers[cp.String()].Volume)
+}
@@ -0,0 +1,218 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+	"github.com/tidwall/gjson"
+
+	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle/types"
+)
+
+const (
+	genericSymbolsPlaceholder = "{symbols}"
+	genericPingMessageText    = "text"
+)
+
+var _ Provider = (*GenericWSProvider)(nil)
+
+type (
+	// GenericWSProvider defines an Oracle provider declared in the config,
+	// subscribing to the prices of a websocket.
+	//
+	// The messages matching the config are routed to their pair by symbol and
+	// saved as the ticker and the candle of the minute.
+	GenericWSProvider struct {
+		*genericPriceStore
+
+		wsc             *WebsocketController
+		logger          zerolog.Logger
+		mtx             sync.RWMutex
+		cfg             config.GenericProvider
+		client          *http.Client
+		subscribedPairs map[string]types.CurrencyPair // Symbol => types.CurrencyPair
+	}
+)
+
+// NewGenericWSProvider returns a new generic websocket provider with the
+// subscriptions and pings of the config.
+func NewGenericWSProvider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	cfg config.GenericProvider,
+	pairs ...types.CurrencyPair,
+) (*GenericWSProvider, error) {
+	wsURL, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	pingDuration, err := cfg.GetPingDuration()
+	if err != nil {
+		return nil, err
+	}
+
+	pingMessageType := websocket.PingMessage
+	if cfg.PingMessageType == genericPingMessageText {
+		pingMessageType = websocket.TextMessage
+	}
+
+	provider := &GenericWSProvider{
+		genericPriceStore: newGenericPriceStore(),
+		logger:            logger.With().Str("provider", cfg.Name).Logger(),
+		cfg:               cfg,
+		client:            newDefaultHTTPClient(),
+		subscribedPairs:   map[string]types.CurrencyPair{},
+	}
+
+	provider.setSubscribedPairs(pairs...)
+
+	subscriptionMsgs, err := provider.getSubscriptionMsgs(pairs...)
+	if err != nil {
+		return nil, err
+	}
+
+	provider.wsc = NewWebsocketController(
+		ctx,
+		cfg.Name,
+		*wsURL,
+		subscriptionMsgs,
+		provider.messageReceived,
+		pingDuration,
+		uint(pingMessageType),
+		provider.logger,
+	)
+	if len(cfg.PingMessage) > 0 {
+		provider.wsc.SetPingMessage([]byte(cfg.PingMessage))
+	}
+
+	go provider.wsc.Start()
+
+	return provider, nil
+}
+
+// getSubscriptionMsgs returns the subscription messages of the config for the
+// pairs, a message with the symbols placeholder is sent once for all pairs.
+func (p *GenericWSProvider) getSubscriptionMsgs(cps ...types.CurrencyPair) ([]interface{}, error) {
+	if len(cps) == 0 {
+		return nil, nil
+	}
+
+	var templates []string
+	for _, subscription := range p.cfg.Subscriptions {
+		if !strings.Contains(subscription, genericSymbolsPlaceholder) {
+			for _, cp := range cps {
+				templates = append(templates, genericTemplate(p.cfg, subscription, cp))
+			}
+			continue
+		}
+
+		symbols := make([]string, len(cps))
+		for i, cp := range cps {
+			symbols[i] = genericSymbol(p.cfg, cp)
+		}
+		bz, err := json.Marshal(symbols)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, strings.ReplaceAll(subscription, genericSymbolsPlaceholder, string(bz)))
+	}
+
+	subscriptionMsgs := make([]interface{}, len(templates))
+	for i, template := range templates {
+		if !json.Valid([]byte(template)) {
+			return nil, fmt.Errorf("invalid subscription message for %s: %s", p.cfg.Name, template)
+		}
+		subscriptionMsgs[i] = json.RawMessage(template)
+	}
+
+	return subscriptionMsgs, nil
+}
+
+// SubscribeCurrencyPairs sends the new subscription messages to the websocket
+// and adds them to the providers subscribedPairs array
+func (p *GenericWSProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	newPairs := []types.CurrencyPair{}
+	for _, cp := range cps {
+		if _, ok := p.subscribedPairs[strings.ToUpper(genericSymbol(p.cfg, cp))]; !ok {
+			newPairs = append(newPairs, cp)
+		}
+	}
+
+	newSubscriptionMsgs, err := p.getSubscriptionMsgs(newPairs...)
+	if err != nil {
+		return err
+	}
+	if err := p.wsc.AddSubscriptionMsgs(newSubscriptionMsgs); err != nil {
+		return err
+	}
+
+	p.setSubscribedPairs(newPairs...)
+	return nil
+}
+
+// messageReceived saves the price of the messages matching the config, the
+// other messages such as the subscription results are ignored.
+func (p *GenericWSProvider) messageReceived(messageType int, bz []byte) {
+	if messageType != websocket.TextMessage || !gjson.ValidBytes(bz) {
+		return
+	}
+
+	for selector, value := range p.cfg.Match {
+		if gjson.GetBytes(bz, selector).String() != value {
+			return
+		}
+	}
+
+	symbol := gjson.GetBytes(bz, p.cfg.Symbol).String()
+	p.mtx.RLock()
+	cp, ok := p.subscribedPairs[strings.ToUpper(symbol)]
+	p.mtx.RUnlock()
+	if !ok {
+		p.logger.Debug().Str("symbol", symbol).Msg("received message of an unsubscribed symbol")
+		return
+	}
+
+	ticker, candle, err := extractGenericPrice(p.cfg, cp, bz)
+	if err != nil {
+		p.logger.Error().Err(err).Msg("failed to extract price")
+		return
+	}
+
+	p.setPrice(cp, ticker, candle)
+	telemetry.IncrCounter(
+		1,
+		"websocket",
+		"message",
+		"type",
+		"ticker",
+		"provider",
+		p.cfg.Name,
+	)
+}
+
+// setSubscribedPairs sets N currency pairs to the map of subscribed pairs.
+func (p *GenericWSProvider) setSubscribedPairs(cps ...types.CurrencyPair) {
+	for _, cp := range cps {
+		p.subscribedPairs[strings.ToUpper(genericSymbol(p.cfg, cp))] = cp
+	}
+}
+
+// GetAvailablePairs returns the pairs listed by the pairs url, or nil when
+// the config has no pairs url so the configured pairs are all kept.
+// ex.: map["BTCUSD" => {}, "ETHUSD" => {}].
+func (p *GenericWSProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	return genericAvailablePairs(p.client, p.cfg)
+}
@@ -0,0 +1,161 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/math"
+
+	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle/types"
+)
+
+func TestGenericWSProvider_GetTickerPrices(t *testing.T) {
+	server := NewMockProviderServer()
+	server.Start()
+	defer server.Close()
+
+	// the echo server sends the subscriptions back as price messages
+	p, err := NewGenericWSProvider(
+		context.TODO(),
+		zerolog.Nop(),
+		config.GenericProvider{
+			Name: "myvenue",
+			Type: config.GenericProviderTypeWS,
+			URL:  server.GetWebsocketURL(),
+			Subscriptions: []string{
+				`{"channel":"ticker","data":{"s":"{symbol}","c":"62410.5","v":"1532.31"}}`,
+			},
+			Match:           map[string]string{"channel": "ticker"},
+			Symbol:          "data.s",
+			Price:           "data.c",
+			Volume:          "data.v",
+			SymbolSeparator: "-",
+		},
+		types.CurrencyPair{Base: "BTC", Quote: "USD"},
+	)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		prices, err := p.GetTickerPrices(types.CurrencyPair{Base: "BTC", Quote: "USD"})
+		return err == nil && len(prices) == 1
+	}, 5*time.Second, 50*time.Millisecond)
+
+	prices, err := p.GetTickerPrices(types.CurrencyPair{Base: "BTC", Quote: "USD"})
+	require.NoError(t, err)
+	require.Equal(t, math.LegacyMustNewDecFromStr("62410.5"), prices["BTCUSD"].Price)
+	require.Equal(t, math.LegacyMustNewDecFromStr("1532.31"), prices["BTCUSD"].Volume)
+
+	candles, err := p.GetCandlePrices(types.CurrencyPair{Base: "BTC", Quote: "USD"})
+	require.NoError(t, err)
+	require.Len(t, candles["BTCUSD"], 1)
+}
+
+func TestGenericWSProvider_MessageReceived(t *testing.T) {
+	p := &GenericWSProvider{
+		genericPriceStore: newGenericPriceStore(),
+		logger:            zerolog.Nop(),
+		cfg: config.GenericProvider{
+			Name:          "myvenue",
+			Type:          config.GenericProviderTypeWS,
+			Match:         map[string]string{"arg.channel": "tickers"},
+			Symbol:        "arg.instId",
+			Price:         "data.0.last",
+			Volume:        "data.0.vol24h",
+			Timestamp:     "data.0.ts",
+			SymbolCase:    "upper",
+			SymbolAliases: map[string]string{"BTC": "XBT"},
+		},
+		subscribedPairs: map[string]types.CurrencyPair{},
+	}
+	p.setSubscribedPairs(types.CurrencyPair{Base: "BTC", Quote: "USDT"})
+
+	// the start of the minute, so the prices of the minute share a candle
+	now := time.Now().UnixMilli() / unixMinute * unixMinute
+	tickerWithVolume := func(channel, symbol, price, volume string, timeStamp int64) []byte {
+		bz, err := json.Marshal(map[string]interface{}{
+			"arg":  map[string]string{"channel": channel, "instId": symbol},
+			"data": []map[string]interface{}{{"last": price, "vol24h": volume, "ts": timeStamp}},
+		})
+		require.NoError(t, err)
+		return bz
+	}
+	ticker := func(channel, symbol, price string, timeStamp int64) []byte {
+		return tickerWithVolume(channel, symbol, price, "10", timeStamp)
+	}
+
+	t.Run("ignored_messages", func(t *testing.T) {
+		p.messageReceived(websocket.TextMessage, []byte(`pong`))
+		p.messageReceived(websocket.TextMessage, []byte(`{"event":"subscribe","arg":{"channel":"tickers"}}`))
+		p.messageReceived(websocket.TextMessage, ticker("trades", "XBTUSDT", "1", now))
+		p.messageReceived(websocket.TextMessage, ticker("tickers", "ETHUSDT", "1", now))
+		p.messageReceived(websocket.BinaryMessage, ticker("tickers", "XBTUSDT", "1", now))
+
+		prices, err := p.GetTickerPrices(types.CurrencyPair{Base: "BTC", Quote: "USDT"})
+		require.NoError(t, err)
+		require.Zero(t, len(prices))
+	})
+
+	t.Run("valid_ticker_messages", func(t *testing.T) {
+		p.messageReceived(websocket.TextMessage, ticker("tickers", "XBTUSDT", "62400", now-2*unixMinute))
+		p.messageReceived(websocket.TextMessage, ticker("tickers", "xbtusdt", "62405", now-unixMinute))
+		p.messageReceived(websocket.TextMessage, ticker("tickers", "XBTUSDT", "62410", now))
+
+		prices, err := p.GetTickerPrices(types.CurrencyPair{Base: "BTC", Quote: "USDT"})
+		require.NoError(t, err)
+		require.Equal(t, math.LegacyMustNewDecFromStr("62410"), prices["BTCUSDT"].Price)
+		require.Equal(t, math.LegacyMustNewDecFromStr("10"), prices["BTCUSDT"].Volume)
+
+		candles, err := p.GetCandlePrices(types.CurrencyPair{Base: "BTC", Quote: "USDT"})
+		require.NoError(t, err)
+		require.Len(t, candles["BTCUSDT"], 3)
+		require.Equal(t, now, candles["BTCUSDT"][0].TimeStamp)
+	})
+
+	t.Run("volume_of_the_minute", func(t *testing.T) {
+		// the messages of the same minute add their volume to its candle
+		p.messageReceived(websocket.TextMessage, tickerWithVolume("tickers", "XBTUSDT", "62415", "12", now+1))
+		p.messageReceived(websocket.TextMessage, tickerWithVolume("tickers", "XBTUSDT", "62420", "15", now+2))
+
+		candles, err := p.GetCandlePrices(types.CurrencyPair{Base: "BTC", Quote: "USDT"})
+		require.NoError(t, err)
+		require.Len(t, candles["BTCUSDT"], 3)
+		require.Equal(t, math.LegacyMustNewDecFromStr("62420"), candles["BTCUSDT"][0].Price)
+		require.Equal(t, math.LegacyMustNewDecFromStr("5"), candles["BTCUSDT"][0].Volume)
+	})
+}
+
+func TestGenericWSProvider_GetSubscriptionMsgs(t *testing.T) {
+	p := &GenericWSProvider{
+		cfg: config.GenericProvider{
+			Name: "myvenue",
+			Subscriptions: []string{
+				`{"op":"subscribe","args":{symbols}}`,
+				`{"op":"subscribe","channel":"kline_{base}_{quote}"}`,
+			},
+			SymbolSeparator: "_",
+			SymbolCase:      "lower",
+		},
+	}
+
+	msgs, err := p.getSubscriptionMsgs(
+		types.CurrencyPair{Base: "BTC", Quote: "USDT"},
+		types.CurrencyPair{Base: "ETH", Quote: "USDT"},
+	)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{
+		json.RawMessage(`{"op":"subscribe","args":["btc_usdt","eth_usdt"]}`),
+		json.RawMessage(`{"op":"subscribe","channel":"kline_btc_usdt"}`),
+		json.RawMessage(`{"op":"subscribe","channel":"kline_eth_usdt"}`),
+	}, msgs)
+
+	p.cfg.Subscriptions = []string{`{"op":"subscribe"`}
+	_, err = p.getSubscriptionMsgs(types.CurrencyPair{Base: "BTC", Quote: "USDT"})
+	require.Error(t, err)
+}
@@ -7,7 +7,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
@@ -489,14 +488,8 @@ func (p *HuobiProvider) setSubscribedPairs(cps ...types.CurrencyPair) {
 
 // GetAvailablePairs returns all pairs to which the provider can subscribe.
 func (p *HuobiProvider) GetAvailablePairs() (map[string]struct{}, error) {
-	resp, err := http.Get(p.endpoints.Rest + huobiRestPath)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
 	var pairsSummary HuobiPairsSummary
-	if err := json.NewDecoder(resp.Body).Decode(&pairsSummary); err != nil {
+	if err := getAvailablePairsJSON(p.endpoints.Rest+huobiRestPath, &pairsSummary); err != nil {
 		return nil, err
 	}
 
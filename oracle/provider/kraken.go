@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
@@ -26,9 +25,13 @@ const (
 	KrakenRestPath                = "/0/public/AssetPairs"
 	krakenEventSystemStatus       = "systemStatus"
 	krakenEventSubscriptionStatus = "subscriptionStatus"
+	krakenBookDepth               = 10
 )
 
-var _ Provider = (*KrakenProvider)(nil)
+var (
+	_ Provider          = (*KrakenProvider)(nil)
+	_ OrderBookProvider = (*KrakenProvider)(nil)
+)
 
 type (
 	// KrakenProvider defines an Oracle provider implemented by the Kraken public
@@ -43,6 +46,7 @@ type (
 		endpoints       config.ProviderEndpoint
 		tickers         map[string]TickerPrice        // Symbol => TickerPrice
 		candles         map[string][]KrakenCandle     // Symbol => KrakenCandle
+		orderBooks      map[string]OrderBook          // Symbol => OrderBook
 		subscribedPairs map[string]types.CurrencyPair // Symbol => types.CurrencyPair
 	}
 
@@ -62,6 +66,16 @@ type (
 		Symbol    string // Symbol for this candle
 	}
 
+	// KrakenBook book snapshot or update from Kraken book channel, the levels
+	// are [price, volume, timestamp].
+	// REF: https://docs.kraken.com/websockets/#message-book
+	KrakenBook struct {
+		AsksSnapshot [][]string `json:"as"` // Asks of the snapshot
+		BidsSnapshot [][]string `json:"bs"` // Bids of the snapshot
+		Asks         [][]string `json:"a"`  // Updated asks
+		Bids         [][]string `json:"b"`  // Updated bids
+	}
+
 	// KrakenSubscriptionMsg Msg to subscribe to all the pairs at once.
 	KrakenSubscriptionMsg struct {
 		Event        string                    `json:"event"`        // subscribe/unsubscribe
@@ -71,7 +85,8 @@ type (
 
 	// KrakenSubscriptionChannel Msg with the channel name to be subscribed.
 	KrakenSubscriptionChannel struct {
-		Name string `json:"name"` // channel to be subscribed ex.: ticker
+		Name  string `json:"name"`            // channel to be subscribed ex.: ticker
+		Depth int    `json:"depth,omitempty"` // depth of the book channel
 	}
 
 	// KrakenEvent wraps the possible events from the provider.
@@ -139,6 +154,7 @@ func NewKrakenProvider(
 		endpoints:       endpoints,
 		tickers:         map[string]TickerPrice{},
 		candles:         map[string][]KrakenCandle{},
+		orderBooks:      map[string]OrderBook{},
 		subscribedPairs: map[string]types.CurrencyPair{},
 	}
 
@@ -189,6 +205,21 @@ func (p *KrakenProvider) GetCandlePrices(pairs ...types.CurrencyPair) (map[strin
 	return candlePrices, nil
 }
 
+// GetOrderBooks returns the order books of the provided pairs.
+func (p *KrakenProvider) GetOrderBooks(pairs ...types.CurrencyPair) (map[string]OrderBook, error) {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	orderBooks := make(map[string]OrderBook, len(pairs))
+	for _, cp := range pairs {
+		if orderBook, ok := p.orderBooks[cp.String()]; ok {
+			orderBooks[cp.String()] = orderBook
+		}
+	}
+
+	return orderBooks, nil
+}
+
 // SubscribeCurrencyPairs subscribe all currency pairs into ticker and candle channels.
 func (p *KrakenProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) error {
 	if len(cps) == 0 {
@@ -211,7 +242,8 @@ func (p *KrakenProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) error
 	return nil
 }
 
-// subscribeChannels subscribe all currency pairs into ticker and candle channels.
+// subscribeChannels subscribe all currency pairs into ticker, candle and
+// book channels.
 func (p *KrakenProvider) subscribeChannels(cps ...types.CurrencyPair) error {
 	pairs := make([]string, len(cps))
 
@@ -223,7 +255,11 @@ func (p *KrakenProvider) subscribeChannels(cps ...types.CurrencyPair) error {
 		return err
 	}
 
-	return p.subscribeCandles(pairs...)
+	if err := p.subscribeCandles(pairs...); err != nil {
+		return err
+	}
+
+	return p.subscribeBooks(pairs...)
 }
 
 // subscribedPairsToSlice returns the map of subscribed pairs as slice
@@ -318,6 +354,7 @@ func (p *KrakenProvider) messageReceived(messageType int, bz []byte) {
 		krakenErr   error
 		tickerErr   error
 		candleErr   error
+		bookErr     error
 	)
 
 	krakenErr = json.Unmarshal(bz, &krakenEvent)
@@ -343,10 +380,16 @@ func (p *KrakenProvider) messageReceived(messageType int, bz []byte) {
 		return
 	}
 
+	bookErr = p.messageReceivedBook(bz)
+	if bookErr == nil {
+		return
+	}
+
 	p.logger.Error().
 		Int("length", len(bz)).
 		AnErr("ticker", tickerErr).
 		AnErr("candle", candleErr).
+		AnErr("book", bookErr).
 		AnErr("event", krakenErr).
 		Msg("Error on receive message")
 }
@@ -494,6 +537,57 @@ func (p *KrakenProvider) messageReceivedCandle(bz []byte) error {
 	return nil
 }
 
+// messageReceivedBook handles the book snapshot and update msgs.
+func (p *KrakenProvider) messageReceivedBook(bz []byte) error {
+	// the provider response is an array with the channel id, one or two book
+	// objects, the channel name and the pair
+	// kraken documentation https://docs.kraken.com/websockets/#message-book
+	var bookMessage []json.RawMessage
+	if err := json.Unmarshal(bz, &bookMessage); err != nil {
+		return err
+	}
+
+	if len(bookMessage) != 4 && len(bookMessage) != 5 {
+		return fmt.Errorf("received something different than book")
+	}
+
+	var channelName string
+	if err := json.Unmarshal(bookMessage[len(bookMessage)-2], &channelName); err != nil ||
+		channelName != fmt.Sprintf("book-%d", krakenBookDepth) {
+		return fmt.Errorf("received an unexpected channel name")
+	}
+
+	var krakenPair string
+	if err := json.Unmarshal(bookMessage[len(bookMessage)-1], &krakenPair); err != nil {
+		return fmt.Errorf("received an unexpected pair")
+	}
+	currencyPairSymbol := krakenPairToCurrencyPairSymbol(normalizeKrakenBTCPair(krakenPair))
+
+	books := make([]KrakenBook, 0, 2)
+	for _, bookBz := range bookMessage[1 : len(bookMessage)-2] {
+		var krakenBook KrakenBook
+		if err := json.Unmarshal(bookBz, &krakenBook); err != nil {
+			return err
+		}
+		books = append(books, krakenBook)
+	}
+
+	if err := p.setOrderBook(currencyPairSymbol, books...); err != nil {
+		return err
+	}
+
+	telemetry.IncrCounter(
+		1,
+		"websocket",
+		"message",
+		"type",
+		"depth",
+		"provider",
+		config.ProviderKraken,
+	)
+	return nil
+}
+
 // reconnect closes the last WS connection and create a new one.
 func (p *KrakenProvider) reconnect() error {
 	p.wsClient.Close()
@@ -603,6 +697,36 @@ func (p *KrakenProvider) setCandlePair(candle KrakenCandle) {
 	p.candles[candle.Symbol] = candleList
 }
 
+// setOrderBook applies the book snapshots and updates to the order book of
+// the symbol, a snapshot replaces the previous levels.
+func (p *KrakenProvider) setOrderBook(symbol string, books ...KrakenBook) error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	orderBook := p.orderBooks[symbol]
+	for _, book := range books {
+		if book.AsksSnapshot != nil || book.BidsSnapshot != nil {
+			orderBook = OrderBook{}
+		}
+
+		asks, err := newOrderBookLevels("Kraken", symbol, append(book.AsksSnapshot, book.Asks...))
+		if err != nil {
+			return err
+		}
+		bids, err := newOrderBookLevels("Kraken", symbol, append(book.BidsSnapshot, book.Bids...))
+		if err != nil {
+			return err
+		}
+
+		orderBook.Asks = updateOrderBookLevels(orderBook.Asks, asks, false, krakenBookDepth)
+		orderBook.Bids = updateOrderBookLevels(orderBook.Bids, bids, true, krakenBookDepth)
+	}
+
+	orderBook.TimeStamp = time.Now().UnixMilli()
+	p.orderBooks[symbol] = orderBook
+	return nil
+}
+
 // ping to check websocket connection.
 func (p *KrakenProvider) ping() error {
 	return p.wsClient.WriteMessage(websocket.PingMessage, ping)
@@ -620,6 +744,12 @@ func (p *KrakenProvider) subscribeCandles(pairs ...string) error {
 	return p.wsClient.WriteJSON(subsMsg)
 }
 
+// subscribeBooks write the subscription msg to the provider.
+func (p *KrakenProvider) subscribeBooks(pairs ...string) error {
+	subsMsg := newKrakenBookSubscriptionMsg(pairs...)
+	return p.wsClient.WriteJSON(subsMsg)
+}
+
 // setSubscribedPairs sets N currency pairs to the map of subscribed pairs.
 func (p *KrakenProvider) setSubscribedPairs(cps ...types.CurrencyPair) {
 	p.mtx.Lock()
@@ -642,20 +772,15 @@ func (p *KrakenProvider) removeSubscribedTickers(tickerSymbols ...string) {
 
 // GetAvailablePairs returns all pairs to which the provider can subscribe.
 func (p *KrakenProvider) GetAvailablePairs() (map[string]struct{}, error) {
-	resp, err := http.Get(p.endpoints.Rest + KrakenRestPath)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
 	var pairsSummary KrakenPairsSummary
-	if err := json.NewDecoder(resp.Body).Decode(&pairsSummary); err != nil {
+	if err := getAvailablePairsJSON(p.endpoints.Rest+KrakenRestPath, &pairsSummary); err != nil {
 		return nil, err
 	}
 
 	availablePairs := make(map[string]struct{}, len(pairsSummary.Result))
 	for _, pair := range pairsSummary.Result {
-		splitPair := strings.Split(pair.WsName, "/")
+		// the pairs are subscribed with BTC instead of XBT
+		splitPair := strings.Split(normalizeKrakenBTCPair(pair.WsName), "/")
 		if len(splitPair) != 2 {
 			continue
 		}
@@ -702,6 +827,18 @@ func newKrakenCandleSubscriptionMsg(pairs ...string) KrakenSubscriptionMsg {
 	}
 }
 
+// newKrakenBookSubscriptionMsg returns a new subscription Msg.
+func newKrakenBookSubscriptionMsg(pairs ...string) KrakenSubscriptionMsg {
+	return KrakenSubscriptionMsg{
+		Event: "subscribe",
+		Pair:  pairs,
+		Subscription: KrakenSubscriptionChannel{
+			Name:  "book",
+			Depth: krakenBookDepth,
+		},
+	}
+}
+
 // krakenPairToCurrencyPairSymbol receives a kraken pair formated
 // ex.: ATOM/USDT and return currencyPair Symbol ATOMUSDT.
 func krakenPairToCurrencyPairSymbol(krakenPair string) string {
@@ -2,6 +2,8 @@ package provider
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/rs/zerolog"
@@ -111,3 +113,67 @@ func TestNormalizeKrakenBTCPair(t *testing.T) {
 	atomSymbol := normalizeKrakenBTCPair("ATOM/USDT")
 	require.Equal(t, atomSymbol, "ATOM/USDT")
 }
+
+func TestKrakenProvider_MessageReceivedBook(t *testing.T) {
+	p := &KrakenProvider{logger: zerolog.Nop(), orderBooks: map[string]OrderBook{}}
+	btcUSD := types.CurrencyPair{Base: "BTC", Quote: "USD"}
+
+	require.NoError(t, p.messageReceivedBook([]byte(`[
+		0,
+		{
+			"as": [["5541.3", "2.5", "1534614248.1"], ["5541.8", "0.3", "1534614248.1"]],
+			"bs": [["5541.2", "1.5", "1534614248.1"], ["5539.9", "0.8", "1534614248.1"]]
+		},
+		"book-10",
+		"XBT/USD"
+	]`)))
+
+	orderBooks, err := p.GetOrderBooks(btcUSD)
+	require.NoError(t, err)
+	require.Equal(t, math.LegacyMustNewDecFromStr("5541.2"), orderBooks["BTCUSD"].BestBid())
+	require.Equal(t, math.LegacyMustNewDecFromStr("5541.3"), orderBooks["BTCUSD"].BestAsk())
+
+	// the updates of both sides remove the best ask and add a better bid
+	require.NoError(t, p.messageReceivedBook([]byte(`[
+		1234,
+		{"a": [["5541.3", "0.0", "1534614335.3"]]},
+		{"b": [["5541.25", "1.0", "1534614335.3", "r"]], "c": "974942666"},
+		"book-10",
+		"XBT/USD"
+	]`)))
+
+	orderBooks, err = p.GetOrderBooks(btcUSD)
+	require.NoError(t, err)
+	require.Len(t, orderBooks["BTCUSD"].Bids, 3)
+	require.Equal(t, math.LegacyMustNewDecFromStr("5541.25"), orderBooks["BTCUSD"].BestBid())
+	require.Equal(t, math.LegacyMustNewDecFromStr("5541.8"), orderBooks["BTCUSD"].BestAsk())
+
+	// the ticker messages are not books
+	require.Error(t, p.messageReceivedBook([]byte(`[340, {"c": ["5541.2", "0.1"]}, "ticker", "XBT/USD"]`)))
+}
+
+func TestKrakenProvider_GetAvailablePairs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		require.Equal(t, KrakenRestPath, req.URL.String())
+		resp := `{"result":{
+"XXBTZUSD":{"wsname":"XBT/USD"},
+"XBTUSDT":{"wsname":"XBT/USDT"},
+"ATOMUSD":{"wsname":"ATOM/USD"}
+}}`
+		_, err := rw.Write([]byte(resp))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	p := &KrakenProvider{
+		endpoints: config.ProviderEndpoint{
+			Name: config.ProviderKraken,
+			Rest: server.URL,
+		},
+	}
+
+	// the XBT pairs are listed with BTC, as they are subscribed
+	pairs, err := p.GetAvailablePairs()
+	require.NoError(t, err)
+	require.Equal(t, map[string]struct{}{"BTCUSD": {}, "BTCUSDT": {}, "ATOMUSD": {}}, pairs)
+}
@@ -0,0 +1,525 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+
+	"github.com/cosmos/cosmos-sdk/telemetry"
+
+	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle/types"
+)
+
+const (
+	kucoinWSHost              = "ws-api-spot.kucoin.com"
+	kucoinRestHost            = "https://api.kucoin.com"
+	kucoinRestPath            = "/api/v2/symbols"
+	kucoinBulletPath          = "/api/v1/bullet-public"
+	kucoinSuccessCode         = "200000"
+	kucoinTickerTopicPrefix   = "/market/snapshot:"
+	kucoinCandleTopicPrefix   = "/market/candles:"
+	kucoinCandleTopicSuffix   = "_1min"
+	kucoinCandlePeriod        = int64(time.Minute / time.Millisecond)
+	kucoinMaxTopicSymbols     = 100
+	kucoinMessageType         = "message"
+	kucoinErrorType           = "error"
+	kucoinDefaultPingInterval = 18 * time.Second
+)
+
+var _ Provider = (*KucoinProvider)(nil)
+
+type (
+	// KucoinProvider defines an Oracle provider implemented by the KuCoin public
+	// API. The websocket requires a token, negotiated with the REST API on each
+	// connection together with the websocket server and its ping interval.
+	//
+	// REF: https://www.kucoin.com/docs/websocket/basic-info/apply-connect-token/public-token-no-authentication-required-
+	KucoinProvider struct {
+		wsc             *WebsocketController
+		logger          zerolog.Logger
+		mtx             sync.RWMutex
+		endpoint        config.ProviderEndpoint
+		client          *http.Client
+		pingInterval    time.Duration                 // negotiated with the token
+		tickers         map[string]TickerPrice        // Symbol => TickerPrice
+		candles         map[string][]CandlePrice      // Symbol => CandlePrice
+		subscribedPairs map[string]types.CurrencyPair // Symbol => types.CurrencyPair
+	}
+
+	KucoinBulletResponse struct {
+		Code string           `json:"code"` // 200000 on success
+		Msg  string           `json:"msg"`  // error message
+		Data KucoinBulletData `json:"data"`
+	}
+	KucoinBulletData struct {
+		Token           string                 `json:"token"`
+		InstanceServers []KucoinInstanceServer `json:"instanceServers"`
+	}
+	KucoinInstanceServer struct {
+		Endpoint     string `json:"endpoint"`     // ex.: wss://ws-api-spot.kucoin.com/
+		Protocol     string `json:"protocol"`     // websocket
+		PingInterval int64  `json:"pingInterval"` // Interval between pings in milliseconds
+	}
+
+	// KucoinMessage is any message received from the websocket, the data is
+	// decoded according to the topic
+	KucoinMessage struct {
+		Type  string          `json:"type"`  // welcome, ack, pong, message or error
+		Topic string          `json:"topic"` // ex.: /market/snapshot:BTC-USDT
+		Data  json.RawMessage `json:"data"`
+	}
+	KucoinSnapshot struct {
+		Data KucoinTicker `json:"data"`
+	}
+	KucoinTicker struct {
+		Symbol    string      `json:"symbol"`          // Symbol ex.: BTC-USDT
+		LastPrice json.Number `json:"lastTradedPrice"` // Last traded price
+		Volume    json.Number `json:"vol"`             // Volume over the last 24 hours
+	}
+	KucoinCandle struct {
+		Symbol string   `json:"symbol"`  // Symbol ex.: BTC-USDT
+		Candle []string `json:"candles"` // Start time in seconds, open, close, high, low, volume, turnover
+	}
+
+	KucoinSubscriptionMsg struct {
+		ID             int64  `json:"id"`
+		Type           string `json:"type"`  // subscribe, unsubscribe
+		Topic          string `json:"topic"` // ex.: /market/snapshot:BTC-USDT,ETH-USDT
+		PrivateChannel bool   `json:"privateChannel"`
+		Response       bool   `json:"response"`
+	}
+	KucoinPingMsg struct {
+		ID   int64  `json:"id"`
+		Type string `json:"type"` // ping
+	}
+
+	KucoinPairsSummary struct {
+		Code string       `json:"code"` // 200000 on success
+		Msg  string       `json:"msg"`
+		Data []KucoinPair `json:"data"`
+	}
+	KucoinPair struct {
+		BaseCurrency  string `json:"baseCurrency"`  // Base ex.: BTC
+		QuoteCurrency string `json:"quoteCurrency"` // Quote ex.: USDT
+		EnableTrading bool   `json:"enableTrading"`
+	}
+)
+
+func NewKucoinProvider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	endpoint config.ProviderEndpoint,
+	pairs ...types.CurrencyPair,
+) (*KucoinProvider, error) {
+	if endpoint.Name != config.ProviderKucoin {
+		endpoint = config.ProviderEndpoint{
+			Name:      config.ProviderKucoin,
+			Rest:      kucoinRestHost,
+			Websocket: kucoinWSHost,
+		}
+	}
+
+	provider := &KucoinProvider{
+		logger:          logger.With().Str("provider", "kucoin").Logger(),
+		endpoint:        endpoint,
+		client:          newDefaultHTTPClient(),
+		pingInterval:    kucoinDefaultPingInterval,
+		tickers:         map[string]TickerPrice{},
+		candles:         map[string][]CandlePrice{},
+		subscribedPairs: map[string]types.CurrencyPair{},
+	}
+
+	provider.setSubscribedPairs(pairs...)
+
+	// the url is resolved with a new token on every connection, kucoin expects
+	// a json ping message so the controller ping is disabled
+	provider.wsc = NewWebsocketController(
+		ctx,
+		config.ProviderKucoin,
+		url.URL{},
+		provider.getSubscriptionMsgs(pairs...),
+		provider.messageReceived,
+		disabledPingDuration,
+		websocket.PingMessage,
+		provider.logger,
+	)
+	provider.wsc.SetWebsocketURLFunc(provider.getWebsocketURL)
+
+	go provider.wsc.Start()
+	go provider.pingLoop(ctx)
+
+	return provider, nil
+}
+
+// getWebsocketURL requests a public token and returns the url of the first
+// websocket server, updating the ping interval of the provider
+func (p *KucoinProvider) getWebsocketURL() (url.URL, error) {
+	resp, err := p.client.Post(p.endpoint.Rest+kucoinBulletPath, "application/json", nil)
+	if err != nil {
+		return url.URL{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return url.URL{}, fmt.Errorf("failed to get %s token: status %d", config.ProviderKucoin, resp.StatusCode)
+	}
+
+	var bulletResp KucoinBulletResponse
+	if err := json.NewDecoder(resp.Body).Decode(&bulletResp); err != nil {
+		return url.URL{}, err
+	}
+	if bulletResp.Code != kucoinSuccessCode {
+		return url.URL{}, fmt.Errorf("failed to get %s token: %s %s", config.ProviderKucoin, bulletResp.Code, bulletResp.Msg)
+	}
+
+	// fallback to the configured server when none is returned
+	wsURL := url.URL{
+		Scheme: "wss",
+		Host:   p.endpoint.Websocket,
+		Path:   "/",
+	}
+	for _, server := range bulletResp.Data.InstanceServers {
+		if server.Protocol != "websocket" {
+			continue
+		}
+
+		serverURL, err := url.Parse(server.Endpoint)
+		if err != nil {
+			return url.URL{}, err
+		}
+		wsURL = *serverURL
+
+		if server.PingInterval > 0 {
+			p.mtx.Lock()
+			p.pingInterval = time.Duration(server.PingInterval) * time.Millisecond
+			p.mtx.Unlock()
+		}
+		break
+	}
+
+	query := wsURL.Query()
+	query.Set("token", bulletResp.Data.Token)
+	query.Set("connectId", strconv.FormatInt(time.Now().UnixNano(), 10))
+	wsURL.RawQuery = query.Encode()
+
+	return wsURL, nil
+}
+
+// pingLoop sends a ping message at the negotiated interval, otherwise
+// kucoin closes the connection
+func (p *KucoinProvider) pingLoop(ctx context.Context) {
+	for {
+		p.mtx.RLock()
+		pingInterval := p.pingInterval
+		p.mtx.RUnlock()
+
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-time.After(pingInterval):
+			ping := KucoinPingMsg{
+				ID:   time.Now().UnixMilli(),
+				Type: "ping",
+			}
+			if err := p.wsc.SendJSON(ping); err != nil {
+				p.logger.Debug().Err(err).Msg("could not send ping message")
+			}
+		}
+	}
+}
+
+// getSubscriptionMsgs subscribes to the snapshot of up to 100 pairs per
+// message, and to the 1m candles of each pair
+func (p *KucoinProvider) getSubscriptionMsgs(cps ...types.CurrencyPair) []interface{} {
+	subscriptionMsgs := make([]interface{}, 0, len(cps)/kucoinMaxTopicSymbols+1+len(cps))
+
+	symbols := make([]string, 0, len(cps))
+	for _, cp := range cps {
+		symbols = append(symbols, currencyPairToKucoinPair(cp))
+	}
+	for start := 0; start < len(symbols); start += kucoinMaxTopicSymbols {
+		end := start + kucoinMaxTopicSymbols
+		if end > len(symbols) {
+			end = len(symbols)
+		}
+		topic := kucoinTickerTopicPrefix + strings.Join(symbols[start:end], ",")
+		subscriptionMsgs = append(subscriptionMsgs, newKucoinSubscriptionMsg(topic))
+	}
+
+	for _, symbol := range symbols {
+		topic := kucoinCandleTopicPrefix + symbol + kucoinCandleTopicSuffix
+		subscriptionMsgs = append(subscriptionMsgs, newKucoinSubscriptionMsg(topic))
+	}
+
+	return subscriptionMsgs
+}
+
+// SubscribeCurrencyPairs sends the new subscription messages to the websocket
+// and adds them to the providers subscribedPairs array
+func (p *KucoinProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	newPairs := []types.CurrencyPair{}
+	for _, cp := range cps {
+		if _, ok := p.subscribedPairs[cp.String()]; !ok {
+			newPairs = append(newPairs, cp)
+		}
+	}
+
+	newSubscriptionMsgs := p.getSubscriptionMsgs(newPairs...)
+	if err := p.wsc.AddSubscriptionMsgs(newSubscriptionMsgs); err != nil {
+		return err
+	}
+
+	p.setSubscribedPairs(newPairs...)
+	return nil
+}
+
+// GetTickerPrices returns the tickerPrices based on the saved map.
+func (p *KucoinProvider) GetTickerPrices(pairs ...types.CurrencyPair) (map[string]TickerPrice, error) {
+	tickerPrices := make(map[string]TickerPrice, len(pairs))
+
+	for _, cp := range pairs {
+		key := currencyPairToKucoinPair(cp)
+		price, err := p.getTickerPrice(key)
+		if err != nil {
+			p.logger.Debug().AnErr("err", err).Msg(fmt.Sprint("failed to fetch tickers for pair ", cp))
+			continue
+		}
+		tickerPrices[cp.String()] = price
+	}
+
+	return tickerPrices, nil
+}
+
+// GetCandlePrices returns the candlePrices based on the saved map
+func (p *KucoinProvider) GetCandlePrices(pairs ...types.CurrencyPair) (map[string][]CandlePrice, error) {
+	candlePrices := make(map[string][]CandlePrice, len(pairs))
+
+	for _, cp := range pairs {
+		key := currencyPairToKucoinPair(cp)
+		prices, err := p.getCandlePrices(key)
+		if err != nil {
+			p.logger.Debug().AnErr("err", err).Msg(fmt.Sprint("failed to fetch candles for pair ", cp))
+			continue
+		}
+		candlePrices[cp.String()] = prices
+	}
+
+	return candlePrices, nil
+}
+
+func (p *KucoinProvider) getTickerPrice(key string) (TickerPrice, error) {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	ticker, ok := p.tickers[key]
+	if !ok {
+		return TickerPrice{}, fmt.Errorf("%s ticker not found for %s", config.ProviderKucoin, key)
+	}
+
+	return ticker, nil
+}
+
+func (p *KucoinProvider) getCandlePrices(key string) ([]CandlePrice, error) {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	candles, ok := p.candles[key]
+	if !ok {
+		return []CandlePrice{}, fmt.Errorf("%s candle not found for %s", config.ProviderKucoin, key)
+	}
+
+	candleList := []CandlePrice{}
+	candleList = append(candleList, candles...)
+
+	return candleList, nil
+}
+
+func (p *KucoinProvider) messageReceived(messageType int, bz []byte) {
+	if messageType != websocket.TextMessage {
+		return
+	}
+
+	var msg KucoinMessage
+	if err := json.Unmarshal(bz, &msg); err != nil {
+		p.logger.Error().
+			Int("length", len(bz)).
+			AnErr("message", err).
+			Msg("Error on receive message")
+		return
+	}
+
+	switch {
+	case msg.Type == kucoinErrorType:
+		p.logger.Error().RawJSON("data", msg.Data).Msg("kucoin returned an error")
+
+	case msg.Type != kucoinMessageType:
+		// welcome, ack and pong messages
+
+	case strings.HasPrefix(msg.Topic, kucoinTickerTopicPrefix):
+		var snapshot KucoinSnapshot
+		if err := json.Unmarshal(msg.Data, &snapshot); err != nil {
+			p.logger.Error().Err(err).Str("topic", msg.Topic).Msg("failed to unmarshal ticker")
+			return
+		}
+		p.setTickerPair(snapshot.Data)
+		telemetry.IncrCounter(
+			1,
+			"websocket",
+			"message",
+			"type",
+			"ticker",
+			"provider",
+			config.ProviderKucoin,
+		)
+
+	case strings.HasPrefix(msg.Topic, kucoinCandleTopicPrefix):
+		var candle KucoinCandle
+		if err := json.Unmarshal(msg.Data, &candle); err != nil {
+			p.logger.Error().Err(err).Str("topic", msg.Topic).Msg("failed to unmarshal candle")
+			return
+		}
+		p.setCandlePair(candle)
+		telemetry.IncrCounter(
+			1,
+			"websocket",
+			"message",
+			"type",
+			"candle",
+			"provider",
+			config.ProviderKucoin,
+		)
+
+	default:
+		p.logger.Error().
+			Int("length", len(bz)).
+			Str("topic", msg.Topic).
+			Msg("Error on receive message")
+	}
+}
+
+func (p *KucoinProvider) setTickerPair(ticker KucoinTicker) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	tickerPrice, err := newTickerPrice(
+		config.ProviderKucoin,
+		ticker.Symbol,
+		ticker.LastPrice.String(),
+		ticker.Volume.String(),
+	)
+	if err != nil {
+		p.logger.Warn().Err(err).Msg("kucoin: failed to parse ticker")
+		return
+	}
+
+	p.tickers[ticker.Symbol] = tickerPrice
+}
+
+// setCandlePair stores the candle, replacing the updates received earlier for
+// the same minute, as the candles topic pushes the open candle several times
+func (p *KucoinProvider) setCandlePair(kucoinCandle KucoinCandle) {
+	// start time, open, close, high, low, volume, turnover
+	if len(kucoinCandle.Candle) < 6 {
+		p.logger.Warn().Str("symbol", kucoinCandle.Symbol).Msg("kucoin: invalid candle")
+		return
+	}
+
+	startTime, err := strconv.ParseInt(kucoinCandle.Candle[0], 10, 64)
+	if err != nil {
+		p.logger.Warn().Err(err).Msg("kucoin: failed to parse candle time")
+		return
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	candle, err := newCandlePrice(
+		config.ProviderKucoin,
+		kucoinCandle.Symbol,
+		kucoinCandle.Candle[2],
+		kucoinCandle.Candle[5],
+		startTime*1000+kucoinCandlePeriod, // end time in milliseconds
+	)
+	if err != nil {
+		p.logger.Warn().Err(err).Msg("kucoin: failed to parse candle")
+		return
+	}
+
+	staleTime := PastUnixTime(providerCandlePeriod)
+	candleList := []CandlePrice{}
+	candleList = append(candleList, candle)
+
+	for _, c := range p.candles[kucoinCandle.Symbol] {
+		if staleTime < c.TimeStamp && c.TimeStamp != candle.TimeStamp {
+			candleList = append(candleList, c)
+		}
+	}
+
+	p.candles[kucoinCandle.Symbol] = candleList
+}
+
+// setSubscribedPairs sets N currency pairs to the map of subscribed pairs.
+func (p *KucoinProvider) setSubscribedPairs(cps ...types.CurrencyPair) {
+	for _, cp := range cps {
+		p.subscribedPairs[cp.String()] = cp
+	}
+}
+
+// GetAvailablePairs returns all pairs to which the provider can subscribe.
+// ex.: map["ATOMUSDT" => {}, "UMEEUSDC" => {}].
+func (p *KucoinProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	var pairsSummary KucoinPairsSummary
+	if err := getAvailablePairsJSON(p.endpoint.Rest+kucoinRestPath, &pairsSummary); err != nil {
+		return nil, err
+	}
+	if pairsSummary.Code != kucoinSuccessCode {
+		return nil, fmt.Errorf("failed to get %s available pairs: %s %s", config.ProviderKucoin, pairsSummary.Code, pairsSummary.Msg)
+	}
+
+	availablePairs := make(map[string]struct{}, len(pairsSummary.Data))
+	for _, pair := range pairsSummary.Data {
+		if !pair.EnableTrading {
+			continue
+		}
+
+		cp := types.CurrencyPair{
+			Base:  strings.ToUpper(pair.BaseCurrency),
+			Quote: strings.ToUpper(pair.QuoteCurrency),
+		}
+
+		availablePairs[cp.String()] = struct{}{}
+	}
+
+	return availablePairs, nil
+}
+
+// currencyPairToKucoinPair receives a currency pair and return kucoin
+// symbol ex.: ATOM-USDT.
+func currencyPairToKucoinPair(cp types.CurrencyPair) string {
+	return strings.ToUpper(cp.Base + "-" + cp.Quote)
+}
+
+// newKucoinSubscriptionMsg returns a new subscription Msg.
+func newKucoinSubscriptionMsg(topic string) KucoinSubscriptionMsg {
+	return KucoinSubscriptionMsg{
+		ID:             time.Now().UnixMilli(),
+		Type:           "subscribe",
+		Topic:          topic,
+		PrivateChannel: false,
+		Response:       true,
+	}
+}
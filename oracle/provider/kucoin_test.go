@@ -0,0 +1,257 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/math"
+
+	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle/types"
+)
+
+func TestKucoinProvider_GetTickerPrices(t *testing.T) {
+	p := &KucoinProvider{
+		logger:  zerolog.Nop(),
+		tickers: map[string]TickerPrice{},
+	}
+
+	t.Run("valid_request_single_ticker", func(t *testing.T) {
+		lastPrice := math.LegacyMustNewDecFromStr("2650.1")
+		volume := math.LegacyMustNewDecFromStr("1893.45")
+
+		tickerMap := map[string]TickerPrice{}
+		tickerMap["XAUT-USDT"] = TickerPrice{
+			Price:  lastPrice,
+			Volume: volume,
+		}
+
+		p.tickers = tickerMap
+
+		prices, err := p.GetTickerPrices(types.CurrencyPair{Base: "XAUT", Quote: "USDT"})
+		require.NoError(t, err)
+		require.Len(t, prices, 1)
+		require.Equal(t, lastPrice, prices["XAUTUSDT"].Price)
+		require.Equal(t, volume, prices["XAUTUSDT"].Volume)
+	})
+
+	t.Run("valid_ticker_message", func(t *testing.T) {
+		msg := `{"type":"message","topic":"/market/snapshot:TRX-USDT","subject":"trade.snapshot","data":{"sequence":"1545896669291","data":{"symbol":"TRX-USDT","lastTradedPrice":0.1612,"vol":123456789.5}}}`
+		p.messageReceived(websocket.TextMessage, []byte(msg))
+
+		prices, err := p.GetTickerPrices(types.CurrencyPair{Base: "TRX", Quote: "USDT"})
+		require.NoError(t, err)
+		require.Len(t, prices, 1)
+		require.Equal(t, math.LegacyMustNewDecFromStr("0.1612"), prices["TRXUSDT"].Price)
+		require.Equal(t, math.LegacyMustNewDecFromStr("123456789.5"), prices["TRXUSDT"].Volume)
+	})
+
+	t.Run("invalid_request_invalid_ticker", func(t *testing.T) {
+		prices, err := p.GetTickerPrices(types.CurrencyPair{Base: "FOO", Quote: "BAR"})
+		require.NoError(t, err)
+		require.Zero(t, len(prices))
+	})
+}
+
+func TestKucoinProvider_GetCandlePrices(t *testing.T) {
+	p := &KucoinProvider{
+		logger:  zerolog.Nop(),
+		candles: map[string][]CandlePrice{},
+	}
+
+	t.Run("valid_candle_message_replaces_open_candle", func(t *testing.T) {
+		start := strconv.FormatInt(time.Now().Unix(), 10)
+		first := `{"type":"message","topic":"/market/candles:TRX-USDT_1min","subject":"trade.candles.update","data":{"symbol":"TRX-USDT","candles":["` + start + `","0.1610","0.1611","0.1612","0.1609","1000","161.1"]}}`
+		second := `{"type":"message","topic":"/market/candles:TRX-USDT_1min","subject":"trade.candles.update","data":{"symbol":"TRX-USDT","candles":["` + start + `","0.1610","0.1615","0.1616","0.1609","1500","242.2"]}}`
+		p.messageReceived(websocket.TextMessage, []byte(first))
+		p.messageReceived(websocket.TextMessage, []byte(second))
+
+		prices, err := p.GetCandlePrices(types.CurrencyPair{Base: "TRX", Quote: "USDT"})
+		require.NoError(t, err)
+		require.Len(t, prices["TRXUSDT"], 1)
+		require.Equal(t, math.LegacyMustNewDecFromStr("0.1615"), prices["TRXUSDT"][0].Price)
+		require.Equal(t, math.LegacyMustNewDecFromStr("1500"), prices["TRXUSDT"][0].Volume)
+	})
+
+	t.Run("invalid_request_invalid_candle", func(t *testing.T) {
+		prices, err := p.GetCandlePrices(types.CurrencyPair{Base: "FOO", Quote: "BAR"})
+		require.NoError(t, err)
+		require.Zero(t, len(prices))
+	})
+}
+
+func TestKucoinProvider_GetWebsocketURL(t *testing.T) {
+	tokens := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		require.Equal(t, http.MethodPost, req.Method)
+		require.Equal(t, kucoinBulletPath, req.URL.String())
+		tokens++
+		resp := fmt.Sprintf(`{"code":"200000","data":{"token":"token%d","instanceServers":[
+{"endpoint":"wss://ws-api-spot.kucoin.com/","encrypt":true,"protocol":"websocket","pingInterval":15000,"pingTimeout":10000}
+]}}`, tokens)
+		_, err := rw.Write([]byte(resp))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	p := &KucoinProvider{
+		endpoint: config.ProviderEndpoint{
+			Name:      config.ProviderKucoin,
+			Rest:      server.URL,
+			Websocket: kucoinWSHost,
+		},
+		client:       server.Client(),
+		pingInterval: kucoinDefaultPingInterval,
+	}
+
+	// every connection negotiates a new token
+	for i := 1; i <= 2; i++ {
+		wsURL, err := p.getWebsocketURL()
+		require.NoError(t, err)
+		require.Equal(t, "ws-api-spot.kucoin.com", wsURL.Host)
+		require.Equal(t, fmt.Sprintf("token%d", i), wsURL.Query().Get("token"))
+		require.NotEmpty(t, wsURL.Query().Get("connectId"))
+	}
+	require.Equal(t, 15*time.Second, p.pingInterval)
+}
+
+func TestKucoinProvider_GetWebsocketURLError(t *testing.T) {
+	testCases := map[string]struct {
+		status int
+		resp   string
+		err    string
+	}{
+		"error code": {
+			status: http.StatusOK,
+			resp:   `{"code":"429000","msg":"Too Many Requests"}`,
+			err:    "Too Many Requests",
+		},
+		"error status": {
+			status: http.StatusServiceUnavailable,
+			resp:   `<html>unavailable</html>`,
+			err:    "status 503",
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+				rw.WriteHeader(tc.status)
+				_, err := rw.Write([]byte(tc.resp))
+				require.NoError(t, err)
+			}))
+			defer server.Close()
+
+			p := &KucoinProvider{
+				endpoint: config.ProviderEndpoint{
+					Name: config.ProviderKucoin,
+					Rest: server.URL,
+				},
+				client: server.Client(),
+			}
+
+			_, err := p.getWebsocketURL()
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestKucoinProvider_Connect(t *testing.T) {
+	wsServer := NewMockProviderServer()
+	wsServer.Start()
+	defer wsServer.Close()
+
+	tokens := make(chan string, 1)
+	restServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		resp := fmt.Sprintf(`{"code":"200000","data":{"token":"token","instanceServers":[
+{"endpoint":"%s","protocol":"websocket","pingInterval":18000}
+]}}`, wsServer.GetWebsocketURL())
+		_, err := rw.Write([]byte(resp))
+		require.NoError(t, err)
+		select {
+		case tokens <- "token":
+		default:
+		}
+	}))
+	defer restServer.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p, err := NewKucoinProvider(
+		ctx,
+		zerolog.Nop(),
+		config.ProviderEndpoint{
+			Name:      config.ProviderKucoin,
+			Rest:      restServer.URL,
+			Websocket: wsServer.GetBaseURL(),
+		},
+		types.CurrencyPair{Base: "XAUT", Quote: "USDT"},
+	)
+	require.NoError(t, err)
+
+	select {
+	case <-tokens:
+	case <-time.After(5 * time.Second):
+		t.Fatal("token was not requested")
+	}
+
+	require.Eventually(t, func() bool {
+		return p.SubscribeCurrencyPairs(types.CurrencyPair{Base: "TRX", Quote: "USDT"}) == nil
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestKucoinProvider_GetAvailablePairs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		require.Equal(t, kucoinRestPath, req.URL.String())
+		resp := `{"code":"200000","data":[
+{"symbol":"XAUT-USDT","baseCurrency":"XAUT","quoteCurrency":"USDT","enableTrading":true},
+{"symbol":"TRX-USDT","baseCurrency":"TRX","quoteCurrency":"USDT","enableTrading":true},
+{"symbol":"OLD-USDT","baseCurrency":"OLD","quoteCurrency":"USDT","enableTrading":false}
+]}`
+		_, err := rw.Write([]byte(resp))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	p := &KucoinProvider{
+		endpoint: config.ProviderEndpoint{
+			Name: config.ProviderKucoin,
+			Rest: server.URL,
+		},
+	}
+
+	pairs, err := p.GetAvailablePairs()
+	require.NoError(t, err)
+	require.Equal(t, map[string]struct{}{"XAUTUSDT": {}, "TRXUSDT": {}}, pairs)
+}
+
+func TestKucoinProvider_GetSubscriptionMsgs(t *testing.T) {
+	p := &KucoinProvider{}
+
+	msgs := p.getSubscriptionMsgs(
+		types.CurrencyPair{Base: "XAUT", Quote: "USDT"},
+		types.CurrencyPair{Base: "TRX", Quote: "USDT"},
+	)
+	require.Len(t, msgs, 3)
+	require.Equal(t, "/market/snapshot:XAUT-USDT,TRX-USDT", msgs[0].(KucoinSubscriptionMsg).Topic)
+	require.Equal(t, "/market/candles:XAUT-USDT_1min", msgs[1].(KucoinSubscriptionMsg).Topic)
+	require.Equal(t, "/market/candles:TRX-USDT_1min", msgs[2].(KucoinSubscriptionMsg).Topic)
+}
+
+func TestKucoinCurrencyPairToKucoinPair(t *testing.T) {
+	cp := types.CurrencyPair{Base: "ATOM", Quote: "USDT"}
+	kucoinSymbol := currencyPairToKucoinPair(cp)
+	require.Equal(t, kucoinSymbol, "ATOM-USDT")
+}
@@ -122,12 +122,16 @@ func (p MockProvider) SubscribeCurrencyPairs(_ ...types.CurrencyPair) error {
 
 // GetAvailablePairs return all available pairs symbol to susbscribe.
 func (p MockProvider) GetAvailablePairs() (map[string]struct{}, error) {
-	resp, err := http.Get(p.baseURL)
+	resp, err := availablePairsClient.Get(p.baseURL)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get available pairs from %s: status %d", p.baseURL, resp.StatusCode)
+	}
+
 	csvReader := csv.NewReader(resp.Body)
 	records, err := csvReader.ReadAll()
 	if err != nil {
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
@@ -21,30 +20,47 @@ import (
 )
 
 const (
-	okxWSHost    = "ws.okx.com:8443"
-	okxWSPath    = "/ws/v5/public"
-	okxPingCheck = time.Second * 28 // should be < 30
-	okxRestHost  = "https://www.okx.com"
-	okxRestPath  = "/api/v5/market/tickers?instType=SPOT"
+	okxWSHost         = "ws.okx.com:8443"
+	okxWSPath         = "/ws/v5/public"
+	okxBusinessWSPath = "/ws/v5/business"
+	okxPingCheck      = time.Second * 28 // should be < 30
+	okxRestHost       = "https://www.okx.com"
+	okxRestPath       = "/api/v5/market/tickers?instType=SPOT"
 )
 
-var _ Provider = (*OkxProvider)(nil)
+var (
+	_ Provider          = (*OkxProvider)(nil)
+	_ OrderBookProvider = (*OkxProvider)(nil)
+)
 
 type (
 	// OkxProvider defines an Oracle provider implemented by the Okx public
-	// API.
+	// API. The tickers and the order books are streamed on the public
+	// websocket and the candles on the business websocket.
 	//
 	// REF: https://www.okx.com/docs-v5/en/#websocket-api-public-channel-tickers-channel
+	// REF: https://www.okx.com/docs-v5/en/#order-book-trading-market-data-ws-candlesticks-channel
+	// REF: https://www.okx.com/docs-v5/en/#order-book-trading-market-data-ws-order-book-channel
 	OkxProvider struct {
-		wsURL           url.URL
-		wsClient        *websocket.Conn
-		logger          zerolog.Logger
-		reconnectTimer  *time.Ticker
-		mtx             sync.RWMutex
-		endpoints       config.ProviderEndpoint
-		tickers         map[string]OkxTickerPair      // InstId => OkxTickerPair
-		candles         map[string][]OkxCandlePair    // InstId => 0kxCandlePair
-		subscribedPairs map[string]types.CurrencyPair // Symbol => types.CurrencyPair
+		wsClient         *okxWSConn
+		businessWSClient *okxWSConn
+		logger           zerolog.Logger
+		mtx              sync.RWMutex
+		endpoints        config.ProviderEndpoint
+		tickers          map[string]OkxTickerPair      // InstId => OkxTickerPair
+		candles          map[string][]OkxCandlePair    // InstId => 0kxCandlePair
+		orderBooks       map[string]OrderBook          // InstId => OrderBook
+		subscribedPairs  map[string]types.CurrencyPair // Symbol => types.CurrencyPair
+	}
+
+	// okxWSConn is a websocket connection of the provider, subscribing the
+	// pairs to its channels.
+	okxWSConn struct {
+		url            url.URL
+		client         *websocket.Conn
+		writeMtx       sync.Mutex
+		reconnectTimer *time.Ticker
+		channels       []string
 	}
 
 	// OkxInstId defines the id Symbol of an pair.
@@ -85,6 +101,20 @@ type (
 		ID   OkxID      `json:"arg"`
 	}
 
+	// OkxOrderBook defines the best levels of an Okx order book.
+	OkxOrderBook struct {
+		Asks      [][]string `json:"asks"` // Asks as [price, size, 0, orders] ex.: [["8446", "95", "0", "3"]]
+		Bids      [][]string `json:"bids"` // Bids as [price, size, 0, orders]
+		TimeStamp string     `json:"ts"`   // Linux epoch timestamp in milliseconds
+	}
+
+	// OkxOrderBookResponse defines the response structure of a Okx order book
+	// request.
+	OkxOrderBookResponse struct {
+		Data []OkxOrderBook `json:"data"`
+		ID   OkxID          `json:"arg"`
+	}
+
 	// OkxSubscriptionTopic Topic with the ticker to be subscribed/unsubscribed.
 	OkxSubscriptionTopic struct {
 		Channel string `json:"channel"` // Channel name ex.: tickers
@@ -117,44 +147,74 @@ func NewOkxProvider(
 			Websocket: okxWSHost,
 		}
 	}
+	// the business channels are served on the same host by default
+	if len(endpoints.BusinessWebsocket) == 0 {
+		endpoints.BusinessWebsocket = endpoints.Websocket
+	}
 
-	wsURL := url.URL{
-		Scheme: "wss",
-		Host:   endpoints.Websocket,
-		Path:   okxWSPath,
+	wsClient, err := newOkxWSConn(
+		url.URL{Scheme: "wss", Host: endpoints.Websocket, Path: okxWSPath},
+		"tickers",
+		"books5",
+	)
+	if err != nil {
+		return nil, err
 	}
 
-	wsConn, response, err := websocket.DefaultDialer.Dial(wsURL.String(), nil)
-	defer func() {
-		if response != nil {
-			response.Body.Close()
-		}
-	}()
+	businessWSClient, err := newOkxWSConn(
+		url.URL{Scheme: "wss", Host: endpoints.BusinessWebsocket, Path: okxBusinessWSPath},
+		"candle1m",
+	)
 	if err != nil {
-		return nil, fmt.Errorf("error connecting to Okx websocket: %w", err)
+		wsClient.client.Close()
+		return nil, err
 	}
 
 	provider := &OkxProvider{
-		wsURL:           wsURL,
-		wsClient:        wsConn,
-		logger:          logger.With().Str("provider", "okx").Logger(),
-		reconnectTimer:  time.NewTicker(okxPingCheck),
-		endpoints:       endpoints,
-		tickers:         map[string]OkxTickerPair{},
-		candles:         map[string][]OkxCandlePair{},
-		subscribedPairs: map[string]types.CurrencyPair{},
+		wsClient:         wsClient,
+		businessWSClient: businessWSClient,
+		logger:           logger.With().Str("provider", "okx").Logger(),
+		endpoints:        endpoints,
+		tickers:          map[string]OkxTickerPair{},
+		candles:          map[string][]OkxCandlePair{},
+		orderBooks:       map[string]OrderBook{},
+		subscribedPairs:  map[string]types.CurrencyPair{},
 	}
-	provider.wsClient.SetPongHandler(provider.pongHandler)
 
 	if err := provider.SubscribeCurrencyPairs(pairs...); err != nil {
 		return nil, err
 	}
 
-	go provider.handleReceivedTickers(ctx)
+	go provider.handleReceivedMessages(ctx, provider.wsClient)
+	go provider.handleReceivedMessages(ctx, provider.businessWSClient)
 
 	return provider, nil
 }
 
+// newOkxWSConn connects to a websocket of Okx, the pairs are subscribed to
+// the given channels.
+func newOkxWSConn(wsURL url.URL, channels ...string) (*okxWSConn, error) {
+	wsConn, response, err := websocket.DefaultDialer.Dial(wsURL.String(), nil)
+	defer func() {
+		if response != nil {
+			response.Body.Close()
+		}
+	}()
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to Okx websocket %s: %w", wsURL.Path, err)
+	}
+
+	conn := &okxWSConn{
+		url:            wsURL,
+		client:         wsConn,
+		reconnectTimer: time.NewTicker(okxPingCheck),
+		channels:       channels,
+	}
+	wsConn.SetPongHandler(conn.pongHandler)
+
+	return conn, nil
+}
+
 // GetTickerPrices returns the tickerPrices based on the saved map.
 func (p *OkxProvider) GetTickerPrices(pairs ...types.CurrencyPair) (map[string]TickerPrice, error) {
 	tickerPrices := make(map[string]TickerPrice, len(pairs))
@@ -179,8 +239,8 @@ func (p *OkxProvider) GetCandlePrices(pairs ...types.CurrencyPair) (map[string][
 	for _, currencyPair := range pairs {
 		candles, err := p.getCandlePrices(currencyPair)
 		if err != nil {
-			// CONTEXT: we are ok erroring here because we have disabled the candles subscriptions
-			return nil, err
+			p.logger.Debug().AnErr("err", err).Msg(fmt.Sprint("failed to fetch candles for pair ", currencyPair))
+			continue
 		}
 
 		candlePrices[currencyPair.String()] = candles
@@ -189,6 +249,21 @@ func (p *OkxProvider) GetCandlePrices(pairs ...types.CurrencyPair) (map[string][
 	return candlePrices, nil
 }
 
+// GetOrderBooks returns the order books of the provided pairs.
+func (p *OkxProvider) GetOrderBooks(pairs ...types.CurrencyPair) (map[string]OrderBook, error) {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	orderBooks := make(map[string]OrderBook, len(pairs))
+	for _, cp := range pairs {
+		if orderBook, ok := p.orderBooks[currencyPairToOkxPair(cp)]; ok {
+			orderBooks[cp.String()] = orderBook
+		}
+	}
+
+	return orderBooks, nil
+}
+
 // SubscribeCurrencyPairs subscribe all currency pairs into ticker and candle channels.
 func (p *OkxProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) error {
 	if len(cps) == 0 {
@@ -213,39 +288,13 @@ func (p *OkxProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) error {
 
 // subscribeChannels subscribe all currency pairs into ticker and candle channels.
 func (p *OkxProvider) subscribeChannels(cps ...types.CurrencyPair) error {
-	return p.subscribeTickers(cps...)
-
-	// CONTEXT: we want to no-op the candles subscription because its using a different path and the price feeding provides more instantaneous data using ticker pricing anyways
-	// if err := p.subscribeTickers(cps...); err != nil {
-	// 	return err
-	// }
-
-	// return p.subscribeCandles(cps...)
-}
-
-// subscribeTickers subscribe all currency pairs into ticker channel.
-func (p *OkxProvider) subscribeTickers(cps ...types.CurrencyPair) error {
-	topics := make([]OkxSubscriptionTopic, len(cps))
-
-	for i, cp := range cps {
-		topics[i] = newOkxTickerSubscriptionTopic(currencyPairToOkxPair(cp))
+	if err := p.wsClient.subscribe(cps...); err != nil {
+		return err
 	}
 
-	return p.subscribePairs(topics...)
+	return p.businessWSClient.subscribe(cps...)
 }
 
-// CONTEXT: commented out because okx candles are currently unused
-// // subscribeCandles subscribe all currency pairs into candle channel.
-// func (p *OkxProvider) subscribeCandles(cps ...types.CurrencyPair) error {
-// 	topics := make([]OkxSubscriptionTopic, len(cps))
-
-// 	for i, cp := range cps {
-// 		topics[i] = newOkxCandleSubscriptionTopic(currencyPairToOkxPair(cp))
-// 	}
-
-// 	return p.subscribePairs(topics...)
-// }
-
 // subscribedPairsToSlice returns the map of subscribed pairs as slice
 func (p *OkxProvider) subscribedPairsToSlice() []types.CurrencyPair {
 	p.mtx.RLock()
@@ -288,18 +337,20 @@ func (p *OkxProvider) getCandlePrices(cp types.CurrencyPair) ([]CandlePrice, err
 	return candleList, nil
 }
 
-func (p *OkxProvider) handleReceivedTickers(ctx context.Context) {
+// handleReceivedMessages reads the messages of one of the websockets until
+// the context is done.
+func (p *OkxProvider) handleReceivedMessages(ctx context.Context, conn *okxWSConn) {
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-time.After(defaultReadNewWSMessage):
-			messageType, bz, err := p.wsClient.ReadMessage()
+			messageType, bz, err := conn.client.ReadMessage()
 			if err != nil {
 				// if some error occurs continue to try to read the next message.
-				p.logger.Err(err).Msg("could not read message")
-				if err := p.ping(); err != nil {
-					p.logger.Err(err).Msg("could not send ping")
+				p.logger.Err(err).Str("path", conn.url.Path).Msg("could not read message")
+				if err := conn.ping(); err != nil {
+					p.logger.Err(err).Str("path", conn.url.Path).Msg("could not send ping")
 				}
 				continue
 			}
@@ -308,12 +359,12 @@ func (p *OkxProvider) handleReceivedTickers(ctx context.Context) {
 				continue
 			}
 
-			p.resetReconnectTimer()
+			conn.resetReconnectTimer()
 			p.messageReceived(messageType, bz)
 
-		case <-p.reconnectTimer.C: // reset by the pongHandler.
-			if err := p.reconnect(); err != nil {
-				p.logger.Err(err).Msg("error reconnecting")
+		case <-conn.reconnectTimer.C: // reset by the pongHandler.
+			if err := p.reconnect(conn); err != nil {
+				p.logger.Err(err).Str("path", conn.url.Path).Msg("error reconnecting")
 			}
 		}
 	}
@@ -325,10 +376,12 @@ func (p *OkxProvider) messageReceived(messageType int, bz []byte) {
 	}
 
 	var (
-		tickerResp OkxTickerResponse
-		tickerErr  error
-		candleResp OkxCandleResponse
-		candleErr  error
+		tickerResp    OkxTickerResponse
+		tickerErr     error
+		candleResp    OkxCandleResponse
+		candleErr     error
+		orderBookResp OkxOrderBookResponse
+		orderBookErr  error
 	)
 
 	// sometimes the message received is not a ticker or a candle response.
@@ -366,29 +419,62 @@ func (p *OkxProvider) messageReceived(messageType int, bz []byte) {
 		return
 	}
 
+	orderBookErr = json.Unmarshal(bz, &orderBookResp)
+	if orderBookResp.ID.Channel == "books5" {
+		for _, okxOrderBook := range orderBookResp.Data {
+			p.setOrderBook(okxOrderBook, orderBookResp.ID.InstID)
+			telemetry.IncrCounter(
+				1,
+				"websocket",
+				"message",
+				"type",
+				"depth",
+				"provider",
+				config.ProviderOkx,
+			)
+		}
+		return
+	}
+
 	p.logger.Error().
 		Int("length", len(bz)).
 		AnErr("ticker", tickerErr).
 		AnErr("candle", candleErr).
+		AnErr("depth", orderBookErr).
 		Msg("Error on receive message")
 }
 
-func (p *OkxProvider) setTickerPair(tickerPair OkxTickerPair) {
+func (p *OkxProvider) setOrderBook(okxOrderBook OkxOrderBook, instID string) {
+	ts, err := strconv.ParseInt(okxOrderBook.TimeStamp, 10, 64)
+	if err != nil {
+		ts = time.Now().UnixMilli()
+	}
+
+	orderBook, err := newOrderBook("Okx", instID, okxOrderBook.Bids, okxOrderBook.Asks, ts)
+	if err != nil {
+		p.logger.Err(err).Msg("could not parse order book message")
+		return
+	}
+
 	p.mtx.Lock()
 	defer p.mtx.Unlock()
-	p.tickers[tickerPair.InstID] = tickerPair
+	p.orderBooks[instID] = orderBook
 }
 
-// subscribePairs write the subscription msg to the provider.
-func (p *OkxProvider) subscribePairs(pairs ...OkxSubscriptionTopic) error {
-	subsMsg := newOkxSubscriptionMsg(pairs...)
-	return p.wsClient.WriteJSON(subsMsg)
+func (p *OkxProvider) setTickerPair(tickerPair OkxTickerPair) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.tickers[tickerPair.InstID] = tickerPair
 }
 
 func (p *OkxProvider) setCandlePair(pairData []string, instID string) {
 	p.mtx.Lock()
 	defer p.mtx.Unlock()
 
+	// [ts, o, h, l, c, vol, volCcy, volCcyQuote, confirm]
+	if len(pairData) < 6 {
+		return
+	}
 	ts, err := strconv.ParseInt(pairData[0], 10, 64)
 	if err != nil {
 		return
@@ -405,7 +491,8 @@ func (p *OkxProvider) setCandlePair(pairData []string, instID string) {
 
 	candleList = append(candleList, candle)
 	for _, c := range p.candles[instID] {
-		if staleTime < c.TimeStamp {
+		// the candle of the current minute is pushed on every update
+		if staleTime < c.TimeStamp && c.TimeStamp != ts {
 			candleList = append(candleList, c)
 		}
 	}
@@ -422,10 +509,6 @@ func (p *OkxProvider) setSubscribedPairs(cps ...types.CurrencyPair) {
 	}
 }
 
-func (p *OkxProvider) resetReconnectTimer() {
-	p.reconnectTimer.Reset(okxPingCheck)
-}
-
 // reconnect closes the last WS connection and creates a new one. If there’s a
 // network problem, the system will automatically disable the connection. The
 // connection will break automatically if the subscription is not established or
@@ -436,21 +519,24 @@ func (p *OkxProvider) resetReconnectTimer() {
 // N seconds, send the String 'ping'.
 // 3. Expect a 'pong' as a response. If the response message is not received within
 // N seconds, please raise an error or reconnect.
-func (p *OkxProvider) reconnect() error {
-	p.wsClient.Close()
+func (p *OkxProvider) reconnect(conn *okxWSConn) error {
+	conn.client.Close()
 
-	p.logger.Debug().Msg("reconnecting websocket")
-	wsConn, response, err := websocket.DefaultDialer.Dial(p.wsURL.String(), nil)
+	p.logger.Debug().Str("path", conn.url.Path).Msg("reconnecting websocket")
+	wsConn, response, err := websocket.DefaultDialer.Dial(conn.url.String(), nil)
 	defer func() {
 		if response != nil {
 			response.Body.Close()
 		}
 	}()
 	if err != nil {
-		return fmt.Errorf("error reconnecting to Okx websocket: %w", err)
+		return fmt.Errorf("error reconnecting to Okx websocket %s: %w", conn.url.Path, err)
 	}
-	wsConn.SetPongHandler(p.pongHandler)
-	p.wsClient = wsConn
+	wsConn.SetPongHandler(conn.pongHandler)
+
+	conn.writeMtx.Lock()
+	conn.client = wsConn
+	conn.writeMtx.Unlock()
 
 	currencyPairs := p.subscribedPairsToSlice()
 
@@ -461,32 +547,48 @@ func (p *OkxProvider) reconnect() error {
 		"provider",
 		config.ProviderOkx,
 	)
-	return p.subscribeChannels(currencyPairs...)
+	return conn.subscribe(currencyPairs...)
+}
+
+// subscribe writes the subscription msg of the pairs to the channels of the
+// connection.
+func (c *okxWSConn) subscribe(cps ...types.CurrencyPair) error {
+	topics := make([]OkxSubscriptionTopic, 0, len(cps)*len(c.channels))
+	for _, channel := range c.channels {
+		for _, cp := range cps {
+			topics = append(topics, newOkxSubscriptionTopic(channel, currencyPairToOkxPair(cp)))
+		}
+	}
+
+	c.writeMtx.Lock()
+	defer c.writeMtx.Unlock()
+
+	return c.client.WriteJSON(newOkxSubscriptionMsg(topics...))
 }
 
 // ping to check websocket connection.
-func (p *OkxProvider) ping() error {
-	return p.wsClient.WriteMessage(websocket.PingMessage, ping)
+func (c *okxWSConn) ping() error {
+	c.writeMtx.Lock()
+	defer c.writeMtx.Unlock()
+
+	return c.client.WriteMessage(websocket.PingMessage, ping)
 }
 
-func (p *OkxProvider) pongHandler(_ string) error {
-	p.resetReconnectTimer()
+func (c *okxWSConn) resetReconnectTimer() {
+	c.reconnectTimer.Reset(okxPingCheck)
+}
+
+func (c *okxWSConn) pongHandler(_ string) error {
+	c.resetReconnectTimer()
 	return nil
 }
 
 // GetAvailablePairs return all available pairs symbol to susbscribe.
 func (p *OkxProvider) GetAvailablePairs() (map[string]struct{}, error) {
-	resp, err := http.Get(p.endpoints.Rest + okxRestPath)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
 	var pairsSummary struct {
 		Data []OkxInstID `json:"data"`
 	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&pairsSummary); err != nil {
+	if err := getAvailablePairsJSON(p.endpoints.Rest+okxRestPath, &pairsSummary); err != nil {
 		return nil, err
 	}
 
@@ -521,23 +623,14 @@ func currencyPairToOkxPair(pair types.CurrencyPair) string {
 	return pair.Base + "-" + pair.Quote
 }
 
-// newOkxTickerSubscriptionTopic returns a new subscription topic.
-func newOkxTickerSubscriptionTopic(instID string) OkxSubscriptionTopic {
+// newOkxSubscriptionTopic returns a new subscription topic of a channel.
+func newOkxSubscriptionTopic(channel, instID string) OkxSubscriptionTopic {
 	return OkxSubscriptionTopic{
-		Channel: "tickers",
+		Channel: channel,
 		InstID:  instID,
 	}
 }
 
-// CONTEXT: commented out because okx candles are unused
-// // newOkxSubscriptionTopic returns a new subscription topic.
-// func newOkxCandleSubscriptionTopic(instID string) OkxSubscriptionTopic {
-// 	return OkxSubscriptionTopic{
-// 		Channel: "candle1m",
-// 		InstID:  instID,
-// 	}
-// }
-
 // newOkxSubscriptionMsg returns a new subscription Msg for Okx.
 func newOkxSubscriptionMsg(args ...OkxSubscriptionTopic) OkxSubscriptionMsg {
 	return OkxSubscriptionMsg{
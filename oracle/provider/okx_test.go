@@ -2,8 +2,12 @@ package provider
 
 import (
 	"context"
+	"net/http"
+	"strconv"
 	"testing"
+	"time"
 
+	"github.com/gorilla/websocket"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
 
@@ -101,8 +105,134 @@ func TestOkxProvider_SubscribeCurrencyPairs(t *testing.T) {
 	})
 }
 
+func TestOkxProvider_BusinessWebsocket(t *testing.T) {
+	server := NewMockProviderServer()
+	defer server.Close()
+
+	// the public websocket streams the tickers and the order books, and the
+	// business one the candles
+	server.SetHandler(func(w http.ResponseWriter, r *http.Request) {
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer c.Close()
+
+		var subsMsg OkxSubscriptionMsg
+		if err := c.ReadJSON(&subsMsg); err != nil {
+			return
+		}
+
+		var msgs []string
+		switch {
+		case r.URL.Path == okxWSPath && len(subsMsg.Args) == 2 &&
+			subsMsg.Args[0].Channel == "tickers" && subsMsg.Args[1].Channel == "books5":
+			msgs = []string{
+				`{"arg":{"channel":"tickers","instId":"ATOM-USDT"},"data":[{"instId":"ATOM-USDT","last":"9.5","vol24h":"1000"}]}`,
+				`{"arg":{"channel":"books5","instId":"ATOM-USDT"},"data":[{"asks":[["9.51","10","0","1"]],"bids":[["9.49","10","0","1"]],"ts":"1597026383085"}]}`,
+			}
+		case r.URL.Path == okxBusinessWSPath && len(subsMsg.Args) == 1 && subsMsg.Args[0].Channel == "candle1m":
+			ts := time.Now().Truncate(time.Minute).UnixMilli()
+			msgs = []string{`{"arg":{"channel":"candle1m","instId":"ATOM-USDT"},"data":[["` +
+				strconv.FormatInt(ts, 10) + `","9.4","9.6","9.3","9.5","12","114","114","0"]]}`}
+		default:
+			return
+		}
+		for _, msg := range msgs {
+			if err := c.WriteMessage(websocket.TextMessage, []byte(msg)); err != nil {
+				return
+			}
+		}
+
+		// keep the connection open until the provider closes it
+		for {
+			if _, _, err := c.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	atomUSDT := types.CurrencyPair{Base: "ATOM", Quote: "USDT"}
+	p, err := NewOkxProvider(
+		ctx,
+		zerolog.Nop(),
+		config.ProviderEndpoint{
+			Name:              config.ProviderOkx,
+			Websocket:         server.GetBaseURL(),
+			BusinessWebsocket: server.GetBaseURL(),
+		},
+		atomUSDT,
+	)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		candles, err := p.GetCandlePrices(atomUSDT)
+		return err == nil && len(candles["ATOMUSDT"]) == 1
+	}, 5*time.Second, 20*time.Millisecond)
+
+	candles, err := p.GetCandlePrices(atomUSDT)
+	require.NoError(t, err)
+	require.Equal(t, math.LegacyMustNewDecFromStr("9.5"), candles["ATOMUSDT"][0].Price)
+	require.Equal(t, math.LegacyMustNewDecFromStr("12"), candles["ATOMUSDT"][0].Volume)
+
+	require.Eventually(t, func() bool {
+		prices, err := p.GetTickerPrices(atomUSDT)
+		return err == nil && len(prices) == 1
+	}, 5*time.Second, 20*time.Millisecond)
+
+	require.Eventually(t, func() bool {
+		orderBooks, err := p.GetOrderBooks(atomUSDT)
+		return err == nil && len(orderBooks) == 1
+	}, 5*time.Second, 20*time.Millisecond)
+}
+
+func TestOkxProvider_SetCandlePair(t *testing.T) {
+	p := &OkxProvider{candles: map[string][]OkxCandlePair{}}
+
+	ts := time.Now().Truncate(time.Minute).UnixMilli()
+	tsStr := strconv.FormatInt(ts, 10)
+	prevTsStr := strconv.FormatInt(ts-time.Minute.Milliseconds(), 10)
+
+	p.setCandlePair([]string{prevTsStr, "9.4", "9.6", "9.3", "9.4", "10"}, "ATOM-USDT")
+	p.setCandlePair([]string{tsStr, "9.4", "9.6", "9.3", "9.5", "5"}, "ATOM-USDT")
+
+	// the candle of the current minute is replaced by its updates
+	p.setCandlePair([]string{tsStr, "9.4", "9.6", "9.3", "9.6", "7"}, "ATOM-USDT")
+	require.Len(t, p.candles["ATOM-USDT"], 2)
+	require.Equal(t, "9.6", p.candles["ATOM-USDT"][0].Close)
+	require.Equal(t, "7", p.candles["ATOM-USDT"][0].Volume)
+
+	// the incomplete candles are ignored
+	p.setCandlePair([]string{tsStr, "9.4"}, "ATOM-USDT")
+	require.Len(t, p.candles["ATOM-USDT"], 2)
+}
+
 func TestOkxCurrencyPairToOkxPair(t *testing.T) {
 	cp := types.CurrencyPair{Base: "ATOM", Quote: "USDT"}
 	okxSymbol := currencyPairToOkxPair(cp)
 	require.Equal(t, okxSymbol, "ATOM-USDT")
 }
+
+func TestOkxProvider_MessageReceivedOrderBook(t *testing.T) {
+	p := &OkxProvider{logger: zerolog.Nop(), orderBooks: map[string]OrderBook{}}
+
+	p.messageReceived(websocket.TextMessage, []byte(`{
+		"arg": {"channel": "books5", "instId": "ATOM-USDT"},
+		"data": [{
+			"asks": [["10.02", "20", "0", "2"], ["10.01", "8", "0", "1"]],
+			"bids": [["9.99", "10", "0", "3"], ["9.98", "12", "0", "1"]],
+			"instId": "ATOM-USDT",
+			"ts": "1597026383085"
+		}]
+	}`))
+
+	orderBooks, err := p.GetOrderBooks(types.CurrencyPair{Base: "ATOM", Quote: "USDT"})
+	require.NoError(t, err)
+	require.Len(t, orderBooks, 1)
+	require.Equal(t, math.LegacyMustNewDecFromStr("9.99"), orderBooks["ATOMUSDT"].BestBid())
+	require.Equal(t, math.LegacyMustNewDecFromStr("10.01"), orderBooks["ATOMUSDT"].BestAsk())
+	require.Equal(t, int64(1597026383085), orderBooks["ATOMUSDT"].TimeStamp)
+}
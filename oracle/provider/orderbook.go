@@ -0,0 +1,193 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+
+	"cosmossdk.io/math"
+
+	"github.com/kiichain/price-feeder/oracle/types"
+)
+
+// OrderBookProvider defines the optional interface of the providers streaming
+// the order books of their pairs.
+type OrderBookProvider interface {
+	// GetOrderBooks returns the order books of the provided pairs.
+	GetOrderBooks(...types.CurrencyPair) (map[string]OrderBook, error)
+}
+
+// OrderBookLevel defines the size of a price level of an order book.
+type OrderBookLevel struct {
+	Price math.LegacyDec // level price
+	Size  math.LegacyDec // base size
+}
+
+// OrderBook defines the best levels of an order book, the bids sorted from
+// the highest price and the asks from the lowest.
+type OrderBook struct {
+	Bids      []OrderBookLevel
+	Asks      []OrderBookLevel
+	TimeStamp int64 // timestamp in milliseconds
+}
+
+// AggregatedProviderOrderBooks defines a type alias for a map
+// of provider -> asset -> OrderBook
+type AggregatedProviderOrderBooks map[string]map[string]OrderBook
+
+// BestBid returns the highest bid price.
+func (ob OrderBook) BestBid() math.LegacyDec {
+	return ob.Bids[0].Price
+}
+
+// BestAsk returns the lowest ask price.
+func (ob OrderBook) BestAsk() math.LegacyDec {
+	return ob.Asks[0].Price
+}
+
+// MidPrice returns the price between the best bid and ask.
+func (ob OrderBook) MidPrice() math.LegacyDec {
+	return ob.BestBid().Add(ob.BestAsk()).QuoInt64(2)
+}
+
+// Spread returns the difference between the best ask and bid relative to the
+// mid price, ex. 0.001 for 0.1%.
+func (ob OrderBook) Spread() math.LegacyDec {
+	return ob.BestAsk().Sub(ob.BestBid()).Quo(ob.MidPrice())
+}
+
+// Depth returns the cumulative base size of the bids and the asks within the
+// band around the mid price, ex. 0.02 for ±2%.
+func (ob OrderBook) Depth(band math.LegacyDec) (bidDepth, askDepth math.LegacyDec) {
+	mid := ob.MidPrice()
+	minPrice := mid.Mul(math.LegacyOneDec().Sub(band))
+	maxPrice := mid.Mul(math.LegacyOneDec().Add(band))
+
+	bidDepth = math.LegacyZeroDec()
+	for _, level := range ob.Bids {
+		if level.Price.LT(minPrice) {
+			break
+		}
+		bidDepth = bidDepth.Add(level.Size)
+	}
+
+	askDepth = math.LegacyZeroDec()
+	for _, level := range ob.Asks {
+		if level.Price.GT(maxPrice) {
+			break
+		}
+		askDepth = askDepth.Add(level.Size)
+	}
+
+	return bidDepth, askDepth
+}
+
+// DepthWeightedMid returns the best bid and ask weighted by the depth of the
+// opposite side within the band, so the price leans toward the side with the
+// thinner depth. It returns the mid price when there is no depth in the band.
+func (ob OrderBook) DepthWeightedMid(band math.LegacyDec) math.LegacyDec {
+	bidDepth, askDepth := ob.Depth(band)
+	totalDepth := bidDepth.Add(askDepth)
+	if !bidDepth.IsPositive() || !askDepth.IsPositive() {
+		return ob.MidPrice()
+	}
+
+	return ob.BestBid().Mul(askDepth).Add(ob.BestAsk().Mul(bidDepth)).Quo(totalDepth)
+}
+
+// Validate returns an error if a side of the order book is empty or if the
+// best bid crosses the best ask.
+func (ob OrderBook) Validate() error {
+	if len(ob.Bids) == 0 || len(ob.Asks) == 0 {
+		return fmt.Errorf("order book has an empty side")
+	}
+	if !ob.BestBid().IsPositive() || ob.BestBid().GTE(ob.BestAsk()) {
+		return fmt.Errorf("order book is crossed, bid %s and ask %s", ob.BestBid(), ob.BestAsk())
+	}
+
+	return nil
+}
+
+// newOrderBook parses the [price, size, ...] levels of each side of an order
+// book, dropping the empty levels and sorting both sides from the best price.
+func newOrderBook(provider, symbol string, bids, asks [][]string, timeStamp int64) (OrderBook, error) {
+	bidLevels, err := newOrderBookLevels(provider, symbol, bids)
+	if err != nil {
+		return OrderBook{}, err
+	}
+	askLevels, err := newOrderBookLevels(provider, symbol, asks)
+	if err != nil {
+		return OrderBook{}, err
+	}
+
+	return OrderBook{
+		Bids:      updateOrderBookLevels(nil, bidLevels, true, len(bidLevels)),
+		Asks:      updateOrderBookLevels(nil, askLevels, false, len(askLevels)),
+		TimeStamp: timeStamp,
+	}, nil
+}
+
+// newOrderBookLevels parses the [price, size, ...] levels of a side of an
+// order book, keeping the levels with a size of zero.
+func newOrderBookLevels(provider, symbol string, levels [][]string) ([]OrderBookLevel, error) {
+	orderBookLevels := make([]OrderBookLevel, 0, len(levels))
+	for _, level := range levels {
+		if len(level) < 2 {
+			return nil, fmt.Errorf("failed to parse %s order book level (%v) for %s", provider, level, symbol)
+		}
+
+		price, err := math.LegacyNewDecFromStr(level[0])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s order book price (%s) for %s", provider, level[0], symbol)
+		}
+		size, err := math.LegacyNewDecFromStr(level[1])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s order book size (%s) for %s", provider, level[1], symbol)
+		}
+
+		orderBookLevels = append(orderBookLevels, OrderBookLevel{Price: price, Size: size})
+	}
+
+	return orderBookLevels, nil
+}
+
+// sortOrderBookLevels sorts the levels from the highest price for the bids,
+// or from the lowest price for the asks.
+func sortOrderBookLevels(levels []OrderBookLevel, bids bool) {
+	sort.SliceStable(levels, func(i, j int) bool {
+		if bids {
+			return levels[i].Price.GT(levels[j].Price)
+		}
+		return levels[i].Price.LT(levels[j].Price)
+	})
+}
+
+// updateOrderBookLevels applies the updated levels to a side of an order
+// book, removing the levels with a size of zero and keeping the best depth
+// levels.
+func updateOrderBookLevels(levels, updates []OrderBookLevel, bids bool, depth int) []OrderBookLevel {
+	updatedLevels := make([]OrderBookLevel, 0, len(levels)+len(updates))
+	for _, level := range levels {
+		updated := false
+		for _, update := range updates {
+			if update.Price.Equal(level.Price) {
+				updated = true
+				break
+			}
+		}
+		if !updated {
+			updatedLevels = append(updatedLevels, level)
+		}
+	}
+	for _, update := range updates {
+		if update.Size.IsPositive() {
+			updatedLevels = append(updatedLevels, update)
+		}
+	}
+
+	sortOrderBookLevels(updatedLevels, bids)
+	if len(updatedLevels) > depth {
+		updatedLevels = updatedLevels[:depth]
+	}
+
+	return updatedLevels
+}
@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/math"
+)
+
+func TestOrderBook(t *testing.T) {
+	orderBook, err := newOrderBook(
+		"Test",
+		"ATOMUSDT",
+		[][]string{{"9.9", "30"}, {"10", "10"}, {"9.7", "100"}, {"9.95", "0"}},
+		[][]string{{"10.2", "5"}, {"10.1", "10"}, {"10.5", "100"}},
+		1000,
+	)
+	require.NoError(t, err)
+	require.NoError(t, orderBook.Validate())
+
+	// the levels are sorted from the best price, without the empty ones
+	require.Len(t, orderBook.Bids, 3)
+	require.Equal(t, math.LegacyMustNewDecFromStr("10"), orderBook.BestBid())
+	require.Equal(t, math.LegacyMustNewDecFromStr("10.1"), orderBook.BestAsk())
+	require.Equal(t, math.LegacyMustNewDecFromStr("10.05"), orderBook.MidPrice())
+	require.InDelta(t, 0.00995, orderBook.Spread().MustFloat64(), 0.00001)
+
+	// the band of ±2% is between 9.849 and 10.251
+	bidDepth, askDepth := orderBook.Depth(math.LegacyMustNewDecFromStr("0.02"))
+	require.Equal(t, math.LegacyNewDec(40), bidDepth)
+	require.Equal(t, math.LegacyNewDec(15), askDepth)
+
+	// the price leans toward the ask, the thinner side
+	require.Equal(
+		t,
+		math.LegacyNewDec(10*15+10.1*40).QuoInt64(55).String(),
+		orderBook.DepthWeightedMid(math.LegacyMustNewDecFromStr("0.02")).String(),
+	)
+	require.Equal(t, orderBook.MidPrice(), orderBook.DepthWeightedMid(math.LegacyMustNewDecFromStr("0.001")))
+
+	crossed := OrderBook{
+		Bids: []OrderBookLevel{{Price: math.LegacyNewDec(11), Size: math.LegacyOneDec()}},
+		Asks: orderBook.Asks,
+	}
+	require.EqualError(t, crossed.Validate(), "order book is crossed, bid 11.000000000000000000 and ask 10.100000000000000000")
+	require.EqualError(t, OrderBook{Asks: orderBook.Asks}.Validate(), "order book has an empty side")
+
+	_, err = newOrderBook("Test", "ATOMUSDT", [][]string{{"10"}}, nil, 1000)
+	require.EqualError(t, err, "failed to parse Test order book level ([10]) for ATOMUSDT")
+}
+
+func TestUpdateOrderBookLevels(t *testing.T) {
+	levels := []OrderBookLevel{
+		{Price: math.LegacyNewDec(10), Size: math.LegacyNewDec(1)},
+		{Price: math.LegacyNewDec(9), Size: math.LegacyNewDec(2)},
+		{Price: math.LegacyNewDec(8), Size: math.LegacyNewDec(3)},
+	}
+	updates := []OrderBookLevel{
+		{Price: math.LegacyNewDec(10), Size: math.LegacyZeroDec()},
+		{Price: math.LegacyNewDec(9), Size: math.LegacyNewDec(5)},
+		{Price: math.LegacyMustNewDecFromStr("9.5"), Size: math.LegacyNewDec(4)},
+	}
+
+	updatedLevels := updateOrderBookLevels(levels, updates, true, 2)
+	require.Equal(t, []OrderBookLevel{
+		{Price: math.LegacyMustNewDecFromStr("9.5"), Size: math.LegacyNewDec(4)},
+		{Price: math.LegacyNewDec(9), Size: math.LegacyNewDec(5)},
+	}, updatedLevels)
+}
@@ -0,0 +1,309 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"cosmossdk.io/math"
+
+	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle/types"
+)
+
+const (
+	osmosisGRPCHost       = "grpc.osmosis.zone:9090"
+	osmosisSampleInterval = 30 * time.Second
+	osmosisQueryTimeout   = 10 * time.Second
+)
+
+var _ Provider = (*OsmosisProvider)(nil)
+
+type (
+	// OsmosisProvider defines an Oracle provider implemented by the Osmosis
+	// DEX, querying the pools of the config over gRPC.
+	//
+	// The spot price and the swapped volume of each pool are sampled
+	// periodically. Each sample is a candle with the volume swapped since the
+	// previous sample, and the ticker volume is the volume of the candles.
+	OsmosisProvider struct {
+		logger          zerolog.Logger
+		mtx             sync.RWMutex
+		endpoint        config.ProviderEndpoint
+		conn            *grpc.ClientConn
+		sampleInterval  time.Duration
+		pools           map[string]config.DexPool     // CurrencyPair => DexPool
+		tickers         map[string]TickerPrice        // CurrencyPair => TickerPrice
+		candles         map[string][]CandlePrice      // CurrencyPair => []CandlePrice
+		totalVolumes    map[string]math.LegacyDec     // CurrencyPair => base volume swapped on the pool
+		subscribedPairs map[string]types.CurrencyPair // Symbol => types.CurrencyPair
+	}
+)
+
+// NewOsmosisProvider returns a new Osmosis provider sampling the pools of the
+// pairs, dialing the gRPC endpoint with the dialer.
+func NewOsmosisProvider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	endpoint config.ProviderEndpoint,
+	pools []config.DexPool,
+	dialer func(context.Context, string) (net.Conn, error),
+	pairs ...types.CurrencyPair,
+) (*OsmosisProvider, error) {
+	if endpoint.Name != config.ProviderOsmosis {
+		endpoint = config.ProviderEndpoint{
+			Name: config.ProviderOsmosis,
+			GRPC: osmosisGRPCHost,
+		}
+	}
+
+	conn, err := grpc.NewClient(
+		endpoint.GRPC,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(dialer),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(osmosisCodec{})),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial Osmosis gRPC service: %w", err)
+	}
+
+	provider := &OsmosisProvider{
+		logger:          logger.With().Str("provider", config.ProviderOsmosis).Logger(),
+		endpoint:        endpoint,
+		conn:            conn,
+		sampleInterval:  osmosisSampleInterval,
+		pools:           map[string]config.DexPool{},
+		tickers:         map[string]TickerPrice{},
+		candles:         map[string][]CandlePrice{},
+		totalVolumes:    map[string]math.LegacyDec{},
+		subscribedPairs: map[string]types.CurrencyPair{},
+	}
+
+	for _, pool := range pools {
+		if pool.Provider == config.ProviderOsmosis {
+			provider.pools[pool.Base+pool.Quote] = pool
+		}
+	}
+
+	if err := provider.SubscribeCurrencyPairs(pairs...); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	go provider.poll(ctx)
+
+	return provider, nil
+}
+
+// poll samples the pools periodically, closing the connection once the
+// context is done.
+func (p *OsmosisProvider) poll(ctx context.Context) {
+	sampleTicker := time.NewTicker(p.sampleInterval)
+	defer sampleTicker.Stop()
+	defer p.conn.Close()
+
+	for {
+		p.samplePools(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-sampleTicker.C:
+		}
+	}
+}
+
+// samplePools samples the pool of each subscribed pair.
+func (p *OsmosisProvider) samplePools(ctx context.Context) {
+	p.mtx.RLock()
+	pairs := types.MapPairsToSlice(p.subscribedPairs)
+	p.mtx.RUnlock()
+
+	for _, cp := range pairs {
+		if err := p.samplePool(ctx, cp); err != nil {
+			p.logger.Warn().Err(err).Msg(fmt.Sprint("failed to sample pool for pair ", cp))
+		}
+	}
+}
+
+// samplePool queries the spot price and swapped volume of the pool of the
+// pair, saving them as a new candle.
+func (p *OsmosisProvider) samplePool(ctx context.Context, cp types.CurrencyPair) error {
+	pool := p.pools[cp.String()]
+
+	ctx, cancel := context.WithTimeout(ctx, osmosisQueryTimeout)
+	defer cancel()
+
+	var spotPriceResp OsmosisSpotPriceResponse
+	err := p.conn.Invoke(ctx, osmosisSpotPriceMethod, &OsmosisSpotPriceRequest{
+		PoolID:          pool.PoolID,
+		BaseAssetDenom:  pool.BaseDenom,
+		QuoteAssetDenom: pool.QuoteDenom,
+	}, &spotPriceResp)
+	if err != nil {
+		return fmt.Errorf("failed to query spot price of pool %d: %w", pool.PoolID, err)
+	}
+
+	price, err := osmosisSpotPrice(spotPriceResp.SpotPrice, pool)
+	if err != nil {
+		return err
+	}
+
+	// the volume is left out of the candle when it can't be queried
+	var totalVolumeResp OsmosisTotalVolumeResponse
+	err = p.conn.Invoke(ctx, osmosisTotalVolumeMethod, &OsmosisTotalVolumeRequest{PoolID: pool.PoolID}, &totalVolumeResp)
+	totalVolume, volumeErr := osmosisPoolVolume(totalVolumeResp.Volume, pool)
+	if err == nil {
+		err = volumeErr
+	}
+	if err != nil {
+		p.logger.Warn().Err(err).Uint64("pool_id", pool.PoolID).Msg("failed to query pool volume")
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	key := cp.String()
+	volume := math.LegacyZeroDec()
+	if err == nil {
+		if lastVolume, ok := p.totalVolumes[key]; ok && totalVolume.GT(lastVolume) {
+			volume = totalVolume.Sub(lastVolume)
+		}
+		p.totalVolumes[key] = totalVolume
+	}
+
+	staleTime := PastUnixTime(providerCandlePeriod)
+	candleList := []CandlePrice{}
+	tickerVolume := volume
+	for _, candle := range p.candles[key] {
+		if staleTime < candle.TimeStamp {
+			candleList = append(candleList, candle)
+			tickerVolume = tickerVolume.Add(candle.Volume)
+		}
+	}
+	p.candles[key] = append(candleList, CandlePrice{
+		Price:     price,
+		Volume:    volume,
+		TimeStamp: time.Now().UnixMilli(),
+	})
+	p.tickers[key] = TickerPrice{
+		Price:  price,
+		Volume: tickerVolume,
+	}
+
+	return nil
+}
+
+// SubscribeCurrencyPairs adds the pairs to the sampled pairs, every pair must
+// have a pool.
+func (p *OsmosisProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	for _, cp := range cps {
+		if _, ok := p.pools[cp.String()]; !ok {
+			return fmt.Errorf("no osmosis pool for %s", cp)
+		}
+	}
+
+	p.setSubscribedPairs(cps...)
+	return nil
+}
+
+// GetTickerPrices returns the tickerPrices based on the saved map.
+func (p *OsmosisProvider) GetTickerPrices(pairs ...types.CurrencyPair) (map[string]TickerPrice, error) {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	tickerPrices := make(map[string]TickerPrice, len(pairs))
+	for _, cp := range pairs {
+		if ticker, ok := p.tickers[cp.String()]; ok {
+			tickerPrices[cp.String()] = ticker
+		}
+	}
+
+	return tickerPrices, nil
+}
+
+// GetCandlePrices returns the candlePrices based on the saved map.
+func (p *OsmosisProvider) GetCandlePrices(pairs ...types.CurrencyPair) (map[string][]CandlePrice, error) {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	candlePrices := make(map[string][]CandlePrice, len(pairs))
+	for _, cp := range pairs {
+		if candles, ok := p.candles[cp.String()]; ok {
+			candleList := []CandlePrice{}
+			candleList = append(candleList, candles...)
+			candlePrices[cp.String()] = candleList
+		}
+	}
+
+	return candlePrices, nil
+}
+
+// setSubscribedPairs sets N currency pairs to the map of subscribed pairs.
+func (p *OsmosisProvider) setSubscribedPairs(cps ...types.CurrencyPair) {
+	for _, cp := range cps {
+		p.subscribedPairs[cp.String()] = cp
+	}
+}
+
+// GetAvailablePairs returns the pairs with a pool in the config.
+// ex.: map["OSMOUSDC" => {}, "ATOMUSDC" => {}].
+func (p *OsmosisProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	availablePairs := make(map[string]struct{}, len(p.pools))
+	for key := range p.pools {
+		availablePairs[key] = struct{}{}
+	}
+
+	return availablePairs, nil
+}
+
+// osmosisSpotPrice returns the price of the base in quote from the spot price
+// of the pool, which is in the smallest units of the denoms.
+func osmosisSpotPrice(spotPrice string, pool config.DexPool) (math.LegacyDec, error) {
+	// the spot price may have more decimals than a LegacyDec
+	if i := strings.IndexByte(spotPrice, '.'); i >= 0 && len(spotPrice)-i-1 > math.LegacyPrecision {
+		spotPrice = spotPrice[:i+1+math.LegacyPrecision]
+	}
+
+	price, err := math.LegacyNewDecFromStr(spotPrice)
+	if err != nil {
+		return math.LegacyDec{}, fmt.Errorf("invalid spot price of pool %d: %w", pool.PoolID, err)
+	}
+	if !price.IsPositive() {
+		return math.LegacyDec{}, fmt.Errorf("invalid spot price of pool %d: %s", pool.PoolID, spotPrice)
+	}
+
+	if pool.BaseExponent >= pool.QuoteExponent {
+		return price.Mul(math.LegacyNewDec(10).Power(pool.BaseExponent - pool.QuoteExponent)), nil
+	}
+	return price.Quo(math.LegacyNewDec(10).Power(pool.QuoteExponent - pool.BaseExponent)), nil
+}
+
+// osmosisPoolVolume returns the base volume swapped on the pool.
+func osmosisPoolVolume(volume []OsmosisCoin, pool config.DexPool) (math.LegacyDec, error) {
+	for _, coin := range volume {
+		if coin.Denom != pool.BaseDenom {
+			continue
+		}
+
+		amount, err := math.LegacyNewDecFromStr(coin.Amount)
+		if err != nil {
+			return math.LegacyDec{}, fmt.Errorf("invalid volume of pool %d: %w", pool.PoolID, err)
+		}
+		return amount.Quo(math.LegacyNewDec(10).Power(pool.BaseExponent)), nil
+	}
+
+	// the pool has no swap of the base yet
+	return math.LegacyZeroDec(), nil
+}
@@ -0,0 +1,230 @@
+package provider
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The osmosis poolmanager queries are encoded by hand, as only the few
+// fields read by the provider are needed.
+const (
+	osmosisSpotPriceMethod   = "/osmosis.poolmanager.v1beta1.Query/SpotPrice"
+	osmosisTotalVolumeMethod = "/osmosis.poolmanager.v1beta1.Query/TotalVolumeForPool"
+)
+
+type (
+	// osmosisMessage is a protobuf message of the osmosis queries
+	osmosisMessage interface {
+		Marshal() ([]byte, error)
+		Unmarshal([]byte) error
+	}
+
+	// osmosisCodec encodes the osmosis messages as protobuf
+	osmosisCodec struct{}
+
+	// OsmosisSpotPriceRequest is the SpotPriceRequest of the poolmanager
+	OsmosisSpotPriceRequest struct {
+		PoolID          uint64 // 1
+		BaseAssetDenom  string // 2
+		QuoteAssetDenom string // 3
+	}
+
+	// OsmosisSpotPriceResponse is the SpotPriceResponse of the poolmanager,
+	// the amount of quote asset for one base asset
+	OsmosisSpotPriceResponse struct {
+		SpotPrice string // 1
+	}
+
+	// OsmosisTotalVolumeRequest is the TotalVolumeForPoolRequest of the
+	// poolmanager
+	OsmosisTotalVolumeRequest struct {
+		PoolID uint64 // 1
+	}
+
+	// OsmosisTotalVolumeResponse is the TotalVolumeForPoolResponse of the
+	// poolmanager, the volume swapped since the pool creation by denom
+	OsmosisTotalVolumeResponse struct {
+		Volume []OsmosisCoin // 1
+	}
+
+	// OsmosisCoin is a cosmos coin
+	OsmosisCoin struct {
+		Denom  string // 1
+		Amount string // 2
+	}
+)
+
+func (osmosisCodec) Marshal(v interface{}) ([]byte, error) {
+	msg, ok := v.(osmosisMessage)
+	if !ok {
+		return nil, fmt.Errorf("unexpected osmosis message %T", v)
+	}
+	return msg.Marshal()
+}
+
+func (osmosisCodec) Unmarshal(data []byte, v interface{}) error {
+	msg, ok := v.(osmosisMessage)
+	if !ok {
+		return fmt.Errorf("unexpected osmosis message %T", v)
+	}
+	return msg.Unmarshal(data)
+}
+
+func (osmosisCodec) Name() string {
+	return "proto"
+}
+
+func (m *OsmosisSpotPriceRequest) Marshal() ([]byte, error) {
+	var bz []byte
+	bz = appendOsmosisVarint(bz, 1, m.PoolID)
+	bz = appendOsmosisString(bz, 2, m.BaseAssetDenom)
+	bz = appendOsmosisString(bz, 3, m.QuoteAssetDenom)
+	return bz, nil
+}
+
+func (m *OsmosisSpotPriceRequest) Unmarshal(bz []byte) error {
+	return consumeOsmosisFields(bz, func(num protowire.Number, typ protowire.Type, value []byte, varint uint64) {
+		switch {
+		case num == 1 && typ == protowire.VarintType:
+			m.PoolID = varint
+		case num == 2 && typ == protowire.BytesType:
+			m.BaseAssetDenom = string(value)
+		case num == 3 && typ == protowire.BytesType:
+			m.QuoteAssetDenom = string(value)
+		}
+	})
+}
+
+func (m *OsmosisSpotPriceResponse) Marshal() ([]byte, error) {
+	return appendOsmosisString(nil, 1, m.SpotPrice), nil
+}
+
+func (m *OsmosisSpotPriceResponse) Unmarshal(bz []byte) error {
+	return consumeOsmosisFields(bz, func(num protowire.Number, typ protowire.Type, value []byte, _ uint64) {
+		if num == 1 && typ == protowire.BytesType {
+			m.SpotPrice = string(value)
+		}
+	})
+}
+
+func (m *OsmosisTotalVolumeRequest) Marshal() ([]byte, error) {
+	return appendOsmosisVarint(nil, 1, m.PoolID), nil
+}
+
+func (m *OsmosisTotalVolumeRequest) Unmarshal(bz []byte) error {
+	return consumeOsmosisFields(bz, func(num protowire.Number, typ protowire.Type, _ []byte, varint uint64) {
+		if num == 1 && typ == protowire.VarintType {
+			m.PoolID = varint
+		}
+	})
+}
+
+func (m *OsmosisTotalVolumeResponse) Marshal() ([]byte, error) {
+	var bz []byte
+	for _, coin := range m.Volume {
+		coinBz, err := coin.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		bz = protowire.AppendTag(bz, 1, protowire.BytesType)
+		bz = protowire.AppendBytes(bz, coinBz)
+	}
+	return bz, nil
+}
+
+func (m *OsmosisTotalVolumeResponse) Unmarshal(bz []byte) error {
+	var coinErr error
+	err := consumeOsmosisFields(bz, func(num protowire.Number, typ protowire.Type, value []byte, _ uint64) {
+		if num == 1 && typ == protowire.BytesType {
+			var coin OsmosisCoin
+			if err := coin.Unmarshal(value); err != nil {
+				coinErr = err
+				return
+			}
+			m.Volume = append(m.Volume, coin)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	return coinErr
+}
+
+func (m *OsmosisCoin) Marshal() ([]byte, error) {
+	var bz []byte
+	bz = appendOsmosisString(bz, 1, m.Denom)
+	bz = appendOsmosisString(bz, 2, m.Amount)
+	return bz, nil
+}
+
+func (m *OsmosisCoin) Unmarshal(bz []byte) error {
+	return consumeOsmosisFields(bz, func(num protowire.Number, typ protowire.Type, value []byte, _ uint64) {
+		switch {
+		case num == 1 && typ == protowire.BytesType:
+			m.Denom = string(value)
+		case num == 2 && typ == protowire.BytesType:
+			m.Amount = string(value)
+		}
+	})
+}
+
+// appendOsmosisVarint appends a varint field, skipping the default value
+func appendOsmosisVarint(bz []byte, num protowire.Number, value uint64) []byte {
+	if value == 0 {
+		return bz
+	}
+	bz = protowire.AppendTag(bz, num, protowire.VarintType)
+	return protowire.AppendVarint(bz, value)
+}
+
+// appendOsmosisString appends a string field, skipping the default value
+func appendOsmosisString(bz []byte, num protowire.Number, value string) []byte {
+	if len(value) == 0 {
+		return bz
+	}
+	bz = protowire.AppendTag(bz, num, protowire.BytesType)
+	return protowire.AppendString(bz, value)
+}
+
+// consumeOsmosisFields calls the field func with the varint and bytes fields
+// of a message, skipping the fields of other types
+func consumeOsmosisFields(
+	bz []byte,
+	field func(num protowire.Number, typ protowire.Type, value []byte, varint uint64),
+) error {
+	for len(bz) > 0 {
+		num, typ, n := protowire.ConsumeTag(bz)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		bz = bz[n:]
+
+		switch typ {
+		case protowire.VarintType:
+			varint, n := protowire.ConsumeVarint(bz)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			field(num, typ, nil, varint)
+			bz = bz[n:]
+
+		case protowire.BytesType:
+			value, n := protowire.ConsumeBytes(bz)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			field(num, typ, value, 0)
+			bz = bz[n:]
+
+		default:
+			n := protowire.ConsumeFieldValue(num, typ, bz)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			bz = bz[n:]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,217 @@
+package provider
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"cosmossdk.io/math"
+
+	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle/types"
+)
+
+// osmosisTestServer is a local stand-in of the osmosis poolmanager queries
+type osmosisTestServer struct {
+	mtx          sync.Mutex
+	spotPrices   map[uint64]string
+	totalVolumes map[uint64][]OsmosisCoin
+}
+
+func (s *osmosisTestServer) setTotalVolume(poolID uint64, volume ...OsmosisCoin) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.totalVolumes[poolID] = volume
+}
+
+func startOsmosisTestServer(t *testing.T, s *osmosisTestServer) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer(grpc.ForceServerCodec(osmosisCodec{}))
+	server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "osmosis.poolmanager.v1beta1.Query",
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{
+			{
+				MethodName: "SpotPrice",
+				Handler: func(srv interface{}, _ context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+					var req OsmosisSpotPriceRequest
+					if err := dec(&req); err != nil {
+						return nil, err
+					}
+					s := srv.(*osmosisTestServer)
+					s.mtx.Lock()
+					defer s.mtx.Unlock()
+					return &OsmosisSpotPriceResponse{SpotPrice: s.spotPrices[req.PoolID]}, nil
+				},
+			},
+			{
+				MethodName: "TotalVolumeForPool",
+				Handler: func(srv interface{}, _ context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+					var req OsmosisTotalVolumeRequest
+					if err := dec(&req); err != nil {
+						return nil, err
+					}
+					s := srv.(*osmosisTestServer)
+					s.mtx.Lock()
+					defer s.mtx.Unlock()
+					return &OsmosisTotalVolumeResponse{Volume: s.totalVolumes[req.PoolID]}, nil
+				},
+			},
+		},
+	}, s)
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String()
+}
+
+func osmosisTestDialer(ctx context.Context, addr string) (net.Conn, error) {
+	return (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+}
+
+var osmosisTestPools = []config.DexPool{
+	{
+		Provider:      config.ProviderOsmosis,
+		Base:          "OSMO",
+		Quote:         "USDC",
+		PoolID:        1464,
+		BaseDenom:     "uosmo",
+		QuoteDenom:    "ibc/498A0751C798A0D9A389AA3691123DADA57DAA4FE165D5C75894505B876BA6E4",
+		BaseExponent:  6,
+		QuoteExponent: 6,
+	},
+	{
+		Provider:      config.ProviderOsmosis,
+		Base:          "ETH",
+		Quote:         "OSMO",
+		PoolID:        704,
+		BaseDenom:     "ibc/EA1D43981D5C9A1C4AAEA9C23BB1D4FA126BA9BC7020A25E0AE4AA841EA25DC5",
+		QuoteDenom:    "uosmo",
+		BaseExponent:  18,
+		QuoteExponent: 6,
+	},
+}
+
+func TestOsmosisProvider_SamplePools(t *testing.T) {
+	s := &osmosisTestServer{
+		spotPrices: map[uint64]string{
+			1464: "0.512300000000000000",
+			704:  "0.000000005600000000000000000000000000",
+		},
+		totalVolumes: map[uint64][]OsmosisCoin{
+			1464: {{Denom: "uosmo", Amount: "1000000000"}},
+		},
+	}
+	addr := startOsmosisTestServer(t, s)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	osmoUSDC := types.CurrencyPair{Base: "OSMO", Quote: "USDC"}
+	ethOSMO := types.CurrencyPair{Base: "ETH", Quote: "OSMO"}
+	p, err := NewOsmosisProvider(
+		ctx,
+		zerolog.Nop(),
+		config.ProviderEndpoint{Name: config.ProviderOsmosis, GRPC: addr},
+		osmosisTestPools,
+		osmosisTestDialer,
+		osmoUSDC,
+		ethOSMO,
+	)
+	require.NoError(t, err)
+
+	// the first sample is taken once the provider is created
+	require.Eventually(t, func() bool {
+		prices, err := p.GetTickerPrices(osmoUSDC, ethOSMO)
+		return err == nil && len(prices) == 2
+	}, 5*time.Second, 20*time.Millisecond)
+
+	prices, err := p.GetTickerPrices(osmoUSDC, ethOSMO)
+	require.NoError(t, err)
+	require.Equal(t, math.LegacyMustNewDecFromStr("0.5123"), prices["OSMOUSDC"].Price)
+	require.Equal(t, math.LegacyZeroDec(), prices["OSMOUSDC"].Volume)
+	require.Equal(t, math.LegacyMustNewDecFromStr("5600"), prices["ETHOSMO"].Price)
+
+	// the next sample has the volume swapped since the first one
+	s.setTotalVolume(1464, OsmosisCoin{Denom: "uosmo", Amount: "1250000000"})
+	require.NoError(t, p.samplePool(ctx, osmoUSDC))
+
+	prices, err = p.GetTickerPrices(osmoUSDC)
+	require.NoError(t, err)
+	require.Equal(t, math.LegacyMustNewDecFromStr("250"), prices["OSMOUSDC"].Volume)
+
+	candles, err := p.GetCandlePrices(osmoUSDC)
+	require.NoError(t, err)
+	require.Len(t, candles["OSMOUSDC"], 2)
+	require.Equal(t, math.LegacyZeroDec(), candles["OSMOUSDC"][0].Volume)
+	require.Equal(t, math.LegacyMustNewDecFromStr("250"), candles["OSMOUSDC"][1].Volume)
+}
+
+func TestOsmosisProvider_SubscribeCurrencyPairs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p, err := NewOsmosisProvider(
+		ctx,
+		zerolog.Nop(),
+		config.ProviderEndpoint{Name: config.ProviderOsmosis, GRPC: "127.0.0.1:1"},
+		osmosisTestPools,
+		osmosisTestDialer,
+	)
+	require.NoError(t, err)
+
+	err = p.SubscribeCurrencyPairs(types.CurrencyPair{Base: "ATOM", Quote: "USDC"})
+	require.EqualError(t, err, "no osmosis pool for ATOMUSDC")
+
+	pairs, err := p.GetAvailablePairs()
+	require.NoError(t, err)
+	require.Equal(t, map[string]struct{}{"OSMOUSDC": {}, "ETHOSMO": {}}, pairs)
+}
+
+func TestOsmosisSpotPrice(t *testing.T) {
+	pool := config.DexPool{PoolID: 1, BaseExponent: 6, QuoteExponent: 18}
+
+	price, err := osmosisSpotPrice("2000000000000.000000000000000000000000000000000001", pool)
+	require.NoError(t, err)
+	require.Equal(t, math.LegacyMustNewDecFromStr("2"), price)
+
+	_, err = osmosisSpotPrice("0", pool)
+	require.Error(t, err)
+
+	_, err = osmosisSpotPrice("foo", pool)
+	require.Error(t, err)
+}
+
+func TestOsmosisMessages(t *testing.T) {
+	spotPriceReq := &OsmosisSpotPriceRequest{PoolID: 1464, BaseAssetDenom: "uosmo", QuoteAssetDenom: "uusdc"}
+	bz, err := spotPriceReq.Marshal()
+	require.NoError(t, err)
+
+	var decodedSpotPriceReq OsmosisSpotPriceRequest
+	require.NoError(t, decodedSpotPriceReq.Unmarshal(bz))
+	require.Equal(t, *spotPriceReq, decodedSpotPriceReq)
+
+	totalVolumeResp := &OsmosisTotalVolumeResponse{Volume: []OsmosisCoin{
+		{Denom: "uosmo", Amount: "1000"},
+		{Denom: "uusdc", Amount: "512"},
+	}}
+	bz, err = totalVolumeResp.Marshal()
+	require.NoError(t, err)
+
+	var decodedTotalVolumeResp OsmosisTotalVolumeResponse
+	require.NoError(t, decodedTotalVolumeResp.Unmarshal(bz))
+	require.Equal(t, *totalVolumeResp, decodedTotalVolumeResp)
+
+	require.Error(t, decodedTotalVolumeResp.Unmarshal([]byte{0x0a, 0x05}))
+}
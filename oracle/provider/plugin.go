@@ -0,0 +1,497 @@
+package provider
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"cosmossdk.io/math"
+
+	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle/types"
+)
+
+const (
+	// PluginProtocolVersion is the version of the plugin protocol, a plugin
+	// must answer the init request with the same version
+	PluginProtocolVersion = 1
+
+	PluginMethodInit                   = "init"
+	PluginMethodGetTickerPrices        = "get_ticker_prices"
+	PluginMethodGetCandlePrices        = "get_candle_prices"
+	PluginMethodGetAvailablePairs      = "get_available_pairs"
+	PluginMethodSubscribeCurrencyPairs = "subscribe_currency_pairs"
+
+	startingPluginRestartDuration = 5 * time.Second
+	maxPluginRestartDuration      = 5 * time.Minute
+	maxPluginMessageSize          = 16 * 1024 * 1024
+
+	// maxPluginTimeouts is the amount of consecutive timed out requests after
+	// which a plugin is considered hung and restarted
+	maxPluginTimeouts = 3
+)
+
+var _ Provider = (*PluginProvider)(nil)
+
+type (
+	// PluginProvider defines an Oracle provider served by an external binary.
+	// The plugin is launched with the pairs, restarted when it exits or stops
+	// answering, and receives one JSON request per line on its stdin,
+	// answering with one JSON response per line on its stdout. Its stderr is
+	// logged.
+	PluginProvider struct {
+		ctx             context.Context
+		logger          zerolog.Logger
+		cfg             config.Plugin
+		timeout         time.Duration
+		restartDuration time.Duration
+
+		writeMtx sync.Mutex // serializes the requests written on the stdin
+
+		mtx             sync.Mutex
+		process         *os.Process
+		stdin           io.WriteCloser // nil while the plugin isn't running
+		timeouts        int            // consecutive timed out requests
+		nextID          uint64
+		pending         map[uint64]chan PluginResponse
+		subscribedPairs map[string]types.CurrencyPair // Symbol => types.CurrencyPair
+	}
+
+	// PluginRequest is a request sent to the plugin, on a single line.
+	PluginRequest struct {
+		ID     uint64          `json:"id"`
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params,omitempty"`
+	}
+
+	// PluginResponse is the response of the plugin to the request with the
+	// same id, on a single line.
+	PluginResponse struct {
+		ID     uint64          `json:"id"`
+		Result json.RawMessage `json:"result,omitempty"`
+		Error  string          `json:"error,omitempty"`
+	}
+
+	// PluginInitParams are the params of the init request, sent first.
+	PluginInitParams struct {
+		Version int          `json:"version"`
+		Pairs   []PluginPair `json:"pairs"`
+	}
+
+	// PluginInitResult is the result of the init request.
+	PluginInitResult struct {
+		Version int `json:"version"`
+	}
+
+	// PluginPairsParams are the params of the requests on pairs.
+	PluginPairsParams struct {
+		Pairs []PluginPair `json:"pairs"`
+	}
+
+	// PluginPair is a currency pair, ex. {"base":"BTC","quote":"USD"}.
+	PluginPair struct {
+		Base  string `json:"base"`
+		Quote string `json:"quote"`
+	}
+
+	// PluginTicker is a ticker price by pair, ex. "BTCUSD".
+	PluginTicker struct {
+		Price  math.LegacyDec `json:"price"`
+		Volume math.LegacyDec `json:"volume"`
+	}
+
+	// PluginCandle is a candle price by pair, with a timestamp in ms.
+	PluginCandle struct {
+		Price     math.LegacyDec `json:"price"`
+		Volume    math.LegacyDec `json:"volume"`
+		TimeStamp int64          `json:"timestamp"`
+	}
+
+	// pluginLogWriter logs each line written by the plugin on its stderr.
+	pluginLogWriter struct {
+		logger zerolog.Logger
+	}
+)
+
+// NewPluginProvider launches the plugin with the pairs and returns a provider
+// forwarding the requests to it.
+func NewPluginProvider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	cfg config.Plugin,
+	pairs ...types.CurrencyPair,
+) (*PluginProvider, error) {
+	timeout, err := cfg.GetTimeout()
+	if err != nil {
+		return nil, err
+	}
+
+	provider := &PluginProvider{
+		ctx:             ctx,
+		logger:          logger.With().Str("provider", cfg.Name).Logger(),
+		cfg:             cfg,
+		timeout:         timeout,
+		restartDuration: startingPluginRestartDuration,
+		pending:         map[uint64]chan PluginResponse{},
+		subscribedPairs: map[string]types.CurrencyPair{},
+	}
+
+	provider.setSubscribedPairs(pairs...)
+
+	cmd, done, err := provider.start()
+	if err != nil {
+		return nil, err
+	}
+
+	go provider.supervise(cmd, done)
+
+	return provider, nil
+}
+
+// start launches the plugin and sends the init request with the subscribed
+// pairs. The done channel is closed once the plugin stdout is closed.
+func (p *PluginProvider) start() (*exec.Cmd, chan struct{}, error) {
+	cmd := exec.CommandContext(p.ctx, p.cfg.Command, p.cfg.Args...)
+	cmd.Env = os.Environ()
+	for key, value := range p.cfg.Env {
+		cmd.Env = append(cmd.Env, key+"="+value)
+	}
+	cmd.Stderr = pluginLogWriter{logger: p.logger}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, nil, fmt.Errorf("failed to launch plugin %s: %w", p.cfg.Name, err)
+	}
+
+	p.mtx.Lock()
+	p.process = cmd.Process
+	p.stdin = stdin
+	p.timeouts = 0
+	pairs := types.MapPairsToSlice(p.subscribedPairs)
+	p.mtx.Unlock()
+
+	done := make(chan struct{})
+	go p.readResponses(stdout, done)
+
+	var result PluginInitResult
+	err = p.call(PluginMethodInit, PluginInitParams{
+		Version: PluginProtocolVersion,
+		Pairs:   newPluginPairs(pairs),
+	}, &result)
+	if err == nil && result.Version != PluginProtocolVersion {
+		err = fmt.Errorf("plugin %s speaks version %d, expected %d", p.cfg.Name, result.Version, PluginProtocolVersion)
+	}
+	if err != nil {
+		_ = cmd.Process.Kill()
+		<-done
+		_ = cmd.Wait()
+		return nil, nil, err
+	}
+
+	p.logger.Info().Int("pid", cmd.Process.Pid).Msg("plugin started")
+	return cmd, done, nil
+}
+
+// supervise waits for the plugin to exit and restarts it, doubling the
+// duration between the failed restarts, until the context is done.
+func (p *PluginProvider) supervise(cmd *exec.Cmd, done chan struct{}) {
+	for {
+		<-done
+		err := cmd.Wait()
+
+		select {
+		case <-p.ctx.Done():
+			return
+		default:
+		}
+		p.logger.Error().Err(err).Msg("plugin exited, restarting")
+
+		p.mtx.Lock()
+		restartDuration := p.restartDuration
+		p.mtx.Unlock()
+
+		for {
+			select {
+			case <-p.ctx.Done():
+				return
+			case <-time.After(restartDuration):
+			}
+
+			cmd, done, err = p.start()
+			if err == nil {
+				break
+			}
+			p.logger.Error().Err(err).Msg("failed to restart plugin")
+
+			restartDuration *= 2
+			if restartDuration > maxPluginRestartDuration {
+				restartDuration = maxPluginRestartDuration
+			}
+		}
+	}
+}
+
+// readResponses relays the responses of the plugin to the pending requests,
+// failing the ones left when the plugin closes its stdout.
+func (p *PluginProvider) readResponses(stdout io.Reader, done chan struct{}) {
+	defer close(done)
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxPluginMessageSize)
+	for scanner.Scan() {
+		var resp PluginResponse
+		if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+			p.logger.Error().Err(err).Msg("failed to unmarshal plugin response")
+			continue
+		}
+
+		p.mtx.Lock()
+		respCh, ok := p.pending[resp.ID]
+		delete(p.pending, resp.ID)
+		p.mtx.Unlock()
+
+		if ok {
+			respCh <- resp
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		p.logger.Error().Err(err).Msg("failed to read plugin response")
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.stdin = nil
+	for id, respCh := range p.pending {
+		respCh <- PluginResponse{ID: id, Error: "plugin exited"}
+		delete(p.pending, id)
+	}
+}
+
+// call sends a request to the plugin and unmarshals the result of its
+// response, waiting for the timeout of the config. The request is written
+// outside of the lock, so a plugin which stops reading its stdin never blocks
+// the relay of the responses.
+func (p *PluginProvider) call(method string, params interface{}, result interface{}) error {
+	paramsBz, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	p.mtx.Lock()
+	if p.stdin == nil {
+		p.mtx.Unlock()
+		return fmt.Errorf("plugin %s is not running", p.cfg.Name)
+	}
+
+	p.nextID++
+	req := PluginRequest{ID: p.nextID, Method: method, Params: paramsBz}
+	respCh := make(chan PluginResponse, 1)
+	p.pending[req.ID] = respCh
+	stdin := p.stdin
+	p.mtx.Unlock()
+
+	reqBz, err := json.Marshal(req)
+	if err != nil {
+		p.removePending(req.ID)
+		return err
+	}
+
+	// a blocked write is released once the hung plugin is killed
+	writeErrCh := make(chan error, 1)
+	go func() {
+		p.writeMtx.Lock()
+		defer p.writeMtx.Unlock()
+
+		_, err := stdin.Write(append(reqBz, '\n'))
+		writeErrCh <- err
+	}()
+
+	timeout := time.NewTimer(p.timeout)
+	defer timeout.Stop()
+
+	var resp PluginResponse
+	for received := false; !received; {
+		select {
+		case resp = <-respCh:
+			received = true
+		case err := <-writeErrCh:
+			if err != nil {
+				p.removePending(req.ID)
+				return fmt.Errorf("failed to send %s to plugin %s: %w", method, p.cfg.Name, err)
+			}
+		case <-timeout.C:
+			p.removePending(req.ID)
+			p.recordTimeout()
+			return fmt.Errorf("plugin %s timed out on %s", p.cfg.Name, method)
+		case <-p.ctx.Done():
+			return p.ctx.Err()
+		}
+	}
+
+	p.mtx.Lock()
+	p.timeouts = 0
+	p.mtx.Unlock()
+
+	if len(resp.Error) > 0 {
+		return fmt.Errorf("plugin %s failed on %s: %s", p.cfg.Name, method, resp.Error)
+	}
+	if result == nil {
+		return nil
+	}
+
+	return json.Unmarshal(resp.Result, result)
+}
+
+// removePending removes a request which won't be answered.
+func (p *PluginProvider) removePending(id uint64) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	delete(p.pending, id)
+}
+
+// recordTimeout counts a timed out request, killing the plugin after too many
+// consecutive timeouts so it is restarted by the supervisor.
+func (p *PluginProvider) recordTimeout() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.timeouts++
+	if p.timeouts < maxPluginTimeouts || p.process == nil || p.stdin == nil {
+		return
+	}
+
+	p.logger.Error().Int("timeouts", p.timeouts).Msg("plugin not answering, killing it")
+	if err := p.process.Kill(); err != nil {
+		p.logger.Error().Err(err).Msg("failed to kill plugin")
+	}
+	p.timeouts = 0
+}
+
+// GetTickerPrices returns the tickerPrices of the plugin.
+func (p *PluginProvider) GetTickerPrices(pairs ...types.CurrencyPair) (map[string]TickerPrice, error) {
+	var tickers map[string]PluginTicker
+	if err := p.call(PluginMethodGetTickerPrices, PluginPairsParams{Pairs: newPluginPairs(pairs)}, &tickers); err != nil {
+		return nil, err
+	}
+
+	tickerPrices := make(map[string]TickerPrice, len(tickers))
+	for symbol, ticker := range tickers {
+		tickerPrices[symbol] = TickerPrice{Price: ticker.Price, Volume: ticker.Volume}
+	}
+
+	return tickerPrices, nil
+}
+
+// GetCandlePrices returns the candlePrices of the plugin.
+func (p *PluginProvider) GetCandlePrices(pairs ...types.CurrencyPair) (map[string][]CandlePrice, error) {
+	var candles map[string][]PluginCandle
+	if err := p.call(PluginMethodGetCandlePrices, PluginPairsParams{Pairs: newPluginPairs(pairs)}, &candles); err != nil {
+		return nil, err
+	}
+
+	candlePrices := make(map[string][]CandlePrice, len(candles))
+	for symbol, candleList := range candles {
+		for _, candle := range candleList {
+			candlePrices[symbol] = append(candlePrices[symbol], CandlePrice{
+				Price:     candle.Price,
+				Volume:    candle.Volume,
+				TimeStamp: candle.TimeStamp,
+			})
+		}
+	}
+
+	return candlePrices, nil
+}
+
+// GetAvailablePairs returns the pairs listed by the plugin, or nil when the
+// plugin can't list them.
+// ex.: map["BTCUSD" => {}, "ETHUSD" => {}].
+func (p *PluginProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	var symbols []string
+	if err := p.call(PluginMethodGetAvailablePairs, struct{}{}, &symbols); err != nil {
+		return nil, err
+	}
+	if symbols == nil {
+		return nil, nil
+	}
+
+	availablePairs := make(map[string]struct{}, len(symbols))
+	for _, symbol := range symbols {
+		availablePairs[strings.ToUpper(symbol)] = struct{}{}
+	}
+
+	return availablePairs, nil
+}
+
+// SubscribeCurrencyPairs sends the new pairs to the plugin and adds them to
+// the pairs sent again when the plugin restarts.
+func (p *PluginProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) error {
+	p.mtx.Lock()
+	newPairs := []types.CurrencyPair{}
+	for _, cp := range cps {
+		if _, ok := p.subscribedPairs[cp.String()]; !ok {
+			newPairs = append(newPairs, cp)
+		}
+	}
+	p.mtx.Unlock()
+
+	if len(newPairs) == 0 {
+		return nil
+	}
+	if err := p.call(PluginMethodSubscribeCurrencyPairs, PluginPairsParams{Pairs: newPluginPairs(newPairs)}, nil); err != nil {
+		return err
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.setSubscribedPairs(newPairs...)
+	return nil
+}
+
+// setSubscribedPairs sets N currency pairs to the map of subscribed pairs.
+func (p *PluginProvider) setSubscribedPairs(cps ...types.CurrencyPair) {
+	for _, cp := range cps {
+		p.subscribedPairs[cp.String()] = cp
+	}
+}
+
+// Write logs the lines written by the plugin.
+func (w pluginLogWriter) Write(bz []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimSpace(string(bz)), "\n") {
+		if len(line) > 0 {
+			w.logger.Info().Str("plugin_log", line).Send()
+		}
+	}
+
+	return len(bz), nil
+}
+
+// newPluginPairs returns the pairs as sent to the plugin.
+func newPluginPairs(cps []types.CurrencyPair) []PluginPair {
+	pairs := make([]PluginPair, len(cps))
+	for i, cp := range cps {
+		pairs[i] = PluginPair{Base: cp.Base, Quote: cp.Quote}
+	}
+
+	return pairs
+}
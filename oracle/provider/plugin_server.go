@@ -0,0 +1,150 @@
+package provider
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/kiichain/price-feeder/oracle/types"
+)
+
+// NewPluginProviderFunc creates the provider served by a plugin, with the
+// pairs of the init request.
+type NewPluginProviderFunc func(pairs ...types.CurrencyPair) (Provider, error)
+
+// ServePlugin serves a provider with the plugin protocol, reading the requests
+// from r and writing the responses to w until r is closed. It lets a plugin
+// written in Go wrap an implementation of the Provider interface, ex.
+//
+//	err := provider.ServePlugin(os.Stdin, os.Stdout, newOTCDeskProvider)
+func ServePlugin(r io.Reader, w io.Writer, newProvider NewPluginProviderFunc) error {
+	var priceProvider Provider
+
+	encoder := json.NewEncoder(w)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxPluginMessageSize)
+	for scanner.Scan() {
+		var req PluginRequest
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			return fmt.Errorf("failed to unmarshal plugin request: %w", err)
+		}
+
+		var (
+			result interface{}
+			err    error
+		)
+		switch {
+		case req.Method == PluginMethodInit:
+			priceProvider, result, err = servePluginInit(req.Params, newProvider)
+		case priceProvider == nil:
+			err = fmt.Errorf("plugin not initialized")
+		default:
+			result, err = servePluginRequest(priceProvider, req)
+		}
+
+		resp := PluginResponse{ID: req.ID}
+		if err == nil {
+			resp.Result, err = json.Marshal(result)
+		}
+		if err != nil {
+			resp.Error = err.Error()
+		}
+
+		// the encoder writes one response per line
+		if err := encoder.Encode(resp); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+// servePluginInit checks the protocol version and creates the provider.
+func servePluginInit(params json.RawMessage, newProvider NewPluginProviderFunc) (Provider, interface{}, error) {
+	var initParams PluginInitParams
+	if err := json.Unmarshal(params, &initParams); err != nil {
+		return nil, nil, err
+	}
+	if initParams.Version != PluginProtocolVersion {
+		return nil, nil, fmt.Errorf("unsupported protocol version %d, expected %d", initParams.Version, PluginProtocolVersion)
+	}
+
+	priceProvider, err := newProvider(newCurrencyPairs(initParams.Pairs)...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return priceProvider, PluginInitResult{Version: PluginProtocolVersion}, nil
+}
+
+// servePluginRequest calls the provider method of the request.
+func servePluginRequest(priceProvider Provider, req PluginRequest) (interface{}, error) {
+	var pairsParams PluginPairsParams
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &pairsParams); err != nil {
+			return nil, err
+		}
+	}
+	pairs := newCurrencyPairs(pairsParams.Pairs)
+
+	switch req.Method {
+	case PluginMethodGetTickerPrices:
+		tickerPrices, err := priceProvider.GetTickerPrices(pairs...)
+		if err != nil {
+			return nil, err
+		}
+
+		tickers := make(map[string]PluginTicker, len(tickerPrices))
+		for symbol, ticker := range tickerPrices {
+			tickers[symbol] = PluginTicker{Price: ticker.Price, Volume: ticker.Volume}
+		}
+		return tickers, nil
+
+	case PluginMethodGetCandlePrices:
+		candlePrices, err := priceProvider.GetCandlePrices(pairs...)
+		if err != nil {
+			return nil, err
+		}
+
+		candles := make(map[string][]PluginCandle, len(candlePrices))
+		for symbol, candleList := range candlePrices {
+			for _, candle := range candleList {
+				candles[symbol] = append(candles[symbol], PluginCandle{
+					Price:     candle.Price,
+					Volume:    candle.Volume,
+					TimeStamp: candle.TimeStamp,
+				})
+			}
+		}
+		return candles, nil
+
+	case PluginMethodGetAvailablePairs:
+		availablePairs, err := priceProvider.GetAvailablePairs()
+		if err != nil || availablePairs == nil {
+			return nil, err
+		}
+
+		symbols := make([]string, 0, len(availablePairs))
+		for symbol := range availablePairs {
+			symbols = append(symbols, symbol)
+		}
+		return symbols, nil
+
+	case PluginMethodSubscribeCurrencyPairs:
+		return nil, priceProvider.SubscribeCurrencyPairs(pairs...)
+
+	default:
+		return nil, fmt.Errorf("unsupported method: %s", req.Method)
+	}
+}
+
+// newCurrencyPairs returns the pairs sent by the price feeder.
+func newCurrencyPairs(pluginPairs []PluginPair) []types.CurrencyPair {
+	pairs := make([]types.CurrencyPair, len(pluginPairs))
+	for i, pair := range pluginPairs {
+		pairs[i] = types.CurrencyPair{Base: pair.Base, Quote: pair.Quote}
+	}
+
+	return pairs
+}
@@ -0,0 +1,229 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/math"
+
+	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle/types"
+)
+
+const pluginHelperEnv = "PRICE_FEEDER_PLUGIN_HELPER"
+
+// pluginTestProvider is the provider served by the plugin helper process
+type pluginTestProvider struct {
+	pairs map[string]types.CurrencyPair
+}
+
+func (p *pluginTestProvider) GetTickerPrices(pairs ...types.CurrencyPair) (map[string]TickerPrice, error) {
+	tickerPrices := make(map[string]TickerPrice, len(pairs))
+	for _, cp := range pairs {
+		// the plugin hangs on the HANG pair
+		if cp.Base == "HANG" {
+			select {}
+		}
+		if _, ok := p.pairs[cp.String()]; ok {
+			tickerPrices[cp.String()] = TickerPrice{Price: math.LegacyMustNewDecFromStr("62410.5"), Volume: math.LegacyOneDec()}
+		}
+	}
+	return tickerPrices, nil
+}
+
+func (p *pluginTestProvider) GetCandlePrices(pairs ...types.CurrencyPair) (map[string][]CandlePrice, error) {
+	candlePrices := make(map[string][]CandlePrice, len(pairs))
+	for _, cp := range pairs {
+		if _, ok := p.pairs[cp.String()]; ok {
+			candlePrices[cp.String()] = []CandlePrice{
+				{Price: math.LegacyMustNewDecFromStr("62400"), Volume: math.LegacyOneDec(), TimeStamp: 1700000000000},
+			}
+		}
+	}
+	return candlePrices, nil
+}
+
+func (p *pluginTestProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	return map[string]struct{}{"BTCUSD": {}, "ETHUSD": {}}, nil
+}
+
+func (p *pluginTestProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) error {
+	for _, cp := range cps {
+		p.pairs[cp.String()] = cp
+	}
+	return nil
+}
+
+// TestPluginHelperProcess isn't a real test, it serves the test provider when
+// launched as a plugin by the other tests.
+func TestPluginHelperProcess(t *testing.T) {
+	if os.Getenv(pluginHelperEnv) != "1" {
+		return
+	}
+
+	err := ServePlugin(os.Stdin, os.Stdout, func(pairs ...types.CurrencyPair) (Provider, error) {
+		p := &pluginTestProvider{pairs: map[string]types.CurrencyPair{}}
+		return p, p.SubscribeCurrencyPairs(pairs...)
+	})
+	if err != nil {
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func newTestPluginProvider(t *testing.T, ctx context.Context, pairs ...types.CurrencyPair) *PluginProvider {
+	p, err := NewPluginProvider(
+		ctx,
+		zerolog.Nop(),
+		config.Plugin{
+			Name:    "otcdesk",
+			Command: os.Args[0],
+			Args:    []string{"-test.run=TestPluginHelperProcess"},
+			Env:     map[string]string{pluginHelperEnv: "1"},
+			Timeout: "500ms",
+		},
+		pairs...,
+	)
+	require.NoError(t, err)
+	return p
+}
+
+func TestPluginProvider(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p := newTestPluginProvider(t, ctx, types.CurrencyPair{Base: "BTC", Quote: "USD"})
+
+	t.Run("get_ticker_prices", func(t *testing.T) {
+		prices, err := p.GetTickerPrices(
+			types.CurrencyPair{Base: "BTC", Quote: "USD"},
+			types.CurrencyPair{Base: "ETH", Quote: "USD"},
+		)
+		require.NoError(t, err)
+		require.Len(t, prices, 1)
+		require.Equal(t, math.LegacyMustNewDecFromStr("62410.5"), prices["BTCUSD"].Price)
+		require.Equal(t, math.LegacyOneDec(), prices["BTCUSD"].Volume)
+	})
+
+	t.Run("get_candle_prices", func(t *testing.T) {
+		prices, err := p.GetCandlePrices(types.CurrencyPair{Base: "BTC", Quote: "USD"})
+		require.NoError(t, err)
+		require.Len(t, prices["BTCUSD"], 1)
+		require.Equal(t, math.LegacyMustNewDecFromStr("62400"), prices["BTCUSD"][0].Price)
+		require.Equal(t, int64(1700000000000), prices["BTCUSD"][0].TimeStamp)
+	})
+
+	t.Run("get_available_pairs", func(t *testing.T) {
+		pairs, err := p.GetAvailablePairs()
+		require.NoError(t, err)
+		require.Equal(t, map[string]struct{}{"BTCUSD": {}, "ETHUSD": {}}, pairs)
+	})
+
+	t.Run("subscribe_currency_pairs", func(t *testing.T) {
+		require.NoError(t, p.SubscribeCurrencyPairs(types.CurrencyPair{Base: "ETH", Quote: "USD"}))
+
+		prices, err := p.GetTickerPrices(types.CurrencyPair{Base: "ETH", Quote: "USD"})
+		require.NoError(t, err)
+		require.Len(t, prices, 1)
+	})
+
+	t.Run("restart_after_exit", func(t *testing.T) {
+		p.mtx.Lock()
+		p.restartDuration = 10 * time.Millisecond
+		process := p.process
+		p.mtx.Unlock()
+		require.NoError(t, process.Kill())
+
+		// the subscribed pairs are sent again to the new plugin
+		require.Eventually(t, func() bool {
+			prices, err := p.GetTickerPrices(types.CurrencyPair{Base: "ETH", Quote: "USD"})
+			return err == nil && len(prices) == 1
+		}, 5*time.Second, 20*time.Millisecond)
+	})
+
+	t.Run("restart_after_timeouts", func(t *testing.T) {
+		p.mtx.Lock()
+		process := p.process
+		p.mtx.Unlock()
+
+		// the hung plugin is killed after the consecutive timeouts
+		for i := 0; i < maxPluginTimeouts; i++ {
+			_, err := p.GetTickerPrices(types.CurrencyPair{Base: "HANG", Quote: "USD"})
+			require.ErrorContains(t, err, "timed out")
+		}
+
+		require.Eventually(t, func() bool {
+			prices, err := p.GetTickerPrices(types.CurrencyPair{Base: "BTC", Quote: "USD"})
+			return err == nil && len(prices) == 1
+		}, 5*time.Second, 20*time.Millisecond)
+
+		p.mtx.Lock()
+		defer p.mtx.Unlock()
+		require.NotEqual(t, process.Pid, p.process.Pid)
+	})
+
+	t.Run("closed_with_context", func(t *testing.T) {
+		cancel()
+
+		require.Eventually(t, func() bool {
+			_, err := p.GetTickerPrices(types.CurrencyPair{Base: "BTC", Quote: "USD"})
+			return err != nil
+		}, 5*time.Second, 20*time.Millisecond)
+	})
+}
+
+func TestNewPluginProvider_InvalidCommand(t *testing.T) {
+	_, err := NewPluginProvider(
+		context.TODO(),
+		zerolog.Nop(),
+		config.Plugin{Name: "otcdesk", Command: "/nonexistent/plugin"},
+	)
+	require.Error(t, err)
+}
+
+func TestServePlugin(t *testing.T) {
+	requests := []PluginRequest{
+		{ID: 1, Method: PluginMethodGetTickerPrices, Params: json.RawMessage(`{"pairs":[{"base":"BTC","quote":"USD"}]}`)},
+		{ID: 2, Method: PluginMethodInit, Params: json.RawMessage(`{"version":2,"pairs":[]}`)},
+		{ID: 3, Method: PluginMethodInit, Params: json.RawMessage(`{"version":1,"pairs":[{"base":"BTC","quote":"USD"}]}`)},
+		{ID: 4, Method: PluginMethodGetTickerPrices, Params: json.RawMessage(`{"pairs":[{"base":"BTC","quote":"USD"}]}`)},
+		{ID: 5, Method: "get_order_book"},
+	}
+
+	var in bytes.Buffer
+	for _, req := range requests {
+		bz, err := json.Marshal(req)
+		require.NoError(t, err)
+		in.Write(append(bz, '\n'))
+	}
+
+	var out bytes.Buffer
+	err := ServePlugin(&in, &out, func(pairs ...types.CurrencyPair) (Provider, error) {
+		p := &pluginTestProvider{pairs: map[string]types.CurrencyPair{}}
+		return p, p.SubscribeCurrencyPairs(pairs...)
+	})
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, len(requests))
+
+	responses := make([]PluginResponse, len(lines))
+	for i, line := range lines {
+		require.NoError(t, json.Unmarshal([]byte(line), &responses[i]))
+		require.Equal(t, requests[i].ID, responses[i].ID)
+	}
+
+	require.Equal(t, "plugin not initialized", responses[0].Error)
+	require.Contains(t, responses[1].Error, "unsupported protocol version 2")
+	require.JSONEq(t, `{"version":1}`, string(responses[2].Result))
+	require.JSONEq(t, `{"BTCUSD":{"price":"62410.500000000000000000","volume":"1.000000000000000000"}}`, string(responses[3].Result))
+	require.Equal(t, "unsupported method: get_order_book", responses[4].Error)
+}
@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
@@ -16,9 +17,16 @@ const (
 	defaultReconnectTime = time.Minute * 20
 	maxReconnectionTries = 3
 	providerCandlePeriod = 10 * time.Minute
+	unixMinute           = 60000 // a minute in milliseconds
 )
 
-var ping = []byte("ping")
+var (
+	ping = []byte("ping")
+
+	// availablePairsClient lists the pairs of the providers, with a timeout
+	// so an unresponsive endpoint never hangs the validation of the pairs
+	availablePairsClient = &http.Client{Timeout: defaultTimeout}
+)
 
 // Provider defines an interface an exchange price provider must implement.
 type Provider interface {
@@ -28,7 +36,8 @@ type Provider interface {
 	// GetCandlePrices returns the candlePrices based on the provided pairs.
 	GetCandlePrices(...types.CurrencyPair) (map[string][]CandlePrice, error)
 
-	// GetAvailablePairs return all available pairs symbol to susbscribe.
+	// GetAvailablePairs return all available pairs symbol to susbscribe, or nil
+	// when the provider can't list them.
 	GetAvailablePairs() (map[string]struct{}, error)
 
 	// SubscribeCurrencyPairs subscribe to ticker and candle channels for all pairs.
@@ -60,18 +69,14 @@ type AggregatedProviderCandles map[string]map[string][]CandlePrice
 
 // preventRedirect avoid any redirect in the http.Client the request call
 // will not return an error, but a valid response with redirect response code.
-//
-//nolint:unused
 func preventRedirect(_ *http.Request, _ []*http.Request) error {
 	return http.ErrUseLastResponse
 }
 
-//nolint:unused,deadcode
 func newDefaultHTTPClient() *http.Client {
 	return newHTTPClientWithTimeout(defaultTimeout)
 }
 
-//nolint:unused
 func newHTTPClientWithTimeout(timeout time.Duration) *http.Client {
 	return &http.Client{
 		Timeout:       timeout,
@@ -124,3 +129,20 @@ func strToDec(str string) math.LegacyDec {
 	}
 	return math.LegacyMustNewDecFromStr(str)
 }
+
+// getAvailablePairsJSON requests the pairs listed by a provider and decodes them
+// into the response, failing when the endpoint does not answer with success so
+// an error body is never read as an empty listing.
+func getAvailablePairsJSON(url string, response interface{}) error {
+	resp, err := availablePairsClient.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to get available pairs from %s: status %d", url, resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(response)
+}
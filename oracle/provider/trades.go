@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+
+	"cosmossdk.io/math"
+)
+
+// TradePrice defines the price, size and time of a trade, kept by the
+// providers which build their candles from the live trades.
+type TradePrice struct {
+	ID        int64          // trade id, zero when the provider has none
+	Price     math.LegacyDec // trade price
+	Volume    math.LegacyDec // trade size
+	TimeStamp int64          // trade time in milliseconds
+}
+
+func newTradePrice(provider, symbol string, id int64, price, size string, timeStamp int64) (TradePrice, error) {
+	priceDec, err := math.LegacyNewDecFromStr(price)
+	if err != nil {
+		return TradePrice{}, fmt.Errorf("failed to parse %s trade price (%s) for %s", provider, price, symbol)
+	}
+
+	sizeDec, err := math.LegacyNewDecFromStr(size)
+	if err != nil {
+		return TradePrice{}, fmt.Errorf("failed to parse %s trade size (%s) for %s", provider, size, symbol)
+	}
+
+	return TradePrice{ID: id, Price: priceDec, Volume: sizeDec, TimeStamp: timeStamp}, nil
+}
+
+// appendTrade adds the trade to a copy of the trades, removing the ones older
+// than the candle period. A trade already received, as the recent trades sent
+// again after a reconnection, is skipped so its size isn't counted twice.
+func appendTrade(trades []TradePrice, trade TradePrice) []TradePrice {
+	staleTime := PastUnixTime(providerCandlePeriod)
+	tradeList := []TradePrice{trade}
+
+	for _, t := range trades {
+		if trade.ID != 0 && t.ID == trade.ID {
+			return trades
+		}
+		if staleTime < t.TimeStamp {
+			tradeList = append(tradeList, t)
+		}
+	}
+
+	return tradeList
+}
+
+// tradesToCandles divides the trades in one minute candles. Each candle has the aggregated size of its trades, and the
+// price and time of its most recent trade.
+func tradesToCandles(trades []TradePrice) []CandlePrice {
+	if len(trades) == 0 {
+		return []CandlePrice{}
+	}
+
+	// sort oldest -> newest
+	sortedTrades := make([]TradePrice, len(trades))
+	copy(sortedTrades, trades)
+	sort.SliceStable(sortedTrades, func(i, j int) bool {
+		return sortedTrades[i].TimeStamp < sortedTrades[j].TimeStamp
+	})
+
+	candles := []CandlePrice{
+		{
+			Price:  math.LegacyZeroDec(),
+			Volume: math.LegacyZeroDec(),
+		},
+	}
+	startTime := sortedTrades[0].TimeStamp
+	index := 0
+
+	for _, trade := range sortedTrades {
+		// every minute, reset the time period
+		if trade.TimeStamp-startTime > unixMinute {
+			index++
+			startTime = trade.TimeStamp
+			candles = append(candles, CandlePrice{
+				Price:  math.LegacyZeroDec(),
+				Volume: math.LegacyZeroDec(),
+			})
+		}
+
+		candles[index] = CandlePrice{
+			Price:     trade.Price,
+			Volume:    candles[index].Volume.Add(trade.Volume),
+			TimeStamp: trade.TimeStamp,
+		}
+	}
+
+	return candles
+}
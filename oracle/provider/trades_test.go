@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/math"
+)
+
+func TestTradesToCandles(t *testing.T) {
+	now := time.Now().UnixMilli()
+	trades := []TradePrice{
+		{Price: math.LegacyMustNewDecFromStr("26010"), Volume: math.LegacyMustNewDecFromStr("0.5"), TimeStamp: now},
+		{Price: math.LegacyMustNewDecFromStr("26000"), Volume: math.LegacyMustNewDecFromStr("1"), TimeStamp: now - 2*unixMinute},
+		{Price: math.LegacyMustNewDecFromStr("26005"), Volume: math.LegacyMustNewDecFromStr("2"), TimeStamp: now - 2*unixMinute + 1000},
+	}
+
+	candles := tradesToCandles(trades)
+	require.Len(t, candles, 2)
+
+	// the trades of the same minute are aggregated, keeping the last price
+	require.Equal(t, math.LegacyMustNewDecFromStr("26005"), candles[0].Price)
+	require.Equal(t, math.LegacyMustNewDecFromStr("3"), candles[0].Volume)
+	require.Equal(t, now-2*unixMinute+1000, candles[0].TimeStamp)
+
+	require.Equal(t, math.LegacyMustNewDecFromStr("26010"), candles[1].Price)
+	require.Equal(t, math.LegacyMustNewDecFromStr("0.5"), candles[1].Volume)
+	require.Equal(t, now, candles[1].TimeStamp)
+
+	// the trades are not reordered in place
+	require.Equal(t, now, trades[0].TimeStamp)
+
+	require.Empty(t, tradesToCandles(nil))
+}
+
+func TestAppendTrade(t *testing.T) {
+	now := time.Now().UnixMilli()
+	stale := TradePrice{TimeStamp: PastUnixTime(providerCandlePeriod) - 1}
+	recent := TradePrice{TimeStamp: now - unixMinute}
+
+	trades := appendTrade([]TradePrice{stale, recent}, TradePrice{TimeStamp: now})
+	require.Equal(t, []TradePrice{{TimeStamp: now}, recent}, trades)
+}
+
+func TestAppendTradeSkipsReceived(t *testing.T) {
+	now := time.Now().UnixMilli()
+	trade := TradePrice{ID: 1, TimeStamp: now - unixMinute}
+
+	trades := appendTrade([]TradePrice{trade}, TradePrice{ID: 2, TimeStamp: now})
+	require.Len(t, trades, 2)
+
+	// a trade received again is skipped
+	require.Equal(t, trades, appendTrade(trades, trade))
+
+	// trades without id are always added
+	trades = appendTrade(trades, TradePrice{TimeStamp: now})
+	trades = appendTrade(trades, TradePrice{TimeStamp: now})
+	require.Len(t, trades, 4)
+}
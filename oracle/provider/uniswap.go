@@ -0,0 +1,489 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog"
+
+	"cosmossdk.io/math"
+
+	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle/types"
+)
+
+const (
+	uniswapJSONRPCHost      = "http://localhost:8545"
+	uniswapSampleInterval   = 30 * time.Second
+	uniswapQueryTimeout     = 10 * time.Second
+	uniswapMaxLogBlockRange = 5000
+	uniswapTickBase         = "1.0001"
+	uniswapPricePrecision   = 256
+)
+
+var _ Provider = (*UniswapProvider)(nil)
+
+type (
+	// UniswapProvider defines an Oracle provider implemented by the Uniswap v3
+	// compatible pools of an EVM chain, read over Ethereum JSON-RPC.
+	//
+	// The ticker price is the TWAP of the pool over its window, read with
+	// observe(), or the spot price of slot0 when the pool doesn't keep enough
+	// observations and allows it. Each sample is a candle with the TWAP since the previous
+	// sample and the base volume of the swaps logged since then.
+	UniswapProvider struct {
+		logger          zerolog.Logger
+		mtx             sync.RWMutex
+		endpoint        config.ProviderEndpoint
+		client          *uniswapRPCClient
+		sampleInterval  time.Duration
+		pools           map[string]config.DexPool     // CurrencyPair => DexPool
+		baseIsToken0    map[string]bool               // CurrencyPair => base is the token0 of the pool
+		lastBlocks      map[string]uint64             // CurrencyPair => last block of the sampled swaps
+		tickers         map[string]TickerPrice        // CurrencyPair => TickerPrice
+		candles         map[string][]CandlePrice      // CurrencyPair => []CandlePrice
+		subscribedPairs map[string]types.CurrencyPair // Symbol => types.CurrencyPair
+	}
+)
+
+// NewUniswapProvider returns a new Uniswap v3 provider sampling the pools of
+// the pairs.
+func NewUniswapProvider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	endpoint config.ProviderEndpoint,
+	pools []config.DexPool,
+	pairs ...types.CurrencyPair,
+) (*UniswapProvider, error) {
+	if endpoint.Name != config.ProviderUniswap {
+		endpoint = config.ProviderEndpoint{
+			Name:    config.ProviderUniswap,
+			JSONRPC: uniswapJSONRPCHost,
+		}
+	}
+
+	provider := &UniswapProvider{
+		logger:   logger.With().Str("provider", config.ProviderUniswap).Logger(),
+		endpoint: endpoint,
+		client: &uniswapRPCClient{
+			url:    endpoint.JSONRPC,
+			client: &http.Client{Timeout: uniswapQueryTimeout},
+		},
+		sampleInterval:  uniswapSampleInterval,
+		pools:           map[string]config.DexPool{},
+		baseIsToken0:    map[string]bool{},
+		lastBlocks:      map[string]uint64{},
+		tickers:         map[string]TickerPrice{},
+		candles:         map[string][]CandlePrice{},
+		subscribedPairs: map[string]types.CurrencyPair{},
+	}
+
+	for _, pool := range pools {
+		if pool.Provider == config.ProviderUniswap {
+			provider.pools[pool.Base+pool.Quote] = pool
+		}
+	}
+
+	if err := provider.SubscribeCurrencyPairs(pairs...); err != nil {
+		return nil, err
+	}
+
+	go provider.poll(ctx)
+
+	return provider, nil
+}
+
+// poll samples the pools periodically until the context is done.
+func (p *UniswapProvider) poll(ctx context.Context) {
+	sampleTicker := time.NewTicker(p.sampleInterval)
+	defer sampleTicker.Stop()
+
+	for {
+		p.samplePools(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-sampleTicker.C:
+		}
+	}
+}
+
+// samplePools samples the pool of each subscribed pair.
+func (p *UniswapProvider) samplePools(ctx context.Context) {
+	p.mtx.RLock()
+	pairs := types.MapPairsToSlice(p.subscribedPairs)
+	p.mtx.RUnlock()
+
+	for _, cp := range pairs {
+		if err := p.samplePool(ctx, cp); err != nil {
+			p.logger.Warn().Err(err).Msg(fmt.Sprint("failed to sample pool for pair ", cp))
+		}
+	}
+}
+
+// samplePool reads the TWAPs and the swaps of the pool of the pair, saving
+// them as a new candle.
+func (p *UniswapProvider) samplePool(ctx context.Context, cp types.CurrencyPair) error {
+	key := cp.String()
+	pool := p.pools[key]
+	address := common.HexToAddress(pool.Address)
+
+	ctx, cancel := context.WithTimeout(ctx, uniswapQueryTimeout)
+	defer cancel()
+
+	baseIsToken0, err := p.getBaseIsToken0(ctx, key, pool)
+	if err != nil {
+		return err
+	}
+
+	twapWindow, err := pool.GetTwapWindow()
+	if err != nil {
+		return err
+	}
+
+	twapTick, candleTick, err := p.readTicks(ctx, address, twapWindow, pool.AllowSpotPrice)
+	if err != nil {
+		return err
+	}
+
+	price, err := uniswapTickPrice(twapTick, pool, baseIsToken0)
+	if err != nil {
+		return err
+	}
+	candlePrice, err := uniswapTickPrice(candleTick, pool, baseIsToken0)
+	if err != nil {
+		return err
+	}
+
+	// the volume is left out of the candle when the swaps can't be read
+	volume, err := p.readSwapVolume(ctx, key, address, pool, baseIsToken0)
+	if err != nil {
+		p.logger.Warn().Err(err).Str("pool", pool.Address).Msg("failed to read pool swaps")
+		volume = math.LegacyZeroDec()
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	staleTime := PastUnixTime(providerCandlePeriod)
+	candleList := []CandlePrice{}
+	tickerVolume := volume
+	for _, candle := range p.candles[key] {
+		if staleTime < candle.TimeStamp {
+			candleList = append(candleList, candle)
+			tickerVolume = tickerVolume.Add(candle.Volume)
+		}
+	}
+	p.candles[key] = append(candleList, CandlePrice{
+		Price:     candlePrice,
+		Volume:    volume,
+		TimeStamp: time.Now().UnixMilli(),
+	})
+	p.tickers[key] = TickerPrice{
+		Price:  price,
+		Volume: tickerVolume,
+	}
+
+	return nil
+}
+
+// getBaseIsToken0 returns whether the base of the pair is the token0 of the
+// pool, reading it once from the pool.
+func (p *UniswapProvider) getBaseIsToken0(ctx context.Context, key string, pool config.DexPool) (bool, error) {
+	p.mtx.RLock()
+	baseIsToken0, ok := p.baseIsToken0[key]
+	p.mtx.RUnlock()
+	if ok {
+		return baseIsToken0, nil
+	}
+
+	outputs, err := p.client.callContract(ctx, common.HexToAddress(pool.Address), "token0")
+	if err != nil {
+		return false, err
+	}
+
+	token0 := outputs[0].(common.Address)
+	switch token0 {
+	case common.HexToAddress(pool.BaseDenom):
+		baseIsToken0 = true
+	case common.HexToAddress(pool.QuoteDenom):
+		baseIsToken0 = false
+	default:
+		return false, fmt.Errorf("pool %s is not between %s and %s", pool.Address, pool.BaseDenom, pool.QuoteDenom)
+	}
+
+	p.mtx.Lock()
+	p.baseIsToken0[key] = baseIsToken0
+	p.mtx.Unlock()
+
+	return baseIsToken0, nil
+}
+
+// readTicks returns the mean ticks of the pool over the TWAP window and since
+// the previous sample. When the pool doesn't keep enough observations, both are
+// the spot tick of slot0 if the pool allows it, the sample failing otherwise.
+func (p *UniswapProvider) readTicks(
+	ctx context.Context,
+	address common.Address,
+	twapWindow time.Duration,
+	allowSpotPrice bool,
+) (int64, int64, error) {
+	slot0, err := p.client.callContract(ctx, address, "slot0")
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(slot0) != len(uniswapPool.Methods["slot0"].Outputs) {
+		return 0, 0, fmt.Errorf("unexpected slot0 of %s", address)
+	}
+	spotTick := slot0[1].(*big.Int).Int64()
+	observationCardinality := slot0[3].(uint16)
+
+	twapTick, candleTick, err := p.observeTicks(ctx, address, twapWindow, observationCardinality)
+	if err == nil {
+		return twapTick, candleTick, nil
+	}
+
+	telemetry.IncrCounter(
+		1,
+		"failure",
+		"provider",
+		"type",
+		"twap",
+		"provider",
+		config.ProviderUniswap,
+	)
+	if !allowSpotPrice {
+		return 0, 0, fmt.Errorf("failed to observe pool, skipping the sample: %w", err)
+	}
+
+	p.logger.Warn().Err(err).Str("pool", address.Hex()).Msg("failed to observe pool, using spot price")
+	return spotTick, spotTick, nil
+}
+
+// observeTicks returns the mean ticks of the pool over the TWAP window and
+// since the previous sample, read with observe().
+func (p *UniswapProvider) observeTicks(
+	ctx context.Context,
+	address common.Address,
+	twapWindow time.Duration,
+	observationCardinality uint16,
+) (int64, int64, error) {
+	if observationCardinality <= 1 {
+		return 0, 0, fmt.Errorf("pool %s keeps a single observation", address)
+	}
+
+	// fails when the oldest observation is more recent than the window
+	secondsAgos := []uint32{uint32(twapWindow.Seconds()), uint32(p.sampleInterval.Seconds()), 0}
+	outputs, err := p.client.callContract(ctx, address, "observe", secondsAgos)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	tickCumulatives := outputs[0].([]*big.Int)
+	if len(tickCumulatives) != len(secondsAgos) {
+		return 0, 0, fmt.Errorf("unexpected observations of %s", address)
+	}
+
+	twapTick := uniswapMeanTick(tickCumulatives[0], tickCumulatives[2], secondsAgos[0])
+	candleTick := uniswapMeanTick(tickCumulatives[1], tickCumulatives[2], secondsAgos[1])
+	return twapTick, candleTick, nil
+}
+
+// readSwapVolume returns the base volume of the swaps of the pool since the
+// previous sample, the first sample only saving the latest block.
+func (p *UniswapProvider) readSwapVolume(
+	ctx context.Context,
+	key string,
+	address common.Address,
+	pool config.DexPool,
+	baseIsToken0 bool,
+) (math.LegacyDec, error) {
+	latestBlock, err := p.client.blockNumber(ctx)
+	if err != nil {
+		return math.LegacyDec{}, err
+	}
+
+	p.mtx.RLock()
+	lastBlock, ok := p.lastBlocks[key]
+	p.mtx.RUnlock()
+	if !ok || lastBlock >= latestBlock {
+		p.mtx.Lock()
+		p.lastBlocks[key] = latestBlock
+		p.mtx.Unlock()
+		return math.LegacyZeroDec(), nil
+	}
+
+	// the blocks older than the range are skipped after a long outage
+	fromBlock := lastBlock + 1
+	if latestBlock-fromBlock >= uniswapMaxLogBlockRange {
+		fromBlock = latestBlock - uniswapMaxLogBlockRange + 1
+	}
+
+	logs, err := p.client.swapLogs(ctx, address, fromBlock, latestBlock)
+	if err != nil {
+		return math.LegacyDec{}, err
+	}
+
+	amount := new(big.Int)
+	for _, log := range logs {
+		swap, err := uniswapPool.Unpack("Swap", log.Data)
+		if err != nil {
+			return math.LegacyDec{}, fmt.Errorf("invalid swap of %s: %w", address, err)
+		}
+
+		baseAmount := swap[1].(*big.Int)
+		if baseIsToken0 {
+			baseAmount = swap[0].(*big.Int)
+		}
+		amount.Add(amount, new(big.Int).Abs(baseAmount))
+	}
+
+	p.mtx.Lock()
+	p.lastBlocks[key] = latestBlock
+	p.mtx.Unlock()
+
+	return math.LegacyNewDecFromBigInt(amount).Quo(math.LegacyNewDec(10).Power(pool.BaseExponent)), nil
+}
+
+// SubscribeCurrencyPairs adds the pairs to the sampled pairs, every pair must
+// have a pool.
+func (p *UniswapProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	for _, cp := range cps {
+		if _, ok := p.pools[cp.String()]; !ok {
+			return fmt.Errorf("no uniswapv3 pool for %s", cp)
+		}
+	}
+
+	p.setSubscribedPairs(cps...)
+	return nil
+}
+
+// GetTickerPrices returns the tickerPrices based on the saved map.
+func (p *UniswapProvider) GetTickerPrices(pairs ...types.CurrencyPair) (map[string]TickerPrice, error) {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	tickerPrices := make(map[string]TickerPrice, len(pairs))
+	for _, cp := range pairs {
+		if ticker, ok := p.tickers[cp.String()]; ok {
+			tickerPrices[cp.String()] = ticker
+		}
+	}
+
+	return tickerPrices, nil
+}
+
+// GetCandlePrices returns the candlePrices based on the saved map.
+func (p *UniswapProvider) GetCandlePrices(pairs ...types.CurrencyPair) (map[string][]CandlePrice, error) {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	candlePrices := make(map[string][]CandlePrice, len(pairs))
+	for _, cp := range pairs {
+		if candles, ok := p.candles[cp.String()]; ok {
+			candleList := []CandlePrice{}
+			candleList = append(candleList, candles...)
+			candlePrices[cp.String()] = candleList
+		}
+	}
+
+	return candlePrices, nil
+}
+
+// setSubscribedPairs sets N currency pairs to the map of subscribed pairs.
+func (p *UniswapProvider) setSubscribedPairs(cps ...types.CurrencyPair) {
+	for _, cp := range cps {
+		p.subscribedPairs[cp.String()] = cp
+	}
+}
+
+// GetAvailablePairs returns the pairs with a pool in the config.
+// ex.: map["WETHUSDC" => {}, "WBTCUSDC" => {}].
+func (p *UniswapProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	availablePairs := make(map[string]struct{}, len(p.pools))
+	for key := range p.pools {
+		availablePairs[key] = struct{}{}
+	}
+
+	return availablePairs, nil
+}
+
+// uniswapMeanTick returns the arithmetic mean tick between two tick
+// cumulatives, rounded to negative infinity like the Uniswap OracleLibrary.
+func uniswapMeanTick(olderCumulative, newerCumulative *big.Int, seconds uint32) int64 {
+	delta := new(big.Int).Sub(newerCumulative, olderCumulative).Int64()
+	tick := delta / int64(seconds)
+	if delta < 0 && delta%int64(seconds) != 0 {
+		tick--
+	}
+
+	return tick
+}
+
+// uniswapTickPrice returns the price of the base in quote at a tick of the
+// pool, the tick being the price of the token0 in token1 in their smallest
+// units.
+func uniswapTickPrice(tick int64, pool config.DexPool, baseIsToken0 bool) (math.LegacyDec, error) {
+	if !baseIsToken0 {
+		tick = -tick
+	}
+
+	// price = 1.0001^tick * 10^(baseExponent - quoteExponent)
+	tickBase, _, err := big.ParseFloat(uniswapTickBase, 10, uniswapPricePrecision, big.ToNearestEven)
+	if err != nil {
+		return math.LegacyDec{}, err
+	}
+	price := uniswapPow(tickBase, absInt64(tick))
+	if tick < 0 {
+		price.Quo(big.NewFloat(1).SetPrec(uniswapPricePrecision), price)
+	}
+
+	scale := uniswapPow(big.NewFloat(10).SetPrec(uniswapPricePrecision), absInt64(int64(pool.BaseExponent)-int64(pool.QuoteExponent)))
+	if pool.BaseExponent >= pool.QuoteExponent {
+		price.Mul(price, scale)
+	} else {
+		price.Quo(price, scale)
+	}
+
+	dec, err := math.LegacyNewDecFromStr(price.Text('f', math.LegacyPrecision))
+	if err != nil {
+		return math.LegacyDec{}, fmt.Errorf("invalid price of pool %s at tick %d: %w", pool.Address, tick, err)
+	}
+	if !dec.IsPositive() {
+		return math.LegacyDec{}, fmt.Errorf("invalid price of pool %s at tick %d", pool.Address, tick)
+	}
+
+	return dec, nil
+}
+
+// uniswapPow returns x^n by squaring.
+func uniswapPow(x *big.Float, n uint64) *big.Float {
+	result := big.NewFloat(1).SetPrec(uniswapPricePrecision)
+	base := new(big.Float).SetPrec(uniswapPricePrecision).Set(x)
+	for ; n > 0; n >>= 1 {
+		if n&1 == 1 {
+			result.Mul(result, base)
+		}
+		base.Mul(base, base)
+	}
+
+	return result
+}
+
+func absInt64(n int64) uint64 {
+	if n < 0 {
+		return uint64(-n)
+	}
+	return uint64(n)
+}
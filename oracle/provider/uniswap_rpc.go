@@ -0,0 +1,181 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// uniswapPoolABI is the part of the Uniswap v3 pool interface read by the
+// provider.
+const uniswapPoolABI = `[
+	{"type":"function","name":"token0","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address"}]},
+	{"type":"function","name":"slot0","stateMutability":"view","inputs":[],"outputs":[
+		{"name":"sqrtPriceX96","type":"uint160"},
+		{"name":"tick","type":"int24"},
+		{"name":"observationIndex","type":"uint16"},
+		{"name":"observationCardinality","type":"uint16"},
+		{"name":"observationCardinalityNext","type":"uint16"},
+		{"name":"feeProtocol","type":"uint8"},
+		{"name":"unlocked","type":"bool"}
+	]},
+	{"type":"function","name":"observe","stateMutability":"view","inputs":[{"name":"secondsAgos","type":"uint32[]"}],"outputs":[
+		{"name":"tickCumulatives","type":"int56[]"},
+		{"name":"secondsPerLiquidityCumulativeX128s","type":"uint160[]"}
+	]},
+	{"type":"event","name":"Swap","anonymous":false,"inputs":[
+		{"name":"sender","type":"address","indexed":true},
+		{"name":"recipient","type":"address","indexed":true},
+		{"name":"amount0","type":"int256","indexed":false},
+		{"name":"amount1","type":"int256","indexed":false},
+		{"name":"sqrtPriceX96","type":"uint160","indexed":false},
+		{"name":"liquidity","type":"uint128","indexed":false},
+		{"name":"tick","type":"int24","indexed":false}
+	]}
+]`
+
+var uniswapPool = func() abi.ABI {
+	poolABI, err := abi.JSON(strings.NewReader(uniswapPoolABI))
+	if err != nil {
+		panic(err)
+	}
+	return poolABI
+}()
+
+type (
+	// uniswapRPCClient calls an Ethereum JSON-RPC endpoint over HTTP
+	uniswapRPCClient struct {
+		url    string
+		client *http.Client
+		id     atomic.Uint64
+	}
+
+	uniswapRPCRequest struct {
+		JSONRPC string        `json:"jsonrpc"`
+		ID      uint64        `json:"id"`
+		Method  string        `json:"method"`
+		Params  []interface{} `json:"params"`
+	}
+
+	uniswapRPCResponse struct {
+		ID     uint64          `json:"id"`
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+
+	uniswapCallParams struct {
+		To   common.Address `json:"to"`
+		Data hexutil.Bytes  `json:"data"`
+	}
+
+	uniswapLogFilter struct {
+		Address   common.Address  `json:"address"`
+		FromBlock hexutil.Uint64  `json:"fromBlock"`
+		ToBlock   hexutil.Uint64  `json:"toBlock"`
+		Topics    [][]common.Hash `json:"topics"`
+	}
+
+	uniswapLog struct {
+		Data hexutil.Bytes `json:"data"`
+	}
+)
+
+// call sends a JSON-RPC request and decodes its result.
+func (c *uniswapRPCClient) call(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+	bz, err := json.Marshal(uniswapRPCRequest{
+		JSONRPC: "2.0",
+		ID:      c.id.Add(1),
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(bz))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", method, resp.StatusCode)
+	}
+
+	bz, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var rpcResp uniswapRPCResponse
+	if err := json.Unmarshal(bz, &rpcResp); err != nil {
+		return fmt.Errorf("failed to unmarshal %s response: %w", method, err)
+	}
+	if rpcResp.Error != nil {
+		return fmt.Errorf("%s failed: %s (code %d)", method, rpcResp.Error.Message, rpcResp.Error.Code)
+	}
+
+	return json.Unmarshal(rpcResp.Result, result)
+}
+
+// callContract calls a view method of a pool at the latest block and returns
+// its unpacked outputs.
+func (c *uniswapRPCClient) callContract(
+	ctx context.Context,
+	address common.Address,
+	method string,
+	args ...interface{},
+) ([]interface{}, error) {
+	data, err := uniswapPool.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var result hexutil.Bytes
+	if err := c.call(ctx, &result, "eth_call", uniswapCallParams{To: address, Data: data}, "latest"); err != nil {
+		return nil, fmt.Errorf("failed to call %s of %s: %w", method, address, err)
+	}
+
+	return uniswapPool.Unpack(method, result)
+}
+
+// blockNumber returns the latest block number.
+func (c *uniswapRPCClient) blockNumber(ctx context.Context) (uint64, error) {
+	var blockNumber hexutil.Uint64
+	if err := c.call(ctx, &blockNumber, "eth_blockNumber"); err != nil {
+		return 0, err
+	}
+
+	return uint64(blockNumber), nil
+}
+
+// swapLogs returns the swaps of a pool between two blocks, both included.
+func (c *uniswapRPCClient) swapLogs(ctx context.Context, address common.Address, fromBlock, toBlock uint64) ([]uniswapLog, error) {
+	var logs []uniswapLog
+	err := c.call(ctx, &logs, "eth_getLogs", uniswapLogFilter{
+		Address:   address,
+		FromBlock: hexutil.Uint64(fromBlock),
+		ToBlock:   hexutil.Uint64(toBlock),
+		Topics:    [][]common.Hash{{uniswapPool.Events["Swap"].ID}},
+	})
+
+	return logs, err
+}
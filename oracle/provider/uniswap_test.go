@@ -0,0 +1,274 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/math"
+
+	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle/types"
+)
+
+const (
+	uniswapTestUSDC = "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+	uniswapTestWETH = "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"
+	uniswapTestPool = "0x88e6A0c2dDD26FEEb64F039a2c41296FcB3f5640"
+)
+
+// uniswapTestServer is a local stand-in of the JSON-RPC endpoint of an EVM
+// chain with a USDC/WETH pool
+type uniswapTestServer struct {
+	mtx                    sync.Mutex
+	spotTick               int64
+	observationCardinality uint16
+	tickCumulatives        []*big.Int
+	blockNumber            uint64
+	swaps                  [][2]*big.Int
+}
+
+func (s *uniswapTestServer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	var rpcReq struct {
+		ID     uint64            `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&rpcReq); err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var (
+		result interface{}
+		err    error
+	)
+	switch rpcReq.Method {
+	case "eth_call":
+		var params uniswapCallParams
+		if err := json.Unmarshal(rpcReq.Params[0], &params); err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var bz []byte
+		bz, err = s.call(params.Data)
+		result = hexutil.Bytes(bz)
+
+	case "eth_blockNumber":
+		result = hexutil.Uint64(s.blockNumber)
+
+	case "eth_getLogs":
+		logs := []uniswapLog{}
+		for _, swap := range s.swaps {
+			bz, err := uniswapPool.Events["Swap"].Inputs.NonIndexed().Pack(swap[0], swap[1], big.NewInt(1), big.NewInt(1), big.NewInt(0))
+			if err != nil {
+				rw.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			logs = append(logs, uniswapLog{Data: bz})
+		}
+		s.swaps = nil
+		result = logs
+	}
+
+	resp := map[string]interface{}{"jsonrpc": "2.0", "id": rpcReq.ID, "result": result}
+	if err != nil {
+		resp = map[string]interface{}{"jsonrpc": "2.0", "id": rpcReq.ID, "error": map[string]interface{}{"code": 3, "message": err.Error()}}
+	}
+	_ = json.NewEncoder(rw).Encode(resp)
+}
+
+func (s *uniswapTestServer) call(data []byte) ([]byte, error) {
+	method, err := uniswapPool.MethodById(data[:4])
+	if err != nil {
+		return nil, err
+	}
+
+	switch method.Name {
+	case "token0":
+		return method.Outputs.Pack(common.HexToAddress(uniswapTestUSDC))
+
+	case "slot0":
+		return method.Outputs.Pack(big.NewInt(1), big.NewInt(s.spotTick), uint16(0), s.observationCardinality, s.observationCardinality, uint8(0), true)
+
+	case "observe":
+		if s.tickCumulatives == nil {
+			return nil, errExecutionReverted
+		}
+		return method.Outputs.Pack(s.tickCumulatives, []*big.Int{big.NewInt(0), big.NewInt(0), big.NewInt(0)})
+	}
+
+	return nil, errExecutionReverted
+}
+
+var errExecutionReverted = errors.New("execution reverted: OLD")
+
+func newTestUniswapProvider(t *testing.T, url string) *UniswapProvider {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	p, err := NewUniswapProvider(
+		ctx,
+		zerolog.Nop(),
+		config.ProviderEndpoint{Name: config.ProviderUniswap, JSONRPC: url},
+		[]config.DexPool{{
+			Provider:      config.ProviderUniswap,
+			Base:          "WETH",
+			Quote:         "USDC",
+			Address:       uniswapTestPool,
+			BaseDenom:     uniswapTestWETH,
+			QuoteDenom:    uniswapTestUSDC,
+			BaseExponent:  18,
+			QuoteExponent: 6,
+		}},
+	)
+	require.NoError(t, err)
+
+	return p
+}
+
+func TestUniswapProvider_SamplePool(t *testing.T) {
+	s := &uniswapTestServer{
+		spotTick:               200000,
+		observationCardinality: 100,
+		blockNumber:            1000,
+	}
+	// the mean tick is 200311 over the 5m window and 200000 over the last 30s
+	s.tickCumulatives = []*big.Int{
+		big.NewInt(1000),
+		big.NewInt(1000 + 300*200311 - 30*200000),
+		big.NewInt(1000 + 300*200311),
+	}
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	p := newTestUniswapProvider(t, server.URL)
+	wethUSDC := types.CurrencyPair{Base: "WETH", Quote: "USDC"}
+
+	require.NoError(t, p.samplePool(context.TODO(), wethUSDC))
+
+	prices, err := p.GetTickerPrices(wethUSDC)
+	require.NoError(t, err)
+	require.InDelta(t, 2000, prices["WETHUSDC"].Price.MustFloat64(), 1)
+	require.Equal(t, math.LegacyZeroDec(), prices["WETHUSDC"].Volume)
+
+	candles, err := p.GetCandlePrices(wethUSDC)
+	require.NoError(t, err)
+	require.Len(t, candles["WETHUSDC"], 1)
+	require.InDelta(t, 2063.2, candles["WETHUSDC"][0].Price.MustFloat64(), 1)
+
+	// the next sample has the base volume of the swaps since the first one
+	s.mtx.Lock()
+	s.blockNumber = 1010
+	s.swaps = [][2]*big.Int{
+		{big.NewInt(3000_000000), new(big.Int).Mul(big.NewInt(-15), big.NewInt(1e17))},
+		{big.NewInt(-1000_000000), new(big.Int).Mul(big.NewInt(5), big.NewInt(1e17))},
+	}
+	s.mtx.Unlock()
+	require.NoError(t, p.samplePool(context.TODO(), wethUSDC))
+
+	prices, err = p.GetTickerPrices(wethUSDC)
+	require.NoError(t, err)
+	require.Equal(t, math.LegacyNewDec(2), prices["WETHUSDC"].Volume)
+}
+
+func TestUniswapProvider_SpotPriceFallback(t *testing.T) {
+	s := &uniswapTestServer{spotTick: 200311, observationCardinality: 1, blockNumber: 1000}
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	p := newTestUniswapProvider(t, server.URL)
+	wethUSDC := types.CurrencyPair{Base: "WETH", Quote: "USDC"}
+
+	// a single observation can't be observed over the window, the sample is
+	// skipped unless the pool allows the spot price
+	_, _, err := p.readTicks(context.TODO(), common.HexToAddress(uniswapTestPool), 5*time.Minute, false)
+	require.Error(t, err)
+	require.Error(t, p.samplePool(context.TODO(), wethUSDC))
+
+	twapTick, candleTick, err := p.readTicks(context.TODO(), common.HexToAddress(uniswapTestPool), 5*time.Minute, true)
+	require.NoError(t, err)
+	require.Equal(t, int64(200311), twapTick)
+	require.Equal(t, int64(200311), candleTick)
+
+	// the observations don't cover the window
+	s.mtx.Lock()
+	s.observationCardinality = 10
+	s.mtx.Unlock()
+
+	pool := p.pools["WETHUSDC"]
+	pool.AllowSpotPrice = true
+	p.pools["WETHUSDC"] = pool
+	require.NoError(t, p.samplePool(context.TODO(), wethUSDC))
+
+	prices, err := p.GetTickerPrices(wethUSDC)
+	require.NoError(t, err)
+	require.InDelta(t, 2000, prices["WETHUSDC"].Price.MustFloat64(), 1)
+}
+
+func TestUniswapProvider_SubscribeCurrencyPairs(t *testing.T) {
+	p := newTestUniswapProvider(t, "http://127.0.0.1:1")
+
+	err := p.SubscribeCurrencyPairs(types.CurrencyPair{Base: "WBTC", Quote: "USDC"})
+	require.EqualError(t, err, "no uniswapv3 pool for WBTCUSDC")
+
+	pairs, err := p.GetAvailablePairs()
+	require.NoError(t, err)
+	require.Equal(t, map[string]struct{}{"WETHUSDC": {}}, pairs)
+}
+
+func TestUniswapTickPrice(t *testing.T) {
+	pool := config.DexPool{BaseExponent: 18, QuoteExponent: 6}
+
+	price, err := uniswapTickPrice(0, pool, true)
+	require.NoError(t, err)
+	require.Equal(t, math.LegacyNewDec(1_000_000_000_000), price)
+
+	// 1.0001^23027 is close to 10
+	price, err = uniswapTickPrice(23027, config.DexPool{}, true)
+	require.NoError(t, err)
+	require.InDelta(t, 10, price.MustFloat64(), 0.0001)
+
+	price, err = uniswapTickPrice(23027, config.DexPool{}, false)
+	require.NoError(t, err)
+	require.InDelta(t, 0.1, price.MustFloat64(), 0.000001)
+
+	price, err = uniswapTickPrice(-23027, config.DexPool{BaseExponent: 6, QuoteExponent: 8}, true)
+	require.NoError(t, err)
+	require.InDelta(t, 0.001, price.MustFloat64(), 0.000001)
+
+	// the price is below the precision of a LegacyDec
+	_, err = uniswapTickPrice(-887272, config.DexPool{}, true)
+	require.Error(t, err)
+}
+
+func TestUniswapMeanTick(t *testing.T) {
+	require.Equal(t, int64(3), uniswapMeanTick(big.NewInt(0), big.NewInt(7), 2))
+	require.Equal(t, int64(-4), uniswapMeanTick(big.NewInt(0), big.NewInt(-7), 2))
+	require.Equal(t, int64(-3), uniswapMeanTick(big.NewInt(10), big.NewInt(4), 2))
+}
+
+func TestUniswapRPCClient_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = rw.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"header not found"}}`))
+	}))
+	defer server.Close()
+
+	client := &uniswapRPCClient{url: server.URL, client: server.Client()}
+	_, err := client.blockNumber(context.TODO())
+	require.EqualError(t, err, "eth_blockNumber failed: header not found (code -32000)")
+}
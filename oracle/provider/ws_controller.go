@@ -31,10 +31,12 @@ type (
 		websocketCancelFunc context.CancelFunc
 		providerName        string
 		websocketURL        url.URL
+		websocketURLFunc    func() (url.URL, error)
 		subscriptionMsgs    []interface{}
 		messageHandler      MessageHandler
 		pingDuration        time.Duration
 		pingMessageType     uint
+		pingMessage         []byte
 		logger              zerolog.Logger
 
 		mtx              sync.Mutex
@@ -64,6 +66,7 @@ func NewWebsocketController(
 		messageHandler:   messageHandler,
 		pingDuration:     pingDuration,
 		pingMessageType:  pingMessageType,
+		pingMessage:      ping,
 		logger:           logger,
 		dialer:           websocket.DefaultDialer,
 	}
@@ -101,8 +104,29 @@ func (wsc *WebsocketController) Start() {
 	}
 }
 
+// SetWebsocketURLFunc sets a function resolving the websocket url before each
+// connection, for the providers which negotiate a token to connect. It must
+// be called before Start.
+func (wsc *WebsocketController) SetWebsocketURLFunc(websocketURLFunc func() (url.URL, error)) {
+	wsc.websocketURLFunc = websocketURLFunc
+}
+
+// SetPingMessage sets the payload of the pings, for the providers expecting
+// another payload than "ping". It must be called before Start.
+func (wsc *WebsocketController) SetPingMessage(pingMessage []byte) {
+	wsc.pingMessage = pingMessage
+}
+
 // connect dials the websocket and sets the client to the established connection
 func (wsc *WebsocketController) connect() error {
+	if wsc.websocketURLFunc != nil {
+		websocketURL, err := wsc.websocketURLFunc()
+		if err != nil {
+			return fmt.Errorf("failed to resolve WS url for %s: %w", wsc.providerName, err)
+		}
+		wsc.websocketURL = websocketURL
+	}
+
 	wsc.mtx.Lock()
 	defer wsc.mtx.Unlock()
 
@@ -203,7 +227,7 @@ func (wsc *WebsocketController) ping() error {
 		return fmt.Errorf("unable to ping closed connection")
 	}
 
-	err := wsc.client.WriteMessage(int(wsc.pingMessageType), ping)
+	err := wsc.client.WriteMessage(int(wsc.pingMessageType), wsc.pingMessage)
 	if err != nil {
 		wsc.logger.Err(fmt.Errorf("failed to send WS message for %s: %w", wsc.providerName, err)).Send()
 	}
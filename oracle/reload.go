@@ -0,0 +1,150 @@
+package oracle
+
+import (
+	"context"
+	"reflect"
+
+	sdkmath "cosmossdk.io/math"
+
+	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle/types"
+)
+
+// ReloadConfig holds the oracle settings which can change without a restart
+type ReloadConfig struct {
+	CurrencyPairs    []config.CurrencyPair
+	Deviations       map[string]sdkmath.LegacyDec
+	Endpoints        map[string]config.ProviderEndpoint
+	GenericProviders map[string]config.GenericProvider
+	Plugins          map[string]config.Plugin
+	DexPools         map[string][]config.DexPool
+	FXProviders      map[string]config.FXProvider
+	OrderBooks       map[string]OrderBookSettings
+	CandleFreshness  CandleFreshness
+	CircuitBreakers  map[string]CircuitBreaker
+}
+
+// Reload queues a new configuration, applied by the oracle between ticks so
+// the providers are never changed during a tick. A configuration still
+// pending is replaced by the newest one.
+func (o *Oracle) Reload(cfg ReloadConfig) {
+	for {
+		select {
+		case o.reloads <- cfg:
+			return
+
+		default:
+			// drop the pending configuration
+			select {
+			case <-o.reloads:
+			default:
+			}
+		}
+	}
+}
+
+// applyReload diffs the new configuration with the current one. The existing
+// providers are subscribed to their new pairs, keeping their candle history,
+// while the providers removed or with a new endpoint or config are closed. The pairs,
+// chain denoms and deviations are swapped at once, the new providers being
+// created on the next tick.
+func (o *Oracle) applyReload(ctx context.Context, cfg ReloadConfig) {
+	chainDenomMapping, providerPairs := createMappingsFromPairs(cfg.CurrencyPairs)
+	addFXPairs(providerPairs, cfg.CurrencyPairs, cfg.FXProviders)
+
+	unsupportedPairs := make(map[string][]string)
+	for providerName, priceProvider := range o.priceProviders {
+		pairs, ok := providerPairs[providerName]
+		if !ok || o.endpoints[providerName] != cfg.Endpoints[providerName] ||
+			!reflect.DeepEqual(o.genericProviders[providerName], cfg.GenericProviders[providerName]) ||
+			!reflect.DeepEqual(o.plugins[providerName], cfg.Plugins[providerName]) ||
+			!reflect.DeepEqual(o.dexPools[providerName], cfg.DexPools[providerName]) ||
+			!reflect.DeepEqual(o.fxProviders[providerName], cfg.FXProviders[providerName]) {
+			o.closeProvider(providerName)
+			continue
+		}
+
+		// keep dropping the pairs the provider doesn't support
+		var droppedPairs []types.CurrencyPair
+		for _, pair := range pairs {
+			if containsString(o.pairValidation.Unsupported[providerName], pair.String()) {
+				droppedPairs = append(droppedPairs, pair)
+				unsupportedPairs[providerName] = append(unsupportedPairs[providerName], pair.String())
+			}
+		}
+		pairs = removePairs(pairs, droppedPairs)
+		providerPairs[providerName] = pairs
+
+		// subscribe to the pairs the provider didn't have, validated in the
+		// background
+		newPairs := removePairs(pairs, o.providerPairs[providerName])
+		if len(newPairs) == 0 {
+			continue
+		}
+		if err := priceProvider.SubscribeCurrencyPairs(newPairs...); err != nil {
+			o.logger.Error().Err(err).Str("provider", providerName).Msg("failed to subscribe to the new pairs, recreating provider")
+			o.closeProvider(providerName)
+			continue
+		}
+		o.logger.Info().Str("provider", providerName).Int("pairs", len(newPairs)).Msg("subscribed to the new pairs")
+		o.validateProviderPairs(ctx, providerName, priceProvider, newPairs)
+	}
+
+	// stop retrying the failed providers which were removed
+	for providerName := range o.supervisor.GetStatuses() {
+		if _, ok := providerPairs[providerName]; !ok {
+			o.supervisor.Remove(providerName)
+		}
+	}
+
+	o.mtx.Lock()
+	o.providerPairs = providerPairs
+	o.chainDenomMapping = chainDenomMapping
+	o.deviations = cfg.Deviations
+	o.aggregations = getAggregations(cfg.CurrencyPairs)
+	o.endpoints = cfg.Endpoints
+	o.genericProviders = cfg.GenericProviders
+	o.plugins = cfg.Plugins
+	o.dexPools = cfg.DexPools
+	o.fxProviders = cfg.FXProviders
+	o.orderBooks = cfg.OrderBooks
+	o.candleFreshness = cfg.CandleFreshness
+	o.circuitBreakers = cfg.CircuitBreakers
+	o.pairValidation = newPairValidation()
+	o.pairValidation.Unsupported = unsupportedPairs
+	o.pairValidation.UnderProvided = o.basesBelowMinimumProviders()
+	o.mtx.Unlock()
+
+	o.logger.Info().
+		Int("currency_pairs", len(cfg.CurrencyPairs)).
+		Int("providers", len(providerPairs)).
+		Msg("reloaded oracle configuration")
+}
+
+// ApplyReloads applies the reloaded configurations until the context is
+// done. It is used instead of Start when the oracle doesn't vote.
+func (o *Oracle) ApplyReloads(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case reloadConfig := <-o.reloads:
+			o.applyReload(ctx, reloadConfig)
+
+		case result := <-o.pairValidations:
+			o.applyPairValidation(result)
+		}
+	}
+}
+
+// closeProvider stops the provider connections and removes it, so it is
+// created again if it is still configured
+func (o *Oracle) closeProvider(providerName string) {
+	if cancel, ok := o.providerCancels[providerName]; ok {
+		cancel()
+		delete(o.providerCancels, providerName)
+	}
+	delete(o.priceProviders, providerName)
+	o.supervisor.Remove(providerName)
+}
@@ -0,0 +1,111 @@
+package oracle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/math"
+
+	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle/client"
+	"github.com/kiichain/price-feeder/oracle/provider"
+	"github.com/kiichain/price-feeder/oracle/types"
+)
+
+type subscribingProvider struct {
+	availablePairsProvider
+	subscribed *[]types.CurrencyPair
+}
+
+func (p subscribingProvider) SubscribeCurrencyPairs(pairs ...types.CurrencyPair) error {
+	*p.subscribed = append(*p.subscribed, pairs...)
+	return nil
+}
+
+func TestApplyReload(t *testing.T) {
+	providers := []string{config.ProviderBinance, config.ProviderKraken}
+	o := New(zerolog.Nop(), client.OracleClient{}, Options{
+		ReloadConfig: ReloadConfig{
+			CurrencyPairs: []config.CurrencyPair{
+				{Base: "ATOM", ChainDenom: "uatom", Quote: "USDT", Providers: providers},
+			},
+			Deviations: map[string]math.LegacyDec{"ATOM": math.LegacyMustNewDecFromStr("1")},
+			Endpoints:  make(map[string]config.ProviderEndpoint),
+		},
+		ProviderTimeout: time.Second,
+	})
+
+	var subscribed []types.CurrencyPair
+	availablePairs := map[string]struct{}{"ATOMUSDT": {}, "BTCUSDT": {}}
+	o.priceProviders = map[string]provider.Provider{
+		config.ProviderBinance: subscribingProvider{
+			availablePairsProvider: availablePairsProvider{availablePairs: availablePairs},
+			subscribed:             &subscribed,
+		},
+		config.ProviderKraken: availablePairsProvider{availablePairs: availablePairs},
+	}
+
+	// BTC is added to binance, and kraken is removed
+	canceled := false
+	o.providerCancels[config.ProviderKraken] = func() { canceled = true }
+
+	o.applyReload(context.Background(), ReloadConfig{
+		CurrencyPairs: []config.CurrencyPair{
+			{Base: "ATOM", ChainDenom: "uatom", Quote: "USDT", Providers: []string{config.ProviderBinance}},
+			{Base: "BTC", ChainDenom: "ubtc", Quote: "USDT", Providers: []string{config.ProviderBinance}},
+		},
+		Deviations: map[string]math.LegacyDec{"ATOM": math.LegacyMustNewDecFromStr("2")},
+		Endpoints:  make(map[string]config.ProviderEndpoint),
+	})
+
+	require.Equal(t, []types.CurrencyPair{{Base: "BTC", Quote: "USDT"}}, subscribed)
+	o.applyPairValidation(<-o.pairValidations)
+	require.Len(t, o.providerPairs[config.ProviderBinance], 2)
+	require.Equal(t, "ubtc", o.chainDenomMapping["BTC"])
+	require.Equal(t, math.LegacyMustNewDecFromStr("2"), o.deviations["ATOM"])
+
+	require.True(t, canceled)
+	require.NotContains(t, o.priceProviders, config.ProviderKraken)
+	require.NotContains(t, o.providerPairs, config.ProviderKraken)
+	require.Equal(t, []string{"ATOM", "BTC"}, o.GetPairValidation().UnderProvided)
+}
+
+func TestApplyReloads(t *testing.T) {
+	o := &Oracle{
+		logger:          zerolog.Nop(),
+		reloads:         make(chan ReloadConfig, 1),
+		pairValidations: make(chan pairValidationResult),
+		supervisor:      NewProviderSupervisor(zerolog.Nop(), defaultInitialBackoff, defaultMaxBackoff),
+		pairValidation:  newPairValidation(),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- o.ApplyReloads(ctx) }()
+
+	// the reload is applied without the oracle running
+	o.Reload(ReloadConfig{Deviations: map[string]math.LegacyDec{"ATOM": math.LegacyOneDec()}})
+	require.Eventually(t, func() bool {
+		o.mtx.RLock()
+		defer o.mtx.RUnlock()
+		return o.deviations["ATOM"].Equal(math.LegacyOneDec())
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestReloadKeepsNewest(t *testing.T) {
+	o := &Oracle{reloads: make(chan ReloadConfig, 1)}
+
+	o.Reload(ReloadConfig{Deviations: map[string]math.LegacyDec{"ATOM": math.LegacyOneDec()}})
+	o.Reload(ReloadConfig{Deviations: map[string]math.LegacyDec{"BTC": math.LegacyOneDec()}})
+
+	reloadConfig := <-o.reloads
+	require.Contains(t, reloadConfig.Deviations, "BTC")
+	require.Len(t, o.reloads, 0)
+}
@@ -0,0 +1,60 @@
+package oracle
+
+import (
+	"time"
+
+	oracletypes "github.com/kiichain/kiichain/v3/x/oracle/types"
+)
+
+const (
+	// maxShadowVotes is the amount of shadow votes kept in memory
+	maxShadowVotes = 100
+)
+
+// ShadowVote defines a vote computed by the oracle while running in shadow
+// mode. The vote is recorded instead of being broadcasted to the chain.
+type ShadowVote struct {
+	BlockHeight   int64     `json:"block_height"`
+	VotePeriod    uint64    `json:"vote_period"`
+	ExchangeRates string    `json:"exchange_rates"`
+	Feeder        string    `json:"feeder"`
+	Validator     string    `json:"validator"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// recordShadowVote stores the vote message on the shadow votes history,
+// dropping the oldest vote when the history is full
+func (o *Oracle) recordShadowVote(
+	blockHeight int64,
+	votePeriod float64,
+	voteMsg *oracletypes.MsgAggregateExchangeRateVote,
+) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+
+	o.shadowVotes = append(o.shadowVotes, ShadowVote{
+		BlockHeight:   blockHeight,
+		VotePeriod:    uint64(votePeriod),
+		ExchangeRates: voteMsg.ExchangeRates,
+		Feeder:        voteMsg.Feeder,
+		Validator:     voteMsg.Validator,
+		Timestamp:     time.Now().UTC(),
+	})
+
+	// keep only the latest votes
+	if len(o.shadowVotes) > maxShadowVotes {
+		o.shadowVotes = o.shadowVotes[len(o.shadowVotes)-maxShadowVotes:]
+	}
+}
+
+// GetShadowVotes returns a copy of the votes recorded while running in
+// shadow mode, ordered from the oldest to the newest.
+func (o *Oracle) GetShadowVotes() []ShadowVote {
+	o.mtx.RLock()
+	defer o.mtx.RUnlock()
+
+	votes := make([]ShadowVote, len(o.shadowVotes))
+	copy(votes, o.shadowVotes)
+
+	return votes
+}
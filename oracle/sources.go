@@ -0,0 +1,277 @@
+package oracle
+
+import (
+	sdkmath "cosmossdk.io/math"
+
+	"github.com/kiichain/price-feeder/oracle/provider"
+	"github.com/kiichain/price-feeder/oracle/types"
+)
+
+// Computation methods used to get the final price of an asset
+const (
+	ComputationMethodTVWAP          = "tvwap"           // time volume weighted average of the candles
+	ComputationMethodVWAP           = "vwap"            // volume weighted average of the tickers
+	ComputationMethodMedian         = "median"          // median of the providers
+	ComputationMethodWeightedMedian = "weighted_median" // volume weighted median of the providers
+	ComputationMethodOrderBookMid   = "orderbook_mid"   // depth weighted average of the order book mid prices
+)
+
+// PriceSource defines the price of an asset reported by a provider and how
+// it was used on the computation of the final price.
+type PriceSource struct {
+	Quote    string            `json:"quote"`
+	RawPrice sdkmath.LegacyDec `json:"raw_price"` // as reported by the provider, on the quote currency
+	Volume   sdkmath.LegacyDec `json:"volume"`
+	Price    sdkmath.LegacyDec `json:"price"`                     // converted to USD
+	Filtered bool              `json:"filtered"`                  // dropped by the deviation filter
+	Weight   sdkmath.LegacyDec `json:"weight"`                    // share of the provider on the final price
+	Path     []string          `json:"conversion_path,omitempty"` // pairs converting the quote to USD
+}
+
+// AssetBreakdown defines how the final price of an asset was computed.
+type AssetBreakdown struct {
+	Price   sdkmath.LegacyDec      `json:"price"`
+	Method  string                 `json:"method"`
+	Sources map[string]PriceSource `json:"sources"` // by provider
+}
+
+// PriceBreakdown defines how the final prices were computed, by base.
+type PriceBreakdown map[string]AssetBreakdown
+
+// rawSource defines the price of an asset reported by a provider, before
+// the conversion to USD
+type rawSource struct {
+	quote  string
+	price  sdkmath.LegacyDec
+	volume sdkmath.LegacyDec
+	path   []string
+}
+
+// rawSources defines the raw sources by provider and base
+type rawSources map[string]map[string]rawSource
+
+// add stores the raw source of a provider for the given base
+func (r rawSources) add(providerName, base string, source rawSource) {
+	if _, ok := r[providerName]; !ok {
+		r[providerName] = make(map[string]rawSource)
+	}
+	r[providerName][base] = source
+}
+
+// setConversions stores the conversion path of the sources with a non-USD
+// quote
+func (r rawSources) setConversions(conversions map[string]Conversion) {
+	for _, sources := range r {
+		for base, source := range sources {
+			if conversion, ok := conversions[source.quote]; ok {
+				source.path = conversion.Path
+				sources[base] = source
+			}
+		}
+	}
+}
+
+// getPairQuote returns the quote of the provider pair with the given base
+func getPairQuote(pairs []types.CurrencyPair, base string) string {
+	for _, pair := range pairs {
+		if pair.Base == base {
+			return pair.Quote
+		}
+	}
+	return ""
+}
+
+// getRawCandleSources keeps the latest price and the total volume of the
+// candles of each provider, before they are converted to USD
+func getRawCandleSources(
+	candles provider.AggregatedProviderCandles,
+	providerPairs map[string][]types.CurrencyPair,
+) rawSources {
+	sources := make(rawSources)
+
+	for providerName, providerCandles := range candles {
+		for base, cp := range providerCandles {
+			if len(cp) == 0 {
+				continue
+			}
+
+			latest := cp[0]
+			volume := sdkmath.LegacyZeroDec()
+			for _, candle := range cp {
+				if candle.TimeStamp > latest.TimeStamp {
+					latest = candle
+				}
+				volume = volume.Add(candle.Volume)
+			}
+
+			sources.add(providerName, base, rawSource{
+				quote:  getPairQuote(providerPairs[providerName], base),
+				price:  latest.Price,
+				volume: volume,
+			})
+		}
+	}
+
+	return sources
+}
+
+// getRawTickerSources keeps the tickers of each provider, before they are
+// converted to USD
+func getRawTickerSources(
+	tickers provider.AggregatedProviderPrices,
+	providerPairs map[string][]types.CurrencyPair,
+) rawSources {
+	sources := make(rawSources)
+
+	for providerName, providerTickers := range tickers {
+		for base, ticker := range providerTickers {
+			sources.add(providerName, base, rawSource{
+				quote:  getPairQuote(providerPairs[providerName], base),
+				price:  ticker.Price,
+				volume: ticker.Volume,
+			})
+		}
+	}
+
+	return sources
+}
+
+// addCandleSources stores how the candles of each provider were used on the
+// price of the given bases
+func (b PriceBreakdown) addCandleSources(
+	raw rawSources,
+	converted provider.AggregatedProviderCandles,
+	filtered provider.AggregatedProviderCandles,
+	prices map[string]sdkmath.LegacyDec,
+	bases []string,
+	method string,
+) {
+	now, timePeriod := tvwapTimeWindow()
+
+	for _, base := range bases {
+		asset := AssetBreakdown{
+			Price:   prices[base],
+			Method:  method,
+			Sources: make(map[string]PriceSource),
+		}
+
+		// compute the time weighted volume of each provider
+		weightedPrices := make(map[string]sdkmath.LegacyDec)
+		volumes := make(map[string]sdkmath.LegacyDec)
+		totalVolume := sdkmath.LegacyZeroDec()
+		for providerName, providerCandles := range converted {
+			cp, ok := providerCandles[base]
+			if !ok {
+				continue
+			}
+
+			weightedPrices[providerName], volumes[providerName] = tvwapSums(cp, now, timePeriod)
+			if _, ok := filtered[providerName][base]; ok {
+				totalVolume = totalVolume.Add(volumes[providerName])
+			}
+		}
+
+		for providerName, volume := range volumes {
+			source := PriceSource{
+				Quote:    raw[providerName][base].quote,
+				RawPrice: raw[providerName][base].price,
+				Volume:   raw[providerName][base].volume,
+				Price:    sdkmath.LegacyZeroDec(),
+				Weight:   sdkmath.LegacyZeroDec(),
+				Path:     raw[providerName][base].path,
+			}
+
+			// the time weighted volume may be negative, as on ComputeTVWAP
+			if !volume.IsZero() {
+				source.Price = weightedPrices[providerName].Quo(volume)
+			}
+
+			_, ok := filtered[providerName][base]
+			source.Filtered = !ok
+			if ok && !totalVolume.IsZero() {
+				source.Weight = volume.Quo(totalVolume)
+			}
+
+			asset.Sources[providerName] = source
+		}
+
+		b[base] = asset
+	}
+}
+
+// addTickerSources stores how the tickers of each provider were used on the
+// price of the given bases
+func (b PriceBreakdown) addTickerSources(
+	raw rawSources,
+	converted provider.AggregatedProviderPrices,
+	filtered provider.AggregatedProviderPrices,
+	prices map[string]sdkmath.LegacyDec,
+	bases []string,
+	method string,
+) {
+	for _, base := range bases {
+		asset := AssetBreakdown{
+			Price:   prices[base],
+			Method:  method,
+			Sources: make(map[string]PriceSource),
+		}
+
+		// sum the volume of the providers used on the vwap
+		totalVolume := sdkmath.LegacyZeroDec()
+		for _, providerTickers := range filtered {
+			if ticker, ok := providerTickers[base]; ok {
+				totalVolume = totalVolume.Add(ticker.Volume)
+			}
+		}
+
+		for providerName, providerTickers := range converted {
+			ticker, ok := providerTickers[base]
+			if !ok {
+				continue
+			}
+
+			source := PriceSource{
+				Quote:    raw[providerName][base].quote,
+				RawPrice: raw[providerName][base].price,
+				Volume:   ticker.Volume,
+				Price:    ticker.Price,
+				Weight:   sdkmath.LegacyZeroDec(),
+				Path:     raw[providerName][base].path,
+			}
+
+			_, ok = filtered[providerName][base]
+			source.Filtered = !ok
+			if ok && totalVolume.IsPositive() {
+				source.Weight = ticker.Volume.Quo(totalVolume)
+			}
+
+			asset.Sources[providerName] = source
+		}
+
+		b[base] = asset
+	}
+}
+
+// GetPriceBreakdown returns how the current prices were computed.
+func (o *Oracle) GetPriceBreakdown() PriceBreakdown {
+	o.mtx.RLock()
+	defer o.mtx.RUnlock()
+
+	breakdown := make(PriceBreakdown, len(o.priceBreakdown))
+	for base, asset := range o.priceBreakdown {
+		sources := make(map[string]PriceSource, len(asset.Sources))
+		for providerName, source := range asset.Sources {
+			sources[providerName] = source
+		}
+		asset.Sources = sources
+		breakdown[base] = asset
+	}
+
+	return breakdown
+}
+
+// GetAssetBreakdown returns how the current price of the base was computed.
+func (o *Oracle) GetAssetBreakdown(base string) (AssetBreakdown, bool) {
+	asset, ok := o.GetPriceBreakdown()[base]
+	return asset, ok
+}
@@ -0,0 +1,147 @@
+package oracle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/math"
+
+	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle/provider"
+	"github.com/kiichain/price-feeder/oracle/types"
+)
+
+func TestGetComputedPricesWithBreakdownTickers(t *testing.T) {
+	pair := types.CurrencyPair{Base: "ATOM", Quote: "USD"}
+	providerNames := []string{
+		config.ProviderBinance,
+		config.ProviderKraken,
+		config.ProviderOkx,
+		config.ProviderGate,
+		config.ProviderMexc,
+	}
+
+	providerPrices := make(provider.AggregatedProviderPrices)
+	providerPairs := make(map[string][]types.CurrencyPair)
+	for _, providerName := range providerNames {
+		price := math.LegacyMustNewDecFromStr("10")
+		// the last provider deviates from the others
+		if providerName == config.ProviderMexc {
+			price = math.LegacyMustNewDecFromStr("20")
+		}
+
+		providerPrices[providerName] = map[string]provider.TickerPrice{
+			pair.Base: {Price: price, Volume: math.LegacyOneDec()},
+		}
+		providerPairs[providerName] = []types.CurrencyPair{pair}
+	}
+
+	prices, breakdown, err := GetComputedPricesWithBreakdown(
+		zerolog.Nop(),
+		make(provider.AggregatedProviderCandles),
+		providerPrices,
+		providerPairs,
+		make(map[string]math.LegacyDec),
+		nil,
+		CandleFreshness{},
+		map[string]struct{}{pair.Base: {}},
+	)
+	require.NoError(t, err)
+	require.Equal(t, math.LegacyMustNewDecFromStr("10"), prices[pair.Base])
+
+	asset, ok := breakdown[pair.Base]
+	require.True(t, ok)
+	require.Equal(t, ComputationMethodVWAP, asset.Method)
+	require.Equal(t, prices[pair.Base], asset.Price)
+	require.Len(t, asset.Sources, len(providerNames))
+
+	for _, providerName := range providerNames {
+		source := asset.Sources[providerName]
+		require.Equal(t, pair.Quote, source.Quote, providerName)
+		require.Equal(t, source.RawPrice, source.Price, providerName)
+
+		if providerName == config.ProviderMexc {
+			require.True(t, source.Filtered, providerName)
+			require.True(t, source.Weight.IsZero(), providerName)
+			continue
+		}
+
+		require.False(t, source.Filtered, providerName)
+		require.Equal(t, math.LegacyMustNewDecFromStr("0.25"), source.Weight, providerName)
+	}
+}
+
+func TestGetComputedPricesWithBreakdownCandles(t *testing.T) {
+	pair := types.CurrencyPair{Base: "ATOM", Quote: "USD"}
+	atomPrice := math.LegacyMustNewDecFromStr("29.93")
+	atomVolume := math.LegacyMustNewDecFromStr("894123.00")
+
+	providerCandles := provider.AggregatedProviderCandles{
+		config.ProviderBinance: {
+			pair.Base: {
+				{
+					Price:     atomPrice,
+					Volume:    atomVolume,
+					TimeStamp: provider.PastUnixTime(1 * time.Minute),
+				},
+			},
+		},
+	}
+
+	prices, breakdown, err := GetComputedPricesWithBreakdown(
+		zerolog.Nop(),
+		providerCandles,
+		make(provider.AggregatedProviderPrices),
+		map[string][]types.CurrencyPair{config.ProviderBinance: {pair}},
+		make(map[string]math.LegacyDec),
+		nil,
+		CandleFreshness{},
+		map[string]struct{}{pair.Base: {}},
+	)
+	require.NoError(t, err)
+	require.Equal(t, atomPrice, prices[pair.Base])
+
+	asset, ok := breakdown[pair.Base]
+	require.True(t, ok)
+	require.Equal(t, ComputationMethodTVWAP, asset.Method)
+
+	source := asset.Sources[config.ProviderBinance]
+	require.Equal(t, atomPrice, source.RawPrice)
+	require.Equal(t, atomVolume, source.Volume)
+	require.Equal(t, atomPrice, source.Price)
+	require.False(t, source.Filtered)
+	require.Equal(t, math.LegacyOneDec(), source.Weight)
+}
+
+func TestGetComputedPricesWithBreakdownConversionPath(t *testing.T) {
+	providerPrices := provider.AggregatedProviderPrices{
+		config.ProviderKraken:  {"ATOM": {Price: math.LegacyMustNewDecFromStr("0.004"), Volume: math.LegacyOneDec()}},
+		config.ProviderBinance: {"ETH": {Price: math.LegacyNewDec(2500), Volume: math.LegacyOneDec()}},
+		config.ProviderOkx:     {"USDT": {Price: math.LegacyOneDec(), Volume: math.LegacyOneDec()}},
+	}
+	providerPairs := map[string][]types.CurrencyPair{
+		config.ProviderKraken:  {{Base: "ATOM", Quote: "ETH"}},
+		config.ProviderBinance: {{Base: "ETH", Quote: "USDT"}},
+		config.ProviderOkx:     {{Base: "USDT", Quote: "USD"}},
+	}
+
+	prices, breakdown, err := GetComputedPricesWithBreakdown(
+		zerolog.Nop(),
+		make(provider.AggregatedProviderCandles),
+		providerPrices,
+		providerPairs,
+		make(map[string]math.LegacyDec),
+		nil,
+		CandleFreshness{},
+		map[string]struct{}{"ATOM": {}},
+	)
+	require.NoError(t, err)
+	require.Equal(t, math.LegacyNewDec(10), prices["ATOM"])
+
+	require.Equal(t, []string{"ETH", "USDT", "USD"}, breakdown["ATOM"].Sources[config.ProviderKraken].Path)
+	require.Equal(t, []string{"USDT", "USD"}, breakdown["ETH"].Sources[config.ProviderBinance].Path)
+	require.Nil(t, breakdown["USDT"].Sources[config.ProviderOkx].Path)
+}
@@ -0,0 +1,228 @@
+package oracle
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-metrics"
+	"github.com/rs/zerolog"
+
+	"github.com/cosmos/cosmos-sdk/telemetry"
+)
+
+const (
+	// the states of a provider on the supervisor
+	ProviderStateHealthy    = "healthy"    // initialized and returning prices
+	ProviderStateFailed     = "failed"     // failed to initialize, waiting to retry
+	ProviderStateRecovering = "recovering" // initialized after failing, waiting for prices
+
+	// defaultInitialBackoff is the wait before the first retry of a failed provider
+	defaultInitialBackoff = 5 * time.Second
+	// defaultMaxBackoff is the maximum wait between the retries of a failed provider
+	defaultMaxBackoff = 5 * time.Minute
+)
+
+// ProviderStatus is the state of a provider on the supervisor
+type ProviderStatus struct {
+	State     string    `json:"state"`
+	Since     time.Time `json:"since"`                // time of the last state transition
+	Failures  int       `json:"failures"`             // consecutive failed initializations
+	LastError string    `json:"last_error,omitempty"` // error of the last failed initialization
+	NextRetry time.Time `json:"next_retry,omitempty"` // earliest time to retry the initialization
+}
+
+// ProviderSupervisor tracks the initialization of the providers, retrying
+// the failed ones with a jittered exponential backoff
+type ProviderSupervisor struct {
+	logger zerolog.Logger
+
+	mtx            sync.RWMutex
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	statuses       map[string]*ProviderStatus
+	now            func() time.Time
+	jitter         func(time.Duration) time.Duration
+}
+
+// NewProviderSupervisor creates a supervisor with the given backoff bounds
+func NewProviderSupervisor(logger zerolog.Logger, initialBackoff, maxBackoff time.Duration) *ProviderSupervisor {
+	return &ProviderSupervisor{
+		logger:         logger.With().Str("module", "provider_supervisor").Logger(),
+		initialBackoff: initialBackoff,
+		maxBackoff:     maxBackoff,
+		statuses:       make(map[string]*ProviderStatus),
+		now:            time.Now,
+		jitter:         equalJitter,
+	}
+}
+
+// equalJitter keeps half of the backoff and randomizes the other half, so
+// the providers failing together don't retry together
+func equalJitter(backoff time.Duration) time.Duration {
+	half := backoff / 2
+	if half <= 0 {
+		return backoff
+	}
+
+	return half + time.Duration(rand.Int63n(int64(half)))
+}
+
+// backoff returns the wait before retrying a provider after the given
+// amount of consecutive failures
+func (s *ProviderSupervisor) backoff(failures int) time.Duration {
+	backoff := s.initialBackoff
+	for i := 1; i < failures && backoff < s.maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > s.maxBackoff {
+		backoff = s.maxBackoff
+	}
+
+	return s.jitter(backoff)
+}
+
+// CheckRetry returns the last initialization error while a failed provider
+// waits for its next retry, and nil when the provider can be initialized
+func (s *ProviderSupervisor) CheckRetry(providerName string) error {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	status, ok := s.statuses[providerName]
+	if !ok || status.State != ProviderStateFailed {
+		return nil
+	}
+
+	if s.now().Before(status.NextRetry) {
+		return fmt.Errorf("%s (retrying at %s)", status.LastError, status.NextRetry.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// RecordFailure marks the provider as failed and schedules its next retry
+func (s *ProviderSupervisor) RecordFailure(providerName string, err error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	status, ok := s.statuses[providerName]
+	if !ok {
+		status = &ProviderStatus{}
+		s.statuses[providerName] = status
+	}
+
+	now := s.now()
+	status.Failures++
+	status.LastError = err.Error()
+	status.NextRetry = now.Add(s.backoff(status.Failures))
+
+	s.logger.Warn().
+		Err(err).
+		Str("provider", providerName).
+		Int("failures", status.Failures).
+		Time("next_retry", status.NextRetry).
+		Msg("failed to initialize provider")
+
+	s.transition(providerName, status, ProviderStateFailed, now)
+}
+
+// RecordSuccess marks the provider as initialized. A provider recovering from
+// failures only becomes healthy once it returns prices.
+func (s *ProviderSupervisor) RecordSuccess(providerName string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	status, ok := s.statuses[providerName]
+	if !ok {
+		status = &ProviderStatus{}
+		s.statuses[providerName] = status
+	}
+
+	state := ProviderStateHealthy
+	if status.Failures > 0 {
+		state = ProviderStateRecovering
+	}
+
+	s.transition(providerName, status, state, s.now())
+}
+
+// RecordPrices marks a recovering provider as healthy once it returns prices
+func (s *ProviderSupervisor) RecordPrices(providerName string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	status, ok := s.statuses[providerName]
+	if !ok || status.State != ProviderStateRecovering {
+		return
+	}
+
+	status.Failures = 0
+	status.LastError = ""
+	status.NextRetry = time.Time{}
+
+	s.transition(providerName, status, ProviderStateHealthy, s.now())
+}
+
+// Remove stops tracking a provider which is no longer configured
+func (s *ProviderSupervisor) Remove(providerName string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	delete(s.statuses, providerName)
+}
+
+// IsFailed returns true when the provider failed its last initialization
+func (s *ProviderSupervisor) IsFailed(providerName string) bool {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	status, ok := s.statuses[providerName]
+	return ok && status.State == ProviderStateFailed
+}
+
+// GetStatuses returns a copy of the provider statuses
+func (s *ProviderSupervisor) GetStatuses() map[string]ProviderStatus {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	statuses := make(map[string]ProviderStatus, len(s.statuses))
+	for providerName, status := range s.statuses {
+		statuses[providerName] = *status
+	}
+
+	return statuses
+}
+
+// transition moves the provider to a new state, emitting the telemetry of the
+// transition. It must be called with the lock held.
+func (s *ProviderSupervisor) transition(providerName string, status *ProviderStatus, state string, now time.Time) {
+	previousState := status.State
+	if previousState == state {
+		return
+	}
+
+	status.State = state
+	status.Since = now
+
+	if previousState == "" {
+		previousState = "new"
+	}
+
+	telemetry.IncrCounterWithLabels([]string{"provider", "transition"}, 1, []metrics.Label{
+		{Name: "provider", Value: providerName},
+		{Name: "from", Value: previousState},
+		{Name: "to", Value: state},
+	})
+
+	s.logger.Info().
+		Str("provider", providerName).
+		Str("from", previousState).
+		Str("to", state).
+		Msg("provider state changed")
+}
+
+// GetProviderStatuses returns the state of the providers on the supervisor
+func (o *Oracle) GetProviderStatuses() map[string]ProviderStatus {
+	return o.supervisor.GetStatuses()
+}
@@ -0,0 +1,75 @@
+package oracle
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderSupervisorBackoff(t *testing.T) {
+	s := NewProviderSupervisor(zerolog.Nop(), time.Second, 10*time.Second)
+	s.jitter = func(backoff time.Duration) time.Duration { return backoff }
+
+	testCases := map[int]time.Duration{
+		1: time.Second,
+		2: 2 * time.Second,
+		3: 4 * time.Second,
+		4: 8 * time.Second,
+		5: 10 * time.Second,
+		9: 10 * time.Second,
+	}
+
+	for failures, expected := range testCases {
+		require.Equal(t, expected, s.backoff(failures), failures)
+	}
+}
+
+func TestEqualJitter(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		jittered := equalJitter(10 * time.Second)
+		require.GreaterOrEqual(t, jittered, 5*time.Second)
+		require.Less(t, jittered, 10*time.Second)
+	}
+}
+
+func TestProviderSupervisorTransitions(t *testing.T) {
+	now := time.Unix(1000, 0)
+	s := NewProviderSupervisor(zerolog.Nop(), time.Second, 10*time.Second)
+	s.now = func() time.Time { return now }
+	s.jitter = func(backoff time.Duration) time.Duration { return backoff }
+
+	// a provider initialized at the first try is healthy
+	s.RecordSuccess("binance")
+	require.Equal(t, ProviderStateHealthy, s.GetStatuses()["binance"].State)
+
+	// a failed provider waits for the backoff before retrying
+	s.RecordFailure("kraken", fmt.Errorf("connection refused"))
+	require.True(t, s.IsFailed("kraken"))
+	require.Error(t, s.CheckRetry("kraken"))
+
+	now = now.Add(time.Second)
+	require.NoError(t, s.CheckRetry("kraken"))
+
+	// a second failure doubles the backoff
+	s.RecordFailure("kraken", fmt.Errorf("connection refused"))
+	status := s.GetStatuses()["kraken"]
+	require.Equal(t, 2, status.Failures)
+	require.Equal(t, now.Add(2*time.Second), status.NextRetry)
+
+	// the provider recovers once initialized, and is healthy once it returns prices
+	now = now.Add(2 * time.Second)
+	require.NoError(t, s.CheckRetry("kraken"))
+	s.RecordSuccess("kraken")
+	require.Equal(t, ProviderStateRecovering, s.GetStatuses()["kraken"].State)
+	require.False(t, s.IsFailed("kraken"))
+
+	s.RecordPrices("kraken")
+	status = s.GetStatuses()["kraken"]
+	require.Equal(t, ProviderStateHealthy, status.State)
+	require.Zero(t, status.Failures)
+	require.Empty(t, status.LastError)
+	require.Equal(t, now, status.Since)
+}
@@ -75,17 +75,11 @@ func ComputeVWAP(prices provider.AggregatedProviderPrices) (map[string]math.Lega
 // Ref : https://en.wikipedia.org/wiki/Time-weighted_average_price
 func ComputeTVWAP(prices provider.AggregatedProviderCandles) (map[string]math.LegacyDec, error) {
 	var (
-		weightedPrices = make(map[string]math.LegacyDec)
-		volumeSum      = make(map[string]math.LegacyDec)
-		now            = provider.PastUnixTime(0)
-		timePeriod     = provider.PastUnixTime(tvwapCandlePeriod)
+		weightedPrices  = make(map[string]math.LegacyDec)
+		volumeSum       = make(map[string]math.LegacyDec)
+		now, timePeriod = tvwapTimeWindow()
 	)
 
-	// this lets us mock now for tests
-	if mockNow > 0 {
-		now = mockNow
-	}
-
 	for _, providerPrices := range prices {
 		for base := range providerPrices {
 			cp := providerPrices[base]
@@ -97,40 +91,69 @@ func ComputeTVWAP(prices provider.AggregatedProviderCandles) (map[string]math.Le
 				volumeSum[base] = math.LegacyZeroDec()
 			}
 
-			// Sort by timestamp old -> new
-			sort.SliceStable(cp, func(i, j int) bool {
-				return cp[i].TimeStamp < cp[j].TimeStamp
-			})
+			// get weighted prices, and sum of volumes
+			weightedPrice, volume := tvwapSums(cp, now, timePeriod)
+			weightedPrices[base] = weightedPrices[base].Add(weightedPrice)
+			volumeSum[base] = volumeSum[base].Add(volume)
+		}
+	}
 
-			period := math.LegacyNewDec(now - cp[0].TimeStamp)
+	return vwap(weightedPrices, volumeSum)
+}
 
-			// weight unit is one, then decreased proportionately by candle age
-			weightUnit := math.LegacyZeroDec().Sub(minimumTimeWeight)
+// tvwapTimeWindow returns the current time and the start of the tvwap period
+func tvwapTimeWindow() (now int64, timePeriod int64) {
+	now = provider.PastUnixTime(0)
+	timePeriod = provider.PastUnixTime(tvwapCandlePeriod)
 
-			// if zero, it would divide by zero
-			if !period.Equal(math.LegacyZeroDec()) {
-				weightUnit = weightUnit.Quo(period)
-			}
+	// this lets us mock now for tests
+	if mockNow > 0 {
+		now = mockNow
+	}
 
-			// get weighted prices, and sum of volumes
-			for _, candle := range cp {
-				// we only want candles within the last timePeriod
-				if timePeriod < candle.TimeStamp {
-					// timeDiff = now - candle.TimeStamp
-					timeDiff := math.LegacyNewDec(now - candle.TimeStamp)
-					// volume = candle.Volume * (weightUnit * (period - timeDiff) + minimumTimeWeight)
-					volume := candle.Volume.Mul(
-						weightUnit.Mul(period.Sub(timeDiff).Add(minimumTimeWeight)),
-					)
-					volumeSum[base] = volumeSum[base].Add(volume)
-					weightedPrices[base] = weightedPrices[base].Add(candle.Price.Mul(volume))
-				}
-			}
+	return now, timePeriod
+}
+
+// tvwapSums returns the Σ {P * V} and Σ {V} of the candles within the tvwap
+// period, with the volume of each candle weighted by its age
+func tvwapSums(cp []provider.CandlePrice, now, timePeriod int64) (weightedPrice, volumeSum math.LegacyDec) {
+	weightedPrice = math.LegacyZeroDec()
+	volumeSum = math.LegacyZeroDec()
+
+	if len(cp) == 0 {
+		return weightedPrice, volumeSum
+	}
+
+	// Sort by timestamp old -> new
+	sort.SliceStable(cp, func(i, j int) bool {
+		return cp[i].TimeStamp < cp[j].TimeStamp
+	})
+
+	period := math.LegacyNewDec(now - cp[0].TimeStamp)
+
+	// weight unit is one, then decreased proportionately by candle age
+	weightUnit := math.LegacyZeroDec().Sub(minimumTimeWeight)
 
+	// if zero, it would divide by zero
+	if !period.Equal(math.LegacyZeroDec()) {
+		weightUnit = weightUnit.Quo(period)
+	}
+
+	for _, candle := range cp {
+		// we only want candles within the last timePeriod
+		if timePeriod < candle.TimeStamp {
+			// timeDiff = now - candle.TimeStamp
+			timeDiff := math.LegacyNewDec(now - candle.TimeStamp)
+			// volume = candle.Volume * (weightUnit * (period - timeDiff) + minimumTimeWeight)
+			volume := candle.Volume.Mul(
+				weightUnit.Mul(period.Sub(timeDiff).Add(minimumTimeWeight)),
+			)
+			volumeSum = volumeSum.Add(volume)
+			weightedPrice = weightedPrice.Add(candle.Price.Mul(volume))
 		}
 	}
 
-	return vwap(weightedPrices, volumeSum)
+	return weightedPrice, volumeSum
 }
 
 // StandardDeviation returns maps of the standard deviations and means of assets.
@@ -192,3 +215,97 @@ func StandardDeviation(
 
 	return deviations, means, nil
 }
+
+// MedianAbsoluteDeviation returns maps of the median absolute deviations and
+// medians of assets. Will skip calculating for an asset if there are less than
+// 3 prices.
+func MedianAbsoluteDeviation(
+	prices map[string]map[string]math.LegacyDec,
+) (map[string]math.LegacyDec, map[string]math.LegacyDec, error) {
+	var (
+		deviations = make(map[string]math.LegacyDec)
+		medians    = make(map[string]math.LegacyDec)
+		priceSlice = make(map[string][]math.LegacyDec)
+	)
+
+	for _, providerPrices := range prices {
+		for base, p := range providerPrices {
+			priceSlice[base] = append(priceSlice[base], p)
+		}
+	}
+
+	for base, ps := range priceSlice {
+		// Skip if the median would not be meaningful
+		if len(ps) < 3 {
+			continue
+		}
+
+		medians[base] = median(ps)
+
+		absDeviations := make([]math.LegacyDec, len(ps))
+		for i, price := range ps {
+			absDeviations[i] = price.Sub(medians[base]).Abs()
+		}
+		deviations[base] = median(absDeviations)
+	}
+
+	return deviations, medians, nil
+}
+
+// median returns the median of the prices, the mean of the two middle prices
+// for an even amount.
+func median(prices []math.LegacyDec) math.LegacyDec {
+	sorted := make([]math.LegacyDec, len(prices))
+	copy(sorted, prices)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].LT(sorted[j])
+	})
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return sorted[middle-1].Add(sorted[middle]).QuoInt64(2)
+	}
+
+	return sorted[middle]
+}
+
+// weightedMedian returns the price where the cumulative weight of the sorted
+// prices reaches half of the total weight, the mean of the two prices when it
+// is exactly half. The prices without a positive weight are ignored, and the
+// median is returned when there is no weight.
+func weightedMedian(prices, weights []math.LegacyDec) math.LegacyDec {
+	type weightedPrice struct {
+		price  math.LegacyDec
+		weight math.LegacyDec
+	}
+
+	sorted := []weightedPrice{}
+	totalWeight := math.LegacyZeroDec()
+	for i, price := range prices {
+		if weights[i].IsPositive() {
+			sorted = append(sorted, weightedPrice{price, weights[i]})
+			totalWeight = totalWeight.Add(weights[i])
+		}
+	}
+	if len(sorted) == 0 {
+		return median(prices)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].price.LT(sorted[j].price)
+	})
+
+	half := totalWeight.QuoInt64(2)
+	cumulativeWeight := math.LegacyZeroDec()
+	for i, wp := range sorted {
+		cumulativeWeight = cumulativeWeight.Add(wp.weight)
+		if cumulativeWeight.Equal(half) && i+1 < len(sorted) {
+			return wp.price.Add(sorted[i+1].price).QuoInt64(2)
+		}
+		if cumulativeWeight.GTE(half) {
+			return wp.price
+		}
+	}
+
+	return sorted[len(sorted)-1].price
+}
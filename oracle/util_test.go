@@ -285,3 +285,79 @@ func TestStandardDeviation(t *testing.T) {
 		})
 	}
 }
+
+func TestMedianAbsoluteDeviation(t *testing.T) {
+	prices := map[string]map[string]math.LegacyDec{
+		config.ProviderBinance: {
+			"ATOM": math.LegacyMustNewDecFromStr("10"),
+			"UMEE": math.LegacyMustNewDecFromStr("1.13"),
+		},
+		config.ProviderKraken: {
+			"ATOM": math.LegacyMustNewDecFromStr("10.1"),
+			"UMEE": math.LegacyMustNewDecFromStr("1.14"),
+		},
+		config.ProviderCoinbase: {
+			"ATOM": math.LegacyMustNewDecFromStr("9.95"),
+		},
+		config.ProviderOkx: {
+			"ATOM": math.LegacyMustNewDecFromStr("20"),
+		},
+	}
+
+	deviations, medians, err := MedianAbsoluteDeviation(prices)
+	require.NoError(t, err)
+
+	// the median of 9.95, 10, 10.1 and 20 is not moved by the outlier
+	require.Equal(t, math.LegacyMustNewDecFromStr("10.05"), medians["ATOM"])
+	require.Equal(t, math.LegacyMustNewDecFromStr("0.075"), deviations["ATOM"])
+	require.NotContains(t, medians, "UMEE")
+	require.NotContains(t, deviations, "UMEE")
+}
+
+func TestWeightedMedian(t *testing.T) {
+	decs := func(values ...string) []math.LegacyDec {
+		d := make([]math.LegacyDec, len(values))
+		for i, v := range values {
+			d[i] = math.LegacyMustNewDecFromStr(v)
+		}
+		return d
+	}
+
+	testCases := map[string]struct {
+		prices   []math.LegacyDec
+		weights  []math.LegacyDec
+		expected math.LegacyDec
+	}{
+		"heaviest price": {
+			prices:   decs("10", "11", "12"),
+			weights:  decs("1", "1", "10"),
+			expected: math.LegacyMustNewDecFromStr("12"),
+		},
+		"equal weights": {
+			prices:   decs("12", "10", "11"),
+			weights:  decs("1", "1", "1"),
+			expected: math.LegacyMustNewDecFromStr("11"),
+		},
+		"exactly half": {
+			prices:   decs("10", "12"),
+			weights:  decs("2", "2"),
+			expected: math.LegacyMustNewDecFromStr("11"),
+		},
+		"ignored weights": {
+			prices:   decs("10", "11", "30"),
+			weights:  decs("1", "1", "0"),
+			expected: math.LegacyMustNewDecFromStr("10.5"),
+		},
+		"no weight": {
+			prices:   decs("10", "11", "30"),
+			weights:  decs("0", "0", "0"),
+			expected: math.LegacyMustNewDecFromStr("11"),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.expected, weightedMedian(tc.prices, tc.weights))
+		})
+	}
+}
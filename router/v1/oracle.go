@@ -4,10 +4,19 @@ import (
 	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/kiichain/price-feeder/oracle"
 )
 
 // Oracle defines the Oracle interface contract that the v1 router depends on.
 type Oracle interface {
 	GetLastPriceSyncTimestamp() time.Time
 	GetPrices() sdk.DecCoins
+	GetAssetBreakdown(base string) (oracle.AssetBreakdown, bool)
+	GetShadowVotes() []oracle.ShadowVote
+	GetVoteAccuracy() map[string][]oracle.VoteAccuracy
+	GetPenaltyStatus() *oracle.PenaltyStatus
+	GetPairValidation() *oracle.PairValidation
+	GetProviderStatuses() map[string]oracle.ProviderStatus
+	GetJournalEntries(query oracle.JournalQuery) ([]oracle.JournalEntry, int, error)
 }
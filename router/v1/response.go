@@ -5,11 +5,14 @@ import (
 	"net/http"
 
 	"cosmossdk.io/math"
+
+	"github.com/kiichain/price-feeder/oracle"
 )
 
 // Response constants
 const (
 	StatusAvailable = "available"
+	StatusDegraded  = "degraded"
 )
 
 type (
@@ -17,7 +20,10 @@ type (
 	HealthZResponse struct {
 		Status string `json:"status" yaml:"status"`
 		Oracle struct {
-			LastSync string `json:"last_sync"`
+			LastSync   string                 `json:"last_sync"`
+			ShadowMode bool                   `json:"shadow_mode"`
+			Penalty    *oracle.PenaltyStatus  `json:"penalty,omitempty"`
+			Pairs      *oracle.PairValidation `json:"pairs,omitempty"`
 		} `json:"oracle"`
 	}
 
@@ -26,6 +32,40 @@ type (
 	PricesResponse struct {
 		Prices map[string]math.LegacyDec `json:"prices"`
 	}
+
+	// PriceSourcesResponse defines the response type for getting how the
+	// latest price of an asset was computed from each provider.
+	PriceSourcesResponse struct {
+		Base    string                        `json:"base"`
+		Price   math.LegacyDec                `json:"price"`
+		Method  string                        `json:"method"`
+		Sources map[string]oracle.PriceSource `json:"sources"`
+	}
+
+	// ProvidersResponse defines the response type for getting the state of
+	// the providers on the supervisor.
+	ProvidersResponse struct {
+		Providers map[string]oracle.ProviderStatus `json:"providers"`
+	}
+
+	// ShadowVotesResponse defines the response type for getting the votes
+	// recorded by the oracle while running in shadow mode.
+	ShadowVotesResponse struct {
+		Votes []oracle.ShadowVote `json:"votes"`
+	}
+
+	// VoteAccuracyResponse defines the response type for getting the accuracy
+	// of the latest votes against the on-chain exchange rates.
+	VoteAccuracyResponse struct {
+		Accuracy map[string][]oracle.VoteAccuracy `json:"accuracy"`
+	}
+
+	// JournalResponse defines the response type for getting a page of the
+	// vote journal, from the newest to the oldest vote.
+	JournalResponse struct {
+		Entries []oracle.JournalEntry `json:"entries"`
+		Total   int                   `json:"total"`
+	}
 )
 
 // errorResponse defines the attributes of a JSON error response.
@@ -1,8 +1,10 @@
 package v1
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,6 +16,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/telemetry"
 
 	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle"
 	"github.com/kiichain/price-feeder/pkg/httputil"
 	"github.com/kiichain/price-feeder/router/middleware"
 )
@@ -74,6 +77,36 @@ func (r *Router) RegisterRoutes(rtr *mux.Router, prefix string) {
 		mChain.ThenFunc(r.pricesHandler()),
 	).Methods(httputil.MethodGET)
 
+	// Handle the price sources of an asset
+	v1Router.Handle(
+		"/prices/{base}/sources",
+		mChain.ThenFunc(r.priceSourcesHandler()),
+	).Methods(httputil.MethodGET)
+
+	// Handle the providers state
+	v1Router.Handle(
+		"/providers",
+		mChain.ThenFunc(r.providersHandler()),
+	).Methods(httputil.MethodGET)
+
+	// Handle the shadow votes
+	v1Router.Handle(
+		"/vote/shadow",
+		mChain.ThenFunc(r.shadowVotesHandler()),
+	).Methods(httputil.MethodGET)
+
+	// Handle the vote accuracy
+	v1Router.Handle(
+		"/vote/accuracy",
+		mChain.ThenFunc(r.voteAccuracyHandler()),
+	).Methods(httputil.MethodGET)
+
+	// Handle the vote journal
+	v1Router.Handle(
+		"/vote/journal",
+		mChain.ThenFunc(r.journalHandler()),
+	).Methods(httputil.MethodGET)
+
 	// Handle the metrics endpoint
 	if r.cfg.Telemetry.Enabled {
 		v1Router.Handle(
@@ -93,6 +126,19 @@ func (r *Router) healthzHandler() http.HandlerFunc {
 
 		// Get the last sync time from the oracle
 		resp.Oracle.LastSync = r.oracle.GetLastPriceSyncTimestamp().Format(time.RFC3339)
+		resp.Oracle.ShadowMode = r.cfg.Main.ShadowMode
+
+		// The feeder is degraded when it is heading toward an oracle slash
+		resp.Oracle.Penalty = r.oracle.GetPenaltyStatus()
+		if resp.Oracle.Penalty != nil && resp.Oracle.Penalty.AtRisk {
+			resp.Status = StatusDegraded
+		}
+
+		// The feeder is degraded when a pair is below the minimum amount of providers
+		resp.Oracle.Pairs = r.oracle.GetPairValidation()
+		if resp.Oracle.Pairs != nil && len(resp.Oracle.Pairs.UnderProvided) > 0 {
+			resp.Status = StatusDegraded
+		}
 
 		// Respond on the server
 		httputil.RespondWithJSON(w, http.StatusOK, resp)
@@ -119,6 +165,56 @@ func (r *Router) pricesHandler() http.HandlerFunc {
 	}
 }
 
+// priceSourcesHandler returns a handler function for the price sources endpoint
+func (r *Router) priceSourcesHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		// Get the breakdown of the requested base
+		base := strings.ToUpper(mux.Vars(req)["base"])
+		asset, ok := r.oracle.GetAssetBreakdown(base)
+		if !ok {
+			writeErrorResponse(w, http.StatusNotFound, fmt.Sprintf("no price computed for %s", base))
+			return
+		}
+
+		// Prepare the response
+		resp := PriceSourcesResponse{
+			Base:    base,
+			Price:   asset.Price,
+			Method:  asset.Method,
+			Sources: asset.Sources,
+		}
+
+		// Respond on the server
+		httputil.RespondWithJSON(w, http.StatusOK, resp)
+	}
+}
+
+// providersHandler returns a handler function for the providers state endpoint
+func (r *Router) providersHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		// Prepare the response with the supervisor state
+		resp := ProvidersResponse{
+			Providers: r.oracle.GetProviderStatuses(),
+		}
+
+		// Respond on the server
+		httputil.RespondWithJSON(w, http.StatusOK, resp)
+	}
+}
+
+// shadowVotesHandler returns a handler function for the shadow votes endpoint
+func (r *Router) shadowVotesHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		// Prepare the response with the recorded votes
+		resp := ShadowVotesResponse{
+			Votes: r.oracle.GetShadowVotes(),
+		}
+
+		// Respond on the server
+		httputil.RespondWithJSON(w, http.StatusOK, resp)
+	}
+}
+
 // metricsHandler returns a handler function for the metrics endpoint
 func (r *Router) metricsHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
@@ -142,3 +238,63 @@ func (r *Router) metricsHandler() http.HandlerFunc {
 		_, _ = w.Write(gr.Metrics)
 	}
 }
+
+// voteAccuracyHandler returns a handler function for the vote accuracy endpoint
+func (r *Router) voteAccuracyHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		// Prepare the response with the accuracy history by denom
+		resp := VoteAccuracyResponse{
+			Accuracy: r.oracle.GetVoteAccuracy(),
+		}
+
+		// Respond on the server
+		httputil.RespondWithJSON(w, http.StatusOK, resp)
+	}
+}
+
+// journalHandler returns a handler function for the vote journal endpoint,
+// paginated by the offset and limit query params and filtered by height
+func (r *Router) journalHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		// Parse the query params
+		var query oracle.JournalQuery
+		params := req.URL.Query()
+
+		var err error
+		if value := params.Get("height"); value != "" {
+			if query.Height, err = strconv.ParseInt(value, 10, 64); err != nil {
+				writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("invalid height: %s", value))
+				return
+			}
+		}
+		if value := params.Get("offset"); value != "" {
+			if query.Offset, err = strconv.Atoi(value); err != nil || query.Offset < 0 {
+				writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("invalid offset: %s", value))
+				return
+			}
+		}
+		if value := params.Get("limit"); value != "" {
+			if query.Limit, err = strconv.Atoi(value); err != nil || query.Limit < 0 || query.Limit > oracle.MaxJournalLimit {
+				writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("invalid limit: %s, expected at most %d", value, oracle.MaxJournalLimit))
+				return
+			}
+		}
+
+		// Read the journal page
+		entries, total, err := r.oracle.GetJournalEntries(query)
+		if errors.Is(err, oracle.ErrJournalDisabled) {
+			writeErrorResponse(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if err != nil {
+			writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		// Respond on the server
+		httputil.RespondWithJSON(w, http.StatusOK, JournalResponse{
+			Entries: entries,
+			Total:   total,
+		})
+	}
+}
@@ -17,6 +17,7 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
 	"github.com/kiichain/price-feeder/config"
+	"github.com/kiichain/price-feeder/oracle"
 	v1 "github.com/kiichain/price-feeder/router/v1"
 )
 
@@ -27,6 +28,61 @@ var (
 		sdk.NewDecCoinFromDec("ATOM", math.LegacyMustNewDecFromStr("34.84")),
 		sdk.NewDecCoinFromDec("UMEE", math.LegacyMustNewDecFromStr("4.21")),
 	}
+
+	mockPriceBreakdown = oracle.PriceBreakdown{
+		"ATOM": {
+			Price:  math.LegacyMustNewDecFromStr("34.84"),
+			Method: oracle.ComputationMethodVWAP,
+			Sources: map[string]oracle.PriceSource{
+				"binance": {
+					Quote:    "USDT",
+					RawPrice: math.LegacyMustNewDecFromStr("34.84"),
+					Volume:   math.LegacyMustNewDecFromStr("1000"),
+					Price:    math.LegacyMustNewDecFromStr("34.84"),
+					Weight:   math.LegacyOneDec(),
+				},
+			},
+		},
+	}
+
+	mockShadowVotes = []oracle.ShadowVote{
+		{
+			BlockHeight:   10,
+			VotePeriod:    5,
+			ExchangeRates: "34.840000000000000000ATOM,4.210000000000000000UMEE",
+		},
+	}
+
+	mockPenaltyStatus *oracle.PenaltyStatus
+
+	mockPairValidation *oracle.PairValidation
+
+	mockProviderStatuses = map[string]oracle.ProviderStatus{
+		"binance": {State: oracle.ProviderStateHealthy},
+		"kraken":  {State: oracle.ProviderStateFailed, Failures: 2, LastError: "connection refused"},
+	}
+
+	mockJournalEntries = []oracle.JournalEntry{
+		{
+			BlockHeight:   10,
+			VotePeriod:    5,
+			ExchangeRates: "34.840000000000000000ATOM,4.210000000000000000UMEE",
+			TxHash:        "0xhash",
+		},
+	}
+
+	mockVoteAccuracy = map[string][]oracle.VoteAccuracy{
+		"ATOM": {
+			{
+				BlockHeight:      10,
+				VotePeriod:       5,
+				VotedRate:        math.LegacyMustNewDecFromStr("34.84"),
+				OnChainRate:      math.LegacyMustNewDecFromStr("34.84"),
+				Deviation:        math.LegacyZeroDec(),
+				WithinRewardBand: true,
+			},
+		},
+	}
 )
 
 type mockOracle struct{}
@@ -39,6 +95,38 @@ func (m mockOracle) GetPrices() sdk.DecCoins {
 	return mockPrices
 }
 
+func (m mockOracle) GetAssetBreakdown(base string) (oracle.AssetBreakdown, bool) {
+	asset, ok := mockPriceBreakdown[base]
+	return asset, ok
+}
+
+func (m mockOracle) GetShadowVotes() []oracle.ShadowVote {
+	return mockShadowVotes
+}
+
+func (m mockOracle) GetVoteAccuracy() map[string][]oracle.VoteAccuracy {
+	return mockVoteAccuracy
+}
+
+func (m mockOracle) GetPenaltyStatus() *oracle.PenaltyStatus {
+	return mockPenaltyStatus
+}
+
+func (m mockOracle) GetPairValidation() *oracle.PairValidation {
+	return mockPairValidation
+}
+
+func (m mockOracle) GetProviderStatuses() map[string]oracle.ProviderStatus {
+	return mockProviderStatuses
+}
+
+func (m mockOracle) GetJournalEntries(query oracle.JournalQuery) ([]oracle.JournalEntry, int, error) {
+	if query.Height != 0 && query.Height != mockJournalEntries[0].BlockHeight {
+		return []oracle.JournalEntry{}, 0, nil
+	}
+	return mockJournalEntries, len(mockJournalEntries), nil
+}
+
 type mockMetrics struct{}
 
 func (mockMetrics) Gather(format string) (telemetry.GatherResponse, error) {
@@ -91,6 +179,44 @@ func (rts *RouterTestSuite) TestHealthz() {
 	rts.Require().Equal(respBody["status"], v1.StatusAvailable)
 }
 
+func (rts *RouterTestSuite) TestHealthzDegraded() {
+	mockPenaltyStatus = &oracle.PenaltyStatus{MissCount: 96, RemainingMargin: -1, AtRisk: true}
+	defer func() { mockPenaltyStatus = nil }()
+
+	req, err := http.NewRequest("GET", "/healthz", nil)
+	rts.Require().NoError(err)
+
+	response := rts.executeRequest(req)
+	rts.Require().Equal(http.StatusOK, response.Code)
+
+	var respBody v1.HealthZResponse
+	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &respBody))
+	rts.Require().Equal(v1.StatusDegraded, respBody.Status)
+	rts.Require().NotNil(respBody.Oracle.Penalty)
+	rts.Require().Equal(uint64(96), respBody.Oracle.Penalty.MissCount)
+}
+
+func (rts *RouterTestSuite) TestHealthzUnderProvided() {
+	mockPairValidation = &oracle.PairValidation{
+		Unsupported:   map[string][]string{"kraken": {"UMEEUSDT"}},
+		UnderProvided: []string{"UMEE"},
+	}
+	defer func() { mockPairValidation = nil }()
+
+	req, err := http.NewRequest("GET", "/healthz", nil)
+	rts.Require().NoError(err)
+
+	response := rts.executeRequest(req)
+	rts.Require().Equal(http.StatusOK, response.Code)
+
+	var respBody v1.HealthZResponse
+	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &respBody))
+	rts.Require().Equal(v1.StatusDegraded, respBody.Status)
+	rts.Require().NotNil(respBody.Oracle.Pairs)
+	rts.Require().Equal([]string{"UMEE"}, respBody.Oracle.Pairs.UnderProvided)
+	rts.Require().Equal([]string{"UMEEUSDT"}, respBody.Oracle.Pairs.Unsupported["kraken"])
+}
+
 func (rts *RouterTestSuite) TestPrices() {
 	req, err := http.NewRequest("GET", "/prices", nil)
 	rts.Require().NoError(err)
@@ -104,3 +230,92 @@ func (rts *RouterTestSuite) TestPrices() {
 	rts.Require().Equal(respBody.Prices["UMEE"], mockPrices.AmountOf("UMEE"))
 	rts.Require().Equal(respBody.Prices["FOO"], math.LegacyDec{})
 }
+
+func (rts *RouterTestSuite) TestPriceSources() {
+	req, err := http.NewRequest("GET", "/prices/atom/sources", nil)
+	rts.Require().NoError(err)
+
+	response := rts.executeRequest(req)
+	rts.Require().Equal(http.StatusOK, response.Code)
+
+	var respBody v1.PriceSourcesResponse
+	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &respBody))
+	rts.Require().Equal("ATOM", respBody.Base)
+	rts.Require().Equal(oracle.ComputationMethodVWAP, respBody.Method)
+	rts.Require().Equal(mockPriceBreakdown["ATOM"].Sources["binance"].Weight, respBody.Sources["binance"].Weight)
+
+	// unknown assets are not found
+	req, err = http.NewRequest("GET", "/prices/FOO/sources", nil)
+	rts.Require().NoError(err)
+
+	response = rts.executeRequest(req)
+	rts.Require().Equal(http.StatusNotFound, response.Code)
+}
+
+func (rts *RouterTestSuite) TestProviders() {
+	req, err := http.NewRequest("GET", "/providers", nil)
+	rts.Require().NoError(err)
+
+	response := rts.executeRequest(req)
+	rts.Require().Equal(http.StatusOK, response.Code)
+
+	var respBody v1.ProvidersResponse
+	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &respBody))
+	rts.Require().Len(respBody.Providers, 2)
+	rts.Require().Equal(oracle.ProviderStateHealthy, respBody.Providers["binance"].State)
+	rts.Require().Equal(oracle.ProviderStateFailed, respBody.Providers["kraken"].State)
+	rts.Require().Equal(2, respBody.Providers["kraken"].Failures)
+	rts.Require().Equal("connection refused", respBody.Providers["kraken"].LastError)
+}
+
+func (rts *RouterTestSuite) TestShadowVotes() {
+	req, err := http.NewRequest("GET", "/vote/shadow", nil)
+	rts.Require().NoError(err)
+
+	response := rts.executeRequest(req)
+	rts.Require().Equal(http.StatusOK, response.Code)
+
+	var respBody v1.ShadowVotesResponse
+	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &respBody))
+	rts.Require().Len(respBody.Votes, 1)
+	rts.Require().Equal(mockShadowVotes[0].BlockHeight, respBody.Votes[0].BlockHeight)
+	rts.Require().Equal(mockShadowVotes[0].ExchangeRates, respBody.Votes[0].ExchangeRates)
+}
+
+func (rts *RouterTestSuite) TestVoteAccuracy() {
+	req, err := http.NewRequest("GET", "/vote/accuracy", nil)
+	rts.Require().NoError(err)
+
+	response := rts.executeRequest(req)
+	rts.Require().Equal(http.StatusOK, response.Code)
+
+	var respBody v1.VoteAccuracyResponse
+	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &respBody))
+	rts.Require().Len(respBody.Accuracy["ATOM"], 1)
+	rts.Require().Equal(mockVoteAccuracy["ATOM"][0].VotedRate, respBody.Accuracy["ATOM"][0].VotedRate)
+	rts.Require().True(respBody.Accuracy["ATOM"][0].WithinRewardBand)
+}
+
+func (rts *RouterTestSuite) TestJournal() {
+	req, err := http.NewRequest("GET", "/vote/journal?height=10&offset=0&limit=10", nil)
+	rts.Require().NoError(err)
+
+	response := rts.executeRequest(req)
+	rts.Require().Equal(http.StatusOK, response.Code)
+
+	var respBody v1.JournalResponse
+	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &respBody))
+	rts.Require().Equal(1, respBody.Total)
+	rts.Require().Len(respBody.Entries, 1)
+	rts.Require().Equal(mockJournalEntries[0].TxHash, respBody.Entries[0].TxHash)
+}
+
+func (rts *RouterTestSuite) TestJournalInvalidQuery() {
+	for _, query := range []string{"limit=foo", "limit=100000000", "limit=-1", "offset=-1"} {
+		req, err := http.NewRequest("GET", "/vote/journal?"+query, nil)
+		rts.Require().NoError(err)
+
+		response := rts.executeRequest(req)
+		rts.Require().Equal(http.StatusBadRequest, response.Code, query)
+	}
+}